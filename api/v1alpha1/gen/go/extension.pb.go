@@ -0,0 +1,3509 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.30.0
+// 	protoc        (unknown)
+// source: extension.proto
+
+package _go
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Host allowed to connect to an NVMe subsystem
+type NVMeHost struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// NQN the host connects with
+	Nqn string `protobuf:"bytes,1,opt,name=nqn,proto3" json:"nqn,omitempty"`
+	// name of the keyring key with the TLS pre-shared key the host connects
+	// with to listeners requiring a secure channel
+	Psk string `protobuf:"bytes,2,opt,name=psk,proto3" json:"psk,omitempty"`
+	// name of the keyring key the host must authenticate with using
+	// DH-HMAC-CHAP, no authentication is required if empty
+	DhchapKey string `protobuf:"bytes,3,opt,name=dhchap_key,json=dhchapKey,proto3" json:"dhchap_key,omitempty"`
+	// name of the keyring key the subsystem authenticates to the host with
+	DhchapCtrlrKey string `protobuf:"bytes,4,opt,name=dhchap_ctrlr_key,json=dhchapCtrlrKey,proto3" json:"dhchap_ctrlr_key,omitempty"`
+}
+
+func (x *NVMeHost) Reset() {
+	*x = NVMeHost{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeHost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeHost) ProtoMessage() {}
+
+func (x *NVMeHost) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeHost.ProtoReflect.Descriptor instead.
+func (*NVMeHost) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *NVMeHost) GetNqn() string {
+	if x != nil {
+		return x.Nqn
+	}
+	return ""
+}
+
+func (x *NVMeHost) GetPsk() string {
+	if x != nil {
+		return x.Psk
+	}
+	return ""
+}
+
+func (x *NVMeHost) GetDhchapKey() string {
+	if x != nil {
+		return x.DhchapKey
+	}
+	return ""
+}
+
+func (x *NVMeHost) GetDhchapCtrlrKey() string {
+	if x != nil {
+		return x.DhchapCtrlrKey
+	}
+	return ""
+}
+
+// Access control list of an NVMe subsystem
+type NVMeSubsystemHosts struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// lets every host connect, regardless of hosts
+	AllowAnyHost bool `protobuf:"varint,1,opt,name=allow_any_host,json=allowAnyHost,proto3" json:"allow_any_host,omitempty"`
+	// hosts allowed to connect
+	Hosts []*NVMeHost `protobuf:"bytes,2,rep,name=hosts,proto3" json:"hosts,omitempty"`
+}
+
+func (x *NVMeSubsystemHosts) Reset() {
+	*x = NVMeSubsystemHosts{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeSubsystemHosts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeSubsystemHosts) ProtoMessage() {}
+
+func (x *NVMeSubsystemHosts) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeSubsystemHosts.ProtoReflect.Descriptor instead.
+func (*NVMeSubsystemHosts) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *NVMeSubsystemHosts) GetAllowAnyHost() bool {
+	if x != nil {
+		return x.AllowAnyHost
+	}
+	return false
+}
+
+func (x *NVMeSubsystemHosts) GetHosts() []*NVMeHost {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type UpdateNVMeSubsystemHostsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe subsystem
+	Name  string              `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Hosts *NVMeSubsystemHosts `protobuf:"bytes,2,opt,name=hosts,proto3" json:"hosts,omitempty"`
+	// allow_any_host, hosts or both, all fields are replaced if empty
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+}
+
+func (x *UpdateNVMeSubsystemHostsRequest) Reset() {
+	*x = UpdateNVMeSubsystemHostsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateNVMeSubsystemHostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateNVMeSubsystemHostsRequest) ProtoMessage() {}
+
+func (x *UpdateNVMeSubsystemHostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateNVMeSubsystemHostsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateNVMeSubsystemHostsRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UpdateNVMeSubsystemHostsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateNVMeSubsystemHostsRequest) GetHosts() *NVMeSubsystemHosts {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+func (x *UpdateNVMeSubsystemHostsRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type GetNVMeSubsystemHostsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe subsystem
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetNVMeSubsystemHostsRequest) Reset() {
+	*x = GetNVMeSubsystemHostsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNVMeSubsystemHostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNVMeSubsystemHostsRequest) ProtoMessage() {}
+
+func (x *GetNVMeSubsystemHostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNVMeSubsystemHostsRequest.ProtoReflect.Descriptor instead.
+func (*GetNVMeSubsystemHostsRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetNVMeSubsystemHostsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Transport an NVMe controller is exposed over. Controllers created without
+// it use the default transport of the bridge.
+type NVMeControllerTransport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// tcp, rdma or vfiouser, which needs a registered listener
+	Transport string `protobuf:"bytes,1,opt,name=transport,proto3" json:"transport,omitempty"`
+	// IP address the controller listens on instead of the address of the
+	// listener, valid only with tcp and rdma transports
+	Traddr string `protobuf:"bytes,2,opt,name=traddr,proto3" json:"traddr,omitempty"`
+	// port the controller listens on instead of the port of the listener,
+	// valid only with tcp and rdma transports
+	Trsvcid string `protobuf:"bytes,3,opt,name=trsvcid,proto3" json:"trsvcid,omitempty"`
+}
+
+func (x *NVMeControllerTransport) Reset() {
+	*x = NVMeControllerTransport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeControllerTransport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeControllerTransport) ProtoMessage() {}
+
+func (x *NVMeControllerTransport) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeControllerTransport.ProtoReflect.Descriptor instead.
+func (*NVMeControllerTransport) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NVMeControllerTransport) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *NVMeControllerTransport) GetTraddr() string {
+	if x != nil {
+		return x.Traddr
+	}
+	return ""
+}
+
+func (x *NVMeControllerTransport) GetTrsvcid() string {
+	if x != nil {
+		return x.Trsvcid
+	}
+	return ""
+}
+
+type SetNVMeControllerTransportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe controller, which is not created yet
+	Name      string                   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Transport *NVMeControllerTransport `protobuf:"bytes,2,opt,name=transport,proto3" json:"transport,omitempty"`
+}
+
+func (x *SetNVMeControllerTransportRequest) Reset() {
+	*x = SetNVMeControllerTransportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetNVMeControllerTransportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNVMeControllerTransportRequest) ProtoMessage() {}
+
+func (x *SetNVMeControllerTransportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNVMeControllerTransportRequest.ProtoReflect.Descriptor instead.
+func (*SetNVMeControllerTransportRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetNVMeControllerTransportRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetNVMeControllerTransportRequest) GetTransport() *NVMeControllerTransport {
+	if x != nil {
+		return x.Transport
+	}
+	return nil
+}
+
+type GetNVMeControllerTransportRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe controller
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetNVMeControllerTransportRequest) Reset() {
+	*x = GetNVMeControllerTransportRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNVMeControllerTransportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNVMeControllerTransportRequest) ProtoMessage() {}
+
+func (x *GetNVMeControllerTransportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNVMeControllerTransportRequest.ProtoReflect.Descriptor instead.
+func (*GetNVMeControllerTransportRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetNVMeControllerTransportRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Asymmetric Namespace Access state of the path through an NVMe controller
+// of a subsystem with ANA reporting
+type NVMeControllerAna struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// optimized, non_optimized or inaccessible. SPDK can also report
+	// persistent_loss or change, which cannot be set.
+	AnaState string `protobuf:"bytes,1,opt,name=ana_state,json=anaState,proto3" json:"ana_state,omitempty"`
+}
+
+func (x *NVMeControllerAna) Reset() {
+	*x = NVMeControllerAna{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeControllerAna) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeControllerAna) ProtoMessage() {}
+
+func (x *NVMeControllerAna) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeControllerAna.ProtoReflect.Descriptor instead.
+func (*NVMeControllerAna) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *NVMeControllerAna) GetAnaState() string {
+	if x != nil {
+		return x.AnaState
+	}
+	return ""
+}
+
+type SetNVMeControllerAnaStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe controller
+	Name string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Ana  *NVMeControllerAna `protobuf:"bytes,2,opt,name=ana,proto3" json:"ana,omitempty"`
+}
+
+func (x *SetNVMeControllerAnaStateRequest) Reset() {
+	*x = SetNVMeControllerAnaStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetNVMeControllerAnaStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNVMeControllerAnaStateRequest) ProtoMessage() {}
+
+func (x *SetNVMeControllerAnaStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNVMeControllerAnaStateRequest.ProtoReflect.Descriptor instead.
+func (*SetNVMeControllerAnaStateRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SetNVMeControllerAnaStateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetNVMeControllerAnaStateRequest) GetAna() *NVMeControllerAna {
+	if x != nil {
+		return x.Ana
+	}
+	return nil
+}
+
+type GetNVMeControllerAnaStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe controller
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetNVMeControllerAnaStateRequest) Reset() {
+	*x = GetNVMeControllerAnaStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNVMeControllerAnaStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNVMeControllerAnaStateRequest) ProtoMessage() {}
+
+func (x *GetNVMeControllerAnaStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNVMeControllerAnaStateRequest.ProtoReflect.Descriptor instead.
+func (*GetNVMeControllerAnaStateRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetNVMeControllerAnaStateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Address the SPDK discovery subsystem listens on, so hosts can find the
+// subsystems of the bridge with nvme discover
+type NVMeDiscoveryListener struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name the listener is referenced with
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// tcp or rdma, which needs a registered listener
+	Transport string `protobuf:"bytes,2,opt,name=transport,proto3" json:"transport,omitempty"`
+	// IP address to listen on instead of the address of the listener of the
+	// transport
+	Traddr string `protobuf:"bytes,3,opt,name=traddr,proto3" json:"traddr,omitempty"`
+	// port to listen on instead of the port of the listener of the
+	// transport, 8009 is the well known port
+	Trsvcid string `protobuf:"bytes,4,opt,name=trsvcid,proto3" json:"trsvcid,omitempty"`
+}
+
+func (x *NVMeDiscoveryListener) Reset() {
+	*x = NVMeDiscoveryListener{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeDiscoveryListener) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeDiscoveryListener) ProtoMessage() {}
+
+func (x *NVMeDiscoveryListener) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeDiscoveryListener.ProtoReflect.Descriptor instead.
+func (*NVMeDiscoveryListener) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *NVMeDiscoveryListener) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NVMeDiscoveryListener) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *NVMeDiscoveryListener) GetTraddr() string {
+	if x != nil {
+		return x.Traddr
+	}
+	return ""
+}
+
+func (x *NVMeDiscoveryListener) GetTrsvcid() string {
+	if x != nil {
+		return x.Trsvcid
+	}
+	return ""
+}
+
+type AddNVMeDiscoveryListenerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NvmeDiscoveryListener *NVMeDiscoveryListener `protobuf:"bytes,1,opt,name=nvme_discovery_listener,json=nvmeDiscoveryListener,proto3" json:"nvme_discovery_listener,omitempty"`
+}
+
+func (x *AddNVMeDiscoveryListenerRequest) Reset() {
+	*x = AddNVMeDiscoveryListenerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddNVMeDiscoveryListenerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddNVMeDiscoveryListenerRequest) ProtoMessage() {}
+
+func (x *AddNVMeDiscoveryListenerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddNVMeDiscoveryListenerRequest.ProtoReflect.Descriptor instead.
+func (*AddNVMeDiscoveryListenerRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AddNVMeDiscoveryListenerRequest) GetNvmeDiscoveryListener() *NVMeDiscoveryListener {
+	if x != nil {
+		return x.NvmeDiscoveryListener
+	}
+	return nil
+}
+
+type DeleteNVMeDiscoveryListenerRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name of the listener
+	Name         string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	AllowMissing bool   `protobuf:"varint,2,opt,name=allow_missing,json=allowMissing,proto3" json:"allow_missing,omitempty"`
+}
+
+func (x *DeleteNVMeDiscoveryListenerRequest) Reset() {
+	*x = DeleteNVMeDiscoveryListenerRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteNVMeDiscoveryListenerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteNVMeDiscoveryListenerRequest) ProtoMessage() {}
+
+func (x *DeleteNVMeDiscoveryListenerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteNVMeDiscoveryListenerRequest.ProtoReflect.Descriptor instead.
+func (*DeleteNVMeDiscoveryListenerRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteNVMeDiscoveryListenerRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DeleteNVMeDiscoveryListenerRequest) GetAllowMissing() bool {
+	if x != nil {
+		return x.AllowMissing
+	}
+	return false
+}
+
+type ListNVMeDiscoveryListenersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListNVMeDiscoveryListenersRequest) Reset() {
+	*x = ListNVMeDiscoveryListenersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListNVMeDiscoveryListenersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNVMeDiscoveryListenersRequest) ProtoMessage() {}
+
+func (x *ListNVMeDiscoveryListenersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNVMeDiscoveryListenersRequest.ProtoReflect.Descriptor instead.
+func (*ListNVMeDiscoveryListenersRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{13}
+}
+
+type ListNVMeDiscoveryListenersResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// listeners sorted by name
+	NvmeDiscoveryListeners []*NVMeDiscoveryListener `protobuf:"bytes,1,rep,name=nvme_discovery_listeners,json=nvmeDiscoveryListeners,proto3" json:"nvme_discovery_listeners,omitempty"`
+}
+
+func (x *ListNVMeDiscoveryListenersResponse) Reset() {
+	*x = ListNVMeDiscoveryListenersResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListNVMeDiscoveryListenersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNVMeDiscoveryListenersResponse) ProtoMessage() {}
+
+func (x *ListNVMeDiscoveryListenersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNVMeDiscoveryListenersResponse.ProtoReflect.Descriptor instead.
+func (*ListNVMeDiscoveryListenersResponse) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListNVMeDiscoveryListenersResponse) GetNvmeDiscoveryListeners() []*NVMeDiscoveryListener {
+	if x != nil {
+		return x.NvmeDiscoveryListeners
+	}
+	return nil
+}
+
+// Referral in the discovery log page pointing hosts to another discovery
+// service or subsystem, e.g. of another DPU
+type NVMeDiscoveryReferral struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name the referral is referenced with
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// tcp or rdma
+	Transport string `protobuf:"bytes,2,opt,name=transport,proto3" json:"transport,omitempty"`
+	// IP address of the referred discovery service
+	Traddr string `protobuf:"bytes,3,opt,name=traddr,proto3" json:"traddr,omitempty"`
+	// port of the referred discovery service
+	Trsvcid string `protobuf:"bytes,4,opt,name=trsvcid,proto3" json:"trsvcid,omitempty"`
+	// NQN of the referred subsystem, the discovery NQN if empty
+	Subnqn string `protobuf:"bytes,5,opt,name=subnqn,proto3" json:"subnqn,omitempty"`
+	// requires hosts to connect to the referral with TLS
+	SecureChannel bool `protobuf:"varint,6,opt,name=secure_channel,json=secureChannel,proto3" json:"secure_channel,omitempty"`
+}
+
+func (x *NVMeDiscoveryReferral) Reset() {
+	*x = NVMeDiscoveryReferral{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeDiscoveryReferral) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeDiscoveryReferral) ProtoMessage() {}
+
+func (x *NVMeDiscoveryReferral) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeDiscoveryReferral.ProtoReflect.Descriptor instead.
+func (*NVMeDiscoveryReferral) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *NVMeDiscoveryReferral) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NVMeDiscoveryReferral) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *NVMeDiscoveryReferral) GetTraddr() string {
+	if x != nil {
+		return x.Traddr
+	}
+	return ""
+}
+
+func (x *NVMeDiscoveryReferral) GetTrsvcid() string {
+	if x != nil {
+		return x.Trsvcid
+	}
+	return ""
+}
+
+func (x *NVMeDiscoveryReferral) GetSubnqn() string {
+	if x != nil {
+		return x.Subnqn
+	}
+	return ""
+}
+
+func (x *NVMeDiscoveryReferral) GetSecureChannel() bool {
+	if x != nil {
+		return x.SecureChannel
+	}
+	return false
+}
+
+type AddNVMeDiscoveryReferralRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NvmeDiscoveryReferral *NVMeDiscoveryReferral `protobuf:"bytes,1,opt,name=nvme_discovery_referral,json=nvmeDiscoveryReferral,proto3" json:"nvme_discovery_referral,omitempty"`
+}
+
+func (x *AddNVMeDiscoveryReferralRequest) Reset() {
+	*x = AddNVMeDiscoveryReferralRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddNVMeDiscoveryReferralRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddNVMeDiscoveryReferralRequest) ProtoMessage() {}
+
+func (x *AddNVMeDiscoveryReferralRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddNVMeDiscoveryReferralRequest.ProtoReflect.Descriptor instead.
+func (*AddNVMeDiscoveryReferralRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AddNVMeDiscoveryReferralRequest) GetNvmeDiscoveryReferral() *NVMeDiscoveryReferral {
+	if x != nil {
+		return x.NvmeDiscoveryReferral
+	}
+	return nil
+}
+
+type DeleteNVMeDiscoveryReferralRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name of the referral
+	Name         string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	AllowMissing bool   `protobuf:"varint,2,opt,name=allow_missing,json=allowMissing,proto3" json:"allow_missing,omitempty"`
+}
+
+func (x *DeleteNVMeDiscoveryReferralRequest) Reset() {
+	*x = DeleteNVMeDiscoveryReferralRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteNVMeDiscoveryReferralRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteNVMeDiscoveryReferralRequest) ProtoMessage() {}
+
+func (x *DeleteNVMeDiscoveryReferralRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteNVMeDiscoveryReferralRequest.ProtoReflect.Descriptor instead.
+func (*DeleteNVMeDiscoveryReferralRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeleteNVMeDiscoveryReferralRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DeleteNVMeDiscoveryReferralRequest) GetAllowMissing() bool {
+	if x != nil {
+		return x.AllowMissing
+	}
+	return false
+}
+
+type ListNVMeDiscoveryReferralsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListNVMeDiscoveryReferralsRequest) Reset() {
+	*x = ListNVMeDiscoveryReferralsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListNVMeDiscoveryReferralsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNVMeDiscoveryReferralsRequest) ProtoMessage() {}
+
+func (x *ListNVMeDiscoveryReferralsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNVMeDiscoveryReferralsRequest.ProtoReflect.Descriptor instead.
+func (*ListNVMeDiscoveryReferralsRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{18}
+}
+
+type ListNVMeDiscoveryReferralsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// referrals sorted by name
+	NvmeDiscoveryReferrals []*NVMeDiscoveryReferral `protobuf:"bytes,1,rep,name=nvme_discovery_referrals,json=nvmeDiscoveryReferrals,proto3" json:"nvme_discovery_referrals,omitempty"`
+}
+
+func (x *ListNVMeDiscoveryReferralsResponse) Reset() {
+	*x = ListNVMeDiscoveryReferralsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListNVMeDiscoveryReferralsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNVMeDiscoveryReferralsResponse) ProtoMessage() {}
+
+func (x *ListNVMeDiscoveryReferralsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNVMeDiscoveryReferralsResponse.ProtoReflect.Descriptor instead.
+func (*ListNVMeDiscoveryReferralsResponse) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListNVMeDiscoveryReferralsResponse) GetNvmeDiscoveryReferrals() []*NVMeDiscoveryReferral {
+	if x != nil {
+		return x.NvmeDiscoveryReferrals
+	}
+	return nil
+}
+
+// Persistent reservation configuration of an NVMe namespace. SPDK
+// namespaces always support reservations, they are kept only in memory
+// unless ptpl_file is set.
+type NVMeNamespaceReservation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// absolute path of the file SPDK persists reservations of the namespace
+	// in, so they survive power loss and restarts of SPDK
+	PtplFile string `protobuf:"bytes,1,opt,name=ptpl_file,json=ptplFile,proto3" json:"ptpl_file,omitempty"`
+}
+
+func (x *NVMeNamespaceReservation) Reset() {
+	*x = NVMeNamespaceReservation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeNamespaceReservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeNamespaceReservation) ProtoMessage() {}
+
+func (x *NVMeNamespaceReservation) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeNamespaceReservation.ProtoReflect.Descriptor instead.
+func (*NVMeNamespaceReservation) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *NVMeNamespaceReservation) GetPtplFile() string {
+	if x != nil {
+		return x.PtplFile
+	}
+	return ""
+}
+
+type SetNVMeNamespaceReservationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe namespace, the configuration takes effect when the
+	// namespace is created or updated
+	Name        string                    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Reservation *NVMeNamespaceReservation `protobuf:"bytes,2,opt,name=reservation,proto3" json:"reservation,omitempty"`
+}
+
+func (x *SetNVMeNamespaceReservationRequest) Reset() {
+	*x = SetNVMeNamespaceReservationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetNVMeNamespaceReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNVMeNamespaceReservationRequest) ProtoMessage() {}
+
+func (x *SetNVMeNamespaceReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNVMeNamespaceReservationRequest.ProtoReflect.Descriptor instead.
+func (*SetNVMeNamespaceReservationRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetNVMeNamespaceReservationRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetNVMeNamespaceReservationRequest) GetReservation() *NVMeNamespaceReservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type GetNVMeNamespaceReservationStateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe namespace, which must have a ptpl_file
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetNVMeNamespaceReservationStateRequest) Reset() {
+	*x = GetNVMeNamespaceReservationStateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNVMeNamespaceReservationStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNVMeNamespaceReservationStateRequest) ProtoMessage() {}
+
+func (x *GetNVMeNamespaceReservationStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNVMeNamespaceReservationStateRequest.ProtoReflect.Descriptor instead.
+func (*GetNVMeNamespaceReservationStateRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetNVMeNamespaceReservationStateRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Reservation of an NVMe namespace
+type NVMeReservationState struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// type of the reservation, e.g. write_exclusive, empty if the namespace
+	// is not reserved
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// host identifier of the reservation holder
+	HolderHostId string `protobuf:"bytes,2,opt,name=holder_host_id,json=holderHostId,proto3" json:"holder_host_id,omitempty"`
+	// hosts registered with a reservation key
+	Registrants []*NVMeReservationRegistrant `protobuf:"bytes,3,rep,name=registrants,proto3" json:"registrants,omitempty"`
+}
+
+func (x *NVMeReservationState) Reset() {
+	*x = NVMeReservationState{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeReservationState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeReservationState) ProtoMessage() {}
+
+func (x *NVMeReservationState) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeReservationState.ProtoReflect.Descriptor instead.
+func (*NVMeReservationState) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *NVMeReservationState) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *NVMeReservationState) GetHolderHostId() string {
+	if x != nil {
+		return x.HolderHostId
+	}
+	return ""
+}
+
+func (x *NVMeReservationState) GetRegistrants() []*NVMeReservationRegistrant {
+	if x != nil {
+		return x.Registrants
+	}
+	return nil
+}
+
+// Host registered for reservations of a namespace
+type NVMeReservationRegistrant struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// host identifier of the registrant
+	HostId string `protobuf:"bytes,1,opt,name=host_id,json=hostId,proto3" json:"host_id,omitempty"`
+	// reservation key the host registered with
+	Rkey uint64 `protobuf:"varint,2,opt,name=rkey,proto3" json:"rkey,omitempty"`
+}
+
+func (x *NVMeReservationRegistrant) Reset() {
+	*x = NVMeReservationRegistrant{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeReservationRegistrant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeReservationRegistrant) ProtoMessage() {}
+
+func (x *NVMeReservationRegistrant) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeReservationRegistrant.ProtoReflect.Descriptor instead.
+func (*NVMeReservationRegistrant) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *NVMeReservationRegistrant) GetHostId() string {
+	if x != nil {
+		return x.HostId
+	}
+	return ""
+}
+
+func (x *NVMeReservationRegistrant) GetRkey() uint64 {
+	if x != nil {
+		return x.Rkey
+	}
+	return 0
+}
+
+// Hosts an NVMe namespace is attached to, so tenants sharing a subsystem see
+// only their namespaces
+type NVMeNamespaceVisibility struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// makes the namespace visible only to hosts instead of every host
+	// connected to the subsystem, it can be chosen only before the namespace
+	// is created
+	Hidden bool `protobuf:"varint,1,opt,name=hidden,proto3" json:"hidden,omitempty"`
+	// NQNs of the hosts a hidden namespace is visible to
+	Hosts []string `protobuf:"bytes,2,rep,name=hosts,proto3" json:"hosts,omitempty"`
+}
+
+func (x *NVMeNamespaceVisibility) Reset() {
+	*x = NVMeNamespaceVisibility{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMeNamespaceVisibility) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMeNamespaceVisibility) ProtoMessage() {}
+
+func (x *NVMeNamespaceVisibility) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMeNamespaceVisibility.ProtoReflect.Descriptor instead.
+func (*NVMeNamespaceVisibility) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *NVMeNamespaceVisibility) GetHidden() bool {
+	if x != nil {
+		return x.Hidden
+	}
+	return false
+}
+
+func (x *NVMeNamespaceVisibility) GetHosts() []string {
+	if x != nil {
+		return x.Hosts
+	}
+	return nil
+}
+
+type SetNVMeNamespaceVisibilityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe namespace, hosts of a created hidden namespace are
+	// changed right away
+	Name       string                   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Visibility *NVMeNamespaceVisibility `protobuf:"bytes,2,opt,name=visibility,proto3" json:"visibility,omitempty"`
+}
+
+func (x *SetNVMeNamespaceVisibilityRequest) Reset() {
+	*x = SetNVMeNamespaceVisibilityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetNVMeNamespaceVisibilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNVMeNamespaceVisibilityRequest) ProtoMessage() {}
+
+func (x *SetNVMeNamespaceVisibilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNVMeNamespaceVisibilityRequest.ProtoReflect.Descriptor instead.
+func (*SetNVMeNamespaceVisibilityRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SetNVMeNamespaceVisibilityRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetNVMeNamespaceVisibilityRequest) GetVisibility() *NVMeNamespaceVisibility {
+	if x != nil {
+		return x.Visibility
+	}
+	return nil
+}
+
+type GetNVMeNamespaceVisibilityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe namespace
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetNVMeNamespaceVisibilityRequest) Reset() {
+	*x = GetNVMeNamespaceVisibilityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNVMeNamespaceVisibilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNVMeNamespaceVisibilityRequest) ProtoMessage() {}
+
+func (x *GetNVMeNamespaceVisibilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNVMeNamespaceVisibilityRequest.ProtoReflect.Descriptor instead.
+func (*GetNVMeNamespaceVisibilityRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetNVMeNamespaceVisibilityRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Current size of the volume exposed by a frontend object
+type VolumeCapacity struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlockSize   int64 `protobuf:"varint,1,opt,name=block_size,json=blockSize,proto3" json:"block_size,omitempty"`
+	BlocksCount int64 `protobuf:"varint,2,opt,name=blocks_count,json=blocksCount,proto3" json:"blocks_count,omitempty"`
+}
+
+func (x *VolumeCapacity) Reset() {
+	*x = VolumeCapacity{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VolumeCapacity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VolumeCapacity) ProtoMessage() {}
+
+func (x *VolumeCapacity) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VolumeCapacity.ProtoReflect.Descriptor instead.
+func (*VolumeCapacity) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *VolumeCapacity) GetBlockSize() int64 {
+	if x != nil {
+		return x.BlockSize
+	}
+	return 0
+}
+
+func (x *VolumeCapacity) GetBlocksCount() int64 {
+	if x != nil {
+		return x.BlocksCount
+	}
+	return 0
+}
+
+type GetNVMeNamespaceCapacityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMe namespace
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetNVMeNamespaceCapacityRequest) Reset() {
+	*x = GetNVMeNamespaceCapacityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetNVMeNamespaceCapacityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNVMeNamespaceCapacityRequest) ProtoMessage() {}
+
+func (x *GetNVMeNamespaceCapacityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNVMeNamespaceCapacityRequest.ProtoReflect.Descriptor instead.
+func (*GetNVMeNamespaceCapacityRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetNVMeNamespaceCapacityRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetVirtioBlkCapacityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the virtio-blk controller
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetVirtioBlkCapacityRequest) Reset() {
+	*x = GetVirtioBlkCapacityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetVirtioBlkCapacityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVirtioBlkCapacityRequest) ProtoMessage() {}
+
+func (x *GetVirtioBlkCapacityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVirtioBlkCapacityRequest.ProtoReflect.Descriptor instead.
+func (*GetVirtioBlkCapacityRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetVirtioBlkCapacityRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type GetVirtioScsiLunCapacityRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the virtio-scsi LUN
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetVirtioScsiLunCapacityRequest) Reset() {
+	*x = GetVirtioScsiLunCapacityRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetVirtioScsiLunCapacityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVirtioScsiLunCapacityRequest) ProtoMessage() {}
+
+func (x *GetVirtioScsiLunCapacityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVirtioScsiLunCapacityRequest.ProtoReflect.Descriptor instead.
+func (*GetVirtioScsiLunCapacityRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *GetVirtioScsiLunCapacityRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Options of the vhost controller of a virtio-blk device
+type VirtioBlkOptions struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// hexadecimal mask of the SPDK reactors the controller can be scheduled
+	// on, all reactors if empty. It has to be a subset of the SPDK core mask.
+	Cpumask string `protobuf:"bytes,1,opt,name=cpumask,proto3" json:"cpumask,omitempty"`
+	// number of request queues of the guest device, 0 keeps the default of
+	// QEMU
+	NumQueues int32 `protobuf:"varint,2,opt,name=num_queues,json=numQueues,proto3" json:"num_queues,omitempty"`
+	// makes the device reject writes
+	Readonly bool `protobuf:"varint,3,opt,name=readonly,proto3" json:"readonly,omitempty"`
+	// offers packed virtqueues to the driver
+	PackedRing bool `protobuf:"varint,4,opt,name=packed_ring,json=packedRing,proto3" json:"packed_ring,omitempty"`
+}
+
+func (x *VirtioBlkOptions) Reset() {
+	*x = VirtioBlkOptions{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VirtioBlkOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VirtioBlkOptions) ProtoMessage() {}
+
+func (x *VirtioBlkOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VirtioBlkOptions.ProtoReflect.Descriptor instead.
+func (*VirtioBlkOptions) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *VirtioBlkOptions) GetCpumask() string {
+	if x != nil {
+		return x.Cpumask
+	}
+	return ""
+}
+
+func (x *VirtioBlkOptions) GetNumQueues() int32 {
+	if x != nil {
+		return x.NumQueues
+	}
+	return 0
+}
+
+func (x *VirtioBlkOptions) GetReadonly() bool {
+	if x != nil {
+		return x.Readonly
+	}
+	return false
+}
+
+func (x *VirtioBlkOptions) GetPackedRing() bool {
+	if x != nil {
+		return x.PackedRing
+	}
+	return false
+}
+
+type SetVirtioBlkOptionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the virtio-blk device, the options take effect when the device
+	// is created or updated
+	Name    string            `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Options *VirtioBlkOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+}
+
+func (x *SetVirtioBlkOptionsRequest) Reset() {
+	*x = SetVirtioBlkOptionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetVirtioBlkOptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetVirtioBlkOptionsRequest) ProtoMessage() {}
+
+func (x *SetVirtioBlkOptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetVirtioBlkOptionsRequest.ProtoReflect.Descriptor instead.
+func (*SetVirtioBlkOptionsRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SetVirtioBlkOptionsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetVirtioBlkOptionsRequest) GetOptions() *VirtioBlkOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+type GetVirtioBlkOptionsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the virtio-blk device
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *GetVirtioBlkOptionsRequest) Reset() {
+	*x = GetVirtioBlkOptionsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetVirtioBlkOptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVirtioBlkOptionsRequest) ProtoMessage() {}
+
+func (x *GetVirtioBlkOptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVirtioBlkOptionsRequest.ProtoReflect.Descriptor instead.
+func (*GetVirtioBlkOptionsRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetVirtioBlkOptionsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Credentials an NVMf remote controller connects with
+type NVMfRemoteControllerAuth struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name of the keyring key with the TLS pre-shared key used to secure
+	// the connection of NVMe/TCP remote controllers
+	Psk string `protobuf:"bytes,1,opt,name=psk,proto3" json:"psk,omitempty"`
+	// name of the keyring key the host authenticates with using DH-HMAC-CHAP
+	DhchapKey string `protobuf:"bytes,2,opt,name=dhchap_key,json=dhchapKey,proto3" json:"dhchap_key,omitempty"`
+	// name of the keyring key the remote controller must authenticate with
+	DhchapCtrlrKey string `protobuf:"bytes,3,opt,name=dhchap_ctrlr_key,json=dhchapCtrlrKey,proto3" json:"dhchap_ctrlr_key,omitempty"`
+}
+
+func (x *NVMfRemoteControllerAuth) Reset() {
+	*x = NVMfRemoteControllerAuth{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NVMfRemoteControllerAuth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NVMfRemoteControllerAuth) ProtoMessage() {}
+
+func (x *NVMfRemoteControllerAuth) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NVMfRemoteControllerAuth.ProtoReflect.Descriptor instead.
+func (*NVMfRemoteControllerAuth) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *NVMfRemoteControllerAuth) GetPsk() string {
+	if x != nil {
+		return x.Psk
+	}
+	return ""
+}
+
+func (x *NVMfRemoteControllerAuth) GetDhchapKey() string {
+	if x != nil {
+		return x.DhchapKey
+	}
+	return ""
+}
+
+func (x *NVMfRemoteControllerAuth) GetDhchapCtrlrKey() string {
+	if x != nil {
+		return x.DhchapCtrlrKey
+	}
+	return ""
+}
+
+type SetNVMfRemoteControllerAuthRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// ID of the NVMf remote controller, which is not created yet
+	Name string                    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Auth *NVMfRemoteControllerAuth `protobuf:"bytes,2,opt,name=auth,proto3" json:"auth,omitempty"`
+}
+
+func (x *SetNVMfRemoteControllerAuthRequest) Reset() {
+	*x = SetNVMfRemoteControllerAuthRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetNVMfRemoteControllerAuthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNVMfRemoteControllerAuthRequest) ProtoMessage() {}
+
+func (x *SetNVMfRemoteControllerAuthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNVMfRemoteControllerAuthRequest.ProtoReflect.Descriptor instead.
+func (*SetNVMfRemoteControllerAuthRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SetNVMfRemoteControllerAuthRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SetNVMfRemoteControllerAuthRequest) GetAuth() *NVMfRemoteControllerAuth {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+// Key in the SPDK keyring read from a file, so the key material never
+// passes through the bridge
+type KeyringKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name the key is referenced with
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// absolute path of the file with the key, which must only be accessible
+	// by the owner of the SPDK process
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+}
+
+func (x *KeyringKey) Reset() {
+	*x = KeyringKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KeyringKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyringKey) ProtoMessage() {}
+
+func (x *KeyringKey) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyringKey.ProtoReflect.Descriptor instead.
+func (*KeyringKey) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *KeyringKey) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *KeyringKey) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+type AddKeyringKeyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	KeyringKey *KeyringKey `protobuf:"bytes,1,opt,name=keyring_key,json=keyringKey,proto3" json:"keyring_key,omitempty"`
+}
+
+func (x *AddKeyringKeyRequest) Reset() {
+	*x = AddKeyringKeyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddKeyringKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddKeyringKeyRequest) ProtoMessage() {}
+
+func (x *AddKeyringKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddKeyringKeyRequest.ProtoReflect.Descriptor instead.
+func (*AddKeyringKeyRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *AddKeyringKeyRequest) GetKeyringKey() *KeyringKey {
+	if x != nil {
+		return x.KeyringKey
+	}
+	return nil
+}
+
+type DeleteKeyringKeyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// name of the key
+	Name         string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	AllowMissing bool   `protobuf:"varint,2,opt,name=allow_missing,json=allowMissing,proto3" json:"allow_missing,omitempty"`
+}
+
+func (x *DeleteKeyringKeyRequest) Reset() {
+	*x = DeleteKeyringKeyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteKeyringKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteKeyringKeyRequest) ProtoMessage() {}
+
+func (x *DeleteKeyringKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteKeyringKeyRequest.ProtoReflect.Descriptor instead.
+func (*DeleteKeyringKeyRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *DeleteKeyringKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DeleteKeyringKeyRequest) GetAllowMissing() bool {
+	if x != nil {
+		return x.AllowMissing
+	}
+	return false
+}
+
+type ListKeyringKeysRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListKeyringKeysRequest) Reset() {
+	*x = ListKeyringKeysRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListKeyringKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKeyringKeysRequest) ProtoMessage() {}
+
+func (x *ListKeyringKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKeyringKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListKeyringKeysRequest) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{40}
+}
+
+type ListKeyringKeysResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// keys sorted by name
+	KeyringKeys []*KeyringKey `protobuf:"bytes,1,rep,name=keyring_keys,json=keyringKeys,proto3" json:"keyring_keys,omitempty"`
+}
+
+func (x *ListKeyringKeysResponse) Reset() {
+	*x = ListKeyringKeysResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_extension_proto_msgTypes[41]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListKeyringKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKeyringKeysResponse) ProtoMessage() {}
+
+func (x *ListKeyringKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_extension_proto_msgTypes[41]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKeyringKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListKeyringKeysResponse) Descriptor() ([]byte, []int) {
+	return file_extension_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListKeyringKeysResponse) GetKeyringKeys() []*KeyringKey {
+	if x != nil {
+		return x.KeyringKeys
+	}
+	return nil
+}
+
+var File_extension_proto protoreflect.FileDescriptor
+
+var file_extension_proto_rawDesc = []byte{
+	0x0a, 0x0f, 0x65, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x18, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x1a, 0x1b, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70,
+	0x74, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x5f,
+	0x6d, 0x61, 0x73, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x77, 0x0a, 0x08, 0x4e, 0x56,
+	0x4d, 0x65, 0x48, 0x6f, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6e, 0x71, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6e, 0x71, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x73, 0x6b, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x70, 0x73, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x68,
+	0x63, 0x68, 0x61, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x64, 0x68, 0x63, 0x68, 0x61, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x10, 0x64, 0x68, 0x63,
+	0x68, 0x61, 0x70, 0x5f, 0x63, 0x74, 0x72, 0x6c, 0x72, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x68, 0x63, 0x68, 0x61, 0x70, 0x43, 0x74, 0x72, 0x6c, 0x72,
+	0x4b, 0x65, 0x79, 0x22, 0x74, 0x0a, 0x12, 0x4e, 0x56, 0x4d, 0x65, 0x53, 0x75, 0x62, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x5f, 0x61, 0x6e, 0x79, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x41, 0x6e, 0x79, 0x48, 0x6f, 0x73, 0x74, 0x12,
+	0x38, 0x0a, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22,
+	0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x48, 0x6f,
+	0x73, 0x74, 0x52, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x22, 0xb6, 0x01, 0x0a, 0x1f, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x4e, 0x56, 0x4d, 0x65, 0x53, 0x75, 0x62, 0x73, 0x79, 0x73, 0x74, 0x65,
+	0x6d, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x42, 0x0a, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x2c, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65,
+	0x53, 0x75, 0x62, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x05,
+	0x68, 0x6f, 0x73, 0x74, 0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x5f,
+	0x6d, 0x61, 0x73, 0x6b, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x4d, 0x61, 0x73, 0x6b, 0x52, 0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61,
+	0x73, 0x6b, 0x22, 0x32, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x53, 0x75, 0x62,
+	0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x69, 0x0a, 0x17, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12,
+	0x16, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x64, 0x64, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x74, 0x72, 0x61, 0x64, 0x64, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x72, 0x73, 0x76, 0x63,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x72, 0x73, 0x76, 0x63, 0x69,
+	0x64, 0x22, 0x88, 0x01, 0x0a, 0x21, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x4f, 0x0a, 0x09, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31,
+	0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x37, 0x0a, 0x21,
+	0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65,
+	0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x30, 0x0a, 0x11, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41, 0x6e, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x6e,
+	0x61, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x61,
+	0x6e, 0x61, 0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x75, 0x0a, 0x20, 0x53, 0x65, 0x74, 0x4e, 0x56,
+	0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41, 0x6e, 0x61, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x3d, 0x0a, 0x03, 0x61, 0x6e, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x6f,
+	0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41, 0x6e, 0x61, 0x52, 0x03, 0x61, 0x6e, 0x61, 0x22, 0x36,
+	0x0a, 0x20, 0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x6c, 0x65, 0x72, 0x41, 0x6e, 0x61, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x7b, 0x0a, 0x15, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69,
+	0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x64, 0x64, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x74, 0x72, 0x61, 0x64, 0x64, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x72, 0x73,
+	0x76, 0x63, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x72, 0x73, 0x76,
+	0x63, 0x69, 0x64, 0x22, 0x8a, 0x01, 0x0a, 0x1f, 0x41, 0x64, 0x64, 0x4e, 0x56, 0x4d, 0x65, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x67, 0x0a, 0x17, 0x6e, 0x76, 0x6d, 0x65, 0x5f,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73,
+	0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72,
+	0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x52, 0x15, 0x6e, 0x76, 0x6d, 0x65, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x22, 0x5d, 0x0a, 0x22, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69,
+	0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x22,
+	0x23, 0x0a, 0x21, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f,
+	0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x8f, 0x01, 0x0a, 0x22, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x56, 0x4d,
+	0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x69, 0x0a, 0x18, 0x6e,
+	0x76, 0x6d, 0x65, 0x5f, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x6c, 0x69,
+	0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e,
+	0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x52, 0x16,
+	0x6e, 0x76, 0x6d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73,
+	0x74, 0x65, 0x6e, 0x65, 0x72, 0x73, 0x22, 0xba, 0x01, 0x0a, 0x15, 0x4e, 0x56, 0x4d, 0x65, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f,
+	0x72, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x72, 0x61, 0x64, 0x64, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x74, 0x72, 0x61, 0x64, 0x64, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x72,
+	0x73, 0x76, 0x63, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x74, 0x72, 0x73,
+	0x76, 0x63, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x71, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x75, 0x62, 0x6e, 0x71, 0x6e, 0x12, 0x25, 0x0a, 0x0e,
+	0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x22, 0x8a, 0x01, 0x0a, 0x1f, 0x41, 0x64, 0x64, 0x4e, 0x56, 0x4d, 0x65, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x67, 0x0a, 0x17, 0x6e, 0x76, 0x6d, 0x65, 0x5f,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72,
+	0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73,
+	0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72,
+	0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x52, 0x15, 0x6e, 0x76, 0x6d, 0x65, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c,
+	0x22, 0x5d, 0x0a, 0x22, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69,
+	0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x22,
+	0x23, 0x0a, 0x21, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f,
+	0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x22, 0x8f, 0x01, 0x0a, 0x22, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x56, 0x4d,
+	0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72,
+	0x61, 0x6c, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x69, 0x0a, 0x18, 0x6e,
+	0x76, 0x6d, 0x65, 0x5f, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x72, 0x65,
+	0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e,
+	0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x52, 0x16,
+	0x6e, 0x76, 0x6d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66,
+	0x65, 0x72, 0x72, 0x61, 0x6c, 0x73, 0x22, 0x37, 0x0a, 0x18, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x74, 0x70, 0x6c, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x74, 0x70, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x22,
+	0x8e, 0x01, 0x0a, 0x22, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x54, 0x0a, 0x0b, 0x72, 0x65,
+	0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x32, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67,
+	0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x22, 0x3d, 0x0a, 0x27, 0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22,
+	0xa7, 0x01, 0x0a, 0x14, 0x4e, 0x56, 0x4d, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x24, 0x0a, 0x0e,
+	0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x68, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x48, 0x6f, 0x73, 0x74,
+	0x49, 0x64, 0x12, 0x55, 0x0a, 0x0b, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x6e, 0x74,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70,
+	0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x6e, 0x74, 0x52, 0x0b, 0x72, 0x65,
+	0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x6e, 0x74, 0x73, 0x22, 0x48, 0x0a, 0x19, 0x4e, 0x56, 0x4d,
+	0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x67, 0x69,
+	0x73, 0x74, 0x72, 0x61, 0x6e, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x68, 0x6f, 0x73, 0x74, 0x49, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x72, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x72,
+	0x6b, 0x65, 0x79, 0x22, 0x47, 0x0a, 0x17, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x56, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x68, 0x69, 0x64, 0x64, 0x65, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x05, 0x68, 0x6f, 0x73, 0x74, 0x73, 0x22, 0x8a, 0x01, 0x0a,
+	0x21, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x56, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x51, 0x0a, 0x0a, 0x76, 0x69, 0x73, 0x69, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x6f, 0x70, 0x69,
+	0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x56, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x0a, 0x76,
+	0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x22, 0x37, 0x0a, 0x21, 0x47, 0x65, 0x74,
+	0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x56, 0x69, 0x73,
+	0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x22, 0x52, 0x0a, 0x0e, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x43, 0x61, 0x70, 0x61,
+	0x63, 0x69, 0x74, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x73, 0x69,
+	0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x73, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x62, 0x6c, 0x6f, 0x63, 0x6b,
+	0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x35, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d,
+	0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69,
+	0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x31, 0x0a,
+	0x1b, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x43, 0x61, 0x70,
+	0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x22, 0x35, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x53, 0x63, 0x73,
+	0x69, 0x4c, 0x75, 0x6e, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x88, 0x01, 0x0a, 0x10, 0x56, 0x69, 0x72, 0x74,
+	0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x18, 0x0a, 0x07,
+	0x63, 0x70, 0x75, 0x6d, 0x61, 0x73, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63,
+	0x70, 0x75, 0x6d, 0x61, 0x73, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x75, 0x6d, 0x5f, 0x71, 0x75,
+	0x65, 0x75, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6e, 0x75, 0x6d, 0x51,
+	0x75, 0x65, 0x75, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x61, 0x64, 0x6f, 0x6e, 0x6c,
+	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x61, 0x64, 0x6f, 0x6e, 0x6c,
+	0x79, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x72, 0x69, 0x6e, 0x67,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x70, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x52, 0x69,
+	0x6e, 0x67, 0x22, 0x76, 0x0a, 0x1a, 0x53, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42,
+	0x6c, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x44, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b,
+	0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x30, 0x0a, 0x1a, 0x47, 0x65,
+	0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x75, 0x0a, 0x18,
+	0x4e, 0x56, 0x4d, 0x66, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x6c, 0x65, 0x72, 0x41, 0x75, 0x74, 0x68, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x73, 0x6b, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x70, 0x73, 0x6b, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x68,
+	0x63, 0x68, 0x61, 0x70, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x64, 0x68, 0x63, 0x68, 0x61, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x10, 0x64, 0x68, 0x63,
+	0x68, 0x61, 0x70, 0x5f, 0x63, 0x74, 0x72, 0x6c, 0x72, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x64, 0x68, 0x63, 0x68, 0x61, 0x70, 0x43, 0x74, 0x72, 0x6c, 0x72,
+	0x4b, 0x65, 0x79, 0x22, 0x80, 0x01, 0x0a, 0x22, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x66, 0x52,
+	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41,
+	0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x46,
+	0x0a, 0x04, 0x61, 0x75, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x6f,
+	0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x66, 0x52, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41, 0x75, 0x74, 0x68,
+	0x52, 0x04, 0x61, 0x75, 0x74, 0x68, 0x22, 0x34, 0x0a, 0x0a, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e,
+	0x67, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x22, 0x5d, 0x0a, 0x14,
+	0x41, 0x64, 0x64, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x45, 0x0a, 0x0b, 0x6b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x6f, 0x70, 0x69, 0x5f,
+	0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x52,
+	0x0a, 0x6b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x22, 0x52, 0x0a, 0x17, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x6c,
+	0x6c, 0x6f, 0x77, 0x5f, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6e, 0x67, 0x22,
+	0x18, 0x0a, 0x16, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65,
+	0x79, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x62, 0x0a, 0x17, 0x4c, 0x69, 0x73,
+	0x74, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x0c, 0x6b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x5f,
+	0x6b, 0x65, 0x79, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x6f, 0x70, 0x69,
+	0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79,
+	0x52, 0x0b, 0x6b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x73, 0x32, 0xac, 0x1a,
+	0x0a, 0x10, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x12, 0x85, 0x01, 0x0a, 0x18, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x56, 0x4d,
+	0x65, 0x53, 0x75, 0x62, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x12,
+	0x39, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67,
+	0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x4e, 0x56, 0x4d, 0x65, 0x53, 0x75, 0x62, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x48, 0x6f,
+	0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x6f, 0x70, 0x69,
+	0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x53, 0x75, 0x62, 0x73, 0x79, 0x73,
+	0x74, 0x65, 0x6d, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x22, 0x00, 0x12, 0x7f, 0x0a, 0x15, 0x47, 0x65,
+	0x74, 0x4e, 0x56, 0x4d, 0x65, 0x53, 0x75, 0x62, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x48, 0x6f,
+	0x73, 0x74, 0x73, 0x12, 0x36, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62,
+	0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x47,
+	0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x53, 0x75, 0x62, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x48,
+	0x6f, 0x73, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x6f, 0x70,
+	0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x53, 0x75, 0x62, 0x73, 0x79,
+	0x73, 0x74, 0x65, 0x6d, 0x48, 0x6f, 0x73, 0x74, 0x73, 0x22, 0x00, 0x12, 0x8e, 0x01, 0x0a, 0x1a,
+	0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65,
+	0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x3b, 0x2e, 0x6f, 0x70, 0x69,
+	0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70,
+	0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65,
+	0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x00, 0x12, 0x8e, 0x01, 0x0a,
+	0x1a, 0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c,
+	0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x12, 0x3b, 0x2e, 0x6f, 0x70,
+	0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72,
+	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73,
+	0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c,
+	0x65, 0x72, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x22, 0x00, 0x12, 0x86, 0x01,
+	0x0a, 0x19, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x6c, 0x65, 0x72, 0x41, 0x6e, 0x61, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x3a, 0x2e, 0x6f, 0x70,
+	0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41, 0x6e, 0x61, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70,
+	0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65,
+	0x72, 0x41, 0x6e, 0x61, 0x22, 0x00, 0x12, 0x86, 0x01, 0x0a, 0x19, 0x47, 0x65, 0x74, 0x4e, 0x56,
+	0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41, 0x6e, 0x61, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x3a, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f,
+	0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65,
+	0x72, 0x41, 0x6e, 0x61, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x2b, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65,
+	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41, 0x6e, 0x61, 0x22, 0x00, 0x12,
+	0x88, 0x01, 0x0a, 0x18, 0x41, 0x64, 0x64, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f,
+	0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x12, 0x39, 0x2e, 0x6f,
+	0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x4e, 0x56, 0x4d, 0x65, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70,
+	0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79,
+	0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x22, 0x00, 0x12, 0x75, 0x0a, 0x1b, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72,
+	0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x12, 0x3c, 0x2e, 0x6f, 0x70, 0x69, 0x5f,
+	0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4e, 0x56, 0x4d, 0x65, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22,
+	0x00, 0x12, 0x99, 0x01, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69,
+	0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x65, 0x72, 0x73,
+	0x12, 0x3b, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73,
+	0x74, 0x65, 0x6e, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3c, 0x2e,
+	0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x56, 0x4d,
+	0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x4c, 0x69, 0x73, 0x74, 0x65, 0x6e,
+	0x65, 0x72, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x88, 0x01,
+	0x0a, 0x18, 0x41, 0x64, 0x64, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65,
+	0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x12, 0x39, 0x2e, 0x6f, 0x70, 0x69,
+	0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2f, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b,
+	0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65,
+	0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x22, 0x00, 0x12, 0x75, 0x0a, 0x1b, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52,
+	0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x12, 0x3c, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70,
+	0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73,
+	0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x22, 0x00, 0x12,
+	0x99, 0x01, 0x0a, 0x1a, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x44, 0x69, 0x73, 0x63,
+	0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c, 0x73, 0x12, 0x3b,
+	0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x56,
+	0x4d, 0x65, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72,
+	0x72, 0x61, 0x6c, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3c, 0x2e, 0x6f, 0x70,
+	0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x44,
+	0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x52, 0x65, 0x66, 0x65, 0x72, 0x72, 0x61, 0x6c,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x91, 0x01, 0x0a, 0x1b,
+	0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x3c, 0x2e, 0x6f, 0x70,
+	0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61,
+	0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x6f, 0x70, 0x69, 0x5f,
+	0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61,
+	0x63, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x00, 0x12,
+	0x97, 0x01, 0x0a, 0x20, 0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x41, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f,
+	0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2e, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70,
+	0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x52, 0x65, 0x73, 0x65, 0x72, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x22, 0x00, 0x12, 0x8e, 0x01, 0x0a, 0x1a, 0x53, 0x65,
+	0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x56, 0x69,
+	0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x3b, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73,
+	0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x56, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b,
+	0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x56, 0x69,
+	0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x22, 0x00, 0x12, 0x8e, 0x01, 0x0a, 0x1a, 0x47,
+	0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x56,
+	0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x3b, 0x2e, 0x6f, 0x70, 0x69, 0x5f,
+	0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x73, 0x70, 0x61, 0x63, 0x65, 0x56, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64,
+	0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x2e, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x56,
+	0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x22, 0x00, 0x12, 0x81, 0x01, 0x0a, 0x18,
+	0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65,
+	0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x39, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73,
+	0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62,
+	0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x56,
+	0x6f, 0x6c, 0x75, 0x6d, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x22, 0x00, 0x12,
+	0x79, 0x0a, 0x14, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x43,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x35, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70,
+	0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x43,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28,
+	0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x56, 0x6f, 0x6c, 0x75, 0x6d, 0x65,
+	0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x22, 0x00, 0x12, 0x81, 0x01, 0x0a, 0x18, 0x47,
+	0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x53, 0x63, 0x73, 0x69, 0x4c, 0x75, 0x6e, 0x43,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x12, 0x39, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70,
+	0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x53, 0x63, 0x73, 0x69,
+	0x4c, 0x75, 0x6e, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x28, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72,
+	0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x56, 0x6f,
+	0x6c, 0x75, 0x6d, 0x65, 0x43, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x22, 0x00, 0x12, 0x79,
+	0x0a, 0x13, 0x53, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x34, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b,
+	0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x53, 0x65, 0x74, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x4f, 0x70, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x6f, 0x70,
+	0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b,
+	0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x00, 0x12, 0x79, 0x0a, 0x13, 0x47, 0x65, 0x74,
+	0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x12, 0x34, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64,
+	0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x56,
+	0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64,
+	0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x2e, 0x56, 0x69, 0x72, 0x74, 0x69, 0x6f, 0x42, 0x6c, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x22, 0x00, 0x12, 0x91, 0x01, 0x0a, 0x1b, 0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x66,
+	0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72,
+	0x41, 0x75, 0x74, 0x68, 0x12, 0x3c, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f,
+	0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x53, 0x65, 0x74, 0x4e, 0x56, 0x4d, 0x66, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x41, 0x75, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x32, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72,
+	0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x4e, 0x56,
+	0x4d, 0x66, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c,
+	0x65, 0x72, 0x41, 0x75, 0x74, 0x68, 0x22, 0x00, 0x12, 0x67, 0x0a, 0x0d, 0x41, 0x64, 0x64, 0x4b,
+	0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x12, 0x2e, 0x2e, 0x6f, 0x70, 0x69, 0x5f,
+	0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x41, 0x64, 0x64, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b,
+	0x65, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6f, 0x70, 0x69, 0x5f,
+	0x73, 0x70, 0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x22,
+	0x00, 0x12, 0x5f, 0x0a, 0x10, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x72, 0x69,
+	0x6e, 0x67, 0x4b, 0x65, 0x79, 0x12, 0x31, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b,
+	0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x22, 0x00, 0x12, 0x78, 0x0a, 0x0f, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e,
+	0x67, 0x4b, 0x65, 0x79, 0x73, 0x12, 0x30, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70, 0x64, 0x6b,
+	0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31,
+	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65, 0x79, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x31, 0x2e, 0x6f, 0x70, 0x69, 0x5f, 0x73, 0x70,
+	0x64, 0x6b, 0x5f, 0x62, 0x72, 0x69, 0x64, 0x67, 0x65, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x65, 0x79, 0x72, 0x69, 0x6e, 0x67, 0x4b, 0x65,
+	0x79, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x3b, 0x5a, 0x39,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x69, 0x70, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x2f, 0x6f, 0x70, 0x69, 0x2d, 0x73, 0x70, 0x64, 0x6b, 0x2d, 0x62,
+	0x72, 0x69, 0x64, 0x67, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2f, 0x67, 0x65, 0x6e, 0x2f, 0x67, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_extension_proto_rawDescOnce sync.Once
+	file_extension_proto_rawDescData = file_extension_proto_rawDesc
+)
+
+func file_extension_proto_rawDescGZIP() []byte {
+	file_extension_proto_rawDescOnce.Do(func() {
+		file_extension_proto_rawDescData = protoimpl.X.CompressGZIP(file_extension_proto_rawDescData)
+	})
+	return file_extension_proto_rawDescData
+}
+
+var file_extension_proto_msgTypes = make([]protoimpl.MessageInfo, 42)
+var file_extension_proto_goTypes = []interface{}{
+	(*NVMeHost)(nil),                                // 0: opi_spdk_bridge.v1alpha1.NVMeHost
+	(*NVMeSubsystemHosts)(nil),                      // 1: opi_spdk_bridge.v1alpha1.NVMeSubsystemHosts
+	(*UpdateNVMeSubsystemHostsRequest)(nil),         // 2: opi_spdk_bridge.v1alpha1.UpdateNVMeSubsystemHostsRequest
+	(*GetNVMeSubsystemHostsRequest)(nil),            // 3: opi_spdk_bridge.v1alpha1.GetNVMeSubsystemHostsRequest
+	(*NVMeControllerTransport)(nil),                 // 4: opi_spdk_bridge.v1alpha1.NVMeControllerTransport
+	(*SetNVMeControllerTransportRequest)(nil),       // 5: opi_spdk_bridge.v1alpha1.SetNVMeControllerTransportRequest
+	(*GetNVMeControllerTransportRequest)(nil),       // 6: opi_spdk_bridge.v1alpha1.GetNVMeControllerTransportRequest
+	(*NVMeControllerAna)(nil),                       // 7: opi_spdk_bridge.v1alpha1.NVMeControllerAna
+	(*SetNVMeControllerAnaStateRequest)(nil),        // 8: opi_spdk_bridge.v1alpha1.SetNVMeControllerAnaStateRequest
+	(*GetNVMeControllerAnaStateRequest)(nil),        // 9: opi_spdk_bridge.v1alpha1.GetNVMeControllerAnaStateRequest
+	(*NVMeDiscoveryListener)(nil),                   // 10: opi_spdk_bridge.v1alpha1.NVMeDiscoveryListener
+	(*AddNVMeDiscoveryListenerRequest)(nil),         // 11: opi_spdk_bridge.v1alpha1.AddNVMeDiscoveryListenerRequest
+	(*DeleteNVMeDiscoveryListenerRequest)(nil),      // 12: opi_spdk_bridge.v1alpha1.DeleteNVMeDiscoveryListenerRequest
+	(*ListNVMeDiscoveryListenersRequest)(nil),       // 13: opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryListenersRequest
+	(*ListNVMeDiscoveryListenersResponse)(nil),      // 14: opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryListenersResponse
+	(*NVMeDiscoveryReferral)(nil),                   // 15: opi_spdk_bridge.v1alpha1.NVMeDiscoveryReferral
+	(*AddNVMeDiscoveryReferralRequest)(nil),         // 16: opi_spdk_bridge.v1alpha1.AddNVMeDiscoveryReferralRequest
+	(*DeleteNVMeDiscoveryReferralRequest)(nil),      // 17: opi_spdk_bridge.v1alpha1.DeleteNVMeDiscoveryReferralRequest
+	(*ListNVMeDiscoveryReferralsRequest)(nil),       // 18: opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryReferralsRequest
+	(*ListNVMeDiscoveryReferralsResponse)(nil),      // 19: opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryReferralsResponse
+	(*NVMeNamespaceReservation)(nil),                // 20: opi_spdk_bridge.v1alpha1.NVMeNamespaceReservation
+	(*SetNVMeNamespaceReservationRequest)(nil),      // 21: opi_spdk_bridge.v1alpha1.SetNVMeNamespaceReservationRequest
+	(*GetNVMeNamespaceReservationStateRequest)(nil), // 22: opi_spdk_bridge.v1alpha1.GetNVMeNamespaceReservationStateRequest
+	(*NVMeReservationState)(nil),                    // 23: opi_spdk_bridge.v1alpha1.NVMeReservationState
+	(*NVMeReservationRegistrant)(nil),               // 24: opi_spdk_bridge.v1alpha1.NVMeReservationRegistrant
+	(*NVMeNamespaceVisibility)(nil),                 // 25: opi_spdk_bridge.v1alpha1.NVMeNamespaceVisibility
+	(*SetNVMeNamespaceVisibilityRequest)(nil),       // 26: opi_spdk_bridge.v1alpha1.SetNVMeNamespaceVisibilityRequest
+	(*GetNVMeNamespaceVisibilityRequest)(nil),       // 27: opi_spdk_bridge.v1alpha1.GetNVMeNamespaceVisibilityRequest
+	(*VolumeCapacity)(nil),                          // 28: opi_spdk_bridge.v1alpha1.VolumeCapacity
+	(*GetNVMeNamespaceCapacityRequest)(nil),         // 29: opi_spdk_bridge.v1alpha1.GetNVMeNamespaceCapacityRequest
+	(*GetVirtioBlkCapacityRequest)(nil),             // 30: opi_spdk_bridge.v1alpha1.GetVirtioBlkCapacityRequest
+	(*GetVirtioScsiLunCapacityRequest)(nil),         // 31: opi_spdk_bridge.v1alpha1.GetVirtioScsiLunCapacityRequest
+	(*VirtioBlkOptions)(nil),                        // 32: opi_spdk_bridge.v1alpha1.VirtioBlkOptions
+	(*SetVirtioBlkOptionsRequest)(nil),              // 33: opi_spdk_bridge.v1alpha1.SetVirtioBlkOptionsRequest
+	(*GetVirtioBlkOptionsRequest)(nil),              // 34: opi_spdk_bridge.v1alpha1.GetVirtioBlkOptionsRequest
+	(*NVMfRemoteControllerAuth)(nil),                // 35: opi_spdk_bridge.v1alpha1.NVMfRemoteControllerAuth
+	(*SetNVMfRemoteControllerAuthRequest)(nil),      // 36: opi_spdk_bridge.v1alpha1.SetNVMfRemoteControllerAuthRequest
+	(*KeyringKey)(nil),                              // 37: opi_spdk_bridge.v1alpha1.KeyringKey
+	(*AddKeyringKeyRequest)(nil),                    // 38: opi_spdk_bridge.v1alpha1.AddKeyringKeyRequest
+	(*DeleteKeyringKeyRequest)(nil),                 // 39: opi_spdk_bridge.v1alpha1.DeleteKeyringKeyRequest
+	(*ListKeyringKeysRequest)(nil),                  // 40: opi_spdk_bridge.v1alpha1.ListKeyringKeysRequest
+	(*ListKeyringKeysResponse)(nil),                 // 41: opi_spdk_bridge.v1alpha1.ListKeyringKeysResponse
+	(*fieldmaskpb.FieldMask)(nil),                   // 42: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),                           // 43: google.protobuf.Empty
+}
+var file_extension_proto_depIdxs = []int32{
+	0,  // 0: opi_spdk_bridge.v1alpha1.NVMeSubsystemHosts.hosts:type_name -> opi_spdk_bridge.v1alpha1.NVMeHost
+	1,  // 1: opi_spdk_bridge.v1alpha1.UpdateNVMeSubsystemHostsRequest.hosts:type_name -> opi_spdk_bridge.v1alpha1.NVMeSubsystemHosts
+	42, // 2: opi_spdk_bridge.v1alpha1.UpdateNVMeSubsystemHostsRequest.update_mask:type_name -> google.protobuf.FieldMask
+	4,  // 3: opi_spdk_bridge.v1alpha1.SetNVMeControllerTransportRequest.transport:type_name -> opi_spdk_bridge.v1alpha1.NVMeControllerTransport
+	7,  // 4: opi_spdk_bridge.v1alpha1.SetNVMeControllerAnaStateRequest.ana:type_name -> opi_spdk_bridge.v1alpha1.NVMeControllerAna
+	10, // 5: opi_spdk_bridge.v1alpha1.AddNVMeDiscoveryListenerRequest.nvme_discovery_listener:type_name -> opi_spdk_bridge.v1alpha1.NVMeDiscoveryListener
+	10, // 6: opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryListenersResponse.nvme_discovery_listeners:type_name -> opi_spdk_bridge.v1alpha1.NVMeDiscoveryListener
+	15, // 7: opi_spdk_bridge.v1alpha1.AddNVMeDiscoveryReferralRequest.nvme_discovery_referral:type_name -> opi_spdk_bridge.v1alpha1.NVMeDiscoveryReferral
+	15, // 8: opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryReferralsResponse.nvme_discovery_referrals:type_name -> opi_spdk_bridge.v1alpha1.NVMeDiscoveryReferral
+	20, // 9: opi_spdk_bridge.v1alpha1.SetNVMeNamespaceReservationRequest.reservation:type_name -> opi_spdk_bridge.v1alpha1.NVMeNamespaceReservation
+	24, // 10: opi_spdk_bridge.v1alpha1.NVMeReservationState.registrants:type_name -> opi_spdk_bridge.v1alpha1.NVMeReservationRegistrant
+	25, // 11: opi_spdk_bridge.v1alpha1.SetNVMeNamespaceVisibilityRequest.visibility:type_name -> opi_spdk_bridge.v1alpha1.NVMeNamespaceVisibility
+	32, // 12: opi_spdk_bridge.v1alpha1.SetVirtioBlkOptionsRequest.options:type_name -> opi_spdk_bridge.v1alpha1.VirtioBlkOptions
+	35, // 13: opi_spdk_bridge.v1alpha1.SetNVMfRemoteControllerAuthRequest.auth:type_name -> opi_spdk_bridge.v1alpha1.NVMfRemoteControllerAuth
+	37, // 14: opi_spdk_bridge.v1alpha1.AddKeyringKeyRequest.keyring_key:type_name -> opi_spdk_bridge.v1alpha1.KeyringKey
+	37, // 15: opi_spdk_bridge.v1alpha1.ListKeyringKeysResponse.keyring_keys:type_name -> opi_spdk_bridge.v1alpha1.KeyringKey
+	2,  // 16: opi_spdk_bridge.v1alpha1.ExtensionService.UpdateNVMeSubsystemHosts:input_type -> opi_spdk_bridge.v1alpha1.UpdateNVMeSubsystemHostsRequest
+	3,  // 17: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeSubsystemHosts:input_type -> opi_spdk_bridge.v1alpha1.GetNVMeSubsystemHostsRequest
+	5,  // 18: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMeControllerTransport:input_type -> opi_spdk_bridge.v1alpha1.SetNVMeControllerTransportRequest
+	6,  // 19: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeControllerTransport:input_type -> opi_spdk_bridge.v1alpha1.GetNVMeControllerTransportRequest
+	8,  // 20: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMeControllerAnaState:input_type -> opi_spdk_bridge.v1alpha1.SetNVMeControllerAnaStateRequest
+	9,  // 21: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeControllerAnaState:input_type -> opi_spdk_bridge.v1alpha1.GetNVMeControllerAnaStateRequest
+	11, // 22: opi_spdk_bridge.v1alpha1.ExtensionService.AddNVMeDiscoveryListener:input_type -> opi_spdk_bridge.v1alpha1.AddNVMeDiscoveryListenerRequest
+	12, // 23: opi_spdk_bridge.v1alpha1.ExtensionService.DeleteNVMeDiscoveryListener:input_type -> opi_spdk_bridge.v1alpha1.DeleteNVMeDiscoveryListenerRequest
+	13, // 24: opi_spdk_bridge.v1alpha1.ExtensionService.ListNVMeDiscoveryListeners:input_type -> opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryListenersRequest
+	16, // 25: opi_spdk_bridge.v1alpha1.ExtensionService.AddNVMeDiscoveryReferral:input_type -> opi_spdk_bridge.v1alpha1.AddNVMeDiscoveryReferralRequest
+	17, // 26: opi_spdk_bridge.v1alpha1.ExtensionService.DeleteNVMeDiscoveryReferral:input_type -> opi_spdk_bridge.v1alpha1.DeleteNVMeDiscoveryReferralRequest
+	18, // 27: opi_spdk_bridge.v1alpha1.ExtensionService.ListNVMeDiscoveryReferrals:input_type -> opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryReferralsRequest
+	21, // 28: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMeNamespaceReservation:input_type -> opi_spdk_bridge.v1alpha1.SetNVMeNamespaceReservationRequest
+	22, // 29: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeNamespaceReservationState:input_type -> opi_spdk_bridge.v1alpha1.GetNVMeNamespaceReservationStateRequest
+	26, // 30: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMeNamespaceVisibility:input_type -> opi_spdk_bridge.v1alpha1.SetNVMeNamespaceVisibilityRequest
+	27, // 31: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeNamespaceVisibility:input_type -> opi_spdk_bridge.v1alpha1.GetNVMeNamespaceVisibilityRequest
+	29, // 32: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeNamespaceCapacity:input_type -> opi_spdk_bridge.v1alpha1.GetNVMeNamespaceCapacityRequest
+	30, // 33: opi_spdk_bridge.v1alpha1.ExtensionService.GetVirtioBlkCapacity:input_type -> opi_spdk_bridge.v1alpha1.GetVirtioBlkCapacityRequest
+	31, // 34: opi_spdk_bridge.v1alpha1.ExtensionService.GetVirtioScsiLunCapacity:input_type -> opi_spdk_bridge.v1alpha1.GetVirtioScsiLunCapacityRequest
+	33, // 35: opi_spdk_bridge.v1alpha1.ExtensionService.SetVirtioBlkOptions:input_type -> opi_spdk_bridge.v1alpha1.SetVirtioBlkOptionsRequest
+	34, // 36: opi_spdk_bridge.v1alpha1.ExtensionService.GetVirtioBlkOptions:input_type -> opi_spdk_bridge.v1alpha1.GetVirtioBlkOptionsRequest
+	36, // 37: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMfRemoteControllerAuth:input_type -> opi_spdk_bridge.v1alpha1.SetNVMfRemoteControllerAuthRequest
+	38, // 38: opi_spdk_bridge.v1alpha1.ExtensionService.AddKeyringKey:input_type -> opi_spdk_bridge.v1alpha1.AddKeyringKeyRequest
+	39, // 39: opi_spdk_bridge.v1alpha1.ExtensionService.DeleteKeyringKey:input_type -> opi_spdk_bridge.v1alpha1.DeleteKeyringKeyRequest
+	40, // 40: opi_spdk_bridge.v1alpha1.ExtensionService.ListKeyringKeys:input_type -> opi_spdk_bridge.v1alpha1.ListKeyringKeysRequest
+	1,  // 41: opi_spdk_bridge.v1alpha1.ExtensionService.UpdateNVMeSubsystemHosts:output_type -> opi_spdk_bridge.v1alpha1.NVMeSubsystemHosts
+	1,  // 42: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeSubsystemHosts:output_type -> opi_spdk_bridge.v1alpha1.NVMeSubsystemHosts
+	4,  // 43: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMeControllerTransport:output_type -> opi_spdk_bridge.v1alpha1.NVMeControllerTransport
+	4,  // 44: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeControllerTransport:output_type -> opi_spdk_bridge.v1alpha1.NVMeControllerTransport
+	7,  // 45: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMeControllerAnaState:output_type -> opi_spdk_bridge.v1alpha1.NVMeControllerAna
+	7,  // 46: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeControllerAnaState:output_type -> opi_spdk_bridge.v1alpha1.NVMeControllerAna
+	10, // 47: opi_spdk_bridge.v1alpha1.ExtensionService.AddNVMeDiscoveryListener:output_type -> opi_spdk_bridge.v1alpha1.NVMeDiscoveryListener
+	43, // 48: opi_spdk_bridge.v1alpha1.ExtensionService.DeleteNVMeDiscoveryListener:output_type -> google.protobuf.Empty
+	14, // 49: opi_spdk_bridge.v1alpha1.ExtensionService.ListNVMeDiscoveryListeners:output_type -> opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryListenersResponse
+	15, // 50: opi_spdk_bridge.v1alpha1.ExtensionService.AddNVMeDiscoveryReferral:output_type -> opi_spdk_bridge.v1alpha1.NVMeDiscoveryReferral
+	43, // 51: opi_spdk_bridge.v1alpha1.ExtensionService.DeleteNVMeDiscoveryReferral:output_type -> google.protobuf.Empty
+	19, // 52: opi_spdk_bridge.v1alpha1.ExtensionService.ListNVMeDiscoveryReferrals:output_type -> opi_spdk_bridge.v1alpha1.ListNVMeDiscoveryReferralsResponse
+	20, // 53: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMeNamespaceReservation:output_type -> opi_spdk_bridge.v1alpha1.NVMeNamespaceReservation
+	23, // 54: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeNamespaceReservationState:output_type -> opi_spdk_bridge.v1alpha1.NVMeReservationState
+	25, // 55: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMeNamespaceVisibility:output_type -> opi_spdk_bridge.v1alpha1.NVMeNamespaceVisibility
+	25, // 56: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeNamespaceVisibility:output_type -> opi_spdk_bridge.v1alpha1.NVMeNamespaceVisibility
+	28, // 57: opi_spdk_bridge.v1alpha1.ExtensionService.GetNVMeNamespaceCapacity:output_type -> opi_spdk_bridge.v1alpha1.VolumeCapacity
+	28, // 58: opi_spdk_bridge.v1alpha1.ExtensionService.GetVirtioBlkCapacity:output_type -> opi_spdk_bridge.v1alpha1.VolumeCapacity
+	28, // 59: opi_spdk_bridge.v1alpha1.ExtensionService.GetVirtioScsiLunCapacity:output_type -> opi_spdk_bridge.v1alpha1.VolumeCapacity
+	32, // 60: opi_spdk_bridge.v1alpha1.ExtensionService.SetVirtioBlkOptions:output_type -> opi_spdk_bridge.v1alpha1.VirtioBlkOptions
+	32, // 61: opi_spdk_bridge.v1alpha1.ExtensionService.GetVirtioBlkOptions:output_type -> opi_spdk_bridge.v1alpha1.VirtioBlkOptions
+	35, // 62: opi_spdk_bridge.v1alpha1.ExtensionService.SetNVMfRemoteControllerAuth:output_type -> opi_spdk_bridge.v1alpha1.NVMfRemoteControllerAuth
+	37, // 63: opi_spdk_bridge.v1alpha1.ExtensionService.AddKeyringKey:output_type -> opi_spdk_bridge.v1alpha1.KeyringKey
+	43, // 64: opi_spdk_bridge.v1alpha1.ExtensionService.DeleteKeyringKey:output_type -> google.protobuf.Empty
+	41, // 65: opi_spdk_bridge.v1alpha1.ExtensionService.ListKeyringKeys:output_type -> opi_spdk_bridge.v1alpha1.ListKeyringKeysResponse
+	41, // [41:66] is the sub-list for method output_type
+	16, // [16:41] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_extension_proto_init() }
+func file_extension_proto_init() {
+	if File_extension_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_extension_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeHost); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeSubsystemHosts); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateNVMeSubsystemHostsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNVMeSubsystemHostsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeControllerTransport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetNVMeControllerTransportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNVMeControllerTransportRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeControllerAna); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetNVMeControllerAnaStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNVMeControllerAnaStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeDiscoveryListener); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddNVMeDiscoveryListenerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteNVMeDiscoveryListenerRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListNVMeDiscoveryListenersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListNVMeDiscoveryListenersResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeDiscoveryReferral); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddNVMeDiscoveryReferralRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteNVMeDiscoveryReferralRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListNVMeDiscoveryReferralsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListNVMeDiscoveryReferralsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeNamespaceReservation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetNVMeNamespaceReservationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNVMeNamespaceReservationStateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeReservationState); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeReservationRegistrant); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMeNamespaceVisibility); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetNVMeNamespaceVisibilityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNVMeNamespaceVisibilityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VolumeCapacity); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetNVMeNamespaceCapacityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetVirtioBlkCapacityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetVirtioScsiLunCapacityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VirtioBlkOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetVirtioBlkOptionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetVirtioBlkOptionsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NVMfRemoteControllerAuth); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetNVMfRemoteControllerAuthRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KeyringKey); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AddKeyringKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteKeyringKeyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKeyringKeysRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_extension_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKeyringKeysResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_extension_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   42,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_extension_proto_goTypes,
+		DependencyIndexes: file_extension_proto_depIdxs,
+		MessageInfos:      file_extension_proto_msgTypes,
+	}.Build()
+	File_extension_proto = out.File
+	file_extension_proto_rawDesc = nil
+	file_extension_proto_goTypes = nil
+	file_extension_proto_depIdxs = nil
+}
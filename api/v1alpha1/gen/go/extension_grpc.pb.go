@@ -0,0 +1,1001 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: extension.proto
+
+package _go
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ExtensionService_UpdateNVMeSubsystemHosts_FullMethodName         = "/opi_spdk_bridge.v1alpha1.ExtensionService/UpdateNVMeSubsystemHosts"
+	ExtensionService_GetNVMeSubsystemHosts_FullMethodName            = "/opi_spdk_bridge.v1alpha1.ExtensionService/GetNVMeSubsystemHosts"
+	ExtensionService_SetNVMeControllerTransport_FullMethodName       = "/opi_spdk_bridge.v1alpha1.ExtensionService/SetNVMeControllerTransport"
+	ExtensionService_GetNVMeControllerTransport_FullMethodName       = "/opi_spdk_bridge.v1alpha1.ExtensionService/GetNVMeControllerTransport"
+	ExtensionService_SetNVMeControllerAnaState_FullMethodName        = "/opi_spdk_bridge.v1alpha1.ExtensionService/SetNVMeControllerAnaState"
+	ExtensionService_GetNVMeControllerAnaState_FullMethodName        = "/opi_spdk_bridge.v1alpha1.ExtensionService/GetNVMeControllerAnaState"
+	ExtensionService_AddNVMeDiscoveryListener_FullMethodName         = "/opi_spdk_bridge.v1alpha1.ExtensionService/AddNVMeDiscoveryListener"
+	ExtensionService_DeleteNVMeDiscoveryListener_FullMethodName      = "/opi_spdk_bridge.v1alpha1.ExtensionService/DeleteNVMeDiscoveryListener"
+	ExtensionService_ListNVMeDiscoveryListeners_FullMethodName       = "/opi_spdk_bridge.v1alpha1.ExtensionService/ListNVMeDiscoveryListeners"
+	ExtensionService_AddNVMeDiscoveryReferral_FullMethodName         = "/opi_spdk_bridge.v1alpha1.ExtensionService/AddNVMeDiscoveryReferral"
+	ExtensionService_DeleteNVMeDiscoveryReferral_FullMethodName      = "/opi_spdk_bridge.v1alpha1.ExtensionService/DeleteNVMeDiscoveryReferral"
+	ExtensionService_ListNVMeDiscoveryReferrals_FullMethodName       = "/opi_spdk_bridge.v1alpha1.ExtensionService/ListNVMeDiscoveryReferrals"
+	ExtensionService_SetNVMeNamespaceReservation_FullMethodName      = "/opi_spdk_bridge.v1alpha1.ExtensionService/SetNVMeNamespaceReservation"
+	ExtensionService_GetNVMeNamespaceReservationState_FullMethodName = "/opi_spdk_bridge.v1alpha1.ExtensionService/GetNVMeNamespaceReservationState"
+	ExtensionService_SetNVMeNamespaceVisibility_FullMethodName       = "/opi_spdk_bridge.v1alpha1.ExtensionService/SetNVMeNamespaceVisibility"
+	ExtensionService_GetNVMeNamespaceVisibility_FullMethodName       = "/opi_spdk_bridge.v1alpha1.ExtensionService/GetNVMeNamespaceVisibility"
+	ExtensionService_GetNVMeNamespaceCapacity_FullMethodName         = "/opi_spdk_bridge.v1alpha1.ExtensionService/GetNVMeNamespaceCapacity"
+	ExtensionService_GetVirtioBlkCapacity_FullMethodName             = "/opi_spdk_bridge.v1alpha1.ExtensionService/GetVirtioBlkCapacity"
+	ExtensionService_GetVirtioScsiLunCapacity_FullMethodName         = "/opi_spdk_bridge.v1alpha1.ExtensionService/GetVirtioScsiLunCapacity"
+	ExtensionService_SetVirtioBlkOptions_FullMethodName              = "/opi_spdk_bridge.v1alpha1.ExtensionService/SetVirtioBlkOptions"
+	ExtensionService_GetVirtioBlkOptions_FullMethodName              = "/opi_spdk_bridge.v1alpha1.ExtensionService/GetVirtioBlkOptions"
+	ExtensionService_SetNVMfRemoteControllerAuth_FullMethodName      = "/opi_spdk_bridge.v1alpha1.ExtensionService/SetNVMfRemoteControllerAuth"
+	ExtensionService_AddKeyringKey_FullMethodName                    = "/opi_spdk_bridge.v1alpha1.ExtensionService/AddKeyringKey"
+	ExtensionService_DeleteKeyringKey_FullMethodName                 = "/opi_spdk_bridge.v1alpha1.ExtensionService/DeleteKeyringKey"
+	ExtensionService_ListKeyringKeys_FullMethodName                  = "/opi_spdk_bridge.v1alpha1.ExtensionService/ListKeyringKeys"
+)
+
+// ExtensionServiceClient is the client API for ExtensionService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ExtensionServiceClient interface {
+	UpdateNVMeSubsystemHosts(ctx context.Context, in *UpdateNVMeSubsystemHostsRequest, opts ...grpc.CallOption) (*NVMeSubsystemHosts, error)
+	GetNVMeSubsystemHosts(ctx context.Context, in *GetNVMeSubsystemHostsRequest, opts ...grpc.CallOption) (*NVMeSubsystemHosts, error)
+	SetNVMeControllerTransport(ctx context.Context, in *SetNVMeControllerTransportRequest, opts ...grpc.CallOption) (*NVMeControllerTransport, error)
+	GetNVMeControllerTransport(ctx context.Context, in *GetNVMeControllerTransportRequest, opts ...grpc.CallOption) (*NVMeControllerTransport, error)
+	SetNVMeControllerAnaState(ctx context.Context, in *SetNVMeControllerAnaStateRequest, opts ...grpc.CallOption) (*NVMeControllerAna, error)
+	GetNVMeControllerAnaState(ctx context.Context, in *GetNVMeControllerAnaStateRequest, opts ...grpc.CallOption) (*NVMeControllerAna, error)
+	AddNVMeDiscoveryListener(ctx context.Context, in *AddNVMeDiscoveryListenerRequest, opts ...grpc.CallOption) (*NVMeDiscoveryListener, error)
+	DeleteNVMeDiscoveryListener(ctx context.Context, in *DeleteNVMeDiscoveryListenerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListNVMeDiscoveryListeners(ctx context.Context, in *ListNVMeDiscoveryListenersRequest, opts ...grpc.CallOption) (*ListNVMeDiscoveryListenersResponse, error)
+	AddNVMeDiscoveryReferral(ctx context.Context, in *AddNVMeDiscoveryReferralRequest, opts ...grpc.CallOption) (*NVMeDiscoveryReferral, error)
+	DeleteNVMeDiscoveryReferral(ctx context.Context, in *DeleteNVMeDiscoveryReferralRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListNVMeDiscoveryReferrals(ctx context.Context, in *ListNVMeDiscoveryReferralsRequest, opts ...grpc.CallOption) (*ListNVMeDiscoveryReferralsResponse, error)
+	SetNVMeNamespaceReservation(ctx context.Context, in *SetNVMeNamespaceReservationRequest, opts ...grpc.CallOption) (*NVMeNamespaceReservation, error)
+	GetNVMeNamespaceReservationState(ctx context.Context, in *GetNVMeNamespaceReservationStateRequest, opts ...grpc.CallOption) (*NVMeReservationState, error)
+	SetNVMeNamespaceVisibility(ctx context.Context, in *SetNVMeNamespaceVisibilityRequest, opts ...grpc.CallOption) (*NVMeNamespaceVisibility, error)
+	GetNVMeNamespaceVisibility(ctx context.Context, in *GetNVMeNamespaceVisibilityRequest, opts ...grpc.CallOption) (*NVMeNamespaceVisibility, error)
+	GetNVMeNamespaceCapacity(ctx context.Context, in *GetNVMeNamespaceCapacityRequest, opts ...grpc.CallOption) (*VolumeCapacity, error)
+	GetVirtioBlkCapacity(ctx context.Context, in *GetVirtioBlkCapacityRequest, opts ...grpc.CallOption) (*VolumeCapacity, error)
+	GetVirtioScsiLunCapacity(ctx context.Context, in *GetVirtioScsiLunCapacityRequest, opts ...grpc.CallOption) (*VolumeCapacity, error)
+	SetVirtioBlkOptions(ctx context.Context, in *SetVirtioBlkOptionsRequest, opts ...grpc.CallOption) (*VirtioBlkOptions, error)
+	GetVirtioBlkOptions(ctx context.Context, in *GetVirtioBlkOptionsRequest, opts ...grpc.CallOption) (*VirtioBlkOptions, error)
+	SetNVMfRemoteControllerAuth(ctx context.Context, in *SetNVMfRemoteControllerAuthRequest, opts ...grpc.CallOption) (*NVMfRemoteControllerAuth, error)
+	AddKeyringKey(ctx context.Context, in *AddKeyringKeyRequest, opts ...grpc.CallOption) (*KeyringKey, error)
+	DeleteKeyringKey(ctx context.Context, in *DeleteKeyringKeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListKeyringKeys(ctx context.Context, in *ListKeyringKeysRequest, opts ...grpc.CallOption) (*ListKeyringKeysResponse, error)
+}
+
+type extensionServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExtensionServiceClient(cc grpc.ClientConnInterface) ExtensionServiceClient {
+	return &extensionServiceClient{cc}
+}
+
+func (c *extensionServiceClient) UpdateNVMeSubsystemHosts(ctx context.Context, in *UpdateNVMeSubsystemHostsRequest, opts ...grpc.CallOption) (*NVMeSubsystemHosts, error) {
+	out := new(NVMeSubsystemHosts)
+	err := c.cc.Invoke(ctx, ExtensionService_UpdateNVMeSubsystemHosts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) GetNVMeSubsystemHosts(ctx context.Context, in *GetNVMeSubsystemHostsRequest, opts ...grpc.CallOption) (*NVMeSubsystemHosts, error) {
+	out := new(NVMeSubsystemHosts)
+	err := c.cc.Invoke(ctx, ExtensionService_GetNVMeSubsystemHosts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) SetNVMeControllerTransport(ctx context.Context, in *SetNVMeControllerTransportRequest, opts ...grpc.CallOption) (*NVMeControllerTransport, error) {
+	out := new(NVMeControllerTransport)
+	err := c.cc.Invoke(ctx, ExtensionService_SetNVMeControllerTransport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) GetNVMeControllerTransport(ctx context.Context, in *GetNVMeControllerTransportRequest, opts ...grpc.CallOption) (*NVMeControllerTransport, error) {
+	out := new(NVMeControllerTransport)
+	err := c.cc.Invoke(ctx, ExtensionService_GetNVMeControllerTransport_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) SetNVMeControllerAnaState(ctx context.Context, in *SetNVMeControllerAnaStateRequest, opts ...grpc.CallOption) (*NVMeControllerAna, error) {
+	out := new(NVMeControllerAna)
+	err := c.cc.Invoke(ctx, ExtensionService_SetNVMeControllerAnaState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) GetNVMeControllerAnaState(ctx context.Context, in *GetNVMeControllerAnaStateRequest, opts ...grpc.CallOption) (*NVMeControllerAna, error) {
+	out := new(NVMeControllerAna)
+	err := c.cc.Invoke(ctx, ExtensionService_GetNVMeControllerAnaState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) AddNVMeDiscoveryListener(ctx context.Context, in *AddNVMeDiscoveryListenerRequest, opts ...grpc.CallOption) (*NVMeDiscoveryListener, error) {
+	out := new(NVMeDiscoveryListener)
+	err := c.cc.Invoke(ctx, ExtensionService_AddNVMeDiscoveryListener_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) DeleteNVMeDiscoveryListener(ctx context.Context, in *DeleteNVMeDiscoveryListenerRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ExtensionService_DeleteNVMeDiscoveryListener_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) ListNVMeDiscoveryListeners(ctx context.Context, in *ListNVMeDiscoveryListenersRequest, opts ...grpc.CallOption) (*ListNVMeDiscoveryListenersResponse, error) {
+	out := new(ListNVMeDiscoveryListenersResponse)
+	err := c.cc.Invoke(ctx, ExtensionService_ListNVMeDiscoveryListeners_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) AddNVMeDiscoveryReferral(ctx context.Context, in *AddNVMeDiscoveryReferralRequest, opts ...grpc.CallOption) (*NVMeDiscoveryReferral, error) {
+	out := new(NVMeDiscoveryReferral)
+	err := c.cc.Invoke(ctx, ExtensionService_AddNVMeDiscoveryReferral_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) DeleteNVMeDiscoveryReferral(ctx context.Context, in *DeleteNVMeDiscoveryReferralRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ExtensionService_DeleteNVMeDiscoveryReferral_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) ListNVMeDiscoveryReferrals(ctx context.Context, in *ListNVMeDiscoveryReferralsRequest, opts ...grpc.CallOption) (*ListNVMeDiscoveryReferralsResponse, error) {
+	out := new(ListNVMeDiscoveryReferralsResponse)
+	err := c.cc.Invoke(ctx, ExtensionService_ListNVMeDiscoveryReferrals_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) SetNVMeNamespaceReservation(ctx context.Context, in *SetNVMeNamespaceReservationRequest, opts ...grpc.CallOption) (*NVMeNamespaceReservation, error) {
+	out := new(NVMeNamespaceReservation)
+	err := c.cc.Invoke(ctx, ExtensionService_SetNVMeNamespaceReservation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) GetNVMeNamespaceReservationState(ctx context.Context, in *GetNVMeNamespaceReservationStateRequest, opts ...grpc.CallOption) (*NVMeReservationState, error) {
+	out := new(NVMeReservationState)
+	err := c.cc.Invoke(ctx, ExtensionService_GetNVMeNamespaceReservationState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) SetNVMeNamespaceVisibility(ctx context.Context, in *SetNVMeNamespaceVisibilityRequest, opts ...grpc.CallOption) (*NVMeNamespaceVisibility, error) {
+	out := new(NVMeNamespaceVisibility)
+	err := c.cc.Invoke(ctx, ExtensionService_SetNVMeNamespaceVisibility_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) GetNVMeNamespaceVisibility(ctx context.Context, in *GetNVMeNamespaceVisibilityRequest, opts ...grpc.CallOption) (*NVMeNamespaceVisibility, error) {
+	out := new(NVMeNamespaceVisibility)
+	err := c.cc.Invoke(ctx, ExtensionService_GetNVMeNamespaceVisibility_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) GetNVMeNamespaceCapacity(ctx context.Context, in *GetNVMeNamespaceCapacityRequest, opts ...grpc.CallOption) (*VolumeCapacity, error) {
+	out := new(VolumeCapacity)
+	err := c.cc.Invoke(ctx, ExtensionService_GetNVMeNamespaceCapacity_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) GetVirtioBlkCapacity(ctx context.Context, in *GetVirtioBlkCapacityRequest, opts ...grpc.CallOption) (*VolumeCapacity, error) {
+	out := new(VolumeCapacity)
+	err := c.cc.Invoke(ctx, ExtensionService_GetVirtioBlkCapacity_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) GetVirtioScsiLunCapacity(ctx context.Context, in *GetVirtioScsiLunCapacityRequest, opts ...grpc.CallOption) (*VolumeCapacity, error) {
+	out := new(VolumeCapacity)
+	err := c.cc.Invoke(ctx, ExtensionService_GetVirtioScsiLunCapacity_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) SetVirtioBlkOptions(ctx context.Context, in *SetVirtioBlkOptionsRequest, opts ...grpc.CallOption) (*VirtioBlkOptions, error) {
+	out := new(VirtioBlkOptions)
+	err := c.cc.Invoke(ctx, ExtensionService_SetVirtioBlkOptions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) GetVirtioBlkOptions(ctx context.Context, in *GetVirtioBlkOptionsRequest, opts ...grpc.CallOption) (*VirtioBlkOptions, error) {
+	out := new(VirtioBlkOptions)
+	err := c.cc.Invoke(ctx, ExtensionService_GetVirtioBlkOptions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) SetNVMfRemoteControllerAuth(ctx context.Context, in *SetNVMfRemoteControllerAuthRequest, opts ...grpc.CallOption) (*NVMfRemoteControllerAuth, error) {
+	out := new(NVMfRemoteControllerAuth)
+	err := c.cc.Invoke(ctx, ExtensionService_SetNVMfRemoteControllerAuth_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) AddKeyringKey(ctx context.Context, in *AddKeyringKeyRequest, opts ...grpc.CallOption) (*KeyringKey, error) {
+	out := new(KeyringKey)
+	err := c.cc.Invoke(ctx, ExtensionService_AddKeyringKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) DeleteKeyringKey(ctx context.Context, in *DeleteKeyringKeyRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ExtensionService_DeleteKeyringKey_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *extensionServiceClient) ListKeyringKeys(ctx context.Context, in *ListKeyringKeysRequest, opts ...grpc.CallOption) (*ListKeyringKeysResponse, error) {
+	out := new(ListKeyringKeysResponse)
+	err := c.cc.Invoke(ctx, ExtensionService_ListKeyringKeys_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExtensionServiceServer is the server API for ExtensionService service.
+// All implementations must embed UnimplementedExtensionServiceServer
+// for forward compatibility
+type ExtensionServiceServer interface {
+	UpdateNVMeSubsystemHosts(context.Context, *UpdateNVMeSubsystemHostsRequest) (*NVMeSubsystemHosts, error)
+	GetNVMeSubsystemHosts(context.Context, *GetNVMeSubsystemHostsRequest) (*NVMeSubsystemHosts, error)
+	SetNVMeControllerTransport(context.Context, *SetNVMeControllerTransportRequest) (*NVMeControllerTransport, error)
+	GetNVMeControllerTransport(context.Context, *GetNVMeControllerTransportRequest) (*NVMeControllerTransport, error)
+	SetNVMeControllerAnaState(context.Context, *SetNVMeControllerAnaStateRequest) (*NVMeControllerAna, error)
+	GetNVMeControllerAnaState(context.Context, *GetNVMeControllerAnaStateRequest) (*NVMeControllerAna, error)
+	AddNVMeDiscoveryListener(context.Context, *AddNVMeDiscoveryListenerRequest) (*NVMeDiscoveryListener, error)
+	DeleteNVMeDiscoveryListener(context.Context, *DeleteNVMeDiscoveryListenerRequest) (*emptypb.Empty, error)
+	ListNVMeDiscoveryListeners(context.Context, *ListNVMeDiscoveryListenersRequest) (*ListNVMeDiscoveryListenersResponse, error)
+	AddNVMeDiscoveryReferral(context.Context, *AddNVMeDiscoveryReferralRequest) (*NVMeDiscoveryReferral, error)
+	DeleteNVMeDiscoveryReferral(context.Context, *DeleteNVMeDiscoveryReferralRequest) (*emptypb.Empty, error)
+	ListNVMeDiscoveryReferrals(context.Context, *ListNVMeDiscoveryReferralsRequest) (*ListNVMeDiscoveryReferralsResponse, error)
+	SetNVMeNamespaceReservation(context.Context, *SetNVMeNamespaceReservationRequest) (*NVMeNamespaceReservation, error)
+	GetNVMeNamespaceReservationState(context.Context, *GetNVMeNamespaceReservationStateRequest) (*NVMeReservationState, error)
+	SetNVMeNamespaceVisibility(context.Context, *SetNVMeNamespaceVisibilityRequest) (*NVMeNamespaceVisibility, error)
+	GetNVMeNamespaceVisibility(context.Context, *GetNVMeNamespaceVisibilityRequest) (*NVMeNamespaceVisibility, error)
+	GetNVMeNamespaceCapacity(context.Context, *GetNVMeNamespaceCapacityRequest) (*VolumeCapacity, error)
+	GetVirtioBlkCapacity(context.Context, *GetVirtioBlkCapacityRequest) (*VolumeCapacity, error)
+	GetVirtioScsiLunCapacity(context.Context, *GetVirtioScsiLunCapacityRequest) (*VolumeCapacity, error)
+	SetVirtioBlkOptions(context.Context, *SetVirtioBlkOptionsRequest) (*VirtioBlkOptions, error)
+	GetVirtioBlkOptions(context.Context, *GetVirtioBlkOptionsRequest) (*VirtioBlkOptions, error)
+	SetNVMfRemoteControllerAuth(context.Context, *SetNVMfRemoteControllerAuthRequest) (*NVMfRemoteControllerAuth, error)
+	AddKeyringKey(context.Context, *AddKeyringKeyRequest) (*KeyringKey, error)
+	DeleteKeyringKey(context.Context, *DeleteKeyringKeyRequest) (*emptypb.Empty, error)
+	ListKeyringKeys(context.Context, *ListKeyringKeysRequest) (*ListKeyringKeysResponse, error)
+	mustEmbedUnimplementedExtensionServiceServer()
+}
+
+// UnimplementedExtensionServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedExtensionServiceServer struct {
+}
+
+func (UnimplementedExtensionServiceServer) UpdateNVMeSubsystemHosts(context.Context, *UpdateNVMeSubsystemHostsRequest) (*NVMeSubsystemHosts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNVMeSubsystemHosts not implemented")
+}
+func (UnimplementedExtensionServiceServer) GetNVMeSubsystemHosts(context.Context, *GetNVMeSubsystemHostsRequest) (*NVMeSubsystemHosts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNVMeSubsystemHosts not implemented")
+}
+func (UnimplementedExtensionServiceServer) SetNVMeControllerTransport(context.Context, *SetNVMeControllerTransportRequest) (*NVMeControllerTransport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNVMeControllerTransport not implemented")
+}
+func (UnimplementedExtensionServiceServer) GetNVMeControllerTransport(context.Context, *GetNVMeControllerTransportRequest) (*NVMeControllerTransport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNVMeControllerTransport not implemented")
+}
+func (UnimplementedExtensionServiceServer) SetNVMeControllerAnaState(context.Context, *SetNVMeControllerAnaStateRequest) (*NVMeControllerAna, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNVMeControllerAnaState not implemented")
+}
+func (UnimplementedExtensionServiceServer) GetNVMeControllerAnaState(context.Context, *GetNVMeControllerAnaStateRequest) (*NVMeControllerAna, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNVMeControllerAnaState not implemented")
+}
+func (UnimplementedExtensionServiceServer) AddNVMeDiscoveryListener(context.Context, *AddNVMeDiscoveryListenerRequest) (*NVMeDiscoveryListener, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNVMeDiscoveryListener not implemented")
+}
+func (UnimplementedExtensionServiceServer) DeleteNVMeDiscoveryListener(context.Context, *DeleteNVMeDiscoveryListenerRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNVMeDiscoveryListener not implemented")
+}
+func (UnimplementedExtensionServiceServer) ListNVMeDiscoveryListeners(context.Context, *ListNVMeDiscoveryListenersRequest) (*ListNVMeDiscoveryListenersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNVMeDiscoveryListeners not implemented")
+}
+func (UnimplementedExtensionServiceServer) AddNVMeDiscoveryReferral(context.Context, *AddNVMeDiscoveryReferralRequest) (*NVMeDiscoveryReferral, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNVMeDiscoveryReferral not implemented")
+}
+func (UnimplementedExtensionServiceServer) DeleteNVMeDiscoveryReferral(context.Context, *DeleteNVMeDiscoveryReferralRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteNVMeDiscoveryReferral not implemented")
+}
+func (UnimplementedExtensionServiceServer) ListNVMeDiscoveryReferrals(context.Context, *ListNVMeDiscoveryReferralsRequest) (*ListNVMeDiscoveryReferralsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNVMeDiscoveryReferrals not implemented")
+}
+func (UnimplementedExtensionServiceServer) SetNVMeNamespaceReservation(context.Context, *SetNVMeNamespaceReservationRequest) (*NVMeNamespaceReservation, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNVMeNamespaceReservation not implemented")
+}
+func (UnimplementedExtensionServiceServer) GetNVMeNamespaceReservationState(context.Context, *GetNVMeNamespaceReservationStateRequest) (*NVMeReservationState, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNVMeNamespaceReservationState not implemented")
+}
+func (UnimplementedExtensionServiceServer) SetNVMeNamespaceVisibility(context.Context, *SetNVMeNamespaceVisibilityRequest) (*NVMeNamespaceVisibility, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNVMeNamespaceVisibility not implemented")
+}
+func (UnimplementedExtensionServiceServer) GetNVMeNamespaceVisibility(context.Context, *GetNVMeNamespaceVisibilityRequest) (*NVMeNamespaceVisibility, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNVMeNamespaceVisibility not implemented")
+}
+func (UnimplementedExtensionServiceServer) GetNVMeNamespaceCapacity(context.Context, *GetNVMeNamespaceCapacityRequest) (*VolumeCapacity, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNVMeNamespaceCapacity not implemented")
+}
+func (UnimplementedExtensionServiceServer) GetVirtioBlkCapacity(context.Context, *GetVirtioBlkCapacityRequest) (*VolumeCapacity, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVirtioBlkCapacity not implemented")
+}
+func (UnimplementedExtensionServiceServer) GetVirtioScsiLunCapacity(context.Context, *GetVirtioScsiLunCapacityRequest) (*VolumeCapacity, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVirtioScsiLunCapacity not implemented")
+}
+func (UnimplementedExtensionServiceServer) SetVirtioBlkOptions(context.Context, *SetVirtioBlkOptionsRequest) (*VirtioBlkOptions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetVirtioBlkOptions not implemented")
+}
+func (UnimplementedExtensionServiceServer) GetVirtioBlkOptions(context.Context, *GetVirtioBlkOptionsRequest) (*VirtioBlkOptions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVirtioBlkOptions not implemented")
+}
+func (UnimplementedExtensionServiceServer) SetNVMfRemoteControllerAuth(context.Context, *SetNVMfRemoteControllerAuthRequest) (*NVMfRemoteControllerAuth, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNVMfRemoteControllerAuth not implemented")
+}
+func (UnimplementedExtensionServiceServer) AddKeyringKey(context.Context, *AddKeyringKeyRequest) (*KeyringKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddKeyringKey not implemented")
+}
+func (UnimplementedExtensionServiceServer) DeleteKeyringKey(context.Context, *DeleteKeyringKeyRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteKeyringKey not implemented")
+}
+func (UnimplementedExtensionServiceServer) ListKeyringKeys(context.Context, *ListKeyringKeysRequest) (*ListKeyringKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeyringKeys not implemented")
+}
+func (UnimplementedExtensionServiceServer) mustEmbedUnimplementedExtensionServiceServer() {}
+
+// UnsafeExtensionServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ExtensionServiceServer will
+// result in compilation errors.
+type UnsafeExtensionServiceServer interface {
+	mustEmbedUnimplementedExtensionServiceServer()
+}
+
+func RegisterExtensionServiceServer(s grpc.ServiceRegistrar, srv ExtensionServiceServer) {
+	s.RegisterService(&ExtensionService_ServiceDesc, srv)
+}
+
+func _ExtensionService_UpdateNVMeSubsystemHosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateNVMeSubsystemHostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).UpdateNVMeSubsystemHosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_UpdateNVMeSubsystemHosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).UpdateNVMeSubsystemHosts(ctx, req.(*UpdateNVMeSubsystemHostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_GetNVMeSubsystemHosts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNVMeSubsystemHostsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).GetNVMeSubsystemHosts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_GetNVMeSubsystemHosts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).GetNVMeSubsystemHosts(ctx, req.(*GetNVMeSubsystemHostsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_SetNVMeControllerTransport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNVMeControllerTransportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).SetNVMeControllerTransport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_SetNVMeControllerTransport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).SetNVMeControllerTransport(ctx, req.(*SetNVMeControllerTransportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_GetNVMeControllerTransport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNVMeControllerTransportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).GetNVMeControllerTransport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_GetNVMeControllerTransport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).GetNVMeControllerTransport(ctx, req.(*GetNVMeControllerTransportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_SetNVMeControllerAnaState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNVMeControllerAnaStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).SetNVMeControllerAnaState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_SetNVMeControllerAnaState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).SetNVMeControllerAnaState(ctx, req.(*SetNVMeControllerAnaStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_GetNVMeControllerAnaState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNVMeControllerAnaStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).GetNVMeControllerAnaState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_GetNVMeControllerAnaState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).GetNVMeControllerAnaState(ctx, req.(*GetNVMeControllerAnaStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_AddNVMeDiscoveryListener_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddNVMeDiscoveryListenerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).AddNVMeDiscoveryListener(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_AddNVMeDiscoveryListener_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).AddNVMeDiscoveryListener(ctx, req.(*AddNVMeDiscoveryListenerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_DeleteNVMeDiscoveryListener_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNVMeDiscoveryListenerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).DeleteNVMeDiscoveryListener(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_DeleteNVMeDiscoveryListener_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).DeleteNVMeDiscoveryListener(ctx, req.(*DeleteNVMeDiscoveryListenerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_ListNVMeDiscoveryListeners_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNVMeDiscoveryListenersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).ListNVMeDiscoveryListeners(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_ListNVMeDiscoveryListeners_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).ListNVMeDiscoveryListeners(ctx, req.(*ListNVMeDiscoveryListenersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_AddNVMeDiscoveryReferral_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddNVMeDiscoveryReferralRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).AddNVMeDiscoveryReferral(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_AddNVMeDiscoveryReferral_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).AddNVMeDiscoveryReferral(ctx, req.(*AddNVMeDiscoveryReferralRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_DeleteNVMeDiscoveryReferral_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteNVMeDiscoveryReferralRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).DeleteNVMeDiscoveryReferral(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_DeleteNVMeDiscoveryReferral_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).DeleteNVMeDiscoveryReferral(ctx, req.(*DeleteNVMeDiscoveryReferralRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_ListNVMeDiscoveryReferrals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNVMeDiscoveryReferralsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).ListNVMeDiscoveryReferrals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_ListNVMeDiscoveryReferrals_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).ListNVMeDiscoveryReferrals(ctx, req.(*ListNVMeDiscoveryReferralsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_SetNVMeNamespaceReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNVMeNamespaceReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).SetNVMeNamespaceReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_SetNVMeNamespaceReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).SetNVMeNamespaceReservation(ctx, req.(*SetNVMeNamespaceReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_GetNVMeNamespaceReservationState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNVMeNamespaceReservationStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).GetNVMeNamespaceReservationState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_GetNVMeNamespaceReservationState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).GetNVMeNamespaceReservationState(ctx, req.(*GetNVMeNamespaceReservationStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_SetNVMeNamespaceVisibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNVMeNamespaceVisibilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).SetNVMeNamespaceVisibility(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_SetNVMeNamespaceVisibility_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).SetNVMeNamespaceVisibility(ctx, req.(*SetNVMeNamespaceVisibilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_GetNVMeNamespaceVisibility_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNVMeNamespaceVisibilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).GetNVMeNamespaceVisibility(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_GetNVMeNamespaceVisibility_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).GetNVMeNamespaceVisibility(ctx, req.(*GetNVMeNamespaceVisibilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_GetNVMeNamespaceCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNVMeNamespaceCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).GetNVMeNamespaceCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_GetNVMeNamespaceCapacity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).GetNVMeNamespaceCapacity(ctx, req.(*GetNVMeNamespaceCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_GetVirtioBlkCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVirtioBlkCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).GetVirtioBlkCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_GetVirtioBlkCapacity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).GetVirtioBlkCapacity(ctx, req.(*GetVirtioBlkCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_GetVirtioScsiLunCapacity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVirtioScsiLunCapacityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).GetVirtioScsiLunCapacity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_GetVirtioScsiLunCapacity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).GetVirtioScsiLunCapacity(ctx, req.(*GetVirtioScsiLunCapacityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_SetVirtioBlkOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetVirtioBlkOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).SetVirtioBlkOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_SetVirtioBlkOptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).SetVirtioBlkOptions(ctx, req.(*SetVirtioBlkOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_GetVirtioBlkOptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVirtioBlkOptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).GetVirtioBlkOptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_GetVirtioBlkOptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).GetVirtioBlkOptions(ctx, req.(*GetVirtioBlkOptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_SetNVMfRemoteControllerAuth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNVMfRemoteControllerAuthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).SetNVMfRemoteControllerAuth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_SetNVMfRemoteControllerAuth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).SetNVMfRemoteControllerAuth(ctx, req.(*SetNVMfRemoteControllerAuthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_AddKeyringKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddKeyringKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).AddKeyringKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_AddKeyringKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).AddKeyringKey(ctx, req.(*AddKeyringKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_DeleteKeyringKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteKeyringKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).DeleteKeyringKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_DeleteKeyringKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).DeleteKeyringKey(ctx, req.(*DeleteKeyringKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ExtensionService_ListKeyringKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeyringKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExtensionServiceServer).ListKeyringKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ExtensionService_ListKeyringKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExtensionServiceServer).ListKeyringKeys(ctx, req.(*ListKeyringKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExtensionService_ServiceDesc is the grpc.ServiceDesc for ExtensionService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExtensionService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "opi_spdk_bridge.v1alpha1.ExtensionService",
+	HandlerType: (*ExtensionServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpdateNVMeSubsystemHosts",
+			Handler:    _ExtensionService_UpdateNVMeSubsystemHosts_Handler,
+		},
+		{
+			MethodName: "GetNVMeSubsystemHosts",
+			Handler:    _ExtensionService_GetNVMeSubsystemHosts_Handler,
+		},
+		{
+			MethodName: "SetNVMeControllerTransport",
+			Handler:    _ExtensionService_SetNVMeControllerTransport_Handler,
+		},
+		{
+			MethodName: "GetNVMeControllerTransport",
+			Handler:    _ExtensionService_GetNVMeControllerTransport_Handler,
+		},
+		{
+			MethodName: "SetNVMeControllerAnaState",
+			Handler:    _ExtensionService_SetNVMeControllerAnaState_Handler,
+		},
+		{
+			MethodName: "GetNVMeControllerAnaState",
+			Handler:    _ExtensionService_GetNVMeControllerAnaState_Handler,
+		},
+		{
+			MethodName: "AddNVMeDiscoveryListener",
+			Handler:    _ExtensionService_AddNVMeDiscoveryListener_Handler,
+		},
+		{
+			MethodName: "DeleteNVMeDiscoveryListener",
+			Handler:    _ExtensionService_DeleteNVMeDiscoveryListener_Handler,
+		},
+		{
+			MethodName: "ListNVMeDiscoveryListeners",
+			Handler:    _ExtensionService_ListNVMeDiscoveryListeners_Handler,
+		},
+		{
+			MethodName: "AddNVMeDiscoveryReferral",
+			Handler:    _ExtensionService_AddNVMeDiscoveryReferral_Handler,
+		},
+		{
+			MethodName: "DeleteNVMeDiscoveryReferral",
+			Handler:    _ExtensionService_DeleteNVMeDiscoveryReferral_Handler,
+		},
+		{
+			MethodName: "ListNVMeDiscoveryReferrals",
+			Handler:    _ExtensionService_ListNVMeDiscoveryReferrals_Handler,
+		},
+		{
+			MethodName: "SetNVMeNamespaceReservation",
+			Handler:    _ExtensionService_SetNVMeNamespaceReservation_Handler,
+		},
+		{
+			MethodName: "GetNVMeNamespaceReservationState",
+			Handler:    _ExtensionService_GetNVMeNamespaceReservationState_Handler,
+		},
+		{
+			MethodName: "SetNVMeNamespaceVisibility",
+			Handler:    _ExtensionService_SetNVMeNamespaceVisibility_Handler,
+		},
+		{
+			MethodName: "GetNVMeNamespaceVisibility",
+			Handler:    _ExtensionService_GetNVMeNamespaceVisibility_Handler,
+		},
+		{
+			MethodName: "GetNVMeNamespaceCapacity",
+			Handler:    _ExtensionService_GetNVMeNamespaceCapacity_Handler,
+		},
+		{
+			MethodName: "GetVirtioBlkCapacity",
+			Handler:    _ExtensionService_GetVirtioBlkCapacity_Handler,
+		},
+		{
+			MethodName: "GetVirtioScsiLunCapacity",
+			Handler:    _ExtensionService_GetVirtioScsiLunCapacity_Handler,
+		},
+		{
+			MethodName: "SetVirtioBlkOptions",
+			Handler:    _ExtensionService_SetVirtioBlkOptions_Handler,
+		},
+		{
+			MethodName: "GetVirtioBlkOptions",
+			Handler:    _ExtensionService_GetVirtioBlkOptions_Handler,
+		},
+		{
+			MethodName: "SetNVMfRemoteControllerAuth",
+			Handler:    _ExtensionService_SetNVMfRemoteControllerAuth_Handler,
+		},
+		{
+			MethodName: "AddKeyringKey",
+			Handler:    _ExtensionService_AddKeyringKey_Handler,
+		},
+		{
+			MethodName: "DeleteKeyringKey",
+			Handler:    _ExtensionService_DeleteKeyringKey_Handler,
+		},
+		{
+			MethodName: "ListKeyringKeys",
+			Handler:    _ExtensionService_ListKeyringKeys_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "extension.proto",
+}
@@ -4,80 +4,286 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/opiproject/gospdk/spdk"
 
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
 	"github.com/opiproject/opi-spdk-bridge/pkg/backend"
+	"github.com/opiproject/opi-spdk-bridge/pkg/config"
+	"github.com/opiproject/opi-spdk-bridge/pkg/extension"
 	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
 	"github.com/opiproject/opi-spdk-bridge/pkg/kvm"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/metrics"
 	"github.com/opiproject/opi-spdk-bridge/pkg/middleend"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 )
 
-func main() {
-	var port int
-	flag.IntVar(&port, "port", 50051, "The Server port")
-
-	var spdkAddress string
-	flag.StringVar(&spdkAddress, "spdk_addr", "/var/tmp/spdk.sock", "Points to SPDK unix socket/tcp socket to interact with")
+// spdkProbeInterval is the interval between checks whether SPDK is up
+const spdkProbeInterval = 5 * time.Second
 
-	var useKvm bool
-	flag.BoolVar(&useKvm, "kvm", false, "Automates interaction with QEMU to plug/unplug SPDK devices")
-
-	var qmpAddress string
-	flag.StringVar(&qmpAddress, "qmp_addr", "127.0.0.1:5555", "Points to QMP unix socket/tcp socket to interact with. Valid only with -kvm option")
+func main() {
+	printConfig := flag.Bool("print-config", false, "Print the effective configuration in YAML format accepted by -config and exit")
+	cfg, err := config.Parse(flag.CommandLine, os.Args[1:], os.LookupEnv)
+	if err != nil {
+		log.Fatalf("invalid configuration: %v", err)
+	}
+	if *printConfig {
+		fmt.Print(cfg)
+		return
+	}
 
-	var ctrlrDir string
-	flag.StringVar(&ctrlrDir, "ctrlr_dir", "", "Directory with created SPDK device unix sockets (-S option in SPDK). Valid only with -kvm option")
+	policy, err := server.ParseReconcilePolicy(cfg.Reconcile)
+	if err != nil {
+		log.Fatalf("invalid -reconcile value: %v", err)
+	}
 
-	var tcpTransportListenAddr string
-	flag.StringVar(&tcpTransportListenAddr, "tcp_trid", "127.0.0.1:4420", "ipv4 address:port (aka traddr:trsvcid) or ipv6 [address]:port tuple (aka [traddr]:trsvcid) to listen on for Nvme/TCP transport")
-	flag.Parse()
+	if err := logging.Setup(cfg.Logging); err != nil {
+		log.Fatalf("failed to set up logging: %v", err)
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatalf("failed to set up tracing: %v", err)
+	}
 
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Port))
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	s := grpc.NewServer()
+	var m *metrics.Metrics
+	if cfg.Metrics.Address != "" {
+		m = metrics.New()
+	}
+	s := newGrpcServer(cfg, m)
+
+	st := store.NewMemoryStore()
+	if cfg.Store != "" {
+		st, err = store.NewFileStore(cfg.Store)
+		if err != nil {
+			log.Fatalf("failed to open store: %v", err)
+		}
+	}
 
-	jsonRPC := spdk.NewSpdkJSONRPC(spdkAddress)
+	jsonRPC := spdk.NewSpdkJSONRPC(cfg.SpdkAddress)
+	if m != nil {
+		jsonRPC = m.InstrumentJSONRPC(jsonRPC)
+	}
+	keyringServer := keyring.NewServer(jsonRPC)
+	if err := keyringServer.UseStore(st); err != nil {
+		log.Fatalf("failed to restore keyring keys: %v", err)
+	}
 	backendServer := backend.NewServer(jsonRPC)
+	if err := backendServer.UseStore(st); err != nil {
+		log.Fatalf("failed to restore backend objects: %v", err)
+	}
+	if err := backendServer.SetPaginationLimits(cfg.Pagination); err != nil {
+		log.Fatalf("failed to configure backend: %v", err)
+	}
+	if err := backendServer.SetAioBlockSize(cfg.AioBlockSize); err != nil {
+		log.Fatalf("failed to configure backend: %v", err)
+	}
+	if err := backendServer.SetDhchap(cfg.NvmeDhchap); err != nil {
+		log.Fatalf("failed to configure backend: %v", err)
+	}
 	middleendServer := middleend.NewServer(jsonRPC)
+	if err := middleendServer.UseStore(st); err != nil {
+		log.Fatalf("failed to restore middleend objects: %v", err)
+	}
+	if err := middleendServer.SetPaginationLimits(cfg.Pagination); err != nil {
+		log.Fatalf("failed to configure middleend: %v", err)
+	}
 
-	if useKvm {
-		log.Println("Creating KVM server.")
-		frontendServer := frontend.NewServerWithSubsystemListener(jsonRPC,
-			kvm.NewVfiouserSubsystemListener(ctrlrDir))
-		kvmServer := kvm.NewServer(frontendServer, qmpAddress, ctrlrDir)
-
-		pb.RegisterFrontendNvmeServiceServer(s, kvmServer)
-		pb.RegisterFrontendVirtioBlkServiceServer(s, kvmServer)
-		pb.RegisterFrontendVirtioScsiServiceServer(s, kvmServer)
-	} else {
-		frontendServer := frontend.NewServerWithSubsystemListener(jsonRPC,
-			frontend.NewTCPSubsystemListener(tcpTransportListenAddr))
-		pb.RegisterFrontendNvmeServiceServer(s, frontendServer)
-		pb.RegisterFrontendVirtioBlkServiceServer(s, frontendServer)
-		pb.RegisterFrontendVirtioScsiServiceServer(s, frontendServer)
+	frontendServer := newFrontendServer(s, cfg, jsonRPC, st)
+	if err := frontendServer.SetPaginationLimits(cfg.Pagination); err != nil {
+		log.Fatalf("failed to configure frontend: %v", err)
 	}
+	frontendServer.SetAllowAnyHostByDefault(cfg.NvmeAllowAnyHost)
+	frontendServer.SetAnaReportingByDefault(cfg.NvmeAnaReporting)
 
 	pb.RegisterNVMfRemoteControllerServiceServer(s, backendServer)
 	pb.RegisterNullDebugServiceServer(s, backendServer)
 	pb.RegisterAioControllerServiceServer(s, backendServer)
 	pb.RegisterMiddleendEncryptionServiceServer(s, middleendServer)
 	pb.RegisterMiddleendQosVolumeServiceServer(s, middleendServer)
+	pe.RegisterExtensionServiceServer(s, extension.NewServer(frontendServer, backendServer, keyringServer))
 
 	reflection.Register(s)
 
+	// keys go first since subsystem hosts and remote controllers refer to them
+	reconcilers := []server.Reconciler{keyringServer, backendServer, middleendServer, frontendServer}
+	reconcileAtStartup(cfg.SpdkAddress, policy, reconcilers)
+	go reconcileOnSignal(cfg.SpdkAddress, policy, reconcilers)
+
+	if m != nil {
+		m.CountObjects(keyringServer, backendServer, middleendServer, frontendServer)
+		go m.ScrapeIostat(context.Background(), jsonRPC, cfg.SpdkAddress, cfg.Metrics.IostatInterval)
+		go serveMetrics(cfg.Metrics.Address, m)
+	}
+
+	go stopOnSignal(s)
 	log.Printf("Server listening at %v", lis.Addr())
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Printf("failed to flush spans: %v", err)
+	}
+}
+
+// newFrontendServer creates the frontend server, optionally backed by KVM,
+// restores its objects from st and registers its services on s
+func newFrontendServer(s *grpc.Server, cfg *config.Config, jsonRPC spdk.JSONRPC, st store.Store) *frontend.Server {
+	tcpListener := frontend.NewTCPSubsystemListener(cfg.TCPTransportListenAddr)
+	if cfg.TCPSecureChannel {
+		tcpListener = frontend.NewSecureTCPSubsystemListener(cfg.TCPTransportListenAddr)
+	}
+	frontendServer := frontend.NewServerWithSubsystemListener(jsonRPC, tcpListener)
+	frontendServer.RegisterSubsystemListener(frontend.NewRDMASubsystemListener(cfg.RDMATransportListenAddr))
+	if err := frontendServer.UseStore(st); err != nil {
+		log.Fatalf("failed to restore frontend objects: %v", err)
+	}
+	transport := cfg.NvmeTransport
+	var service interface {
+		pb.FrontendNvmeServiceServer
+		pb.FrontendVirtioBlkServiceServer
+		pb.FrontendVirtioScsiServiceServer
+	} = frontendServer
+	if cfg.Kvm {
+		log.Println("Creating KVM server.")
+		kvmServer := kvm.NewServer(frontendServer, cfg.QmpAddress, cfg.CtrlrDir)
+		if err := kvmServer.SetTimeouts(cfg.KvmTimeouts.Timeout, cfg.KvmTimeouts.PollDevicePresenceStep); err != nil {
+			log.Fatalf("failed to configure KVM server: %v", err)
+		}
+		if transport == "" {
+			transport = frontend.TransportVfioUser
+		}
+		service = kvmServer
+	}
+	if transport != "" {
+		if err := frontendServer.SetDefaultTransport(transport); err != nil {
+			log.Fatalf("failed to configure frontend server: %v", err)
+		}
+	}
+	pb.RegisterFrontendNvmeServiceServer(s, service)
+	pb.RegisterFrontendVirtioBlkServiceServer(s, service)
+	pb.RegisterFrontendVirtioScsiServiceServer(s, service)
+	return frontendServer
+}
+
+// newGrpcServer creates gRPC server secured and instrumented according to cfg.
+// Metrics are not collected if m is nil.
+func newGrpcServer(cfg *config.Config, m *metrics.Metrics) *grpc.Server {
+	var serverOptions []grpc.ServerOption
+	if cfg.TLS.Enabled() {
+		tlsConfig, err := server.NewServerTLSConfig(cfg.TLS)
+		if err != nil {
+			log.Fatalf("failed to configure TLS: %v", err)
+		}
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	} else {
+		log.Println("TLS is not configured, gRPC endpoint accepts plaintext connections")
+	}
+	// logging, tracing and metrics go first to also record rejected calls
+	serverOptions = append(serverOptions,
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor()))
+	if cfg.Tracing.Enabled() {
+		serverOptions = append(serverOptions,
+			grpc.ChainUnaryInterceptor(tracing.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(tracing.StreamServerInterceptor()))
+	}
+	if m != nil {
+		serverOptions = append(serverOptions,
+			grpc.ChainUnaryInterceptor(m.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(m.StreamServerInterceptor()))
+	}
+	if cfg.AuthzPolicy != "" {
+		authorizer, err := server.LoadAuthorizer(cfg.AuthzPolicy)
+		if err != nil {
+			log.Fatalf("failed to load authorization policy: %v", err)
+		}
+		serverOptions = append(serverOptions,
+			grpc.ChainUnaryInterceptor(authorizer.UnaryInterceptor()),
+			grpc.ChainStreamInterceptor(authorizer.StreamInterceptor()))
+	}
+	return grpc.NewServer(serverOptions...)
+}
+
+// serveMetrics serves Prometheus metrics over HTTP at /metrics
+func serveMetrics(address string, m *metrics.Metrics) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	metricsServer := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	log.Printf("Metrics listening at %v", address)
+	if err := metricsServer.ListenAndServe(); err != nil {
+		log.Fatalf("failed to serve metrics: %v", err)
+	}
+}
+
+// stopOnSignal stops the server gracefully when SIGINT or SIGTERM is received,
+// so that buffered spans are flushed before exit
+func stopOnSignal(s *grpc.Server) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+	log.Println("Received termination signal, stopping the server")
+	s.GracefulStop()
+}
+
+// reconcileAtStartup reconciles with SPDK right away if it is reachable.
+// Otherwise the bridge starts without it and reconciles once SPDK is up,
+// since a call to unreachable SPDK terminates the bridge.
+func reconcileAtStartup(spdkAddress string, policy server.ReconcilePolicy, reconcilers []server.Reconciler) {
+	if err := server.ProbeSpdk(spdkAddress); err == nil {
+		_, _ = server.Reconcile(policy, reconcilers...)
+		return
+	}
+	log.Printf("SPDK is not reachable at %v, reconciliation is postponed until it is up", spdkAddress)
+	go func() {
+		ticker := time.NewTicker(spdkProbeInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if server.ProbeSpdk(spdkAddress) == nil {
+				log.Println("SPDK is up, reconciling with SPDK")
+				_, _ = server.Reconcile(policy, reconcilers...)
+				return
+			}
+		}
+	}()
+}
+
+// reconcileOnSignal runs reconciliation again every time SIGHUP is received
+func reconcileOnSignal(spdkAddress string, policy server.ReconcilePolicy, reconcilers []server.Reconciler) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	for range sig {
+		if err := server.ProbeSpdk(spdkAddress); err != nil {
+			log.Printf("Received SIGHUP, but SPDK is not reachable: %v", err)
+			continue
+		}
+		log.Println("Received SIGHUP, reconciling with SPDK")
+		_, _ = server.Reconcile(policy, reconcilers...)
+	}
 }
@@ -8,12 +8,13 @@ package backend
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/opiproject/gospdk/spdk"
 	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 
 	"github.com/google/uuid"
 	"github.com/ulule/deepcopier"
@@ -23,139 +24,194 @@ import (
 )
 
 // CreateAioController creates an Aio controller
-func (s *Server) CreateAioController(_ context.Context, in *pb.CreateAioControllerRequest) (*pb.AioController, error) {
-	log.Printf("CreateAioController: Received from client: %v", in)
+func (s *Server) CreateAioController(ctx context.Context, in *pb.CreateAioControllerRequest) (*pb.AioController, error) {
+	logging.FromContext(ctx).Infof("CreateAioController: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(aioVolumesTable, in.AioController.Handle.Value))
+	defer unlock()
 	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
 	volume, ok := s.Volumes.AioVolumes[in.AioController.Handle.Value]
+	s.mu.RUnlock()
 	if ok {
-		log.Printf("Already existing AioController with id %v", in.AioController.Handle.Value)
+		logging.FromContext(ctx).Infof("Already existing AioController with id %v", in.AioController.Handle.Value)
 		return volume, nil
 	}
 	// not found, so create a new one
 	params := spdk.BdevAioCreateParams{
 		Name:      in.AioController.Handle.Value,
-		BlockSize: 512,
+		BlockSize: s.getAioBlockSize(),
 		Filename:  in.AioController.Filename,
 	}
 	var result spdk.BdevAioCreateResult
-	err := s.rpc.Call("bdev_aio_create", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_aio_create", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if result == "" {
 		msg := fmt.Sprintf("Could not create Aio Dev: %s", in.AioController.Handle.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	response := &pb.AioController{}
 	err = deepcopier.Copy(in.AioController).To(response)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
+	if err := s.store.Set(aioVolumesTable, in.AioController.Handle.Value, response); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.Volumes.AioVolumes[in.AioController.Handle.Value] = response
+	s.mu.Unlock()
 	return response, nil
 }
 
 // DeleteAioController deletes an Aio controller
-func (s *Server) DeleteAioController(_ context.Context, in *pb.DeleteAioControllerRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteAioController: Received from client: %v", in)
+func (s *Server) DeleteAioController(ctx context.Context, in *pb.DeleteAioControllerRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteAioController: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(aioVolumesTable, in.Name))
+	defer unlock()
+	s.mu.RLock()
 	volume, ok := s.Volumes.AioVolumes[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		if in.AllowMissing {
 			return &emptypb.Empty{}, nil
 		}
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	params := spdk.BdevAioDeleteParams{
 		Name: in.Name,
 	}
 	var result spdk.BdevAioDeleteResult
-	err := s.rpc.Call("bdev_aio_delete", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_aio_delete", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
 		msg := fmt.Sprintf("Could not delete Aio Dev: %s", volume.Handle.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
+	if err := s.store.Delete(aioVolumesTable, volume.Handle.Value); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	delete(s.Volumes.AioVolumes, volume.Handle.Value)
+	s.mu.Unlock()
 	return &emptypb.Empty{}, nil
 }
 
-// UpdateAioController updates an Aio controller
-func (s *Server) UpdateAioController(_ context.Context, in *pb.UpdateAioControllerRequest) (*pb.AioController, error) {
-	log.Printf("UpdateAioController: Received from client: %v", in)
+// UpdateAioController updates an Aio controller. A larger blocks_count
+// grows a created Aio controller online to the size of its file, which has
+// to be grown before. It cannot shrink.
+func (s *Server) UpdateAioController(ctx context.Context, in *pb.UpdateAioControllerRequest) (*pb.AioController, error) {
+	logging.FromContext(ctx).Infof("UpdateAioController: Received from client: %v", logging.Redact(in))
+	if in.GetAioController().GetHandle().GetValue() == "" {
+		err := status.Error(codes.InvalidArgument, "aio_controller.handle cannot be empty")
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	fields, err := server.UpdatedFields(in.UpdateMask, "blocks_count")
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	unlock := s.locks.Lock(server.LockKey(aioVolumesTable, in.AioController.Handle.Value))
+	defer unlock()
+	s.mu.RLock()
+	volume, ok := s.Volumes.AioVolumes[in.AioController.Handle.Value]
+	s.mu.RUnlock()
+	if ok && fields["blocks_count"] && in.AioController.BlocksCount != 0 && in.AioController.BlocksCount != volume.BlocksCount {
+		response, err := s.growAioController(ctx, volume, in.AioController.BlocksCount)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		return response, nil
+	}
 	params1 := spdk.BdevAioDeleteParams{
 		Name: in.AioController.Handle.Value,
 	}
 	var result1 spdk.BdevAioDeleteResult
-	err1 := s.rpc.Call("bdev_aio_delete", &params1, &result1)
+	err1 := tracing.Call(ctx, s.rpc, "bdev_aio_delete", &params1, &result1)
 	if err1 != nil {
-		log.Printf("error: %v", err1)
+		logging.FromContext(ctx).Error(err1)
 		return nil, err1
 	}
-	log.Printf("Received from SPDK: %v", result1)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result1)
 	if !result1 {
 		msg := fmt.Sprintf("Could not delete Aio Dev: %s", in.AioController.Handle.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	params2 := spdk.BdevAioCreateParams{
 		Name:      in.AioController.Handle.Value,
-		BlockSize: 512,
+		BlockSize: s.getAioBlockSize(),
 		Filename:  in.AioController.Filename,
 	}
 	var result2 spdk.BdevAioCreateResult
-	err2 := s.rpc.Call("bdev_aio_create", &params2, &result2)
+	err2 := tracing.Call(ctx, s.rpc, "bdev_aio_create", &params2, &result2)
 	if err2 != nil {
-		log.Printf("error: %v", err2)
+		logging.FromContext(ctx).Error(err2)
 		return nil, err2
 	}
-	log.Printf("Received from SPDK: %v", result2)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result2)
 	if result2 == "" {
 		msg := fmt.Sprintf("Could not create Aio Dev: %s", in.AioController.Handle.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	response := &pb.AioController{}
 	err3 := deepcopier.Copy(in.AioController).To(response)
 	if err3 != nil {
-		log.Printf("error: %v", err3)
+		logging.FromContext(ctx).Error(err3)
 		return nil, err3
 	}
+	if err := s.store.Set(aioVolumesTable, in.AioController.Handle.Value, response); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.Volumes.AioVolumes[in.AioController.Handle.Value] = response
+	s.mu.Unlock()
 	return response, nil
 }
 
 // ListAioControllers lists Aio controllers
-func (s *Server) ListAioControllers(_ context.Context, in *pb.ListAioControllersRequest) (*pb.ListAioControllersResponse, error) {
-	log.Printf("ListAioControllers: Received from client: %v", in)
-	size, offset, perr := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+func (s *Server) ListAioControllers(ctx context.Context, in *pb.ListAioControllersRequest) (*pb.ListAioControllersResponse, error) {
+	logging.FromContext(ctx).Infof("ListAioControllers: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	size, offset, perr := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if perr != nil {
-		log.Printf("error: %v", perr)
+		logging.FromContext(ctx).Error(perr)
 		return nil, perr
 	}
 	var result []spdk.BdevGetBdevsResult
-	err := s.rpc.Call("bdev_get_bdevs", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_bdevs", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	token := ""
-	log.Printf("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
+	logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
 	result, hasMoreElements := server.LimitPagination(result, offset, size)
 	if hasMoreElements {
 		token = uuid.New().String()
+		s.mu.Lock()
 		s.Pagination[token] = offset + size
+		s.mu.Unlock()
 	}
 	Blobarray := make([]*pb.AioController, len(result))
 	for i := range result {
@@ -166,43 +222,43 @@ func (s *Server) ListAioControllers(_ context.Context, in *pb.ListAioControllers
 }
 
 // GetAioController gets an Aio controller
-func (s *Server) GetAioController(_ context.Context, in *pb.GetAioControllerRequest) (*pb.AioController, error) {
-	log.Printf("GetAioController: Received from client: %v", in)
+func (s *Server) GetAioController(ctx context.Context, in *pb.GetAioControllerRequest) (*pb.AioController, error) {
+	logging.FromContext(ctx).Infof("GetAioController: Received from client: %v", logging.Redact(in))
 	params := spdk.BdevGetBdevsParams{
 		Name: in.Name,
 	}
 	var result []spdk.BdevGetBdevsResult
-	err := s.rpc.Call("bdev_get_bdevs", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_bdevs", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	return &pb.AioController{Handle: &pc.ObjectKey{Value: result[0].Name}, BlockSize: result[0].BlockSize, BlocksCount: result[0].NumBlocks}, nil
 }
 
 // AioControllerStats gets an Aio controller stats
-func (s *Server) AioControllerStats(_ context.Context, in *pb.AioControllerStatsRequest) (*pb.AioControllerStatsResponse, error) {
-	log.Printf("AioControllerStats: Received from client: %v", in)
+func (s *Server) AioControllerStats(ctx context.Context, in *pb.AioControllerStatsRequest) (*pb.AioControllerStatsResponse, error) {
+	logging.FromContext(ctx).Infof("AioControllerStats: Received from client: %v", logging.Redact(in))
 	params := spdk.BdevGetIostatParams{
 		Name: in.GetHandle().GetValue(),
 	}
 	// See https://mholt.github.io/json-to-go/
 	var result spdk.BdevGetIostatResult
-	err := s.rpc.Call("bdev_get_iostat", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_iostat", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result.Bdevs) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result.Bdevs))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	return &pb.AioControllerStatsResponse{Stats: &pb.VolumeStats{
@@ -217,3 +273,9 @@ func (s *Server) AioControllerStats(_ context.Context, in *pb.AioControllerStats
 		UnmapLatencyTicks: int32(result.Bdevs[0].UnmapLatencyTicks),
 	}}, nil
 }
+
+func (s *Server) getAioBlockSize() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.aioBlockSize
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package backend implememnts the BackEnd APIs (network facing) of the storage Server
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NVMfRemoteControllerAuth are the credentials an NVMf remote controller
+// connects with, set before the controller is created
+type NVMfRemoteControllerAuth struct {
+	// Psk is the name of the keyring key with the TLS pre-shared key used
+	// to secure the connection of NVMe/TCP remote controllers
+	Psk string `json:"psk,omitempty"`
+	// DhchapKey is the name of the keyring key the host authenticates with
+	// using DH-HMAC-CHAP
+	DhchapKey string `json:"dhchap_key,omitempty"`
+	// DhchapCtrlrKey is the name of the keyring key the remote controller
+	// must authenticate with (bidirectional authentication)
+	DhchapCtrlrKey string `json:"dhchap_ctrlr_key,omitempty"`
+}
+
+// SetNVMfRemoteControllerAuthRequest sets the credentials of an NVMf remote controller
+type SetNVMfRemoteControllerAuthRequest struct {
+	// Name is the ID of the NVMf remote controller
+	Name string
+	Auth *NVMfRemoteControllerAuth
+}
+
+// SetNVMfRemoteControllerAuth sets the credentials an NVMf remote controller
+// is going to be created with. Credentials of a created controller cannot
+// be changed since SPDK uses them only when it connects.
+func (s *Server) SetNVMfRemoteControllerAuth(ctx context.Context, in *SetNVMfRemoteControllerAuthRequest) (*NVMfRemoteControllerAuth, error) {
+	logging.FromContext(ctx).Infof("SetNVMfRemoteControllerAuth: Received from client: %v %+v", in.Name, in.Auth)
+	if err := validateAuth(in.Auth); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	unlock := s.locks.Lock(server.LockKey(nvmeVolumesTable, in.Name))
+	defer unlock()
+	s.mu.RLock()
+	_, ok := s.Volumes.NvmeVolumes[in.Name]
+	s.mu.RUnlock()
+	if ok {
+		err := status.Errorf(codes.FailedPrecondition, "credentials of created NVMfRemoteController %s cannot be changed", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	auth := *in.Auth
+	if err := store.SetJSON(s.store, nvmeAuthTable, in.Name, &auth); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Volumes.NvmeAuth[in.Name] = &auth
+	s.mu.Unlock()
+	response := auth
+	return &response, nil
+}
+
+// checkAuth returns InvalidArgument if auth cannot be used for controller or
+// refers to keys missing in the SPDK keyring
+func (s *Server) checkAuth(ctx context.Context, controller *pb.NVMfRemoteController, auth *NVMfRemoteControllerAuth) error {
+	if auth == nil {
+		return nil
+	}
+	if auth.Psk != "" && controller.Trtype != pb.NvmeTransportType_NVME_TRANSPORT_TCP {
+		return status.Errorf(codes.InvalidArgument, "psk of NVMfRemoteController %s requires tcp transport", controller.Id.Value)
+	}
+	return keyring.Check(ctx, s.rpc, auth.Psk, auth.DhchapKey, auth.DhchapCtrlrKey)
+}
+
+// applyDhchap sets the configured DH-HMAC-CHAP digests and groups in SPDK
+func (s *Server) applyDhchap(ctx context.Context) error {
+	s.mu.RLock()
+	cfg := s.dhchap
+	s.mu.RUnlock()
+	if !cfg.Enabled() {
+		return nil
+	}
+	params := bdevNvmeSetOptionsParams{DhchapDigests: cfg.Digests, DhchapDhgroups: cfg.Dhgroups}
+	var result bool
+	err := tracing.Call(ctx, s.rpc, "bdev_nvme_set_options", &params, &result)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if !result {
+		return fmt.Errorf("could not set DH-HMAC-CHAP digests %v and dhgroups %v", cfg.Digests, cfg.Dhgroups)
+	}
+	return nil
+}
+
+func validateAuth(auth *NVMfRemoteControllerAuth) error {
+	if auth == nil {
+		return fmt.Errorf("auth cannot be empty")
+	}
+	if auth.DhchapCtrlrKey != "" && auth.DhchapKey == "" {
+		return fmt.Errorf("dhchap_ctrlr_key requires dhchap_key")
+	}
+	for _, key := range []string{auth.Psk, auth.DhchapKey, auth.DhchapCtrlrKey} {
+		if err := keyring.ValidateName(key); key != "" && err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package backend implememnts the BackEnd APIs (network facing) of the storage Server
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/opiproject/gospdk/spdk"
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBackEnd_SetNVMfRemoteControllerAuth(t *testing.T) {
+	tests := map[string]struct {
+		in      *NVMfRemoteControllerAuth
+		out     *NVMfRemoteControllerAuth
+		errCode codes.Code
+		errMsg  string
+		exist   bool
+	}{
+		"bidirectional authentication": {
+			&NVMfRemoteControllerAuth{DhchapKey: "key0", DhchapCtrlrKey: "ckey0"},
+			&NVMfRemoteControllerAuth{DhchapKey: "key0", DhchapCtrlrKey: "ckey0"},
+			codes.OK,
+			"",
+			false,
+		},
+		"controller key without host key": {
+			&NVMfRemoteControllerAuth{DhchapCtrlrKey: "ckey0"},
+			nil,
+			codes.InvalidArgument,
+			"dhchap_ctrlr_key requires dhchap_key",
+			false,
+		},
+		"invalid key name": {
+			&NVMfRemoteControllerAuth{DhchapKey: "key 0"},
+			nil,
+			codes.InvalidArgument,
+			`key name "key 0" must be 1 to 64 letters, digits, '_', '.' or '-'`,
+			false,
+		},
+		"empty auth": {
+			nil,
+			nil,
+			codes.InvalidArgument,
+			"auth cannot be empty",
+			false,
+		},
+		"already created controller": {
+			&NVMfRemoteControllerAuth{DhchapKey: "key0"},
+			nil,
+			codes.FailedPrecondition,
+			"credentials of created NVMfRemoteController OpiNvme8 cannot be changed",
+			true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+			if tt.exist {
+				s.Volumes.NvmeVolumes["OpiNvme8"] = &pb.NVMfRemoteController{Id: &pc.ObjectKey{Value: "OpiNvme8"}}
+			}
+
+			request := &SetNVMfRemoteControllerAuthRequest{Name: "OpiNvme8", Auth: tt.in}
+			response, err := s.SetNVMfRemoteControllerAuth(context.Background(), request)
+			if !reflect.DeepEqual(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if tt.out != nil && !reflect.DeepEqual(s.Volumes.NvmeAuth["OpiNvme8"], tt.out) {
+				t.Error("expected credentials to be kept", tt.out)
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestBackEnd_AttachControllerParams(t *testing.T) {
+	controller := &pb.NVMfRemoteController{
+		Id:      &pc.ObjectKey{Value: "OpiNvme8"},
+		Trtype:  pb.NvmeTransportType_NVME_TRANSPORT_TCP,
+		Adrfam:  pb.NvmeAddressFamily_NVMF_ADRFAM_IPV4,
+		Traddr:  "127.0.0.1",
+		Trsvcid: 4444,
+		Subnqn:  "nqn.2016-06.io.spdk:cnode1",
+	}
+	tests := map[string]struct {
+		auth      *NVMfRemoteControllerAuth
+		multipath pb.NvmeMultipath
+		want      map[string]interface{}
+	}{
+		"without authentication": {
+			nil,
+			pb.NvmeMultipath_NVME_MULTIPATH_UNSPECIFIED,
+			map[string]interface{}{},
+		},
+		"with authentication": {
+			&NVMfRemoteControllerAuth{DhchapKey: "key0", DhchapCtrlrKey: "ckey0"},
+			pb.NvmeMultipath_NVME_MULTIPATH_UNSPECIFIED,
+			map[string]interface{}{"dhchap_key": "key0", "dhchap_ctrlr_key": "ckey0"},
+		},
+		"with tls": {
+			&NVMfRemoteControllerAuth{Psk: "psk0"},
+			pb.NvmeMultipath_NVME_MULTIPATH_UNSPECIFIED,
+			map[string]interface{}{"psk": "psk0"},
+		},
+		"multipath disabled": {
+			nil,
+			pb.NvmeMultipath_NVME_MULTIPATH_DISABLE,
+			map[string]interface{}{"multipath": "disable"},
+		},
+		"failover": {
+			nil,
+			pb.NvmeMultipath_NVME_MULTIPATH_FAILOVER,
+			map[string]interface{}{"multipath": "failover"},
+		},
+		"multipath": {
+			nil,
+			pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+			map[string]interface{}{"multipath": "multipath"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			controller.Multipath = tt.multipath
+			out, err := json.Marshal(attachControllerParams(controller, tt.auth))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var params map[string]interface{}
+			if err := json.Unmarshal(out, &params); err != nil {
+				t.Fatal(err)
+			}
+			if params["name"] != "OpiNvme8" || params["traddr"] != "127.0.0.1" {
+				t.Error("expected transport of the controller in", params)
+			}
+			for _, key := range []string{"psk", "dhchap_key", "dhchap_ctrlr_key", "multipath"} {
+				if params[key] != tt.want[key] {
+					t.Error("expected", key, tt.want[key], "received", params[key])
+				}
+			}
+		})
+	}
+}
+
+func TestBackEnd_ApplyDhchap(t *testing.T) {
+	rpc := server.CreateTestSpdkStub(map[string]string{
+		"bdev_get_bdevs":            `[]`,
+		"bdev_nvme_get_controllers": `[]`,
+		"bdev_nvme_set_options":     `true`,
+	})
+	s := NewServer(rpc)
+	if err := s.SetDhchap(keyring.Dhchap{Digests: []string{"md5"}}); err == nil {
+		t.Error("expected unknown digest to be rejected")
+	}
+	if err := s.SetDhchap(keyring.Dhchap{Digests: []string{"sha512"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Reconcile(server.ReconcilePolicy{}); err != nil {
+		t.Error("expected options to be applied on reconciliation, received", err)
+	}
+}
+
+func TestBackEnd_CheckAuth(t *testing.T) {
+	rpc := server.CreateTestSpdkStub(map[string]string{
+		"keyring_get_keys": `[{"name":"psk0","path":"/etc/opi/psk0","removed":false}]`,
+	})
+	tests := map[string]struct {
+		trtype  pb.NvmeTransportType
+		auth    *NVMfRemoteControllerAuth
+		errCode codes.Code
+		errMsg  string
+	}{
+		"tls over tcp": {
+			pb.NvmeTransportType_NVME_TRANSPORT_TCP,
+			&NVMfRemoteControllerAuth{Psk: "psk0"},
+			codes.OK,
+			"",
+		},
+		"tls over rdma": {
+			pb.NvmeTransportType_NVME_TRANSPORT_RDMA,
+			&NVMfRemoteControllerAuth{Psk: "psk0"},
+			codes.InvalidArgument,
+			"psk of NVMfRemoteController OpiNvme8 requires tcp transport",
+		},
+		"psk missing in keyring": {
+			pb.NvmeTransportType_NVME_TRANSPORT_TCP,
+			&NVMfRemoteControllerAuth{Psk: "psk1"},
+			codes.InvalidArgument,
+			"key psk1 not found in keyring",
+		},
+		"no credentials": {
+			pb.NvmeTransportType_NVME_TRANSPORT_RDMA,
+			nil,
+			codes.OK,
+			"",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(rpc)
+			controller := &pb.NVMfRemoteController{Id: &pc.ObjectKey{Value: "OpiNvme8"}, Trtype: tt.trtype}
+			err := s.checkAuth(context.Background(), controller, tt.auth)
+			er, ok := status.FromError(err)
+			if !ok {
+				t.Fatal("expected grpc error status")
+			}
+			if er.Code() != tt.errCode {
+				t.Error("error code: expected", tt.errCode, "received", er.Code())
+			}
+			if er.Message() != tt.errMsg {
+				t.Error("error message: expected", tt.errMsg, "received", er.Message())
+			}
+		})
+	}
+}
+
+func TestBackEnd_MultipathController(t *testing.T) {
+	rpc := server.CreateTestSpdkStub(map[string]string{
+		"bdev_nvme_get_controllers": `[{"name":"OpiNvme8","ctrlrs":[{"state":"enabled","trid":` +
+			`{"trtype":"TCP","adrfam":"IPv4","traddr":"127.0.0.1","trsvcid":"4444","subnqn":"nqn.2016-06.io.spdk:cnode1"}},` +
+			`{"state":"enabled","trid":` +
+			`{"trtype":"TCP","adrfam":"IPv4","traddr":"127.0.0.2","trsvcid":"4444","subnqn":"nqn.2016-06.io.spdk:cnode1"}}]}]`,
+	})
+	s := NewServer(rpc)
+	path := &pb.NVMfRemoteController{
+		Id:        &pc.ObjectKey{Value: "OpiNvme9"},
+		Trtype:    pb.NvmeTransportType_NVME_TRANSPORT_TCP,
+		Adrfam:    pb.NvmeAddressFamily_NVMF_ADRFAM_IPV4,
+		Traddr:    "127.0.0.2",
+		Trsvcid:   4444,
+		Subnqn:    "nqn.2016-06.io.spdk:cnode1",
+		Multipath: pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH,
+	}
+	ctx := context.Background()
+
+	params := attachControllerParams(path, nil)
+	if err := s.usePathController(ctx, path, &params); err != nil {
+		t.Fatal(err)
+	}
+	if params.Name != "OpiNvme8" {
+		t.Error("expected path to be attached to OpiNvme8, received", params.Name)
+	}
+
+	detach, err := s.detachControllerParams(ctx, "OpiNvme9", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detach.Name != "OpiNvme8" || detach.Traddr != "127.0.0.2" || detach.Subnqn != path.Subnqn {
+		t.Error("expected only the path to be detached, received", detach)
+	}
+
+	s.Volumes.NvmeVolumes["OpiNvme9"] = path
+	present := s.presentNvmeVolumes(nil)
+	if len(present) != 0 {
+		t.Error("expected no volumes without SPDK controllers, received", present)
+	}
+	var ctrlrs []spdk.BdevNvmeGetControllerResult
+	if err := rpc.Call("bdev_nvme_get_controllers", nil, &ctrlrs); err != nil {
+		t.Fatal(err)
+	}
+	present = s.presentNvmeVolumes(ctrlrs)
+	if _, ok := present["OpiNvme9"]; !ok {
+		t.Error("expected path to be present in SPDK")
+	}
+	if _, ok := present["OpiNvme8"]; ok {
+		t.Error("expected controller of the path not to be adopted")
+	}
+}
@@ -6,10 +6,28 @@
 package backend
 
 import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
 	"github.com/opiproject/gospdk/spdk"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+)
+
+// tables used to persist backend objects
+const (
+	aioVolumesTable  = "aio_volumes"
+	nullVolumesTable = "null_volumes"
+	nvmeVolumesTable = "nvme_volumes"
+	nvmeAuthTable    = "nvme_volumes_auth"
 )
 
+const defaultAioBlockSize = 512
+
 // TODO: can we combine all of volume types into a single list?
 //		 maybe create a volume abstraction like bdev in SPDK?
 
@@ -18,6 +36,7 @@ type VolumeParameters struct {
 	AioVolumes  map[string]*pb.AioController
 	NullVolumes map[string]*pb.NullDebug
 	NvmeVolumes map[string]*pb.NVMfRemoteController
+	NvmeAuth    map[string]*NVMfRemoteControllerAuth
 }
 
 // Server contains backend related OPI services
@@ -27,20 +46,112 @@ type Server struct {
 	pb.UnimplementedAioControllerServiceServer
 
 	rpc        spdk.JSONRPC
+	store      store.Store
 	Volumes    VolumeParameters
 	Pagination map[string]int
+	pageLimits server.PaginationLimits
+
+	// aioBlockSize is the block size of created Aio controllers
+	aioBlockSize int
+	// dhchap is applied to SPDK before NVMf remote controllers are attached
+	dhchap keyring.Dhchap
+
+	// mu guards the volume maps and Pagination, locks serializes operations
+	// on the same volumes while SPDK is called
+	mu    sync.RWMutex
+	locks *server.KeyLocker
 }
 
 // NewServer creates initialized instance of BackEnd server communicating
 // with provided jsonRPC
 func NewServer(jsonRPC spdk.JSONRPC) *Server {
 	return &Server{
-		rpc: jsonRPC,
+		rpc:   jsonRPC,
+		store: store.NewMemoryStore(),
 		Volumes: VolumeParameters{
 			AioVolumes:  make(map[string]*pb.AioController),
 			NullVolumes: make(map[string]*pb.NullDebug),
 			NvmeVolumes: make(map[string]*pb.NVMfRemoteController),
+			NvmeAuth:    make(map[string]*NVMfRemoteControllerAuth),
 		},
-		Pagination: make(map[string]int),
+		Pagination:   make(map[string]int),
+		pageLimits:   server.DefaultPaginationLimits(),
+		aioBlockSize: defaultAioBlockSize,
+		locks:        server.NewKeyLocker(),
+	}
+}
+
+// UseStore makes the server persist its objects in the provided store and
+// restores all objects previously saved there
+func (s *Server) UseStore(st store.Store) error {
+	if st == nil {
+		return errors.New("nil store is not allowed")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	newAio := func() *pb.AioController { return &pb.AioController{} }
+	if err := store.Load(st, aioVolumesTable, s.Volumes.AioVolumes, newAio); err != nil {
+		return err
+	}
+	newNull := func() *pb.NullDebug { return &pb.NullDebug{} }
+	if err := store.Load(st, nullVolumesTable, s.Volumes.NullVolumes, newNull); err != nil {
+		return err
+	}
+	newNvme := func() *pb.NVMfRemoteController { return &pb.NVMfRemoteController{} }
+	if err := store.Load(st, nvmeVolumesTable, s.Volumes.NvmeVolumes, newNvme); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, nvmeAuthTable, s.Volumes.NvmeAuth); err != nil {
+		return err
+	}
+	log.Printf("Restored %d aio, %d null, %d nvme volumes",
+		len(s.Volumes.AioVolumes), len(s.Volumes.NullVolumes), len(s.Volumes.NvmeVolumes))
+	s.store = st
+	return nil
+}
+
+// SetPaginationLimits changes the page sizes used by List calls
+func (s *Server) SetPaginationLimits(limits server.PaginationLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageLimits = limits
+	return nil
+}
+
+// SetAioBlockSize changes the block size used for created Aio controllers
+func (s *Server) SetAioBlockSize(size int) error {
+	if size < defaultAioBlockSize || size&(size-1) != 0 {
+		return fmt.Errorf("aio block size must be a power of two not less than %d, got %d", defaultAioBlockSize, size)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.aioBlockSize = size
+	return nil
+}
+
+// SetDhchap changes the digests and Diffie-Hellman groups offered for
+// DH-HMAC-CHAP by NVMf remote controllers. SPDK accepts them only while no
+// controller is attached, so they are applied on reconciliation with SPDK.
+func (s *Server) SetDhchap(cfg keyring.Dhchap) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dhchap = cfg
+	return nil
+}
+
+// ObjectCounts returns the number of objects managed by the server per type
+func (s *Server) ObjectCounts() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return map[string]int{
+		"aio_controller":         len(s.Volumes.AioVolumes),
+		"null_debug":             len(s.Volumes.NullVolumes),
+		"nvmf_remote_controller": len(s.Volumes.NvmeVolumes),
 	}
 }
@@ -7,17 +7,24 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net"
 	"os"
+	"testing"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/opiproject/gospdk/spdk"
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
 )
 
 // TODO: move test infrastructure code to a separate (test/server) package to avoid duplication
@@ -90,3 +97,83 @@ func dialer(opiSpdkServer *Server) func(context.Context, string) (net.Conn, erro
 		return listener.Dial()
 	}
 }
+
+func TestBackEnd_UseStore(t *testing.T) {
+	st := store.NewMemoryStore()
+	if err := st.Set(nullVolumesTable, testNullVolume.Handle.Value, &testNullVolume); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Set(aioVolumesTable, testAioVolume.Handle.Value, &testAioVolume); err != nil {
+		t.Fatal(err)
+	}
+
+	testEnv := createTestEnvironment(true, []string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`})
+	defer testEnv.Close()
+
+	if err := testEnv.opiSpdkServer.UseStore(nil); err == nil {
+		t.Error("expected error for nil store")
+	}
+	if err := testEnv.opiSpdkServer.UseStore(st); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(testEnv.opiSpdkServer.Volumes.NullVolumes[testNullVolume.Handle.Value], &testNullVolume) {
+		t.Error("expected restored null volume", &testNullVolume, "received", testEnv.opiSpdkServer.Volumes.NullVolumes)
+	}
+	if !proto.Equal(testEnv.opiSpdkServer.Volumes.AioVolumes[testAioVolume.Handle.Value], &testAioVolume) {
+		t.Error("expected restored aio volume", &testAioVolume, "received", testEnv.opiSpdkServer.Volumes.AioVolumes)
+	}
+
+	request := &pb.DeleteNullDebugRequest{Name: testNullVolume.Handle.Value}
+	if _, err := testEnv.client.DeleteNullDebug(testEnv.ctx, request); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := st.Entries(nullVolumesTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Error("expected deleted volume to be removed from store, received", entries)
+	}
+}
+
+// failingStore rejects all writes
+type failingStore struct {
+	store.Store
+}
+
+func (failingStore) Set(string, string, proto.Message) error {
+	return errors.New("disk full")
+}
+
+func (failingStore) Delete(string, string) error {
+	return errors.New("disk full")
+}
+
+func TestBackEnd_StoreFailure(t *testing.T) {
+	opiSpdkServer := NewServer(server.CreateTestSpdkStub(map[string]string{
+		"bdev_null_create": `"mytest"`,
+		"bdev_null_delete": `true`,
+	}))
+	opiSpdkServer.Volumes.NullVolumes[testNullVolume.Handle.Value] = &testNullVolume
+	if err := opiSpdkServer.UseStore(failingStore{store.NewMemoryStore()}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := opiSpdkServer.CreateNullDebug(context.Background(), &pb.CreateNullDebugRequest{
+		NullDebug: &pb.NullDebug{Handle: &pc.ObjectKey{Value: "unsaved"}},
+	})
+	if status.Code(err) != codes.Internal {
+		t.Error("expected Internal error on create, received", err)
+	}
+	if _, ok := opiSpdkServer.Volumes.NullVolumes["unsaved"]; ok {
+		t.Error("expected volume which was not persisted to be unknown")
+	}
+
+	_, err = opiSpdkServer.DeleteNullDebug(context.Background(), &pb.DeleteNullDebugRequest{Name: testNullVolume.Handle.Value})
+	if status.Code(err) != codes.Internal {
+		t.Error("expected Internal error on delete, received", err)
+	}
+	if _, ok := opiSpdkServer.Volumes.NullVolumes[testNullVolume.Handle.Value]; !ok {
+		t.Error("expected volume whose deletion was not persisted to be kept")
+	}
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package backend implememnts the BackEnd APIs (network facing) of the storage Server
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+func TestBackEnd_Concurrency(t *testing.T) {
+	jsonRPC := server.CreateTestSpdkStub(map[string]string{
+		"bdev_aio_create":             `"aio"`,
+		"bdev_aio_delete":             `true`,
+		"bdev_null_create":            `"null"`,
+		"bdev_null_delete":            `true`,
+		"bdev_nvme_attach_controller": `["nvme"]`,
+		"bdev_nvme_detach_controller": `true`,
+		"bdev_get_bdevs":              `[]`,
+		"bdev_nvme_get_controllers":   `[]`,
+	})
+	opiSpdkServer := NewServer(jsonRPC)
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx,
+		"",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer(opiSpdkServer)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.CloseGrpcConnection(conn)
+	aioClient := pb.NewAioControllerServiceClient(conn)
+	nullClient := pb.NewNullDebugServiceClient(conn)
+	nvmeClient := pb.NewNVMfRemoteControllerServiceClient(conn)
+
+	const workers = 20
+	run := func(work func(name string)) {
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				// every volume name is shared by two workers to race on the same key
+				work(fmt.Sprintf("volume-%d", i/2))
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	run(func(name string) {
+		_, err := aioClient.CreateAioController(ctx, &pb.CreateAioControllerRequest{
+			AioController: &pb.AioController{Handle: &pc.ObjectKey{Value: name}, Filename: "/tmp/aio_bdev_file"},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		_, err = aioClient.UpdateAioController(ctx, &pb.UpdateAioControllerRequest{
+			AioController: &pb.AioController{Handle: &pc.ObjectKey{Value: name}, Filename: "/tmp/aio_bdev_file"},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		_, err = nullClient.CreateNullDebug(ctx, &pb.CreateNullDebugRequest{
+			NullDebug: &pb.NullDebug{Handle: &pc.ObjectKey{Value: name}},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		_, err = nvmeClient.CreateNVMfRemoteController(ctx, &pb.CreateNVMfRemoteControllerRequest{
+			NvMfRemoteController: &pb.NVMfRemoteController{Id: &pc.ObjectKey{Value: name}, Traddr: "127.0.0.1", Trsvcid: 4444},
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		if _, err := aioClient.ListAioControllers(ctx, &pb.ListAioControllersRequest{}); err != nil {
+			t.Error(err)
+		}
+		if _, err := nvmeClient.ListNVMfRemoteControllers(ctx, &pb.ListNVMfRemoteControllersRequest{}); err != nil {
+			t.Error(err)
+		}
+	})
+	if len(opiSpdkServer.Volumes.AioVolumes) != workers/2 {
+		t.Error("expected", workers/2, "aio volumes, received", len(opiSpdkServer.Volumes.AioVolumes))
+	}
+
+	run(func(name string) {
+		_, err := aioClient.DeleteAioController(ctx, &pb.DeleteAioControllerRequest{Name: name, AllowMissing: true})
+		if err != nil {
+			t.Error(err)
+		}
+		_, err = nullClient.DeleteNullDebug(ctx, &pb.DeleteNullDebugRequest{Name: name, AllowMissing: true})
+		if err != nil {
+			t.Error(err)
+		}
+		_, err = nvmeClient.DeleteNVMfRemoteController(ctx, &pb.DeleteNVMfRemoteControllerRequest{Name: name, AllowMissing: true})
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	if len(opiSpdkServer.Volumes.AioVolumes) != 0 {
+		t.Error("expected all aio volumes to be deleted, received", opiSpdkServer.Volumes.AioVolumes)
+	}
+	if len(opiSpdkServer.Volumes.NullVolumes) != 0 {
+		t.Error("expected all null volumes to be deleted, received", opiSpdkServer.Volumes.NullVolumes)
+	}
+	if len(opiSpdkServer.Volumes.NvmeVolumes) != 0 {
+		t.Error("expected all nvme volumes to be deleted, received", opiSpdkServer.Volumes.NvmeVolumes)
+	}
+	entries, err := opiSpdkServer.store.Entries(nullVolumesTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Error("expected all null volumes to be removed from store, received", entries)
+	}
+}
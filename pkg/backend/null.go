@@ -8,12 +8,13 @@ package backend
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/opiproject/gospdk/spdk"
 	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 
 	"github.com/google/uuid"
 	"github.com/ulule/deepcopier"
@@ -22,140 +23,209 @@ import (
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
+// geometry of created Null debugs
+const (
+	defaultNullBlockSize   = 512
+	defaultNullBlocksCount = 64
+)
+
 // CreateNullDebug creates a Null Debug instance
-func (s *Server) CreateNullDebug(_ context.Context, in *pb.CreateNullDebugRequest) (*pb.NullDebug, error) {
-	log.Printf("CreateNullDebug: Received from client: %v", in)
+func (s *Server) CreateNullDebug(ctx context.Context, in *pb.CreateNullDebugRequest) (*pb.NullDebug, error) {
+	logging.FromContext(ctx).Infof("CreateNullDebug: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(nullVolumesTable, in.NullDebug.Handle.Value))
+	defer unlock()
 	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
 	volume, ok := s.Volumes.NullVolumes[in.NullDebug.Handle.Value]
+	s.mu.RUnlock()
 	if ok {
-		log.Printf("Already existing NullDebug with id %v", in.NullDebug.Handle.Value)
+		logging.FromContext(ctx).Infof("Already existing NullDebug with id %v", in.NullDebug.Handle.Value)
 		return volume, nil
 	}
 	// not found, so create a new one
 	params := spdk.BdevNullCreateParams{
 		Name:      in.NullDebug.Handle.Value,
-		BlockSize: 512,
-		NumBlocks: 64,
+		BlockSize: defaultNullBlockSize,
+		NumBlocks: defaultNullBlocksCount,
 	}
 	var result spdk.BdevNullCreateResult
-	err := s.rpc.Call("bdev_null_create", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_null_create", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if result == "" {
 		msg := fmt.Sprintf("Could not create Null Dev: %s", in.NullDebug.Handle.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	response := &pb.NullDebug{}
 	err = deepcopier.Copy(in.NullDebug).To(response)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
+	// the stored geometry is the one the bdev is re-created with
+	response.BlockSize = defaultNullBlockSize
+	response.BlocksCount = defaultNullBlocksCount
+	if err := s.store.Set(nullVolumesTable, in.NullDebug.Handle.Value, response); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.Volumes.NullVolumes[in.NullDebug.Handle.Value] = response
+	s.mu.Unlock()
 	return response, nil
 }
 
 // DeleteNullDebug deletes a Null Debug instance
-func (s *Server) DeleteNullDebug(_ context.Context, in *pb.DeleteNullDebugRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteNullDebug: Received from client: %v", in)
+func (s *Server) DeleteNullDebug(ctx context.Context, in *pb.DeleteNullDebugRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteNullDebug: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(nullVolumesTable, in.Name))
+	defer unlock()
+	s.mu.RLock()
 	volume, ok := s.Volumes.NullVolumes[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		if in.AllowMissing {
 			return &emptypb.Empty{}, nil
 		}
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	params := spdk.BdevNullDeleteParams{
 		Name: in.Name,
 	}
 	var result spdk.BdevNullDeleteResult
-	err := s.rpc.Call("bdev_null_delete", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_null_delete", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
 		msg := fmt.Sprintf("Could not delete Null Dev: %s", volume.Handle.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
+	if err := s.store.Delete(nullVolumesTable, volume.Handle.Value); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	delete(s.Volumes.NullVolumes, volume.Handle.Value)
+	s.mu.Unlock()
 	return &emptypb.Empty{}, nil
 }
 
-// UpdateNullDebug updates a Null Debug instance
-func (s *Server) UpdateNullDebug(_ context.Context, in *pb.UpdateNullDebugRequest) (*pb.NullDebug, error) {
-	log.Printf("UpdateNullDebug: Received from client: %v", in)
+// UpdateNullDebug updates a Null Debug instance. A larger blocks_count
+// grows a created Null Debug online, it cannot shrink.
+func (s *Server) UpdateNullDebug(ctx context.Context, in *pb.UpdateNullDebugRequest) (*pb.NullDebug, error) {
+	logging.FromContext(ctx).Infof("UpdateNullDebug: Received from client: %v", logging.Redact(in))
+	if in.GetNullDebug().GetHandle().GetValue() == "" {
+		err := status.Error(codes.InvalidArgument, "null_debug.handle cannot be empty")
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	fields, err := server.UpdatedFields(in.UpdateMask, "blocks_count")
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	unlock := s.locks.Lock(server.LockKey(nullVolumesTable, in.NullDebug.Handle.Value))
+	defer unlock()
+	s.mu.RLock()
+	volume, ok := s.Volumes.NullVolumes[in.NullDebug.Handle.Value]
+	s.mu.RUnlock()
+	if ok && fields["blocks_count"] && in.NullDebug.BlocksCount != 0 && in.NullDebug.BlocksCount != volume.BlocksCount {
+		response, err := s.growNullDebug(ctx, volume, in.NullDebug.BlocksCount)
+		if err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		return response, nil
+	}
+	blockSize, blocksCount := defaultNullBlockSize, defaultNullBlocksCount
+	if ok {
+		blockSize, blocksCount = nullGeometry(volume)
+	}
 	params1 := spdk.BdevNullDeleteParams{
 		Name: in.NullDebug.Handle.Value,
 	}
 	var result1 spdk.BdevNullDeleteResult
-	err1 := s.rpc.Call("bdev_null_delete", &params1, &result1)
+	err1 := tracing.Call(ctx, s.rpc, "bdev_null_delete", &params1, &result1)
 	if err1 != nil {
-		log.Printf("error: %v", err1)
+		logging.FromContext(ctx).Error(err1)
 		return nil, err1
 	}
-	log.Printf("Received from SPDK: %v", result1)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result1)
 	if !result1 {
 		msg := fmt.Sprintf("Could not delete Null Dev: %s", in.NullDebug.Handle.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	params2 := spdk.BdevNullCreateParams{
 		Name:      in.NullDebug.Handle.Value,
-		BlockSize: 512,
-		NumBlocks: 64,
+		BlockSize: blockSize,
+		NumBlocks: blocksCount,
 	}
 	var result2 spdk.BdevNullCreateResult
-	err2 := s.rpc.Call("bdev_null_create", &params2, &result2)
+	err2 := tracing.Call(ctx, s.rpc, "bdev_null_create", &params2, &result2)
 	if err2 != nil {
-		log.Printf("error: %v", err2)
+		logging.FromContext(ctx).Error(err2)
 		return nil, err2
 	}
-	log.Printf("Received from SPDK: %v", result2)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result2)
 	if result2 == "" {
 		msg := fmt.Sprintf("Could not create Null Dev: %s", in.NullDebug.Handle.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	response := &pb.NullDebug{}
 	err3 := deepcopier.Copy(in.NullDebug).To(response)
 	if err3 != nil {
-		log.Printf("error: %v", err3)
+		logging.FromContext(ctx).Error(err3)
 		return nil, err3
 	}
+	response.BlockSize = int64(blockSize)
+	response.BlocksCount = int64(blocksCount)
+	if err := s.store.Set(nullVolumesTable, in.NullDebug.Handle.Value, response); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.Volumes.NullVolumes[in.NullDebug.Handle.Value] = response
+	s.mu.Unlock()
 	return response, nil
 }
 
 // ListNullDebugs lists Null Debug instances
-func (s *Server) ListNullDebugs(_ context.Context, in *pb.ListNullDebugsRequest) (*pb.ListNullDebugsResponse, error) {
-	log.Printf("ListNullDebugs: Received from client: %v", in)
-	size, offset, perr := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+func (s *Server) ListNullDebugs(ctx context.Context, in *pb.ListNullDebugsRequest) (*pb.ListNullDebugsResponse, error) {
+	logging.FromContext(ctx).Infof("ListNullDebugs: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	size, offset, perr := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if perr != nil {
-		log.Printf("error: %v", perr)
+		logging.FromContext(ctx).Error(perr)
 		return nil, perr
 	}
 	var result []spdk.BdevGetBdevsResult
-	err := s.rpc.Call("bdev_get_bdevs", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_bdevs", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	token := ""
-	log.Printf("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
+	logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
 	result, hasMoreElements := server.LimitPagination(result, offset, size)
 	if hasMoreElements {
 		token = uuid.New().String()
+		s.mu.Lock()
 		s.Pagination[token] = offset + size
+		s.mu.Unlock()
 	}
 	Blobarray := make([]*pb.NullDebug, len(result))
 	for i := range result {
@@ -166,43 +236,43 @@ func (s *Server) ListNullDebugs(_ context.Context, in *pb.ListNullDebugsRequest)
 }
 
 // GetNullDebug gets a a Null Debug instance
-func (s *Server) GetNullDebug(_ context.Context, in *pb.GetNullDebugRequest) (*pb.NullDebug, error) {
-	log.Printf("GetNullDebug: Received from client: %v", in)
+func (s *Server) GetNullDebug(ctx context.Context, in *pb.GetNullDebugRequest) (*pb.NullDebug, error) {
+	logging.FromContext(ctx).Infof("GetNullDebug: Received from client: %v", logging.Redact(in))
 	params := spdk.BdevGetBdevsParams{
 		Name: in.Name,
 	}
 	var result []spdk.BdevGetBdevsResult
-	err := s.rpc.Call("bdev_get_bdevs", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_bdevs", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	return &pb.NullDebug{Handle: &pc.ObjectKey{Value: result[0].Name}, Uuid: &pc.Uuid{Value: result[0].UUID}, BlockSize: result[0].BlockSize, BlocksCount: result[0].NumBlocks}, nil
 }
 
 // NullDebugStats gets a Null Debug instance stats
-func (s *Server) NullDebugStats(_ context.Context, in *pb.NullDebugStatsRequest) (*pb.NullDebugStatsResponse, error) {
-	log.Printf("NullDebugStats: Received from client: %v", in)
+func (s *Server) NullDebugStats(ctx context.Context, in *pb.NullDebugStatsRequest) (*pb.NullDebugStatsResponse, error) {
+	logging.FromContext(ctx).Infof("NullDebugStats: Received from client: %v", logging.Redact(in))
 	params := spdk.BdevGetIostatParams{
 		Name: in.Handle.Value,
 	}
 	// See https://mholt.github.io/json-to-go/
 	var result spdk.BdevGetIostatResult
-	err := s.rpc.Call("bdev_get_iostat", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_iostat", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result.Bdevs) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result.Bdevs))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	return &pb.NullDebugStatsResponse{Stats: &pb.VolumeStats{
@@ -217,3 +287,16 @@ func (s *Server) NullDebugStats(_ context.Context, in *pb.NullDebugStatsRequest)
 		UnmapLatencyTicks: int32(result.Bdevs[0].UnmapLatencyTicks),
 	}}, nil
 }
+
+// nullGeometry returns the block size and count a Null debug was created
+// with. Volumes stored without them were created with the defaults.
+func nullGeometry(volume *pb.NullDebug) (int, int) {
+	blockSize, blocksCount := int(volume.BlockSize), int(volume.BlocksCount)
+	if blockSize == 0 {
+		blockSize = defaultNullBlockSize
+	}
+	if blocksCount == 0 {
+		blocksCount = defaultNullBlocksCount
+	}
+	return blockSize, blocksCount
+}
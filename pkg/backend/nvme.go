@@ -7,14 +7,15 @@ package backend
 import (
 	"context"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
 	"github.com/opiproject/gospdk/spdk"
 	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 
 	"github.com/google/uuid"
 	"github.com/ulule/deepcopier"
@@ -24,150 +25,288 @@ import (
 )
 
 // CreateNVMfRemoteController creates an NVMf remote controller
-func (s *Server) CreateNVMfRemoteController(_ context.Context, in *pb.CreateNVMfRemoteControllerRequest) (*pb.NVMfRemoteController, error) {
-	log.Printf("CreateNVMfRemoteController: Received from client: %v", in)
+func (s *Server) CreateNVMfRemoteController(ctx context.Context, in *pb.CreateNVMfRemoteControllerRequest) (*pb.NVMfRemoteController, error) {
+	logging.FromContext(ctx).Infof("CreateNVMfRemoteController: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(nvmeVolumesTable, in.NvMfRemoteController.Id.Value))
+	defer unlock()
 	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
 	volume, ok := s.Volumes.NvmeVolumes[in.NvMfRemoteController.Id.Value]
+	s.mu.RUnlock()
 	if ok {
-		log.Printf("Already existing NVMfRemoteController with id %v", in.NvMfRemoteController.Id.Value)
+		logging.FromContext(ctx).Infof("Already existing NVMfRemoteController with id %v", in.NvMfRemoteController.Id.Value)
 		return volume, nil
 	}
 	// not found, so create a new one
-	params := spdk.BdevNvmeAttachControllerParams{
-		Name:    in.NvMfRemoteController.Id.Value,
-		Trtype:  strings.ReplaceAll(in.NvMfRemoteController.Trtype.String(), "NVME_TRANSPORT_", ""),
-		Traddr:  in.NvMfRemoteController.Traddr,
-		Adrfam:  strings.ReplaceAll(in.NvMfRemoteController.Adrfam.String(), "NVMF_ADRFAM_", ""),
-		Trsvcid: fmt.Sprint(in.NvMfRemoteController.Trsvcid),
-		Subnqn:  in.NvMfRemoteController.Subnqn,
-		Hostnqn: in.NvMfRemoteController.Hostnqn,
-		Hdgst:   in.NvMfRemoteController.Hdgst,
-		Ddgst:   in.NvMfRemoteController.Ddgst,
+	s.mu.RLock()
+	auth := s.Volumes.NvmeAuth[in.NvMfRemoteController.Id.Value]
+	s.mu.RUnlock()
+	if err := s.checkAuth(ctx, in.NvMfRemoteController, auth); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	params := attachControllerParams(in.NvMfRemoteController, auth)
+	if err := s.usePathController(ctx, in.NvMfRemoteController, &params); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
 	}
 	var result []spdk.BdevNvmeAttachControllerResult
-	err := s.rpc.Call("bdev_nvme_attach_controller", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_nvme_attach_controller", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result) != 1 {
-		log.Printf("expecting exactly 1 result")
+		logging.FromContext(ctx).Infof("expecting exactly 1 result")
 	}
 	response := &pb.NVMfRemoteController{}
 	err = deepcopier.Copy(in.NvMfRemoteController).To(response)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
+	if err := s.store.Set(nvmeVolumesTable, in.NvMfRemoteController.Id.Value, response); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.Volumes.NvmeVolumes[in.NvMfRemoteController.Id.Value] = response
+	s.mu.Unlock()
 	return response, nil
 }
 
 // DeleteNVMfRemoteController deletes an NVMf remote controller
-func (s *Server) DeleteNVMfRemoteController(_ context.Context, in *pb.DeleteNVMfRemoteControllerRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteNVMfRemoteController: Received from client: %v", in)
+func (s *Server) DeleteNVMfRemoteController(ctx context.Context, in *pb.DeleteNVMfRemoteControllerRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteNVMfRemoteController: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(nvmeVolumesTable, in.Name))
+	defer unlock()
+	s.mu.RLock()
 	volume, ok := s.Volumes.NvmeVolumes[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		if in.AllowMissing {
 			return &emptypb.Empty{}, nil
 		}
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v -> %v", err, volume)
+		logging.FromContext(ctx).Errorf("%v -> %v", err, volume)
 		// return nil, err
 	}
-	params := spdk.BdevNvmeDetachControllerParams{
-		Name: in.Name,
+	params, err := s.detachControllerParams(ctx, in.Name, volume)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
 	}
 	var result spdk.BdevNvmeDetachControllerResult
-	err := s.rpc.Call("bdev_nvme_detach_controller", &params, &result)
+	err = tracing.Call(ctx, s.rpc, "bdev_nvme_detach_controller", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	// delete(s.Volumes.NvmeVolumes, volume.Id.Value)
+	if err := s.store.Delete(nvmeVolumesTable, in.Name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	if err := s.store.Delete(nvmeAuthTable, in.Name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	delete(s.Volumes.NvmeVolumes, in.Name)
+	delete(s.Volumes.NvmeAuth, in.Name)
+	s.mu.Unlock()
 	return &emptypb.Empty{}, nil
 }
 
 // NVMfRemoteControllerReset resets an NVMf remote controller
-func (s *Server) NVMfRemoteControllerReset(_ context.Context, in *pb.NVMfRemoteControllerResetRequest) (*emptypb.Empty, error) {
-	log.Printf("Received: %v", in.GetId())
+func (s *Server) NVMfRemoteControllerReset(ctx context.Context, in *pb.NVMfRemoteControllerResetRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("Received: %v", in.GetId())
 	return &emptypb.Empty{}, nil
 }
 
 // ListNVMfRemoteControllers lists an NVMf remote controllers
-func (s *Server) ListNVMfRemoteControllers(_ context.Context, in *pb.ListNVMfRemoteControllersRequest) (*pb.ListNVMfRemoteControllersResponse, error) {
-	log.Printf("ListNVMfRemoteControllers: Received from client: %v", in)
-	size, offset, perr := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+func (s *Server) ListNVMfRemoteControllers(ctx context.Context, in *pb.ListNVMfRemoteControllersRequest) (*pb.ListNVMfRemoteControllersResponse, error) {
+	logging.FromContext(ctx).Infof("ListNVMfRemoteControllers: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	size, offset, perr := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if perr != nil {
-		log.Printf("error: %v", perr)
+		logging.FromContext(ctx).Error(perr)
 		return nil, perr
 	}
 	var result []spdk.BdevNvmeGetControllerResult
-	err := s.rpc.Call("bdev_nvme_get_controllers", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_nvme_get_controllers", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	token := ""
-	log.Printf("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
+	logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
 	result, hasMoreElements := server.LimitPagination(result, offset, size)
 	if hasMoreElements {
 		token = uuid.New().String()
+		s.mu.Lock()
 		s.Pagination[token] = offset + size
+		s.mu.Unlock()
 	}
 	Blobarray := make([]*pb.NVMfRemoteController, len(result))
 	for i := range result {
-		r := &result[i]
-		port, _ := strconv.ParseInt(r.Ctrlrs[0].Trid.Trsvcid, 10, 64)
-		Blobarray[i] = &pb.NVMfRemoteController{
-			Id:      &pc.ObjectKey{Value: r.Name},
-			Hostnqn: r.Ctrlrs[0].Host.Nqn,
-			Trtype:  pb.NvmeTransportType(pb.NvmeTransportType_value["NVME_TRANSPORT_"+strings.ToUpper(r.Ctrlrs[0].Trid.Trtype)]),
-			Adrfam:  pb.NvmeAddressFamily(pb.NvmeAddressFamily_value["NVMF_ADRFAM_"+strings.ToUpper(r.Ctrlrs[0].Trid.Adrfam)]),
-			Traddr:  r.Ctrlrs[0].Trid.Traddr,
-			Subnqn:  r.Ctrlrs[0].Trid.Subnqn,
-			Trsvcid: port,
-		}
+		Blobarray[i] = remoteControllerFromSpdk(&result[i])
 	}
 	return &pb.ListNVMfRemoteControllersResponse{NvMfRemoteControllers: Blobarray, NextPageToken: token}, nil
 }
 
 // GetNVMfRemoteController gets an NVMf remote controller
-func (s *Server) GetNVMfRemoteController(_ context.Context, in *pb.GetNVMfRemoteControllerRequest) (*pb.NVMfRemoteController, error) {
-	log.Printf("GetNVMfRemoteController: Received from client: %v", in)
+func (s *Server) GetNVMfRemoteController(ctx context.Context, in *pb.GetNVMfRemoteControllerRequest) (*pb.NVMfRemoteController, error) {
+	logging.FromContext(ctx).Infof("GetNVMfRemoteController: Received from client: %v", logging.Redact(in))
 	params := spdk.BdevNvmeGetControllerParams{
 		Name: in.Name,
 	}
 	var result []spdk.BdevNvmeGetControllerResult
-	err := s.rpc.Call("bdev_nvme_get_controllers", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_nvme_get_controllers", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
-	port, _ := strconv.ParseInt(result[0].Ctrlrs[0].Trid.Trsvcid, 10, 64)
-	return &pb.NVMfRemoteController{
-		Id:      &pc.ObjectKey{Value: result[0].Name},
-		Hostnqn: result[0].Ctrlrs[0].Host.Nqn,
-		Trtype:  pb.NvmeTransportType(pb.NvmeTransportType_value["NVME_TRANSPORT_"+strings.ToUpper(result[0].Ctrlrs[0].Trid.Trtype)]),
-		Adrfam:  pb.NvmeAddressFamily(pb.NvmeAddressFamily_value["NVMF_ADRFAM_"+strings.ToUpper(result[0].Ctrlrs[0].Trid.Adrfam)]),
-		Traddr:  result[0].Ctrlrs[0].Trid.Traddr,
-		Subnqn:  result[0].Ctrlrs[0].Trid.Subnqn,
-		Trsvcid: port,
-	}, nil
+	return remoteControllerFromSpdk(&result[0]), nil
 }
 
 // NVMfRemoteControllerStats gets NVMf remote controller stats
-func (s *Server) NVMfRemoteControllerStats(_ context.Context, in *pb.NVMfRemoteControllerStatsRequest) (*pb.NVMfRemoteControllerStatsResponse, error) {
-	log.Printf("Received: %v", in.GetId())
+func (s *Server) NVMfRemoteControllerStats(ctx context.Context, in *pb.NVMfRemoteControllerStatsRequest) (*pb.NVMfRemoteControllerStatsResponse, error) {
+	logging.FromContext(ctx).Infof("Received: %v", in.GetId())
 	return &pb.NVMfRemoteControllerStatsResponse{Stats: &pb.VolumeStats{ReadOpsCount: -1, WriteOpsCount: -1}}, nil
 }
+
+func attachControllerParams(c *pb.NVMfRemoteController, auth *NVMfRemoteControllerAuth) bdevNvmeAttachControllerParams {
+	params := bdevNvmeAttachControllerParams{
+		BdevNvmeAttachControllerParams: spdk.BdevNvmeAttachControllerParams{
+			Name:    c.Id.Value,
+			Trtype:  strings.ReplaceAll(c.Trtype.String(), "NVME_TRANSPORT_", ""),
+			Traddr:  c.Traddr,
+			Adrfam:  strings.ReplaceAll(c.Adrfam.String(), "NVMF_ADRFAM_", ""),
+			Trsvcid: fmt.Sprint(c.Trsvcid),
+			Subnqn:  c.Subnqn,
+			Hostnqn: c.Hostnqn,
+			Hdgst:   c.Hdgst,
+			Ddgst:   c.Ddgst,
+		},
+		Multipath: multipathMode(c.Multipath),
+	}
+	if auth != nil {
+		params.Psk = auth.Psk
+		params.DhchapKey = auth.DhchapKey
+		params.DhchapCtrlrKey = auth.DhchapCtrlrKey
+	}
+	return params
+}
+
+// multipathMode returns the SPDK multipath mode of m or empty string to
+// use the SPDK default
+func multipathMode(m pb.NvmeMultipath) string {
+	switch m {
+	case pb.NvmeMultipath_NVME_MULTIPATH_DISABLE:
+		return "disable"
+	case pb.NvmeMultipath_NVME_MULTIPATH_FAILOVER:
+		return "failover"
+	case pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH:
+		return "multipath"
+	default:
+		return ""
+	}
+}
+
+// isPath returns true if c is attached as another path to an SPDK
+// controller connected to the same subsystem
+func isPath(c *pb.NVMfRemoteController) bool {
+	return c.Multipath == pb.NvmeMultipath_NVME_MULTIPATH_FAILOVER ||
+		c.Multipath == pb.NvmeMultipath_NVME_MULTIPATH_MULTIPATH
+}
+
+// usePathController makes params attach c under the name of the SPDK
+// controller already connected to the subsystem of c, if there is one,
+// since SPDK adds paths only to controllers of the same name
+func (s *Server) usePathController(ctx context.Context, c *pb.NVMfRemoteController, params *bdevNvmeAttachControllerParams) error {
+	if !isPath(c) {
+		return nil
+	}
+	var ctrlrs []spdk.BdevNvmeGetControllerResult
+	err := tracing.Call(ctx, s.rpc, "bdev_nvme_get_controllers", nil, &ctrlrs)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", ctrlrs)
+	for i := range ctrlrs {
+		for _, ctrlr := range ctrlrs[i].Ctrlrs {
+			if ctrlr.Trid.Subnqn == c.Subnqn {
+				params.Name = ctrlrs[i].Name
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// detachControllerParams returns params detaching only the path of volume
+// if it was attached as one, otherwise the whole SPDK controller name
+func (s *Server) detachControllerParams(ctx context.Context, name string, volume *pb.NVMfRemoteController) (bdevNvmeDetachControllerParams, error) {
+	params := bdevNvmeDetachControllerParams{
+		BdevNvmeDetachControllerParams: spdk.BdevNvmeDetachControllerParams{Name: name},
+	}
+	if volume == nil || !isPath(volume) {
+		return params, nil
+	}
+	var ctrlrs []spdk.BdevNvmeGetControllerResult
+	err := tracing.Call(ctx, s.rpc, "bdev_nvme_get_controllers", nil, &ctrlrs)
+	if err != nil {
+		return params, err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", ctrlrs)
+	for i := range ctrlrs {
+		if hasPath(&ctrlrs[i], volume) {
+			attach := attachControllerParams(volume, nil)
+			params.Name = ctrlrs[i].Name
+			params.Trtype = attach.Trtype
+			params.Traddr = attach.Traddr
+			params.Adrfam = attach.Adrfam
+			params.Trsvcid = attach.Trsvcid
+			params.Subnqn = attach.Subnqn
+			break
+		}
+	}
+	return params, nil
+}
+
+// hasPath returns true if the SPDK controller is connected through the
+// transport address of c
+func hasPath(r *spdk.BdevNvmeGetControllerResult, c *pb.NVMfRemoteController) bool {
+	for _, ctrlr := range r.Ctrlrs {
+		if strings.EqualFold(ctrlr.Trid.Trtype, strings.ReplaceAll(c.Trtype.String(), "NVME_TRANSPORT_", "")) &&
+			ctrlr.Trid.Traddr == c.Traddr && ctrlr.Trid.Trsvcid == fmt.Sprint(c.Trsvcid) &&
+			ctrlr.Trid.Subnqn == c.Subnqn {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteControllerFromSpdk(r *spdk.BdevNvmeGetControllerResult) *pb.NVMfRemoteController {
+	port, _ := strconv.ParseInt(r.Ctrlrs[0].Trid.Trsvcid, 10, 64)
+	return &pb.NVMfRemoteController{
+		Id:      &pc.ObjectKey{Value: r.Name},
+		Hostnqn: r.Ctrlrs[0].Host.Nqn,
+		Trtype:  pb.NvmeTransportType(pb.NvmeTransportType_value["NVME_TRANSPORT_"+strings.ToUpper(r.Ctrlrs[0].Trid.Trtype)]),
+		Adrfam:  pb.NvmeAddressFamily(pb.NvmeAddressFamily_value["NVMF_ADRFAM_"+strings.ToUpper(r.Ctrlrs[0].Trid.Adrfam)]),
+		Traddr:  r.Ctrlrs[0].Trid.Traddr,
+		Subnqn:  r.Ctrlrs[0].Trid.Subnqn,
+		Trsvcid: port,
+	}
+}
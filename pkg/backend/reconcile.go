@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package backend implememnts the BackEnd APIs (network facing) of the storage Server
+package backend
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/opiproject/gospdk/spdk"
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+)
+
+// Reconcile compares Aio, Null and NVMe volumes known to the server with the
+// bdevs and NVMe controllers present in SPDK and handles the differences
+// according to the policy
+func (s *Server) Reconcile(policy server.ReconcilePolicy) ([]server.Drift, error) {
+	// no object can be changed by other operations during reconciliation
+	unlock := s.locks.LockAll()
+	defer unlock()
+
+	var bdevs []bdev
+	// reconciliation is not a part of any request, so SPDK calls start new traces
+	err := tracing.Call(context.Background(), s.rpc, "bdev_get_bdevs", nil, &bdevs)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return nil, err
+	}
+	log.Printf("Received from SPDK: %v", bdevs)
+	var ctrlrs []spdk.BdevNvmeGetControllerResult
+	err = tracing.Call(context.Background(), s.rpc, "bdev_nvme_get_controllers", nil, &ctrlrs)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return nil, err
+	}
+	log.Printf("Received from SPDK: %v", ctrlrs)
+	// SPDK accepts options only until the first controller is attached
+	if len(ctrlrs) == 0 {
+		if err := s.applyDhchap(context.Background()); err != nil {
+			log.Printf("error: %v", err)
+			return nil, err
+		}
+	}
+
+	aioBdevs := make(map[string]*bdev)
+	nullBdevs := make(map[string]*bdev)
+	for i := range bdevs {
+		switch bdevs[i].ProductName {
+		case aioProductName:
+			aioBdevs[bdevs[i].Name] = &bdevs[i]
+		case nullProductName:
+			nullBdevs[bdevs[i].Name] = &bdevs[i]
+		}
+	}
+	nvmeCtrlrs := s.presentNvmeVolumes(ctrlrs)
+
+	drifts := server.ReconcileObjects("AioController", policy,
+		s.Volumes.AioVolumes, aioBdevs, s.recreateAioController, s.adoptAioController)
+	drifts = append(drifts, server.ReconcileObjects("NullDebug", policy,
+		s.Volumes.NullVolumes, nullBdevs, s.recreateNullDebug, s.adoptNullDebug)...)
+	drifts = append(drifts, server.ReconcileObjects("NVMfRemoteController", policy,
+		s.Volumes.NvmeVolumes, nvmeCtrlrs, s.recreateNVMfRemoteController, s.adoptNVMfRemoteController)...)
+	return drifts, nil
+}
+
+// presentNvmeVolumes maps IDs of NVMe volumes to the SPDK controllers they
+// are attached to. Volumes attached as paths share the SPDK controller of
+// the first volume of their subsystem, its name is not adopted again.
+func (s *Server) presentNvmeVolumes(ctrlrs []spdk.BdevNvmeGetControllerResult) map[string]*spdk.BdevNvmeGetControllerResult {
+	present := make(map[string]*spdk.BdevNvmeGetControllerResult, len(ctrlrs))
+	for i := range ctrlrs {
+		if len(ctrlrs[i].Ctrlrs) != 0 {
+			present[ctrlrs[i].Name] = &ctrlrs[i]
+		}
+	}
+	claimed := make(map[string]bool)
+	for id, volume := range s.Volumes.NvmeVolumes {
+		if !isPath(volume) {
+			continue
+		}
+		for i := range ctrlrs {
+			if ctrlrs[i].Name != id && hasPath(&ctrlrs[i], volume) {
+				present[id] = &ctrlrs[i]
+				claimed[ctrlrs[i].Name] = true
+			}
+		}
+	}
+	for name := range claimed {
+		if _, ok := s.Volumes.NvmeVolumes[name]; !ok {
+			delete(present, name)
+		}
+	}
+	return present
+}
+
+func (s *Server) recreateAioController(volume *pb.AioController) error {
+	params := spdk.BdevAioCreateParams{
+		Name:      volume.Handle.Value,
+		BlockSize: s.getAioBlockSize(),
+		Filename:  volume.Filename,
+	}
+	var result spdk.BdevAioCreateResult
+	err := tracing.Call(context.Background(), s.rpc, "bdev_aio_create", &params, &result)
+	if err != nil {
+		return err
+	}
+	log.Printf("Received from SPDK: %v", result)
+	if result == "" {
+		return fmt.Errorf("could not create Aio Dev: %s", volume.Handle.Value)
+	}
+	return nil
+}
+
+func (s *Server) recreateNullDebug(volume *pb.NullDebug) error {
+	blockSize, blocksCount := nullGeometry(volume)
+	params := spdk.BdevNullCreateParams{
+		Name:      volume.Handle.Value,
+		BlockSize: blockSize,
+		NumBlocks: blocksCount,
+	}
+	var result spdk.BdevNullCreateResult
+	err := tracing.Call(context.Background(), s.rpc, "bdev_null_create", &params, &result)
+	if err != nil {
+		return err
+	}
+	log.Printf("Received from SPDK: %v", result)
+	if result == "" {
+		return fmt.Errorf("could not create Null Dev: %s", volume.Handle.Value)
+	}
+	return nil
+}
+
+func (s *Server) recreateNVMfRemoteController(volume *pb.NVMfRemoteController) error {
+	params := attachControllerParams(volume, s.Volumes.NvmeAuth[volume.Id.Value])
+	if err := s.usePathController(context.Background(), volume, &params); err != nil {
+		return err
+	}
+	var result []spdk.BdevNvmeAttachControllerResult
+	err := tracing.Call(context.Background(), s.rpc, "bdev_nvme_attach_controller", &params, &result)
+	if err != nil {
+		return err
+	}
+	log.Printf("Received from SPDK: %v", result)
+	if len(result) == 0 {
+		return fmt.Errorf("could not attach NVMf controller: %s", volume.Id.Value)
+	}
+	return nil
+}
+
+func (s *Server) adoptAioController(name string, b *bdev) error {
+	volume := &pb.AioController{
+		Handle:      &pc.ObjectKey{Value: name},
+		BlockSize:   b.BlockSize,
+		BlocksCount: b.NumBlocks,
+	}
+	if b.DriverSpecific.Aio != nil {
+		volume.Filename = b.DriverSpecific.Aio.Filename
+	}
+	if err := s.store.Set(aioVolumesTable, name, volume); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.Volumes.AioVolumes[name] = volume
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Server) adoptNullDebug(name string, b *bdev) error {
+	volume := &pb.NullDebug{
+		Handle:      &pc.ObjectKey{Value: name},
+		BlockSize:   b.BlockSize,
+		BlocksCount: b.NumBlocks,
+	}
+	if err := s.store.Set(nullVolumesTable, name, volume); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.Volumes.NullVolumes[name] = volume
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Server) adoptNVMfRemoteController(name string, ctrlr *spdk.BdevNvmeGetControllerResult) error {
+	volume := remoteControllerFromSpdk(ctrlr)
+	if err := s.store.Set(nvmeVolumesTable, name, volume); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.Volumes.NvmeVolumes[name] = volume
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package backend implememnts the BackEnd APIs (network facing) of the storage Server
+package backend
+
+import (
+	"reflect"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+func TestBackEnd_Reconcile(t *testing.T) {
+	aioVolume := &pb.AioController{
+		Handle:   &pc.ObjectKey{Value: "aio-test"},
+		Filename: "/tmp/aio_bdev_file",
+	}
+	nullVolume := &pb.NullDebug{
+		Handle: &pc.ObjectKey{Value: "null-test"},
+	}
+	nvmeVolume := &pb.NVMfRemoteController{
+		Id:      &pc.ObjectKey{Value: "OpiNvme8"},
+		Trtype:  pb.NvmeTransportType_NVME_TRANSPORT_TCP,
+		Adrfam:  pb.NvmeAddressFamily_NVMF_ADRFAM_IPV4,
+		Traddr:  "127.0.0.1",
+		Trsvcid: 4444,
+		Subnqn:  "nqn.2016-06.io.spdk:cnode1",
+	}
+	bdevsInSync := `{"id":%d,"error":{"code":0,"message":""},"result":[` +
+		`{"name":"aio-test","product_name":"AIO disk","block_size":512,"num_blocks":12,"driver_specific":{"aio":{"filename":"/tmp/aio_bdev_file"}}},` +
+		`{"name":"null-test","product_name":"Null disk","block_size":512,"num_blocks":64},` +
+		`{"name":"Malloc0","product_name":"Malloc disk","block_size":512,"num_blocks":64}]}`
+	ctrlrsInSync := `{"id":%d,"error":{"code":0,"message":""},"result":[{"name":"OpiNvme8","ctrlrs":[{"state":"enabled",` +
+		`"trid":{"trtype":"TCP","adrfam":"IPv4","traddr":"127.0.0.1","trsvcid":"4444","subnqn":"nqn.2016-06.io.spdk:cnode1"},` +
+		`"host":{"nqn":"","addr":"","svcid":""}}]}]}`
+	empty := `{"id":%d,"error":{"code":0,"message":""},"result":[]}`
+
+	tests := map[string]struct {
+		policy  server.ReconcilePolicy
+		spdk    []string
+		known   bool
+		drifts  []server.Drift
+		errMsg  string
+		adopted bool
+	}{
+		"in sync": {
+			server.ReconcilePolicy{Recreate: true, Adopt: true},
+			[]string{bdevsInSync, ctrlrsInSync},
+			true,
+			nil,
+			"",
+			false,
+		},
+		"missing volumes reported": {
+			server.ReconcilePolicy{},
+			[]string{empty, empty},
+			true,
+			[]server.Drift{
+				{Object: "AioController", ID: "aio-test", Kind: server.DriftMissingInSpdk, Resolution: "reported"},
+				{Object: "NullDebug", ID: "null-test", Kind: server.DriftMissingInSpdk, Resolution: "reported"},
+				{Object: "NVMfRemoteController", ID: "OpiNvme8", Kind: server.DriftMissingInSpdk, Resolution: "reported"},
+			},
+			"",
+			false,
+		},
+		"missing volumes recreated": {
+			server.ReconcilePolicy{Recreate: true},
+			[]string{empty, empty,
+				`{"id":%d,"error":{"code":0,"message":""},"result":"aio-test"}`,
+				`{"id":%d,"error":{"code":0,"message":""},"result":""}`,
+				`{"id":%d,"error":{"code":0,"message":""},"result":["OpiNvme8n1"]}`},
+			true,
+			[]server.Drift{
+				{Object: "AioController", ID: "aio-test", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+				{Object: "NullDebug", ID: "null-test", Kind: server.DriftMissingInSpdk, Resolution: "failed to recreate: could not create Null Dev: null-test"},
+				{Object: "NVMfRemoteController", ID: "OpiNvme8", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+			},
+			"",
+			false,
+		},
+		"unknown volumes adopted": {
+			server.ReconcilePolicy{Adopt: true},
+			[]string{bdevsInSync, ctrlrsInSync},
+			false,
+			[]server.Drift{
+				{Object: "AioController", ID: "aio-test", Kind: server.DriftUnknownToBridge, Resolution: "adopted"},
+				{Object: "NullDebug", ID: "null-test", Kind: server.DriftUnknownToBridge, Resolution: "adopted"},
+				{Object: "NVMfRemoteController", ID: "OpiNvme8", Kind: server.DriftUnknownToBridge, Resolution: "adopted"},
+			},
+			"",
+			true,
+		},
+		"error from SPDK": {
+			server.ReconcilePolicy{},
+			[]string{empty, `{"id":%d,"error":{"code":1,"message":"myopierr"},"result":[]}`},
+			true,
+			nil,
+			"bdev_nvme_get_controllers: json response error: myopierr",
+			false,
+		},
+	}
+
+	// run tests
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+
+			if tt.known {
+				testEnv.opiSpdkServer.Volumes.AioVolumes[aioVolume.Handle.Value] = aioVolume
+				testEnv.opiSpdkServer.Volumes.NullVolumes[nullVolume.Handle.Value] = nullVolume
+				testEnv.opiSpdkServer.Volumes.NvmeVolumes[nvmeVolume.Id.Value] = nvmeVolume
+			}
+
+			drifts, err := testEnv.opiSpdkServer.Reconcile(tt.policy)
+			if !reflect.DeepEqual(drifts, tt.drifts) {
+				t.Error("drifts: expected", tt.drifts, "received", drifts)
+			}
+			if err != nil && err.Error() != tt.errMsg {
+				t.Error("error: expected", tt.errMsg, "received", err)
+			}
+			if err == nil && tt.errMsg != "" {
+				t.Error("expected error", tt.errMsg)
+			}
+
+			if tt.adopted {
+				aio := testEnv.opiSpdkServer.Volumes.AioVolumes["aio-test"]
+				if aio == nil || aio.Filename != "/tmp/aio_bdev_file" || aio.BlocksCount != 12 {
+					t.Error("expected adopted aio volume, received", testEnv.opiSpdkServer.Volumes.AioVolumes)
+				}
+				if _, ok := testEnv.opiSpdkServer.Volumes.NullVolumes["null-test"]; !ok {
+					t.Error("expected adopted null volume, received", testEnv.opiSpdkServer.Volumes.NullVolumes)
+				}
+				nvme := testEnv.opiSpdkServer.Volumes.NvmeVolumes["OpiNvme8"]
+				if nvme == nil || nvme.Subnqn != nvmeVolume.Subnqn || nvme.Trsvcid != nvmeVolume.Trsvcid {
+					t.Error("expected adopted nvme volume, received", testEnv.opiSpdkServer.Volumes.NvmeVolumes)
+				}
+				if _, ok := testEnv.opiSpdkServer.Volumes.AioVolumes["Malloc0"]; ok {
+					t.Error("expected Malloc0 not to be adopted")
+				}
+			}
+		})
+	}
+}
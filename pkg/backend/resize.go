@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package backend implememnts the BackEnd APIs (network facing) of the storage Server
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opiproject/gospdk/spdk"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const mib = 1024 * 1024
+
+// growNullDebug resizes volume online to blocksCount blocks. SPDK notifies
+// the users of the bdev about the new size, so NVMe hosts get a namespace
+// attribute changed notice and virtio devices a configuration change.
+func (s *Server) growNullDebug(ctx context.Context, volume *pb.NullDebug, blocksCount int64) (*pb.NullDebug, error) {
+	blockSize, oldCount := nullGeometry(volume)
+	if blocksCount < int64(oldCount) {
+		return nil, status.Errorf(codes.InvalidArgument, "volume %s cannot shrink from %d to %d blocks",
+			volume.Handle.Value, oldCount, blocksCount)
+	}
+	size := blocksCount * int64(blockSize)
+	if size%mib != 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "size of volume %s must be a multiple of 1 MiB, got %d blocks of %d bytes",
+			volume.Handle.Value, blocksCount, blockSize)
+	}
+	params := bdevNullResizeParams{Name: volume.Handle.Value, NewSize: size / mib}
+	if err := s.callResize(ctx, "bdev_null_resize", &params, volume.Handle.Value); err != nil {
+		return nil, err
+	}
+	response := proto.Clone(volume).(*pb.NullDebug)
+	response.BlockSize = int64(blockSize)
+	response.BlocksCount = blocksCount
+	if err := s.store.Set(nullVolumesTable, response.Handle.Value, response); err != nil {
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Volumes.NullVolumes[response.Handle.Value] = response
+	s.mu.Unlock()
+	return response, nil
+}
+
+// growAioController makes volume take the size of its file, which has to
+// be grown to at least blocksCount blocks before
+func (s *Server) growAioController(ctx context.Context, volume *pb.AioController, blocksCount int64) (*pb.AioController, error) {
+	old, err := s.getBdev(ctx, volume.Handle.Value)
+	if err != nil {
+		return nil, err
+	}
+	if blocksCount < old.NumBlocks {
+		return nil, status.Errorf(codes.InvalidArgument, "volume %s cannot shrink from %d to %d blocks",
+			volume.Handle.Value, old.NumBlocks, blocksCount)
+	}
+	params := bdevAioRescanParams{Name: volume.Handle.Value}
+	if err := s.callResize(ctx, "bdev_aio_rescan", &params, volume.Handle.Value); err != nil {
+		return nil, err
+	}
+	resized, err := s.getBdev(ctx, volume.Handle.Value)
+	if err != nil {
+		return nil, err
+	}
+	if resized.NumBlocks < blocksCount {
+		return nil, status.Errorf(codes.FailedPrecondition, "volume %s holds only %d blocks", volume.Handle.Value, resized.NumBlocks)
+	}
+	response := proto.Clone(volume).(*pb.AioController)
+	response.BlockSize = resized.BlockSize
+	response.BlocksCount = resized.NumBlocks
+	if err := s.store.Set(aioVolumesTable, response.Handle.Value, response); err != nil {
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Volumes.AioVolumes[response.Handle.Value] = response
+	s.mu.Unlock()
+	return response, nil
+}
+
+func (s *Server) callResize(ctx context.Context, method string, params interface{}, name string) error {
+	var result bool
+	err := tracing.Call(ctx, s.rpc, method, params, &result)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if !result {
+		return status.Errorf(codes.InvalidArgument, "Could not resize volume: %s", name)
+	}
+	return nil
+}
+
+func (s *Server) getBdev(ctx context.Context, name string) (*bdev, error) {
+	params := spdk.BdevGetBdevsParams{
+		Name: name,
+	}
+	var result []bdev
+	err := tracing.Call(ctx, s.rpc, "bdev_get_bdevs", &params, &result)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if len(result) != 1 {
+		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
+		return nil, status.Errorf(codes.InvalidArgument, msg)
+	}
+	return &result[0], nil
+}
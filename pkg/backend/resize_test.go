@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package backend implememnts the BackEnd APIs (network facing) of the storage Server
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/opiproject/gospdk/spdk"
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// resizeSpdkStub reports the bdev with its new size once it was resized
+type resizeSpdkStub struct {
+	spdk.JSONRPC
+	bdev    string
+	resized string
+	params  string
+}
+
+func (s *resizeSpdkStub) Call(method string, params, result interface{}) error {
+	switch method {
+	case "bdev_get_bdevs":
+		return json.Unmarshal([]byte(s.bdev), result)
+	case "bdev_aio_rescan", "bdev_null_resize":
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		s.params = method + " " + string(data)
+		s.bdev = s.resized
+	}
+	return s.JSONRPC.Call(method, params, result)
+}
+
+func TestBackEnd_GrowVolume(t *testing.T) {
+	tests := map[string]struct {
+		product     string
+		blocksCount int64
+		mask        []string
+		resized     int64
+		params      string
+		out         int64
+		errCode     codes.Code
+		errMsg      string
+	}{
+		"grown file of aio": {
+			aioProductName,
+			8192,
+			[]string{"blocks_count"},
+			8192,
+			`bdev_aio_rescan {"name":"volume-test"}`,
+			8192,
+			codes.OK,
+			"",
+		},
+		"aio file grown more": {
+			aioProductName,
+			4096,
+			nil,
+			8192,
+			`bdev_aio_rescan {"name":"volume-test"}`,
+			8192,
+			codes.OK,
+			"",
+		},
+		"aio file not grown enough": {
+			aioProductName,
+			8192,
+			[]string{"blocks_count"},
+			4096,
+			`bdev_aio_rescan {"name":"volume-test"}`,
+			0,
+			codes.FailedPrecondition,
+			"volume volume-test holds only 4096 blocks",
+		},
+		"aio shrink": {
+			aioProductName,
+			1024,
+			[]string{"blocks_count"},
+			0,
+			"",
+			0,
+			codes.InvalidArgument,
+			"volume volume-test cannot shrink from 2048 to 1024 blocks",
+		},
+		"null": {
+			nullProductName,
+			4096,
+			[]string{"blocks_count"},
+			4096,
+			`bdev_null_resize {"name":"volume-test","new_size":2}`,
+			4096,
+			codes.OK,
+			"",
+		},
+		"null size not in MiB": {
+			nullProductName,
+			4097,
+			[]string{"blocks_count"},
+			0,
+			"",
+			0,
+			codes.InvalidArgument,
+			"size of volume volume-test must be a multiple of 1 MiB, got 4097 blocks of 512 bytes",
+		},
+		"null shrink": {
+			nullProductName,
+			1024,
+			nil,
+			0,
+			"",
+			0,
+			codes.InvalidArgument,
+			"volume volume-test cannot shrink from 2048 to 1024 blocks",
+		},
+		"field not updatable": {
+			nullProductName,
+			4096,
+			[]string{"block_size"},
+			0,
+			"",
+			0,
+			codes.InvalidArgument,
+			`field "block_size" cannot be updated, updatable fields: blocks_count`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			bdev := `[{"name":"volume-test","product_name":"%s","block_size":512,"num_blocks":%d}]`
+			rpc := &resizeSpdkStub{
+				JSONRPC: server.CreateTestSpdkStub(map[string]string{
+					"bdev_aio_rescan":  `true`,
+					"bdev_null_resize": `true`,
+				}),
+				bdev:    fmt.Sprintf(bdev, tt.product, 2048),
+				resized: fmt.Sprintf(bdev, tt.product, tt.resized),
+			}
+			s := NewServer(rpc)
+			handle := &pc.ObjectKey{Value: "volume-test"}
+			mask := &fieldmaskpb.FieldMask{Paths: tt.mask}
+			var blocksCount int64
+			var err error
+			switch tt.product {
+			case aioProductName:
+				s.Volumes.AioVolumes[handle.Value] = &pb.AioController{Handle: handle, BlockSize: 512, BlocksCount: 2048}
+				var response *pb.AioController
+				response, err = s.UpdateAioController(context.Background(), &pb.UpdateAioControllerRequest{
+					AioController: &pb.AioController{Handle: handle, BlocksCount: tt.blocksCount}, UpdateMask: mask})
+				blocksCount = response.GetBlocksCount()
+			case nullProductName:
+				s.Volumes.NullVolumes[handle.Value] = &pb.NullDebug{Handle: handle, BlockSize: 512, BlocksCount: 2048}
+				var response *pb.NullDebug
+				response, err = s.UpdateNullDebug(context.Background(), &pb.UpdateNullDebugRequest{
+					NullDebug: &pb.NullDebug{Handle: handle, BlocksCount: tt.blocksCount}, UpdateMask: mask})
+				blocksCount = response.GetBlocksCount()
+			}
+			if blocksCount != tt.out {
+				t.Error("response: expected", tt.out, "blocks, received", blocksCount)
+			}
+			if rpc.params != tt.params {
+				t.Error("expected SPDK call", tt.params, "received", rpc.params)
+			}
+			if tt.errCode == codes.OK {
+				if volume, ok := s.Volumes.AioVolumes[handle.Value]; ok && volume.BlocksCount != tt.out {
+					t.Error("expected blocks count of Aio controller", tt.out, "received", volume.BlocksCount)
+				}
+				if volume, ok := s.Volumes.NullVolumes[handle.Value]; ok && volume.BlocksCount != tt.out {
+					t.Error("expected blocks count of Null debug", tt.out, "received", volume.BlocksCount)
+				}
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
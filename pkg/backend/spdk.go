@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package backend implememnts the BackEnd APIs (network facing) of the storage Server
+package backend
+
+import "github.com/opiproject/gospdk/spdk"
+
+// SPDK JSON-RPC structures which are used by the backend and are not
+// provided by gospdk
+
+// product names reported by SPDK for the bdev types created by the backend
+const (
+	aioProductName  = "AIO disk"
+	nullProductName = "Null disk"
+)
+
+// bdev is an entry of bdev_get_bdevs result including driver specific data
+type bdev struct {
+	spdk.BdevGetBdevsResult
+	ProductName    string `json:"product_name"`
+	DriverSpecific struct {
+		Aio *struct {
+			Filename string `json:"filename"`
+		} `json:"aio"`
+	} `json:"driver_specific"`
+}
+
+// bdevNvmeAttachControllerParams are params of bdev_nvme_attach_controller
+// including the fields missing in gospdk
+type bdevNvmeAttachControllerParams struct {
+	spdk.BdevNvmeAttachControllerParams
+	Psk            string `json:"psk,omitempty"`
+	DhchapKey      string `json:"dhchap_key,omitempty"`
+	DhchapCtrlrKey string `json:"dhchap_ctrlr_key,omitempty"`
+	Multipath      string `json:"multipath,omitempty"`
+}
+
+// bdevNvmeDetachControllerParams are params of bdev_nvme_detach_controller
+// including the fields selecting a single path of the controller
+type bdevNvmeDetachControllerParams struct {
+	spdk.BdevNvmeDetachControllerParams
+	Trtype  string `json:"trtype,omitempty"`
+	Traddr  string `json:"traddr,omitempty"`
+	Adrfam  string `json:"adrfam,omitempty"`
+	Trsvcid string `json:"trsvcid,omitempty"`
+	Subnqn  string `json:"subnqn,omitempty"`
+}
+
+// bdevNvmeSetOptionsParams are params of bdev_nvme_set_options
+type bdevNvmeSetOptionsParams struct {
+	DhchapDigests  []string `json:"dhchap_digests,omitempty"`
+	DhchapDhgroups []string `json:"dhchap_dhgroups,omitempty"`
+}
+
+// bdevAioRescanParams are params of bdev_aio_rescan
+type bdevAioRescanParams struct {
+	Name string `json:"name"`
+}
+
+// bdevNullResizeParams are params of bdev_null_resize
+type bdevNullResizeParams struct {
+	Name    string `json:"name"`
+	NewSize int64  `json:"new_size"`
+}
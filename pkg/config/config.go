@@ -0,0 +1,289 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package config implements the configuration of the bridge read from
+// a file, environment variables and command line flags
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
+	"github.com/opiproject/opi-spdk-bridge/pkg/kvm"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+)
+
+// EnvPrefix starts the names of all environment variables overriding
+// the configuration, e.g. OPI_SPDK_BRIDGE_PORT or OPI_SPDK_BRIDGE_PAGINATION_MAX_PAGE_SIZE
+const EnvPrefix = "OPI_SPDK_BRIDGE_"
+
+// configFileEnv points to the configuration file if -config flag is not set
+const configFileEnv = EnvPrefix + "CONFIG"
+
+const minAioBlockSize = 512
+
+// KvmTimeouts defines how long to wait for QEMU to add or remove a device
+type KvmTimeouts struct {
+	// Timeout is the maximum time to wait for a device
+	Timeout time.Duration `yaml:"timeout"`
+	// PollDevicePresenceStep is the interval between device presence checks
+	PollDevicePresenceStep time.Duration `yaml:"poll_device_presence_step"`
+}
+
+// Metrics defines where Prometheus metrics are served
+type Metrics struct {
+	// Address to serve /metrics on, metrics are disabled if empty
+	Address string `yaml:"addr"`
+	// IostatInterval is the interval between scrapes of bdev I/O statistics
+	IostatInterval time.Duration `yaml:"iostat_interval"`
+}
+
+// Config contains all settings of the bridge
+type Config struct {
+	Port                    int    `yaml:"port"`
+	SpdkAddress             string `yaml:"spdk_addr"`
+	Kvm                     bool   `yaml:"kvm"`
+	QmpAddress              string `yaml:"qmp_addr"`
+	CtrlrDir                string `yaml:"ctrlr_dir"`
+	TCPTransportListenAddr  string `yaml:"tcp_trid"`
+	TCPSecureChannel        bool   `yaml:"tcp_secure_channel"`
+	RDMATransportListenAddr string `yaml:"rdma_trid"`
+	NvmeTransport           string `yaml:"nvme_transport"`
+	Store                   string `yaml:"store"`
+	Reconcile               string `yaml:"reconcile"`
+	AuthzPolicy             string `yaml:"authz_policy"`
+	NvmeAllowAnyHost        bool   `yaml:"nvme_allow_any_host"`
+	NvmeAnaReporting        bool   `yaml:"nvme_ana_reporting"`
+
+	AioBlockSize int                     `yaml:"aio_block_size"`
+	Pagination   server.PaginationLimits `yaml:"pagination"`
+	KvmTimeouts  KvmTimeouts             `yaml:"kvm_timeouts"`
+	TLS          server.TLSConfig        `yaml:"tls"`
+	Metrics      Metrics                 `yaml:"metrics"`
+	Tracing      tracing.Config          `yaml:"tracing"`
+	Logging      logging.Config          `yaml:"logging"`
+	NvmeDhchap   keyring.Dhchap          `yaml:"nvme_dhchap"`
+}
+
+// Default returns the configuration used when nothing is overridden
+func Default() *Config {
+	return &Config{
+		Port:                    50051,
+		SpdkAddress:             "/var/tmp/spdk.sock",
+		Kvm:                     false,
+		QmpAddress:              "127.0.0.1:5555",
+		CtrlrDir:                "",
+		TCPTransportListenAddr:  "127.0.0.1:4420",
+		TCPSecureChannel:        false,
+		RDMATransportListenAddr: "127.0.0.1:4420",
+		NvmeTransport:           "",
+		Store:                   "",
+		Reconcile:               "report",
+		AuthzPolicy:             "",
+		NvmeAllowAnyHost:        true,
+		NvmeAnaReporting:        false,
+		AioBlockSize:            minAioBlockSize,
+		Pagination:              server.DefaultPaginationLimits(),
+		KvmTimeouts: KvmTimeouts{
+			Timeout:                kvm.DefaultTimeout,
+			PollDevicePresenceStep: kvm.DefaultPollDevicePresenceStep,
+		},
+		Metrics: Metrics{
+			Address:        "",
+			IostatInterval: 10 * time.Second,
+		},
+		Tracing: tracing.Config{
+			Exporter:     tracing.ExporterNone,
+			OtlpEndpoint: "localhost:4317",
+		},
+		Logging: logging.Config{
+			Level:  "info",
+			Format: logging.FormatJSON,
+		},
+	}
+}
+
+// Parse builds the configuration from defaults, a configuration file,
+// environment variables and command line args, each one overriding the previous.
+// The flags are registered in fs, lookupEnv is used to read environment variables.
+func Parse(fs *flag.FlagSet, args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	var path string
+	fs.StringVar(&path, "config", "", "YAML or JSON file to read the configuration from. Environment variables "+EnvPrefix+"* and flags override its values")
+	cfg.registerFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	// remember explicitly set flags to apply them on top of file and environment
+	setFlags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = f.Value.String()
+	})
+	if path == "" {
+		path, _ = lookupEnv(configFileEnv)
+	}
+
+	*cfg = *Default()
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.applyEnv(lookupEnv); err != nil {
+		return nil, err
+	}
+	for name, value := range setFlags {
+		if err := fs.Set(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) registerFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.Port, "port", c.Port, "The Server port")
+	fs.StringVar(&c.SpdkAddress, "spdk_addr", c.SpdkAddress, "Points to SPDK unix socket/tcp socket to interact with")
+	fs.BoolVar(&c.Kvm, "kvm", c.Kvm, "Automates interaction with QEMU to plug/unplug SPDK devices")
+	fs.StringVar(&c.QmpAddress, "qmp_addr", c.QmpAddress, "Points to QMP unix socket/tcp socket to interact with. Valid only with -kvm option")
+	fs.StringVar(&c.CtrlrDir, "ctrlr_dir", c.CtrlrDir, "Directory with created SPDK device unix sockets (-S option in SPDK). Valid only with -kvm option")
+	fs.StringVar(&c.TCPTransportListenAddr, "tcp_trid", c.TCPTransportListenAddr, "ipv4 address:port (aka traddr:trsvcid) or ipv6 [address]:port tuple (aka [traddr]:trsvcid) to listen on for Nvme/TCP transport")
+	fs.BoolVar(&c.TCPSecureChannel, "tcp_secure_channel", c.TCPSecureChannel, "Accept only Nvme/TCP connections secured with TLS using the PSK of the host")
+	fs.StringVar(&c.RDMATransportListenAddr, "rdma_trid", c.RDMATransportListenAddr, "ipv4 address:port (aka traddr:trsvcid) or ipv6 [address]:port tuple (aka [traddr]:trsvcid) to listen on for Nvme/RDMA transport")
+	fs.StringVar(&c.NvmeTransport, "nvme_transport", c.NvmeTransport, "Transport of Nvme controllers created without SetNVMeControllerTransport: tcp, rdma or vfiouser. Empty selects vfiouser with -kvm option and tcp otherwise")
+	fs.StringVar(&c.Store, "store", c.Store, "File to persist created objects in and restore them from on startup. Objects are kept in memory only if empty")
+	fs.StringVar(&c.TLS.CertFile, "tls_cert", c.TLS.CertFile, "Server certificate in PEM format. Enables TLS for the gRPC endpoint together with -tls_key")
+	fs.StringVar(&c.TLS.KeyFile, "tls_key", c.TLS.KeyFile, "Private key of the server certificate in PEM format")
+	fs.StringVar(&c.TLS.ClientCAFile, "tls_client_ca", c.TLS.ClientCAFile, "CA certificates in PEM format to verify client certificates with. Enables mutual TLS")
+	fs.StringVar(&c.AuthzPolicy, "authz_policy", c.AuthzPolicy, "YAML or JSON file mapping client identities to roles allowed to call gRPC methods. All calls are allowed if empty")
+	fs.StringVar(&c.Metrics.Address, "metrics_addr", c.Metrics.Address, "address:port to serve Prometheus metrics on at /metrics. Metrics are disabled if empty")
+	fs.StringVar(&c.Tracing.Exporter, "tracing_exporter", c.Tracing.Exporter, "Where to export OpenTelemetry spans to: otlp or file. Tracing is disabled if empty")
+	fs.StringVar(&c.Tracing.OtlpEndpoint, "tracing_otlp_endpoint", c.Tracing.OtlpEndpoint, "host:port of OTLP/gRPC collector to export spans to with otlp exporter")
+	fs.StringVar(&c.Tracing.File, "tracing_file", c.Tracing.File, "File to append spans to in JSON format with file exporter")
+	fs.StringVar(&c.Logging.Level, "log_level", c.Logging.Level, "Lowest level of written log records: debug, info, warning or error")
+	fs.StringVar(&c.Logging.Format, "log_format", c.Logging.Format, "Format of log records: json or text")
+	fs.BoolVar(&c.NvmeAllowAnyHost, "nvme_allow_any_host", c.NvmeAllowAnyHost, "New Nvme subsystems accept connections from any host. Otherwise only hosts added to their access control list can connect")
+	fs.BoolVar(&c.NvmeAnaReporting, "nvme_ana_reporting", c.NvmeAnaReporting, "New Nvme subsystems report Asymmetric Namespace Access states of their controllers to hosts for multipath")
+	fs.StringVar(&c.Reconcile, "reconcile", c.Reconcile, "Comma separated actions taken on startup and on SIGHUP for differences between created objects and SPDK: report, recreate, adopt")
+}
+
+// loadFile reads the configuration from a YAML file. JSON files are
+// accepted too since JSON is a subset of YAML.
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return fmt.Errorf("cannot open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("cannot parse config file %v: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks that the configuration can be used to start the bridge
+func (c *Config) Validate() error {
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("invalid port: %d", c.Port)
+	}
+	if c.SpdkAddress == "" {
+		return errors.New("spdk_addr cannot be empty")
+	}
+	if err := validateTransportAddress(c.TCPTransportListenAddr); err != nil {
+		return fmt.Errorf("invalid tcp_trid: %w", err)
+	}
+	if err := validateTransportAddress(c.RDMATransportListenAddr); err != nil {
+		return fmt.Errorf("invalid rdma_trid: %w", err)
+	}
+	switch c.NvmeTransport {
+	case "", "tcp", "rdma":
+	case "vfiouser":
+		if !c.Kvm {
+			return errors.New("nvme_transport vfiouser requires kvm")
+		}
+	default:
+		return fmt.Errorf("invalid nvme_transport: %q is not tcp, rdma or vfiouser", c.NvmeTransport)
+	}
+	if c.Kvm {
+		if c.QmpAddress == "" {
+			return errors.New("qmp_addr cannot be empty with kvm")
+		}
+		if c.CtrlrDir == "" {
+			return errors.New("ctrlr_dir cannot be empty with kvm")
+		}
+	}
+	if _, err := server.ParseReconcilePolicy(c.Reconcile); err != nil {
+		return fmt.Errorf("invalid reconcile: %w", err)
+	}
+	if c.AioBlockSize < minAioBlockSize || c.AioBlockSize&(c.AioBlockSize-1) != 0 {
+		return fmt.Errorf("invalid aio_block_size: %d is not a power of two not less than %d", c.AioBlockSize, minAioBlockSize)
+	}
+	if err := c.Pagination.Validate(); err != nil {
+		return fmt.Errorf("invalid pagination: %w", err)
+	}
+	if err := c.TLS.Validate(); err != nil {
+		return fmt.Errorf("invalid tls: %w", err)
+	}
+	if _, _, err := net.SplitHostPort(c.Metrics.Address); c.Metrics.Address != "" && err != nil {
+		return fmt.Errorf("invalid metrics addr: %w", err)
+	}
+	if c.Metrics.IostatInterval <= 0 {
+		return fmt.Errorf("invalid metrics iostat_interval: %v", c.Metrics.IostatInterval)
+	}
+	if err := c.Tracing.Validate(); err != nil {
+		return fmt.Errorf("invalid tracing: %w", err)
+	}
+	if err := c.Logging.Validate(); err != nil {
+		return fmt.Errorf("invalid logging: %w", err)
+	}
+	if err := c.NvmeDhchap.Validate(); err != nil {
+		return fmt.Errorf("invalid nvme_dhchap: %w", err)
+	}
+	timeout, step := c.KvmTimeouts.Timeout, c.KvmTimeouts.PollDevicePresenceStep
+	if timeout <= 0 || step <= 0 || step >= timeout {
+		return fmt.Errorf("invalid kvm_timeouts: poll_device_presence_step %v must be positive and less than timeout %v", step, timeout)
+	}
+	return nil
+}
+
+func validateTransportAddress(addr string) error {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	if net.ParseIP(host) == nil {
+		return fmt.Errorf("%v is not an IP address", host)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p <= 0 || p > 65535 {
+		return fmt.Errorf("invalid port %v", port)
+	}
+	return nil
+}
+
+// String returns the configuration in YAML format accepted by -config
+func (c *Config) String() string {
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("cannot print config: %v", err)
+	}
+	return string(out)
+}
@@ -0,0 +1,338 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package config implements the configuration of the bridge read from
+// a file, environment variables and command line flags
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfig_Parse(t *testing.T) {
+	tests := map[string]struct {
+		file    *string
+		env     map[string]string
+		args    []string
+		modify  func(*Config)
+		wantErr bool
+	}{
+		"defaults": {
+			modify:  func(*Config) {},
+			wantErr: false,
+		},
+		"yaml file": {
+			file: newString("port: 50052\nkvm: true\nctrlr_dir: /var/tmp\naio_block_size: 4096\n" +
+				"pagination:\n  default_page_size: 10\n  max_page_size: 20\n" +
+				"kvm_timeouts:\n  timeout: 3s\n  poll_device_presence_step: 10ms\n"),
+			modify: func(c *Config) {
+				c.Port = 50052
+				c.Kvm = true
+				c.CtrlrDir = "/var/tmp"
+				c.AioBlockSize = 4096
+				c.Pagination.DefaultPageSize = 10
+				c.Pagination.MaxPageSize = 20
+				c.KvmTimeouts.Timeout = 3 * time.Second
+				c.KvmTimeouts.PollDevicePresenceStep = 10 * time.Millisecond
+			},
+			wantErr: false,
+		},
+		"json file": {
+			file: newString(`{"spdk_addr": "10.10.10.10:9009", "tcp_trid": "[::1]:4421", "pagination": {"max_page_size": 100}}`),
+			modify: func(c *Config) {
+				c.SpdkAddress = "10.10.10.10:9009"
+				c.TCPTransportListenAddr = "[::1]:4421"
+				c.Pagination.MaxPageSize = 100
+			},
+			wantErr: false,
+		},
+		"empty file": {
+			file:    newString(""),
+			modify:  func(*Config) {},
+			wantErr: false,
+		},
+		"unknown field in file": {
+			file:    newString("prot: 50052\n"),
+			wantErr: true,
+		},
+		"environment overrides file": {
+			file: newString("port: 50052\nstore: /var/lib/bridge.json\n"),
+			env: map[string]string{
+				"OPI_SPDK_BRIDGE_PORT":                                   "50053",
+				"OPI_SPDK_BRIDGE_RECONCILE":                              "recreate,adopt",
+				"OPI_SPDK_BRIDGE_PAGINATION_DEFAULT_PAGE_SIZE":           "25",
+				"OPI_SPDK_BRIDGE_KVM_TIMEOUTS_TIMEOUT":                   "1s",
+				"OPI_SPDK_BRIDGE_KVM_TIMEOUTS_POLL_DEVICE_PRESENCE_STEP": "1ms",
+			},
+			modify: func(c *Config) {
+				c.Port = 50053
+				c.Store = "/var/lib/bridge.json"
+				c.Reconcile = "recreate,adopt"
+				c.Pagination.DefaultPageSize = 25
+				c.KvmTimeouts.Timeout = time.Second
+				c.KvmTimeouts.PollDevicePresenceStep = time.Millisecond
+			},
+			wantErr: false,
+		},
+		"secure tcp listener": {
+			args: []string{"-tcp_secure_channel"},
+			modify: func(c *Config) {
+				c.TCPSecureChannel = true
+			},
+			wantErr: false,
+		},
+		"flags override environment": {
+			env: map[string]string{
+				"OPI_SPDK_BRIDGE_PORT":     "50053",
+				"OPI_SPDK_BRIDGE_TCP_TRID": "127.0.0.1:4422",
+			},
+			args: []string{"-port", "50054"},
+			modify: func(c *Config) {
+				c.Port = 50054
+				c.TCPTransportListenAddr = "127.0.0.1:4422"
+			},
+			wantErr: false,
+		},
+		"tls from environment": {
+			env: map[string]string{
+				"OPI_SPDK_BRIDGE_TLS_CERT_FILE":       "/etc/opi/server.pem",
+				"OPI_SPDK_BRIDGE_TLS_KEY_FILE":        "/etc/opi/server.key",
+				"OPI_SPDK_BRIDGE_TLS_CLIENT_CA_FILE":  "/etc/opi/ca.pem",
+				"OPI_SPDK_BRIDGE_TLS_ALLOWED_CLIENTS": "client1, client2",
+			},
+			args: []string{"-tls_cert", "/etc/opi/other.pem"},
+			modify: func(c *Config) {
+				c.TLS.CertFile = "/etc/opi/other.pem"
+				c.TLS.KeyFile = "/etc/opi/server.key"
+				c.TLS.ClientCAFile = "/etc/opi/ca.pem"
+				c.TLS.AllowedClients = []string{"client1", "client2"}
+			},
+			wantErr: false,
+		},
+		"tls key without certificate": {
+			args:    []string{"-tls_key", "/etc/opi/server.key"},
+			wantErr: true,
+		},
+		"metrics from environment": {
+			env: map[string]string{
+				"OPI_SPDK_BRIDGE_METRICS_ADDR":            ":9090",
+				"OPI_SPDK_BRIDGE_METRICS_IOSTAT_INTERVAL": "30s",
+			},
+			modify: func(c *Config) {
+				c.Metrics.Address = ":9090"
+				c.Metrics.IostatInterval = 30 * time.Second
+			},
+			wantErr: false,
+		},
+		"metrics address without port": {
+			args:    []string{"-metrics_addr", "localhost"},
+			wantErr: true,
+		},
+		"tracing to otlp collector": {
+			env: map[string]string{
+				"OPI_SPDK_BRIDGE_TRACING_EXPORTER":      "otlp",
+				"OPI_SPDK_BRIDGE_TRACING_OTLP_INSECURE": "true",
+			},
+			args: []string{"-tracing_otlp_endpoint", "collector:4317"},
+			modify: func(c *Config) {
+				c.Tracing.Exporter = "otlp"
+				c.Tracing.OtlpEndpoint = "collector:4317"
+				c.Tracing.OtlpInsecure = true
+			},
+			wantErr: false,
+		},
+		"tracing to file without path": {
+			args:    []string{"-tracing_exporter", "file"},
+			wantErr: true,
+		},
+		"text logs at debug level": {
+			env:  map[string]string{"OPI_SPDK_BRIDGE_LOGGING_FORMAT": "text"},
+			args: []string{"-log_level", "debug"},
+			modify: func(c *Config) {
+				c.Logging.Level = "debug"
+				c.Logging.Format = "text"
+			},
+			wantErr: false,
+		},
+		"unknown log level": {
+			args:    []string{"-log_level", "verbose"},
+			wantErr: true,
+		},
+		"unknown log format": {
+			file:    newString("logging:\n  format: xml\n"),
+			wantErr: true,
+		},
+		"nvme subsystems without hosts": {
+			args: []string{"-nvme_allow_any_host=false"},
+			modify: func(c *Config) {
+				c.NvmeAllowAnyHost = false
+			},
+			wantErr: false,
+		},
+		"nvme subsystems with ana reporting": {
+			file: newString("nvme_ana_reporting: true\n"),
+			modify: func(c *Config) {
+				c.NvmeAnaReporting = true
+			},
+			wantErr: false,
+		},
+		"nvme dh-hmac-chap from environment": {
+			env: map[string]string{
+				"OPI_SPDK_BRIDGE_NVME_DHCHAP_DIGESTS":  "sha384,sha512",
+				"OPI_SPDK_BRIDGE_NVME_DHCHAP_DHGROUPS": "ffdhe4096",
+			},
+			modify: func(c *Config) {
+				c.NvmeDhchap.Digests = []string{"sha384", "sha512"}
+				c.NvmeDhchap.Dhgroups = []string{"ffdhe4096"}
+			},
+			wantErr: false,
+		},
+		"unknown nvme dh-hmac-chap digest": {
+			file:    newString("nvme_dhchap:\n  digests: [md5]\n"),
+			wantErr: true,
+		},
+		"invalid environment value": {
+			env:     map[string]string{"OPI_SPDK_BRIDGE_KVM": "maybe"},
+			wantErr: true,
+		},
+		"invalid port": {
+			args:    []string{"-port", "70000"},
+			wantErr: true,
+		},
+		"kvm without ctrlr_dir": {
+			args:    []string{"-kvm"},
+			wantErr: true,
+		},
+		"rdma transport": {
+			env:  map[string]string{"OPI_SPDK_BRIDGE_NVME_TRANSPORT": "rdma"},
+			args: []string{"-rdma_trid", "[fd00::1]:4420"},
+			modify: func(c *Config) {
+				c.NvmeTransport = "rdma"
+				c.RDMATransportListenAddr = "[fd00::1]:4420"
+			},
+			wantErr: false,
+		},
+		"vfiouser transport": {
+			args: []string{"-kvm", "-ctrlr_dir", "/var/tmp", "-nvme_transport", "vfiouser"},
+			modify: func(c *Config) {
+				c.Kvm = true
+				c.CtrlrDir = "/var/tmp"
+				c.NvmeTransport = "vfiouser"
+			},
+			wantErr: false,
+		},
+		"vfiouser transport without kvm": {
+			args:    []string{"-nvme_transport", "vfiouser"},
+			wantErr: true,
+		},
+		"unknown nvme transport": {
+			args:    []string{"-nvme_transport", "fc"},
+			wantErr: true,
+		},
+		"rdma_trid without port": {
+			args:    []string{"-rdma_trid", "10.0.0.1"},
+			wantErr: true,
+		},
+		"tcp_trid is not an ip": {
+			args:    []string{"-tcp_trid", "localhost:4420"},
+			wantErr: true,
+		},
+		"unknown reconcile action": {
+			args:    []string{"-reconcile", "delete"},
+			wantErr: true,
+		},
+		"aio block size is not a power of two": {
+			env:     map[string]string{"OPI_SPDK_BRIDGE_AIO_BLOCK_SIZE": "1000"},
+			wantErr: true,
+		},
+		"max page size less than default": {
+			env:     map[string]string{"OPI_SPDK_BRIDGE_PAGINATION_MAX_PAGE_SIZE": "10"},
+			wantErr: true,
+		},
+		"poll step exceeds timeout": {
+			env:     map[string]string{"OPI_SPDK_BRIDGE_KVM_TIMEOUTS_POLL_DEVICE_PRESENCE_STEP": "1m"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			env := make(map[string]string)
+			for k, v := range tt.env {
+				env[k] = v
+			}
+			if tt.file != nil {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				if err := os.WriteFile(path, []byte(*tt.file), 0600); err != nil {
+					t.Fatal(err)
+				}
+				env[configFileEnv] = path
+			}
+			lookupEnv := func(key string) (string, bool) {
+				v, ok := env[key]
+				return v, ok
+			}
+
+			cfg, err := Parse(flag.NewFlagSet(name, flag.ContinueOnError), tt.args, lookupEnv)
+			if (err != nil) != tt.wantErr {
+				t.Fatal("error: expected", tt.wantErr, "received", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			want := Default()
+			tt.modify(want)
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("expected %v, received %v", want, cfg)
+			}
+		})
+	}
+}
+
+func TestConfig_ConfigFlag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("port: 50055\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	lookupEnv := func(key string) (string, bool) {
+		if key == configFileEnv {
+			return "/missing/config.yaml", true
+		}
+		return "", false
+	}
+
+	cfg, err := Parse(flag.NewFlagSet("test", flag.ContinueOnError), []string{"-config", path}, lookupEnv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 50055 {
+		t.Error("expected port 50055 from -config file, received", cfg.Port)
+	}
+}
+
+func TestConfig_String(t *testing.T) {
+	cfg := Default()
+	cfg.Port = 50056
+	cfg.KvmTimeouts.Timeout = 3 * time.Second
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(cfg.String()), 0600); err != nil {
+		t.Fatal(err)
+	}
+	parsed := Default()
+	if err := parsed.loadFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg, parsed) {
+		t.Errorf("expected printed config %v to be parsed back, received %v", cfg, parsed)
+	}
+}
+
+func newString(s string) *string {
+	return &s
+}
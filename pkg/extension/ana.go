@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+)
+
+// SetNVMeControllerAnaState changes the ANA state reported to hosts for the
+// path through an NVMe controller
+func (s *Server) SetNVMeControllerAnaState(ctx context.Context, in *pe.SetNVMeControllerAnaStateRequest) (*pe.NVMeControllerAna, error) {
+	request := &frontend.SetNVMeControllerAnaStateRequest{Name: in.Name}
+	if in.Ana != nil {
+		request.Ana = &frontend.NVMeControllerAna{AnaState: in.Ana.AnaState}
+	}
+	ana, err := s.frontend.SetNVMeControllerAnaState(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &pe.NVMeControllerAna{AnaState: ana.AnaState}, nil
+}
+
+// GetNVMeControllerAnaState reports the ANA state of the path through an
+// NVMe controller
+func (s *Server) GetNVMeControllerAnaState(ctx context.Context, in *pe.GetNVMeControllerAnaStateRequest) (*pe.NVMeControllerAna, error) {
+	ana, err := s.frontend.GetNVMeControllerAnaState(ctx, &frontend.GetNVMeControllerAnaStateRequest{Name: in.Name})
+	if err != nil {
+		return nil, err
+	}
+	return &pe.NVMeControllerAna{AnaState: ana.AnaState}, nil
+}
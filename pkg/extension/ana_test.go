@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"strings"
+	"testing"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtension_SetNVMeControllerAnaState(t *testing.T) {
+	tests := map[string]struct {
+		in        *pe.NVMeControllerAna
+		out       *pe.NVMeControllerAna
+		spdk      []string
+		reporting bool
+		request   string
+		errCode   codes.Code
+		errMsg    string
+	}{
+		"valid request": {
+			&pe.NVMeControllerAna{AnaState: "inaccessible"},
+			&pe.NVMeControllerAna{AnaState: "inaccessible"},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			true,
+			`"method":"nvmf_subsystem_listener_set_ana_state"`,
+			codes.OK,
+			"",
+		},
+		"missing ana": {
+			nil,
+			nil,
+			[]string{},
+			true,
+			"",
+			codes.InvalidArgument,
+			"ana cannot be empty",
+		},
+		"no ANA reporting": {
+			&pe.NVMeControllerAna{AnaState: "inaccessible"},
+			nil,
+			[]string{},
+			false,
+			"",
+			codes.FailedPrecondition,
+			"ANA reporting is not enabled for NVMeSubsystem subsystem-test",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Subsystems[testSubsystem.Spec.Id.Value] = &testSubsystem
+			testEnv.frontend.Nvme.SubsystemAna[testSubsystem.Spec.Id.Value] = &frontend.NVMeSubsystemAna{Reporting: tt.reporting}
+			testEnv.frontend.Nvme.Controllers[testController.Spec.Id.Value] = &testController
+
+			request := &pe.SetNVMeControllerAnaStateRequest{Name: testController.Spec.Id.Value, Ana: tt.in}
+			response, err := testEnv.client.SetNVMeControllerAnaState(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+			var last string
+			for len(testEnv.requests) > 0 {
+				last = <-testEnv.requests
+			}
+			if !strings.Contains(last, tt.request) {
+				t.Error("request: expected", tt.request, "received", last)
+			}
+		})
+	}
+}
+
+func TestExtension_GetNVMeControllerAnaState(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		out     *pe.NVMeControllerAna
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"state reported by SPDK": {
+			testController.Spec.Id.Value,
+			&pe.NVMeControllerAna{AnaState: "non_optimized"},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[{"address":` +
+				`{"trtype":"TCP","adrfam":"IPv4","traddr":"127.0.0.1","trsvcid":"4420"},` +
+				`"ana_states":[{"ana_group":1,"ana_state":"non_optimized"}]}]}`},
+			codes.OK,
+			"",
+		},
+		"unknown controller": {
+			"unknown-controller",
+			nil,
+			[]string{},
+			codes.NotFound,
+			"unable to find key unknown-controller",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Subsystems[testSubsystem.Spec.Id.Value] = &testSubsystem
+			testEnv.frontend.Nvme.Controllers[testController.Spec.Id.Value] = &testController
+
+			request := &pe.GetNVMeControllerAnaStateRequest{Name: tt.in}
+			response, err := testEnv.client.GetNVMeControllerAnaState(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
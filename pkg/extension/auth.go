@@ -0,0 +1,34 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/backend"
+)
+
+// SetNVMfRemoteControllerAuth sets the credentials an NVMf remote controller
+// is going to be created with
+func (s *Server) SetNVMfRemoteControllerAuth(ctx context.Context, in *pe.SetNVMfRemoteControllerAuthRequest) (*pe.NVMfRemoteControllerAuth, error) {
+	request := &backend.SetNVMfRemoteControllerAuthRequest{Name: in.Name}
+	if in.Auth != nil {
+		request.Auth = &backend.NVMfRemoteControllerAuth{
+			Psk:            in.Auth.Psk,
+			DhchapKey:      in.Auth.DhchapKey,
+			DhchapCtrlrKey: in.Auth.DhchapCtrlrKey,
+		}
+	}
+	auth, err := s.backend.SetNVMfRemoteControllerAuth(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &pe.NVMfRemoteControllerAuth{
+		Psk:            auth.Psk,
+		DhchapKey:      auth.DhchapKey,
+		DhchapCtrlrKey: auth.DhchapCtrlrKey,
+	}, nil
+}
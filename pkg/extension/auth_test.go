@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtension_SetNVMfRemoteControllerAuth(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		in      *pe.NVMfRemoteControllerAuth
+		out     *pe.NVMfRemoteControllerAuth
+		errCode codes.Code
+		errMsg  string
+	}{
+		"valid request": {
+			"nvme-test",
+			&pe.NVMfRemoteControllerAuth{DhchapKey: "key0", DhchapCtrlrKey: "key1"},
+			&pe.NVMfRemoteControllerAuth{DhchapKey: "key0", DhchapCtrlrKey: "key1"},
+			codes.OK,
+			"",
+		},
+		"missing auth": {
+			"nvme-test",
+			nil,
+			nil,
+			codes.InvalidArgument,
+			"auth cannot be empty",
+		},
+		"controller key without host key": {
+			"nvme-test",
+			&pe.NVMfRemoteControllerAuth{DhchapCtrlrKey: "key1"},
+			nil,
+			codes.InvalidArgument,
+			"dhchap_ctrlr_key requires dhchap_key",
+		},
+		"created controller": {
+			"created-nvme",
+			&pe.NVMfRemoteControllerAuth{Psk: "key0"},
+			nil,
+			codes.FailedPrecondition,
+			"credentials of created NVMfRemoteController created-nvme cannot be changed",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, []string{})
+			defer testEnv.Close()
+			testEnv.backend.Volumes.NvmeVolumes["created-nvme"] = &pb.NVMfRemoteController{
+				Id: &pc.ObjectKey{Value: "created-nvme"},
+			}
+
+			request := &pe.SetNVMfRemoteControllerAuthRequest{Name: tt.name, Auth: tt.in}
+			response, err := testEnv.client.SetNVMfRemoteControllerAuth(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+)
+
+// SetVirtioBlkOptions sets the options of the vhost controller of a
+// virtio-blk device
+func (s *Server) SetVirtioBlkOptions(ctx context.Context, in *pe.SetVirtioBlkOptionsRequest) (*pe.VirtioBlkOptions, error) {
+	request := &frontend.SetVirtioBlkOptionsRequest{Name: in.Name}
+	if in.Options != nil {
+		request.Options = &frontend.VirtioBlkOptions{
+			Cpumask:    in.Options.Cpumask,
+			NumQueues:  int(in.Options.NumQueues),
+			ReadOnly:   in.Options.Readonly,
+			PackedRing: in.Options.PackedRing,
+		}
+	}
+	options, err := s.frontend.SetVirtioBlkOptions(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return blkOptionsToProto(options), nil
+}
+
+// GetVirtioBlkOptions reports the options the vhost controller of a
+// virtio-blk device runs with
+func (s *Server) GetVirtioBlkOptions(ctx context.Context, in *pe.GetVirtioBlkOptionsRequest) (*pe.VirtioBlkOptions, error) {
+	options, err := s.frontend.GetVirtioBlkOptions(ctx, &frontend.GetVirtioBlkOptionsRequest{Name: in.Name})
+	if err != nil {
+		return nil, err
+	}
+	return blkOptionsToProto(options), nil
+}
+
+func blkOptionsToProto(in *frontend.VirtioBlkOptions) *pe.VirtioBlkOptions {
+	return &pe.VirtioBlkOptions{
+		Cpumask:    in.Cpumask,
+		NumQueues:  int32(in.NumQueues),
+		Readonly:   in.ReadOnly,
+		PackedRing: in.PackedRing,
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtension_SetVirtioBlkOptions(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		in      *pe.VirtioBlkOptions
+		out     *pe.VirtioBlkOptions
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"device not created yet": {
+			"virtio-blk-new",
+			&pe.VirtioBlkOptions{Cpumask: "0x3", NumQueues: 4, Readonly: true},
+			&pe.VirtioBlkOptions{Cpumask: "0x3", NumQueues: 4, Readonly: true},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":{"tick_rate":2490000000,"reactors":[{"lcore":0},{"lcore":1}]}}`},
+			codes.OK,
+			"",
+		},
+		"cpumask out of the core mask": {
+			"virtio-blk-new",
+			&pe.VirtioBlkOptions{Cpumask: "0x4"},
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":{"tick_rate":2490000000,"reactors":[{"lcore":0},{"lcore":1}]}}`},
+			codes.InvalidArgument,
+			"cpumask 0x4 selects cores out of the SPDK core mask 0x3",
+		},
+		"too many queues": {
+			"virtio-blk-new",
+			&pe.VirtioBlkOptions{NumQueues: 257},
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			"num_queues must be between 0 and 256, got 257",
+		},
+		"missing options": {
+			"virtio-blk-new",
+			nil,
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			"options cannot be empty",
+		},
+		"invalid name": {
+			"../virtio-blk",
+			&pe.VirtioBlkOptions{},
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			`name "../virtio-blk" must be 1 to 64 letters, digits, '_', '.' or '-' and cannot start with '.'`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+
+			request := &pe.SetVirtioBlkOptionsRequest{Name: tt.name, Options: tt.in}
+			response, err := testEnv.client.SetVirtioBlkOptions(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
+
+func TestExtension_GetVirtioBlkOptions(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		out     *pe.VirtioBlkOptions
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"options read from SPDK": {
+			"virtio-blk-test",
+			&pe.VirtioBlkOptions{Cpumask: "0x1", NumQueues: 4, Readonly: true, PackedRing: true},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[{"ctrlr":"virtio-blk-test","cpumask":"0x1",` +
+				`"backend_specific":{"block":{"readonly":true,"bdev":"Malloc1"}}}]}`},
+			codes.OK,
+			"",
+		},
+		"unknown device": {
+			"unknown-blk",
+			nil,
+			[]string{},
+			codes.NotFound,
+			"unable to find key unknown-blk",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.frontend.Virt.BlkCtrls["virtio-blk-test"] = &pb.VirtioBlk{
+				Id: &pc.ObjectKey{Value: "virtio-blk-test"}, VolumeId: &pc.ObjectKey{Value: "Malloc1"},
+			}
+			testEnv.frontend.Virt.ActiveBlkOptions["virtio-blk-test"] = &frontend.VirtioBlkOptions{
+				Cpumask: "0x3", NumQueues: 4, PackedRing: true,
+			}
+
+			request := &pe.GetVirtioBlkOptionsRequest{Name: tt.in}
+			response, err := testEnv.client.GetVirtioBlkOptions(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
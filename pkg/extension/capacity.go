@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+)
+
+// GetNVMeNamespaceCapacity reports the current size of the volume of an NVMe
+// namespace
+func (s *Server) GetNVMeNamespaceCapacity(ctx context.Context, in *pe.GetNVMeNamespaceCapacityRequest) (*pe.VolumeCapacity, error) {
+	capacity, err := s.frontend.GetNVMeNamespaceCapacity(ctx, &frontend.GetCapacityRequest{Name: in.Name})
+	if err != nil {
+		return nil, err
+	}
+	return capacityToProto(capacity), nil
+}
+
+// GetVirtioBlkCapacity reports the current size of the volume of a
+// virtio-blk controller
+func (s *Server) GetVirtioBlkCapacity(ctx context.Context, in *pe.GetVirtioBlkCapacityRequest) (*pe.VolumeCapacity, error) {
+	capacity, err := s.frontend.GetVirtioBlkCapacity(ctx, &frontend.GetCapacityRequest{Name: in.Name})
+	if err != nil {
+		return nil, err
+	}
+	return capacityToProto(capacity), nil
+}
+
+// GetVirtioScsiLunCapacity reports the current size of the volume of a
+// virtio-scsi LUN
+func (s *Server) GetVirtioScsiLunCapacity(ctx context.Context, in *pe.GetVirtioScsiLunCapacityRequest) (*pe.VolumeCapacity, error) {
+	capacity, err := s.frontend.GetVirtioScsiLunCapacity(ctx, &frontend.GetCapacityRequest{Name: in.Name})
+	if err != nil {
+		return nil, err
+	}
+	return capacityToProto(capacity), nil
+}
+
+func capacityToProto(in *frontend.VolumeCapacity) *pe.VolumeCapacity {
+	return &pe.VolumeCapacity{BlockSize: in.BlockSize, BlocksCount: in.BlocksCount}
+}
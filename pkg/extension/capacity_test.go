@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"strings"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtension_GetCapacity(t *testing.T) {
+	spdkBdev := `{"id":%d,"error":{"code":0,"message":""},"result":[{"name":"Malloc1","block_size":512,"num_blocks":4096}]}`
+	tests := map[string]struct {
+		get     func(testEnv *testEnv) (*pe.VolumeCapacity, error)
+		out     *pe.VolumeCapacity
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"nvme namespace": {
+			func(testEnv *testEnv) (*pe.VolumeCapacity, error) {
+				request := &pe.GetNVMeNamespaceCapacityRequest{Name: testNamespace.Spec.Id.Value}
+				return testEnv.client.GetNVMeNamespaceCapacity(testEnv.ctx, request)
+			},
+			&pe.VolumeCapacity{BlockSize: 512, BlocksCount: 4096},
+			[]string{spdkBdev},
+			codes.OK,
+			"",
+		},
+		"virtio-blk": {
+			func(testEnv *testEnv) (*pe.VolumeCapacity, error) {
+				request := &pe.GetVirtioBlkCapacityRequest{Name: "virtio-blk-test"}
+				return testEnv.client.GetVirtioBlkCapacity(testEnv.ctx, request)
+			},
+			&pe.VolumeCapacity{BlockSize: 512, BlocksCount: 4096},
+			[]string{spdkBdev},
+			codes.OK,
+			"",
+		},
+		"virtio-scsi lun": {
+			func(testEnv *testEnv) (*pe.VolumeCapacity, error) {
+				request := &pe.GetVirtioScsiLunCapacityRequest{Name: "lun-test"}
+				return testEnv.client.GetVirtioScsiLunCapacity(testEnv.ctx, request)
+			},
+			&pe.VolumeCapacity{BlockSize: 512, BlocksCount: 4096},
+			[]string{spdkBdev},
+			codes.OK,
+			"",
+		},
+		"unknown virtio-blk": {
+			func(testEnv *testEnv) (*pe.VolumeCapacity, error) {
+				request := &pe.GetVirtioBlkCapacityRequest{Name: "unknown-blk"}
+				return testEnv.client.GetVirtioBlkCapacity(testEnv.ctx, request)
+			},
+			nil,
+			[]string{},
+			codes.NotFound,
+			"unable to find key unknown-blk",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Namespaces[testNamespace.Spec.Id.Value] = &testNamespace
+			testEnv.frontend.Virt.BlkCtrls["virtio-blk-test"] = &pb.VirtioBlk{
+				Id: &pc.ObjectKey{Value: "virtio-blk-test"}, VolumeId: &pc.ObjectKey{Value: "Malloc1"},
+			}
+			testEnv.frontend.Virt.ScsiLuns["lun-test"] = &pb.VirtioScsiLun{
+				Id: &pc.ObjectKey{Value: "lun-test"}, VolumeId: &pc.ObjectKey{Value: "Malloc1"},
+			}
+
+			response, err := tt.get(testEnv)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+			var last string
+			for len(testEnv.requests) > 0 {
+				last = <-testEnv.requests
+			}
+			if tt.errCode == codes.OK && !strings.Contains(last, `"params":{"name":"Malloc1"}`) {
+				t.Error("request: expected bdev_get_bdevs of Malloc1, received", last)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// AddNVMeDiscoveryListener makes the SPDK discovery subsystem listen on an
+// address
+func (s *Server) AddNVMeDiscoveryListener(ctx context.Context, in *pe.AddNVMeDiscoveryListenerRequest) (*pe.NVMeDiscoveryListener, error) {
+	var request *frontend.NVMeDiscoveryListener
+	if l := in.NvmeDiscoveryListener; l != nil {
+		request = &frontend.NVMeDiscoveryListener{Name: l.Name, Transport: l.Transport, Traddr: l.Traddr, Trsvcid: l.Trsvcid}
+	}
+	listener, err := s.frontend.AddNVMeDiscoveryListener(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return discoveryListenerToProto(listener), nil
+}
+
+// DeleteNVMeDiscoveryListener stops the SPDK discovery subsystem listening on
+// the address of a listener
+func (s *Server) DeleteNVMeDiscoveryListener(ctx context.Context, in *pe.DeleteNVMeDiscoveryListenerRequest) (*emptypb.Empty, error) {
+	if err := s.frontend.DeleteNVMeDiscoveryListener(ctx, in.Name, in.AllowMissing); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListNVMeDiscoveryListeners lists the addresses the SPDK discovery subsystem
+// listens on
+func (s *Server) ListNVMeDiscoveryListeners(ctx context.Context, _ *pe.ListNVMeDiscoveryListenersRequest) (*pe.ListNVMeDiscoveryListenersResponse, error) {
+	listeners, err := s.frontend.ListNVMeDiscoveryListeners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response := &pe.ListNVMeDiscoveryListenersResponse{}
+	for _, listener := range listeners {
+		response.NvmeDiscoveryListeners = append(response.NvmeDiscoveryListeners, discoveryListenerToProto(listener))
+	}
+	return response, nil
+}
+
+// AddNVMeDiscoveryReferral adds a referral to the discovery log page of the
+// SPDK discovery subsystem
+func (s *Server) AddNVMeDiscoveryReferral(ctx context.Context, in *pe.AddNVMeDiscoveryReferralRequest) (*pe.NVMeDiscoveryReferral, error) {
+	var request *frontend.NVMeDiscoveryReferral
+	if r := in.NvmeDiscoveryReferral; r != nil {
+		request = &frontend.NVMeDiscoveryReferral{
+			Name:          r.Name,
+			Transport:     r.Transport,
+			Traddr:        r.Traddr,
+			Trsvcid:       r.Trsvcid,
+			Subnqn:        r.Subnqn,
+			SecureChannel: r.SecureChannel,
+		}
+	}
+	referral, err := s.frontend.AddNVMeDiscoveryReferral(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return discoveryReferralToProto(referral), nil
+}
+
+// DeleteNVMeDiscoveryReferral removes a referral from the discovery log page
+func (s *Server) DeleteNVMeDiscoveryReferral(ctx context.Context, in *pe.DeleteNVMeDiscoveryReferralRequest) (*emptypb.Empty, error) {
+	if err := s.frontend.DeleteNVMeDiscoveryReferral(ctx, in.Name, in.AllowMissing); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListNVMeDiscoveryReferrals lists the referrals of the discovery log page
+func (s *Server) ListNVMeDiscoveryReferrals(ctx context.Context, _ *pe.ListNVMeDiscoveryReferralsRequest) (*pe.ListNVMeDiscoveryReferralsResponse, error) {
+	referrals, err := s.frontend.ListNVMeDiscoveryReferrals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response := &pe.ListNVMeDiscoveryReferralsResponse{}
+	for _, referral := range referrals {
+		response.NvmeDiscoveryReferrals = append(response.NvmeDiscoveryReferrals, discoveryReferralToProto(referral))
+	}
+	return response, nil
+}
+
+func discoveryListenerToProto(in *frontend.NVMeDiscoveryListener) *pe.NVMeDiscoveryListener {
+	return &pe.NVMeDiscoveryListener{
+		Name:      in.Name,
+		Transport: in.Transport,
+		Traddr:    in.Traddr,
+		Trsvcid:   in.Trsvcid,
+	}
+}
+
+func discoveryReferralToProto(in *frontend.NVMeDiscoveryReferral) *pe.NVMeDiscoveryReferral {
+	return &pe.NVMeDiscoveryReferral{
+		Name:          in.Name,
+		Transport:     in.Transport,
+		Traddr:        in.Traddr,
+		Trsvcid:       in.Trsvcid,
+		Subnqn:        in.Subnqn,
+		SecureChannel: in.SecureChannel,
+	}
+}
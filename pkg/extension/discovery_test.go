@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"strings"
+	"testing"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtension_AddNVMeDiscoveryListener(t *testing.T) {
+	tests := map[string]struct {
+		in      *pe.NVMeDiscoveryListener
+		out     *pe.NVMeDiscoveryListener
+		spdk    []string
+		request string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"well known port": {
+			&pe.NVMeDiscoveryListener{Name: "discovery-tcp", Transport: "tcp", Trsvcid: "8009"},
+			&pe.NVMeDiscoveryListener{Name: "discovery-tcp", Transport: "tcp", Trsvcid: "8009"},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			`"nqn":"nqn.2014-08.org.nvmexpress.discovery"`,
+			codes.OK,
+			"",
+		},
+		"missing listener": {
+			nil,
+			nil,
+			[]string{},
+			"",
+			codes.InvalidArgument,
+			"name cannot be empty",
+		},
+		"unregistered transport": {
+			&pe.NVMeDiscoveryListener{Name: "discovery-rdma", Transport: "rdma"},
+			nil,
+			[]string{},
+			"",
+			codes.InvalidArgument,
+			`no SubsystemListener registered for transport "rdma"`,
+		},
+		"valid request with invalid SPDK response": {
+			&pe.NVMeDiscoveryListener{Name: "discovery-tcp", Transport: "tcp"},
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":false}`},
+			`"method":"nvmf_subsystem_add_listener"`,
+			codes.InvalidArgument,
+			"Could not add discovery listener: discovery-tcp",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+
+			request := &pe.AddNVMeDiscoveryListenerRequest{NvmeDiscoveryListener: tt.in}
+			response, err := testEnv.client.AddNVMeDiscoveryListener(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+			var last string
+			for len(testEnv.requests) > 0 {
+				last = <-testEnv.requests
+			}
+			if !strings.Contains(last, tt.request) {
+				t.Error("request: expected", tt.request, "received", last)
+			}
+		})
+	}
+}
+
+func TestExtension_DeleteNVMeDiscoveryListener(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		missing bool
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"valid request": {
+			"discovery-tcp",
+			false,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			codes.OK,
+			"",
+		},
+		"unknown listener": {
+			"unknown-listener",
+			false,
+			[]string{},
+			codes.NotFound,
+			"unable to find key unknown-listener",
+		},
+		"unknown listener with allow missing": {
+			"unknown-listener",
+			true,
+			[]string{},
+			codes.OK,
+			"",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.DiscoveryListeners["discovery-tcp"] = &frontend.NVMeDiscoveryListener{
+				Name: "discovery-tcp", Transport: frontend.TransportTCP,
+			}
+
+			request := &pe.DeleteNVMeDiscoveryListenerRequest{Name: tt.in, AllowMissing: tt.missing}
+			_, err := testEnv.client.DeleteNVMeDiscoveryListener(testEnv.ctx, request)
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
+
+func TestExtension_AddNVMeDiscoveryReferral(t *testing.T) {
+	tests := map[string]struct {
+		in      *pe.NVMeDiscoveryReferral
+		out     *pe.NVMeDiscoveryReferral
+		spdk    []string
+		request string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"secure referral to another dpu": {
+			&pe.NVMeDiscoveryReferral{Name: "dpu-2", Transport: "tcp", Traddr: "10.10.10.20", SecureChannel: true},
+			&pe.NVMeDiscoveryReferral{Name: "dpu-2", Transport: "tcp", Traddr: "10.10.10.20", SecureChannel: true},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			`"secure_channel":true`,
+			codes.OK,
+			"",
+		},
+		"missing address": {
+			&pe.NVMeDiscoveryReferral{Name: "dpu-2", Transport: "tcp"},
+			nil,
+			[]string{},
+			"",
+			codes.InvalidArgument,
+			"traddr cannot be empty",
+		},
+		"valid request with invalid SPDK response": {
+			&pe.NVMeDiscoveryReferral{Name: "dpu-2", Transport: "tcp", Traddr: "10.10.10.20"},
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":false}`},
+			`"method":"nvmf_discovery_add_referral"`,
+			codes.InvalidArgument,
+			"Could not add discovery referral: dpu-2",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+
+			request := &pe.AddNVMeDiscoveryReferralRequest{NvmeDiscoveryReferral: tt.in}
+			response, err := testEnv.client.AddNVMeDiscoveryReferral(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+			var last string
+			for len(testEnv.requests) > 0 {
+				last = <-testEnv.requests
+			}
+			if !strings.Contains(last, tt.request) {
+				t.Error("request: expected", tt.request, "received", last)
+			}
+		})
+	}
+}
+
+func TestExtension_ListNVMeDiscovery(t *testing.T) {
+	testEnv := createTestEnvironment(true, []string{})
+	defer testEnv.Close()
+	testEnv.frontend.Nvme.DiscoveryListeners["discovery-tcp"] = &frontend.NVMeDiscoveryListener{
+		Name: "discovery-tcp", Transport: frontend.TransportTCP, Trsvcid: "8009",
+	}
+	testEnv.frontend.Nvme.DiscoveryReferrals["dpu-3"] = &frontend.NVMeDiscoveryReferral{
+		Name: "dpu-3", Transport: frontend.TransportTCP, Traddr: "10.10.10.30",
+	}
+	testEnv.frontend.Nvme.DiscoveryReferrals["dpu-2"] = &frontend.NVMeDiscoveryReferral{
+		Name: "dpu-2", Transport: frontend.TransportRDMA, Traddr: "10.10.10.20", Subnqn: "nqn.2022-09.io.spdk:opi3",
+	}
+
+	listeners, err := testEnv.client.ListNVMeDiscoveryListeners(testEnv.ctx, &pe.ListNVMeDiscoveryListenersRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedListeners := &pe.ListNVMeDiscoveryListenersResponse{NvmeDiscoveryListeners: []*pe.NVMeDiscoveryListener{
+		{Name: "discovery-tcp", Transport: "tcp", Trsvcid: "8009"},
+	}}
+	if !proto.Equal(listeners, expectedListeners) {
+		t.Error("listeners: expected", expectedListeners, "received", listeners)
+	}
+
+	referrals, err := testEnv.client.ListNVMeDiscoveryReferrals(testEnv.ctx, &pe.ListNVMeDiscoveryReferralsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedReferrals := &pe.ListNVMeDiscoveryReferralsResponse{NvmeDiscoveryReferrals: []*pe.NVMeDiscoveryReferral{
+		{Name: "dpu-2", Transport: "rdma", Traddr: "10.10.10.20", Subnqn: "nqn.2022-09.io.spdk:opi3"},
+		{Name: "dpu-3", Transport: "tcp", Traddr: "10.10.10.30"},
+	}}
+	if !proto.Equal(referrals, expectedReferrals) {
+		t.Error("referrals: expected", expectedReferrals, "received", referrals)
+	}
+}
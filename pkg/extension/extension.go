@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server.
+//
+// SPDK supports properties of the storage objects which the OPI storage API
+// has no fields for, e.g. the hosts allowed to connect to an NVMe subsystem.
+// The ExtensionService defined in api/v1alpha1/extension.proto carries
+// them. Objects are created through the OPI storage API and referred to by
+// their IDs, the calls are served by the frontend, backend and keyring
+// servers which own the objects.
+package extension
+
+//go:generate protoc -I ../../api/v1alpha1 --go_out=../../api/v1alpha1/gen/go --go_opt=paths=source_relative --go-grpc_out=../../api/v1alpha1/gen/go --go-grpc_opt=paths=source_relative extension.proto
+
+import (
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/backend"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
+)
+
+// Server contains the ExtensionService
+type Server struct {
+	pe.UnimplementedExtensionServiceServer
+
+	frontend *frontend.Server
+	backend  *backend.Server
+	keyring  *keyring.Server
+}
+
+// NewServer creates the ExtensionService for objects of the frontend,
+// backend and keyring servers
+func NewServer(frontendServer *frontend.Server, backendServer *backend.Server, keyringServer *keyring.Server) *Server {
+	return &Server{
+		frontend: frontendServer,
+		backend:  backendServer,
+		keyring:  keyringServer,
+	}
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/opiproject/gospdk/spdk"
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/backend"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+type testEnv struct {
+	frontend   *frontend.Server
+	backend    *backend.Server
+	keyring    *keyring.Server
+	client     pe.ExtensionServiceClient
+	ln         net.Listener
+	testSocket string
+	ctx        context.Context
+	conn       *grpc.ClientConn
+	jsonRPC    spdk.JSONRPC
+	// requests are the ones received by the mock SPDK server
+	requests <-chan string
+}
+
+func (e *testEnv) Close() {
+	server.CloseListener(e.ln)
+	server.CloseGrpcConnection(e.conn)
+	if err := os.RemoveAll(e.testSocket); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func createTestEnvironment(startSpdkServer bool, spdkResponses []string) *testEnv {
+	env := &testEnv{}
+	env.testSocket = server.GenerateSocketName("extension")
+	env.ln, env.jsonRPC, env.requests = server.CreateTestSpdkRecordingServer(env.testSocket, startSpdkServer, spdkResponses)
+	env.frontend = frontend.NewServer(env.jsonRPC)
+	env.backend = backend.NewServer(env.jsonRPC)
+	env.keyring = keyring.NewServer(env.jsonRPC)
+
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx,
+		"",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer(NewServer(env.frontend, env.backend, env.keyring))))
+	if err != nil {
+		log.Fatal(err)
+	}
+	env.ctx = ctx
+	env.conn = conn
+	env.client = pe.NewExtensionServiceClient(env.conn)
+
+	return env
+}
+
+func dialer(extensionServer *Server) func(context.Context, string) (net.Conn, error) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pe.RegisterExtensionServiceServer(server, extensionServer)
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	return func(context.Context, string) (net.Conn, error) {
+		return listener.Dial()
+	}
+}
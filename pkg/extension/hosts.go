@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+)
+
+// UpdateNVMeSubsystemHosts changes which hosts can connect to an NVMe
+// subsystem
+func (s *Server) UpdateNVMeSubsystemHosts(ctx context.Context, in *pe.UpdateNVMeSubsystemHostsRequest) (*pe.NVMeSubsystemHosts, error) {
+	hosts, err := s.frontend.UpdateNVMeSubsystemHosts(ctx, &frontend.UpdateNVMeSubsystemHostsRequest{
+		Name:       in.Name,
+		Hosts:      hostsFromProto(in.Hosts),
+		UpdateMask: in.UpdateMask,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hostsToProto(hosts), nil
+}
+
+// GetNVMeSubsystemHosts reports which hosts can connect to an NVMe subsystem
+func (s *Server) GetNVMeSubsystemHosts(ctx context.Context, in *pe.GetNVMeSubsystemHostsRequest) (*pe.NVMeSubsystemHosts, error) {
+	hosts, err := s.frontend.GetNVMeSubsystemHosts(ctx, &frontend.GetNVMeSubsystemHostsRequest{Name: in.Name})
+	if err != nil {
+		return nil, err
+	}
+	return hostsToProto(hosts), nil
+}
+
+func hostsFromProto(in *pe.NVMeSubsystemHosts) *frontend.NVMeSubsystemHosts {
+	if in == nil {
+		return nil
+	}
+	hosts := &frontend.NVMeSubsystemHosts{AllowAnyHost: in.AllowAnyHost, Hosts: []frontend.NVMeHost{}}
+	for _, host := range in.Hosts {
+		hosts.Hosts = append(hosts.Hosts, frontend.NVMeHost{
+			Nqn:            host.GetNqn(),
+			Psk:            host.GetPsk(),
+			DhchapKey:      host.GetDhchapKey(),
+			DhchapCtrlrKey: host.GetDhchapCtrlrKey(),
+		})
+	}
+	return hosts
+}
+
+func hostsToProto(in *frontend.NVMeSubsystemHosts) *pe.NVMeSubsystemHosts {
+	hosts := &pe.NVMeSubsystemHosts{AllowAnyHost: in.AllowAnyHost}
+	for _, host := range in.Hosts {
+		hosts.Hosts = append(hosts.Hosts, &pe.NVMeHost{
+			Nqn:            host.Nqn,
+			Psk:            host.Psk,
+			DhchapKey:      host.DhchapKey,
+			DhchapCtrlrKey: host.DhchapCtrlrKey,
+		})
+	}
+	return hosts
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"strings"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var testSubsystem = pb.NVMeSubsystem{
+	Spec: &pb.NVMeSubsystemSpec{
+		Id:  &pc.ObjectKey{Value: "subsystem-test"},
+		Nqn: "nqn.2022-09.io.spdk:opi3",
+	},
+}
+
+func TestExtension_UpdateNVMeSubsystemHosts(t *testing.T) {
+	spdkSubsystems := `{"id":%d,"error":{"code":0,"message":""},"result":[{"nqn":"nqn.2022-09.io.spdk:opi3",` +
+		`"allow_any_host":true,"hosts":[]}]}`
+	spdkTrue := `{"id":%d,"error":{"code":0,"message":""},"result":true}`
+	tests := map[string]struct {
+		in      *pe.NVMeSubsystemHosts
+		out     *pe.NVMeSubsystemHosts
+		spdk    []string
+		request string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"add host and reject other hosts": {
+			&pe.NVMeSubsystemHosts{Hosts: []*pe.NVMeHost{{Nqn: "nqn.2014-08.org.nvmexpress:uuid:host1"}}},
+			&pe.NVMeSubsystemHosts{Hosts: []*pe.NVMeHost{{Nqn: "nqn.2014-08.org.nvmexpress:uuid:host1"}}},
+			[]string{spdkSubsystems, spdkTrue, spdkTrue},
+			`"method":"nvmf_subsystem_allow_any_host"`,
+			codes.OK,
+			"",
+		},
+		"missing hosts": {
+			nil,
+			nil,
+			[]string{},
+			"",
+			codes.InvalidArgument,
+			"hosts cannot be empty",
+		},
+		"reject other hosts": {
+			&pe.NVMeSubsystemHosts{AllowAnyHost: false},
+			&pe.NVMeSubsystemHosts{},
+			[]string{spdkSubsystems, spdkTrue},
+			`"params":{"nqn":"nqn.2022-09.io.spdk:opi3","allow_any_host":false}`,
+			codes.OK,
+			"",
+		},
+		"invalid host": {
+			&pe.NVMeSubsystemHosts{Hosts: []*pe.NVMeHost{{Nqn: "host1"}}},
+			nil,
+			[]string{},
+			"",
+			codes.InvalidArgument,
+			`invalid host: NQN "host1" must start with "nqn."`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Subsystems[testSubsystem.Spec.Id.Value] = &testSubsystem
+
+			request := &pe.UpdateNVMeSubsystemHostsRequest{Name: testSubsystem.Spec.Id.Value, Hosts: tt.in}
+			response, err := testEnv.client.UpdateNVMeSubsystemHosts(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+			var last string
+			for len(testEnv.requests) > 0 {
+				last = <-testEnv.requests
+			}
+			if !strings.Contains(last, tt.request) {
+				t.Error("request: expected", tt.request, "received", last)
+			}
+		})
+	}
+}
+
+func TestExtension_GetNVMeSubsystemHosts(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		out     *pe.NVMeSubsystemHosts
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"valid request": {
+			testSubsystem.Spec.Id.Value,
+			&pe.NVMeSubsystemHosts{Hosts: []*pe.NVMeHost{{Nqn: "nqn.2014-08.org.nvmexpress:uuid:host1", DhchapKey: "key0"}}},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[{"nqn":"nqn.2022-09.io.spdk:opi3",` +
+				`"allow_any_host":false,"hosts":[{"nqn":"nqn.2014-08.org.nvmexpress:uuid:host1","dhchap_key":"key0"}]}]}`},
+			codes.OK,
+			"",
+		},
+		"any host": {
+			testSubsystem.Spec.Id.Value,
+			&pe.NVMeSubsystemHosts{AllowAnyHost: true},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[{"nqn":"nqn.2022-09.io.spdk:opi3",` +
+				`"allow_any_host":true,"hosts":[]}]}`},
+			codes.OK,
+			"",
+		},
+		"unknown key": {
+			"unknown-id",
+			nil,
+			[]string{},
+			codes.NotFound,
+			"unable to find key unknown-id",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Subsystems[testSubsystem.Spec.Id.Value] = &testSubsystem
+
+			response, err := testEnv.client.GetNVMeSubsystemHosts(testEnv.ctx, &pe.GetNVMeSubsystemHostsRequest{Name: tt.in})
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// AddKeyringKey adds a key read from a file to the SPDK keyring
+func (s *Server) AddKeyringKey(ctx context.Context, in *pe.AddKeyringKeyRequest) (*pe.KeyringKey, error) {
+	var request *keyring.Key
+	if in.KeyringKey != nil {
+		request = &keyring.Key{Name: in.KeyringKey.Name, Path: in.KeyringKey.Path}
+	}
+	key, err := s.keyring.AddKey(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &pe.KeyringKey{Name: key.Name, Path: key.Path}, nil
+}
+
+// DeleteKeyringKey removes a key from the SPDK keyring
+func (s *Server) DeleteKeyringKey(ctx context.Context, in *pe.DeleteKeyringKeyRequest) (*emptypb.Empty, error) {
+	if err := s.keyring.DeleteKey(ctx, in.Name, in.AllowMissing); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// ListKeyringKeys lists the keys added to the SPDK keyring
+func (s *Server) ListKeyringKeys(ctx context.Context, _ *pe.ListKeyringKeysRequest) (*pe.ListKeyringKeysResponse, error) {
+	keys, err := s.keyring.ListKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response := &pe.ListKeyringKeysResponse{}
+	for _, key := range keys {
+		response.KeyringKeys = append(response.KeyringKeys, &pe.KeyringKey{Name: key.Name, Path: key.Path})
+	}
+	return response, nil
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"testing"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtension_AddKeyringKey(t *testing.T) {
+	tests := map[string]struct {
+		in      *pe.KeyringKey
+		out     *pe.KeyringKey
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"valid request": {
+			&pe.KeyringKey{Name: "key0", Path: "/etc/opi/key0"},
+			&pe.KeyringKey{Name: "key0", Path: "/etc/opi/key0"},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			codes.OK,
+			"",
+		},
+		"missing key": {
+			nil,
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			"key cannot be empty",
+		},
+		"relative path": {
+			&pe.KeyringKey{Name: "key0", Path: "key0"},
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			`path "key0" of key key0 must be absolute`,
+		},
+		"valid request with invalid SPDK response": {
+			&pe.KeyringKey{Name: "key0", Path: "/etc/opi/key0"},
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":false}`},
+			codes.InvalidArgument,
+			"Could not add key: key0",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+
+			request := &pe.AddKeyringKeyRequest{KeyringKey: tt.in}
+			response, err := testEnv.client.AddKeyringKey(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
+
+func TestExtension_DeleteKeyringKey(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		missing bool
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"valid request": {
+			"key0",
+			false,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			codes.OK,
+			"",
+		},
+		"valid request with unknown key": {
+			"unknown-key",
+			false,
+			[]string{},
+			codes.NotFound,
+			"unable to find key unknown-key",
+		},
+		"unknown key with allow missing": {
+			"unknown-key",
+			true,
+			[]string{},
+			codes.OK,
+			"",
+		},
+		"valid request with invalid SPDK response": {
+			"key0",
+			false,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":false}`},
+			codes.InvalidArgument,
+			"Could not remove key: key0",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.keyring.Keys["key0"] = &keyring.Key{Name: "key0", Path: "/etc/opi/key0"}
+
+			request := &pe.DeleteKeyringKeyRequest{Name: tt.in, AllowMissing: tt.missing}
+			_, err := testEnv.client.DeleteKeyringKey(testEnv.ctx, request)
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
+
+func TestExtension_ListKeyringKeys(t *testing.T) {
+	testEnv := createTestEnvironment(true, []string{})
+	defer testEnv.Close()
+	testEnv.keyring.Keys["key1"] = &keyring.Key{Name: "key1", Path: "/etc/opi/key1"}
+	testEnv.keyring.Keys["key0"] = &keyring.Key{Name: "key0", Path: "/etc/opi/key0"}
+
+	response, err := testEnv.client.ListKeyringKeys(testEnv.ctx, &pe.ListKeyringKeysRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &pe.ListKeyringKeysResponse{KeyringKeys: []*pe.KeyringKey{
+		{Name: "key0", Path: "/etc/opi/key0"},
+		{Name: "key1", Path: "/etc/opi/key1"},
+	}}
+	if !proto.Equal(response, expected) {
+		t.Error("response: expected", expected, "received", response)
+	}
+}
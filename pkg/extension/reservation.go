@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+)
+
+// SetNVMeNamespaceReservation sets the reservation configuration of an NVMe
+// namespace
+func (s *Server) SetNVMeNamespaceReservation(ctx context.Context, in *pe.SetNVMeNamespaceReservationRequest) (*pe.NVMeNamespaceReservation, error) {
+	request := &frontend.SetNVMeNamespaceReservationRequest{Name: in.Name}
+	if in.Reservation != nil {
+		request.Reservation = &frontend.NVMeNamespaceReservation{PtplFile: in.Reservation.PtplFile}
+	}
+	reservation, err := s.frontend.SetNVMeNamespaceReservation(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &pe.NVMeNamespaceReservation{PtplFile: reservation.PtplFile}, nil
+}
+
+// GetNVMeNamespaceReservationState reports the reservation holder and
+// registrants of an NVMe namespace
+func (s *Server) GetNVMeNamespaceReservationState(ctx context.Context, in *pe.GetNVMeNamespaceReservationStateRequest) (*pe.NVMeReservationState, error) {
+	state, err := s.frontend.GetNVMeNamespaceReservationState(ctx, &frontend.GetNVMeNamespaceReservationStateRequest{Name: in.Name})
+	if err != nil {
+		return nil, err
+	}
+	response := &pe.NVMeReservationState{Type: state.Type, HolderHostId: state.HolderHostID}
+	for _, registrant := range state.Registrants {
+		response.Registrants = append(response.Registrants, &pe.NVMeReservationRegistrant{
+			HostId: registrant.HostID,
+			Rkey:   registrant.Rkey,
+		})
+	}
+	return response, nil
+}
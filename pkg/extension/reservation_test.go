@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var testNamespace = pb.NVMeNamespace{
+	Spec: &pb.NVMeNamespaceSpec{
+		Id:          &pc.ObjectKey{Value: "namespace-test"},
+		SubsystemId: &pc.ObjectKey{Value: "subsystem-test"},
+		VolumeId:    &pc.ObjectKey{Value: "Malloc1"},
+		HostNsid:    22,
+	},
+}
+
+func TestExtension_SetNVMeNamespaceReservation(t *testing.T) {
+	tests := map[string]struct {
+		in      *pe.NVMeNamespaceReservation
+		out     *pe.NVMeNamespaceReservation
+		errCode codes.Code
+		errMsg  string
+	}{
+		"persisted reservations": {
+			&pe.NVMeNamespaceReservation{PtplFile: "/var/lib/spdk/namespace-test.json"},
+			&pe.NVMeNamespaceReservation{PtplFile: "/var/lib/spdk/namespace-test.json"},
+			codes.OK,
+			"",
+		},
+		"missing reservation": {
+			nil,
+			nil,
+			codes.InvalidArgument,
+			"reservation cannot be empty",
+		},
+		"relative ptpl file": {
+			&pe.NVMeNamespaceReservation{PtplFile: "namespace-test.json"},
+			nil,
+			codes.InvalidArgument,
+			`ptpl_file "namespace-test.json" must be a clean absolute path`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, []string{})
+			defer testEnv.Close()
+
+			request := &pe.SetNVMeNamespaceReservationRequest{Name: testNamespace.Spec.Id.Value, Reservation: tt.in}
+			response, err := testEnv.client.SetNVMeNamespaceReservation(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
+
+func TestExtension_GetNVMeNamespaceReservationState(t *testing.T) {
+	ptplFile := filepath.Join(t.TempDir(), "namespace-test.json")
+	content := `{"ptpl":true,"rtype":1,"crkey":7,"bdev_uuid":"b9d2b8f8-1d3a-4c0e-9bb4-8c9a2c2c5a11",` +
+		`"holder_uuid":"6d2a2a2e-1111-4c0e-9bb4-000000000001","registrants":[` +
+		`{"rkey":7,"host_uuid":"6d2a2a2e-1111-4c0e-9bb4-000000000001"}]}`
+	if err := os.WriteFile(ptplFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]struct {
+		ptpl    string
+		out     *pe.NVMeReservationState
+		errCode codes.Code
+		errMsg  string
+	}{
+		"reserved namespace": {
+			ptplFile,
+			&pe.NVMeReservationState{
+				Type:         "write_exclusive",
+				HolderHostId: "6d2a2a2e-1111-4c0e-9bb4-000000000001",
+				Registrants:  []*pe.NVMeReservationRegistrant{{HostId: "6d2a2a2e-1111-4c0e-9bb4-000000000001", Rkey: 7}},
+			},
+			codes.OK,
+			"",
+		},
+		"reservations in memory": {
+			"",
+			nil,
+			codes.FailedPrecondition,
+			"reservations of NVMeNamespace namespace-test are not persisted",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, []string{})
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Namespaces[testNamespace.Spec.Id.Value] = &testNamespace
+			if tt.ptpl != "" {
+				testEnv.frontend.Nvme.ActiveReservations[testNamespace.Spec.Id.Value] = &frontend.NVMeNamespaceReservation{PtplFile: tt.ptpl}
+			}
+
+			request := &pe.GetNVMeNamespaceReservationStateRequest{Name: testNamespace.Spec.Id.Value}
+			response, err := testEnv.client.GetNVMeNamespaceReservationState(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+)
+
+// SetNVMeControllerTransport sets the transport an NVMe controller is going
+// to be created with
+func (s *Server) SetNVMeControllerTransport(ctx context.Context, in *pe.SetNVMeControllerTransportRequest) (*pe.NVMeControllerTransport, error) {
+	request := &frontend.SetNVMeControllerTransportRequest{Name: in.Name}
+	if in.Transport != nil {
+		request.Transport = &frontend.NVMeControllerTransport{
+			Transport: in.Transport.Transport,
+			Traddr:    in.Transport.Traddr,
+			Trsvcid:   in.Transport.Trsvcid,
+		}
+	}
+	transport, err := s.frontend.SetNVMeControllerTransport(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return transportToProto(transport), nil
+}
+
+// GetNVMeControllerTransport reports the transport of an NVMe controller
+func (s *Server) GetNVMeControllerTransport(ctx context.Context, in *pe.GetNVMeControllerTransportRequest) (*pe.NVMeControllerTransport, error) {
+	transport, err := s.frontend.GetNVMeControllerTransport(ctx, &frontend.GetNVMeControllerTransportRequest{Name: in.Name})
+	if err != nil {
+		return nil, err
+	}
+	return transportToProto(transport), nil
+}
+
+func transportToProto(in *frontend.NVMeControllerTransport) *pe.NVMeControllerTransport {
+	return &pe.NVMeControllerTransport{
+		Transport: in.Transport,
+		Traddr:    in.Traddr,
+		Trsvcid:   in.Trsvcid,
+	}
+}
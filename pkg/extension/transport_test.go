@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var testController = pb.NVMeController{
+	Spec: &pb.NVMeControllerSpec{
+		Id:               &pc.ObjectKey{Value: "controller-test"},
+		SubsystemId:      &pc.ObjectKey{Value: "subsystem-test"},
+		NvmeControllerId: 17,
+	},
+}
+
+func TestExtension_SetNVMeControllerTransport(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		in      *pe.NVMeControllerTransport
+		out     *pe.NVMeControllerTransport
+		errCode codes.Code
+		errMsg  string
+	}{
+		"own listen address": {
+			"controller-new",
+			&pe.NVMeControllerTransport{Transport: "tcp", Traddr: "fd00::10", Trsvcid: "4421"},
+			&pe.NVMeControllerTransport{Transport: "tcp", Traddr: "fd00::10", Trsvcid: "4421"},
+			codes.OK,
+			"",
+		},
+		"missing transport": {
+			"controller-new",
+			nil,
+			nil,
+			codes.InvalidArgument,
+			"transport cannot be empty",
+		},
+		"unregistered transport": {
+			"controller-new",
+			&pe.NVMeControllerTransport{Transport: "vfiouser"},
+			nil,
+			codes.InvalidArgument,
+			`no SubsystemListener registered for transport "vfiouser", expected one of [tcp]`,
+		},
+		"created controller": {
+			testController.Spec.Id.Value,
+			&pe.NVMeControllerTransport{Transport: "tcp"},
+			nil,
+			codes.FailedPrecondition,
+			"transport of created NVMeController controller-test cannot be changed",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, []string{})
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Controllers[testController.Spec.Id.Value] = &testController
+
+			request := &pe.SetNVMeControllerTransportRequest{Name: tt.name, Transport: tt.in}
+			response, err := testEnv.client.SetNVMeControllerTransport(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
+
+func TestExtension_GetNVMeControllerTransport(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		out     *pe.NVMeControllerTransport
+		errCode codes.Code
+		errMsg  string
+	}{
+		"default transport": {
+			testController.Spec.Id.Value,
+			&pe.NVMeControllerTransport{Transport: "tcp"},
+			codes.OK,
+			"",
+		},
+		"unknown controller": {
+			"unknown-controller",
+			nil,
+			codes.NotFound,
+			"unable to find key unknown-controller",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, []string{})
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Controllers[testController.Spec.Id.Value] = &testController
+
+			request := &pe.GetNVMeControllerTransportRequest{Name: tt.in}
+			response, err := testEnv.client.GetNVMeControllerTransport(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"context"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+)
+
+// SetNVMeNamespaceVisibility sets the hosts an NVMe namespace is visible to
+func (s *Server) SetNVMeNamespaceVisibility(ctx context.Context, in *pe.SetNVMeNamespaceVisibilityRequest) (*pe.NVMeNamespaceVisibility, error) {
+	request := &frontend.SetNVMeNamespaceVisibilityRequest{Name: in.Name}
+	if in.Visibility != nil {
+		request.Visibility = &frontend.NVMeNamespaceVisibility{
+			Hidden: in.Visibility.Hidden,
+			Hosts:  append([]string{}, in.Visibility.Hosts...),
+		}
+	}
+	visibility, err := s.frontend.SetNVMeNamespaceVisibility(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return &pe.NVMeNamespaceVisibility{Hidden: visibility.Hidden, Hosts: visibility.Hosts}, nil
+}
+
+// GetNVMeNamespaceVisibility reports the hosts an NVMe namespace is visible to
+func (s *Server) GetNVMeNamespaceVisibility(ctx context.Context, in *pe.GetNVMeNamespaceVisibilityRequest) (*pe.NVMeNamespaceVisibility, error) {
+	visibility, err := s.frontend.GetNVMeNamespaceVisibility(ctx, &frontend.GetNVMeNamespaceVisibilityRequest{Name: in.Name})
+	if err != nil {
+		return nil, err
+	}
+	return &pe.NVMeNamespaceVisibility{Hidden: visibility.Hidden, Hosts: visibility.Hosts}, nil
+}
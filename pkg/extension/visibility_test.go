@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package extension implements the ExtensionService of the storage Server
+package extension
+
+import (
+	"strings"
+	"testing"
+
+	pe "github.com/opiproject/opi-spdk-bridge/api/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestExtension_SetNVMeNamespaceVisibility(t *testing.T) {
+	spdkSubsystems := `{"id":%d,"error":{"code":0,"message":""},"result":[{"nqn":"nqn.2022-09.io.spdk:opi3",` +
+		`"subtype":"NVMe","namespaces":[{"nsid":22,"bdev_name":"Malloc1"}]}]}`
+	tests := map[string]struct {
+		name    string
+		in      *pe.NVMeNamespaceVisibility
+		out     *pe.NVMeNamespaceVisibility
+		spdk    []string
+		request string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"hidden namespace not created yet": {
+			"namespace-new",
+			&pe.NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"nqn.2022-09.io.spdk:host1"}},
+			&pe.NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"nqn.2022-09.io.spdk:host1"}},
+			[]string{},
+			"",
+			codes.OK,
+			"",
+		},
+		"attach created namespace to another host": {
+			testNamespace.Spec.Id.Value,
+			&pe.NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"nqn.2022-09.io.spdk:host1", "nqn.2022-09.io.spdk:host2"}},
+			&pe.NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"nqn.2022-09.io.spdk:host1", "nqn.2022-09.io.spdk:host2"}},
+			[]string{spdkSubsystems, `{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			`"params":{"nqn":"nqn.2022-09.io.spdk:opi3","nsid":22,"host":"nqn.2022-09.io.spdk:host2"}`,
+			codes.OK,
+			"",
+		},
+		"hosts of visible namespace": {
+			"namespace-new",
+			&pe.NVMeNamespaceVisibility{Hosts: []string{"nqn.2022-09.io.spdk:host1"}},
+			nil,
+			[]string{},
+			"",
+			codes.InvalidArgument,
+			"hosts can be set only for hidden namespaces",
+		},
+		"missing visibility": {
+			"namespace-new",
+			nil,
+			nil,
+			[]string{},
+			"",
+			codes.InvalidArgument,
+			"visibility cannot be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Subsystems[testSubsystem.Spec.Id.Value] = &testSubsystem
+			testEnv.frontend.Nvme.Namespaces[testNamespace.Spec.Id.Value] = &testNamespace
+			testEnv.frontend.Nvme.NamespaceVisibility[testNamespace.Spec.Id.Value] = &frontend.NVMeNamespaceVisibility{
+				Hidden: true, Hosts: []string{"nqn.2022-09.io.spdk:host1"},
+			}
+
+			request := &pe.SetNVMeNamespaceVisibilityRequest{Name: tt.name, Visibility: tt.in}
+			response, err := testEnv.client.SetNVMeNamespaceVisibility(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+			var last string
+			for len(testEnv.requests) > 0 {
+				last = <-testEnv.requests
+			}
+			if !strings.Contains(last, tt.request) {
+				t.Error("request: expected", tt.request, "received", last)
+			}
+		})
+	}
+}
+
+func TestExtension_GetNVMeNamespaceVisibility(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		out     *pe.NVMeNamespaceVisibility
+		errCode codes.Code
+		errMsg  string
+	}{
+		"visible to all hosts by default": {
+			testNamespace.Spec.Id.Value,
+			&pe.NVMeNamespaceVisibility{},
+			codes.OK,
+			"",
+		},
+		"unknown namespace": {
+			"unknown-namespace",
+			nil,
+			codes.NotFound,
+			"unable to find key unknown-namespace",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, []string{})
+			defer testEnv.Close()
+			testEnv.frontend.Nvme.Namespaces[testNamespace.Spec.Id.Value] = &testNamespace
+
+			request := &pe.GetNVMeNamespaceVisibilityRequest{Name: tt.in}
+			response, err := testEnv.client.GetNVMeNamespaceVisibility(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Asymmetric Namespace Access states of NVMe controller listeners
+const (
+	AnaStateOptimized    = "optimized"
+	AnaStateNonOptimized = "non_optimized"
+	AnaStateInaccessible = "inaccessible"
+)
+
+// anaStates returns the ANA states which can be set for a listener
+func anaStates() []string {
+	return []string{AnaStateOptimized, AnaStateNonOptimized, AnaStateInaccessible}
+}
+
+// NVMeSubsystemAna are the Asymmetric Namespace Access properties of a
+// subsystem chosen when the subsystem is created
+type NVMeSubsystemAna struct {
+	// Reporting lets hosts find out the ANA state of every path to the
+	// namespaces of the subsystem
+	Reporting bool `json:"reporting"`
+}
+
+// NVMeControllerAna is the Asymmetric Namespace Access state of the path
+// through an NVMe controller. GetNVMeController reports controllers with
+// inaccessible paths as not active.
+type NVMeControllerAna struct {
+	// AnaState is optimized, non_optimized or inaccessible. SPDK can also
+	// report persistent_loss or change, which cannot be set.
+	AnaState string `json:"ana_state"`
+}
+
+// SetNVMeControllerAnaStateRequest changes the ANA state of an NVMe controller
+type SetNVMeControllerAnaStateRequest struct {
+	// Name is the ID of the NVMe controller
+	Name string
+	Ana  *NVMeControllerAna
+}
+
+// GetNVMeControllerAnaStateRequest reads the ANA state of an NVMe controller
+type GetNVMeControllerAnaStateRequest struct {
+	// Name is the ID of the NVMe controller
+	Name string
+}
+
+// SetAnaReportingByDefault defines if subsystems created by CreateNVMeSubsystem
+// report ANA states of their controllers to hosts
+func (s *Server) SetAnaReportingByDefault(enable bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.anaReporting = enable
+}
+
+// SetNVMeControllerAnaState changes the ANA state reported to hosts for the
+// path through an NVMe controller of a subsystem with ANA reporting
+func (s *Server) SetNVMeControllerAnaState(ctx context.Context, in *SetNVMeControllerAnaStateRequest) (*NVMeControllerAna, error) {
+	logging.FromContext(ctx).Infof("SetNVMeControllerAnaState: Received from client: %v %+v", in.Name, in.Ana)
+	if err := validateAna(in.Ana); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	unlock := s.lockWithSubsystem(controllersTable, in.Name)
+	defer unlock()
+	s.mu.RLock()
+	controller, ok := s.Nvme.Controllers[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	s.mu.RLock()
+	subsys, ok := s.Nvme.Subsystems[controller.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
+	if !ok {
+		err := fmt.Errorf("unable to find subsystem %s", controller.Spec.SubsystemId.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if !s.subsystemAna(subsys.Spec.Id.Value).Reporting {
+		err := status.Errorf(codes.FailedPrecondition, "ANA reporting is not enabled for NVMeSubsystem %s", subsys.Spec.Id.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	params, err := s.listenerParams(controller, s.controllerTransport(in.Name), subsys.Spec.Nqn)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if err := s.setAnaState(ctx, &params, in.Ana.AnaState); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	ana := *in.Ana
+	if err := store.SetJSON(s.store, controllerAnaTable, in.Name, &ana); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.ControllerAna[in.Name] = &ana
+	s.mu.Unlock()
+	response := ana
+	return &response, nil
+}
+
+// GetNVMeControllerAnaState reports the ANA state SPDK reports to hosts for
+// the path through an NVMe controller
+func (s *Server) GetNVMeControllerAnaState(ctx context.Context, in *GetNVMeControllerAnaStateRequest) (*NVMeControllerAna, error) {
+	logging.FromContext(ctx).Infof("GetNVMeControllerAnaState: Received from client: %v", in.Name)
+	s.mu.RLock()
+	controller, ok := s.Nvme.Controllers[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	s.mu.RLock()
+	subsys, ok := s.Nvme.Subsystems[controller.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
+	if !ok {
+		err := fmt.Errorf("unable to find subsystem %s", controller.Spec.SubsystemId.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	params, err := s.listenerParams(controller, s.controllerTransport(in.Name), subsys.Spec.Nqn)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	state, err := s.anaState(ctx, &params)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	return &NVMeControllerAna{AnaState: state}, nil
+}
+
+// controllerAna returns the ANA state set for controller id or optimized,
+// which is the state of new listeners in SPDK
+func (s *Server) controllerAna(id string) NVMeControllerAna {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if ana, ok := s.Nvme.ControllerAna[id]; ok {
+		return *ana
+	}
+	return NVMeControllerAna{AnaState: AnaStateOptimized}
+}
+
+// subsystemAna returns the ANA properties the subsystem id was created with
+func (s *Server) subsystemAna(id string) NVMeSubsystemAna {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if ana, ok := s.Nvme.SubsystemAna[id]; ok {
+		return *ana
+	}
+	return NVMeSubsystemAna{}
+}
+
+// setAnaState sets the ANA state of the listener with params in SPDK
+func (s *Server) setAnaState(ctx context.Context, params *nvmfSubsystemAddListenerParams, state string) error {
+	anaParams := nvmfSubsystemListenerSetAnaStateParams{
+		NvmfSubsystemAddListenerParams: params.NvmfSubsystemAddListenerParams,
+		AnaState:                       state,
+	}
+	return s.callSpdk(ctx, "nvmf_subsystem_listener_set_ana_state", &anaParams,
+		"Could not set ANA state of listener: "+params.ListenAddress.Traddr)
+}
+
+// anaState reads the ANA state of the listener with params from SPDK
+func (s *Server) anaState(ctx context.Context, params *nvmfSubsystemAddListenerParams) (string, error) {
+	getParams := nvmfSubsystemGetListenersParams{Nqn: params.Nqn}
+	var result []nvmfSubsystemListener
+	err := tracing.Call(ctx, s.rpc, "nvmf_subsystem_get_listeners", &getParams, &result)
+	if err != nil {
+		return "", err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	address := params.ListenAddress
+	for _, listener := range result {
+		if strings.EqualFold(listener.Address.Trtype, address.Trtype) &&
+			listener.Address.Traddr == address.Traddr &&
+			listener.Address.Trsvcid == address.Trsvcid {
+			if len(listener.AnaStates) == 0 {
+				return "", status.Errorf(codes.Internal, "SPDK reports no ANA state for listener %s", address.Traddr)
+			}
+			// the bridge sets the same state for all ANA groups
+			return listener.AnaStates[0].AnaState, nil
+		}
+	}
+	return "", status.Errorf(codes.FailedPrecondition, "listener %s of subsystem %s is missing in SPDK", address.Traddr, params.Nqn)
+}
+
+// restoreAnaState sets the ANA state of controller id again after its
+// listener was re-created in SPDK
+func (s *Server) restoreAnaState(ctx context.Context, id string, params *nvmfSubsystemAddListenerParams) error {
+	s.mu.RLock()
+	ana, ok := s.Nvme.ControllerAna[id]
+	s.mu.RUnlock()
+	if !ok || ana.AnaState == AnaStateOptimized {
+		return nil
+	}
+	return s.setAnaState(ctx, params, ana.AnaState)
+}
+
+func validateAna(ana *NVMeControllerAna) error {
+	if ana == nil {
+		return fmt.Errorf("ana cannot be empty")
+	}
+	for _, state := range anaStates() {
+		if ana.AnaState == state {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown ana_state %q, expected one of %v", ana.AnaState, anaStates())
+}
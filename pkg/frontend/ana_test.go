@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFrontEnd_SetNVMeControllerAnaState(t *testing.T) {
+	tests := map[string]struct {
+		in        *NVMeControllerAna
+		out       *NVMeControllerAna
+		spdk      string
+		reporting bool
+		exist     bool
+		errCode   codes.Code
+		errMsg    string
+	}{
+		"non optimized path": {
+			&NVMeControllerAna{AnaState: AnaStateNonOptimized},
+			&NVMeControllerAna{AnaState: AnaStateNonOptimized},
+			`true`,
+			true,
+			true,
+			codes.OK,
+			"",
+		},
+		"unknown state": {
+			&NVMeControllerAna{AnaState: "change"},
+			nil,
+			`true`,
+			true,
+			true,
+			codes.InvalidArgument,
+			`unknown ana_state "change", expected one of [optimized non_optimized inaccessible]`,
+		},
+		"empty state": {
+			nil,
+			nil,
+			`true`,
+			true,
+			true,
+			codes.InvalidArgument,
+			"ana cannot be empty",
+		},
+		"reporting disabled": {
+			&NVMeControllerAna{AnaState: AnaStateInaccessible},
+			nil,
+			`true`,
+			false,
+			true,
+			codes.FailedPrecondition,
+			"ANA reporting is not enabled for NVMeSubsystem subsystem-test",
+		},
+		"spdk failure": {
+			&NVMeControllerAna{AnaState: AnaStateInaccessible},
+			nil,
+			`false`,
+			true,
+			true,
+			codes.InvalidArgument,
+			"Could not set ANA state of listener: 127.0.0.1",
+		},
+		"unknown controller": {
+			&NVMeControllerAna{AnaState: AnaStateOptimized},
+			nil,
+			`true`,
+			true,
+			false,
+			codes.NotFound,
+			"unable to find key controller-test",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{
+				"nvmf_subsystem_listener_set_ana_state": tt.spdk,
+			}))
+			s.Nvme.Subsystems["subsystem-test"] = &pb.NVMeSubsystem{
+				Spec: &pb.NVMeSubsystemSpec{Id: &pc.ObjectKey{Value: "subsystem-test"}, Nqn: "nqn.2022-09.io.spdk:opi3"},
+			}
+			s.Nvme.SubsystemAna["subsystem-test"] = &NVMeSubsystemAna{Reporting: tt.reporting}
+			if tt.exist {
+				s.Nvme.Controllers["controller-test"] = &pb.NVMeController{Spec: &pb.NVMeControllerSpec{
+					Id: &pc.ObjectKey{Value: "controller-test"}, SubsystemId: &pc.ObjectKey{Value: "subsystem-test"},
+				}}
+			}
+
+			request := &SetNVMeControllerAnaStateRequest{Name: "controller-test", Ana: tt.in}
+			response, err := s.SetNVMeControllerAnaState(context.Background(), request)
+			if !reflect.DeepEqual(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if tt.out != nil && !reflect.DeepEqual(s.Nvme.ControllerAna["controller-test"], tt.out) {
+				t.Error("expected state to be kept", tt.out)
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestFrontEnd_NVMeControllerAnaStateReported(t *testing.T) {
+	tests := map[string]struct {
+		spdk    string
+		out     *NVMeControllerAna
+		errCode codes.Code
+		errMsg  string
+	}{
+		"state of the listener": {
+			`[{"address":{"trtype":"TCP","adrfam":"IPv4","traddr":"127.0.0.1","trsvcid":"4420"},` +
+				`"ana_states":[{"ana_group":1,"ana_state":"change"}]}]`,
+			&NVMeControllerAna{AnaState: "change"},
+			codes.OK,
+			"",
+		},
+		"missing listener": {
+			`[{"address":{"trtype":"TCP","adrfam":"IPv4","traddr":"127.0.0.1","trsvcid":"4421"},` +
+				`"ana_states":[{"ana_group":1,"ana_state":"optimized"}]}]`,
+			nil,
+			codes.FailedPrecondition,
+			"listener 127.0.0.1 of subsystem nqn.2022-09.io.spdk:opi3 is missing in SPDK",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{
+				"nvmf_subsystem_get_listeners": tt.spdk,
+			}))
+			s.Nvme.Subsystems["subsystem-test"] = &pb.NVMeSubsystem{
+				Spec: &pb.NVMeSubsystemSpec{Id: &pc.ObjectKey{Value: "subsystem-test"}, Nqn: "nqn.2022-09.io.spdk:opi3"},
+			}
+			s.Nvme.Controllers["controller-test"] = &pb.NVMeController{Spec: &pb.NVMeControllerSpec{
+				Id: &pc.ObjectKey{Value: "controller-test"}, SubsystemId: &pc.ObjectKey{Value: "subsystem-test"},
+			}}
+
+			ana, err := s.GetNVMeControllerAnaState(context.Background(), &GetNVMeControllerAnaStateRequest{Name: "controller-test"})
+			if !reflect.DeepEqual(ana, tt.out) {
+				t.Error("response: expected", tt.out, "received", ana)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+
+	s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+	if _, err := s.GetNVMeControllerAnaState(context.Background(), &GetNVMeControllerAnaStateRequest{Name: "unknown-id"}); status.Code(err) != codes.NotFound {
+		t.Error("expected NotFound for unknown controller, received", err)
+	}
+}
+
+func TestFrontEnd_GetNVMeControllerActive(t *testing.T) {
+	s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+	s.Nvme.Controllers["controller-test"] = &pb.NVMeController{Spec: &pb.NVMeControllerSpec{
+		Id: &pc.ObjectKey{Value: "controller-test"}, SubsystemId: &pc.ObjectKey{Value: "subsystem-test"},
+	}}
+	ctx := context.Background()
+
+	controller, err := s.GetNVMeController(ctx, &pb.GetNVMeControllerRequest{Name: "controller-test"})
+	if err != nil || !controller.Status.Active {
+		t.Error("expected optimized controller to be active, received", controller, err)
+	}
+
+	s.Nvme.ControllerAna["controller-test"] = &NVMeControllerAna{AnaState: AnaStateInaccessible}
+	controller, err = s.GetNVMeController(ctx, &pb.GetNVMeControllerRequest{Name: "controller-test"})
+	if err != nil || controller.Status.Active {
+		t.Error("expected inaccessible controller not to be active, received", controller, err)
+	}
+}
+
+func TestFrontEnd_AnaReportingByDefault(t *testing.T) {
+	s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+	s.SetAnaReportingByDefault(true)
+	if !s.anaReporting {
+		t.Error("expected ANA reporting for new subsystems")
+	}
+	if s.subsystemAna("subsystem-test").Reporting {
+		t.Error("expected no ANA reporting for unknown subsystem")
+	}
+}
@@ -8,112 +8,185 @@ package frontend
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/google/uuid"
 	"github.com/opiproject/gospdk/spdk"
 	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 	"github.com/ulule/deepcopier"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 // CreateVirtioBlk creates a Virtio block device
-func (s *Server) CreateVirtioBlk(_ context.Context, in *pb.CreateVirtioBlkRequest) (*pb.VirtioBlk, error) {
-	log.Printf("CreateVirtioBlk: Received from client: %v", in)
+func (s *Server) CreateVirtioBlk(ctx context.Context, in *pb.CreateVirtioBlkRequest) (*pb.VirtioBlk, error) {
+	logging.FromContext(ctx).Infof("CreateVirtioBlk: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(blkCtrlsTable, in.VirtioBlk.Id.Value))
+	defer unlock()
 	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
 	controller, ok := s.Virt.BlkCtrls[in.VirtioBlk.Id.Value]
+	s.mu.RUnlock()
 	if ok {
-		log.Printf("Already existing NVMeController with id %v", in.VirtioBlk.Id.Value)
+		logging.FromContext(ctx).Infof("Already existing NVMeController with id %v", in.VirtioBlk.Id.Value)
 		return controller, nil
 	}
 	// not found, so create a new one
-	params := spdk.VhostCreateBlkControllerParams{
-		Ctrlr:   in.VirtioBlk.Id.Value,
-		DevName: in.VirtioBlk.VolumeId.Value,
+	options := s.wantedBlkOptions(in.VirtioBlk.Id.Value)
+	if err := s.createVhostBlk(ctx, in.VirtioBlk, &options); err != nil {
+		logging.FromContext(ctx).Errorf("Could not create: %v", err)
+		return nil, err
 	}
-	var result spdk.VhostCreateBlkControllerResult
-	err := s.rpc.Call("vhost_create_blk_controller", &params, &result)
-	if err != nil {
-		log.Printf("error: %v", err)
-		return nil, fmt.Errorf("%w for %v", spdk.ErrFailedSpdkCall, in)
+	if options != (VirtioBlkOptions{}) {
+		if err := s.setActiveBlkOptions(in.VirtioBlk.Id.Value, &options); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
 	}
-	log.Printf("Received from SPDK: %v", result)
-	if !result {
-		log.Printf("Could not create: %v", in)
-		return nil, fmt.Errorf("%w for %v", spdk.ErrUnexpectedSpdkCallResult, in)
+	if err := s.store.Set(blkCtrlsTable, in.VirtioBlk.Id.Value, in.VirtioBlk); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
 	}
+	s.mu.Lock()
 	s.Virt.BlkCtrls[in.VirtioBlk.Id.Value] = in.VirtioBlk
+	s.mu.Unlock()
 	// s.VirtioCtrls[in.VirtioBlk.Id.Value].Status = &pb.NVMeControllerStatus{Active: true}
 	response := &pb.VirtioBlk{}
-	err = deepcopier.Copy(in.VirtioBlk).To(response)
+	err := deepcopier.Copy(in.VirtioBlk).To(response)
 	if err != nil {
-		log.Printf("Error at response creation: %v", err)
+		logging.FromContext(ctx).Errorf("Error at response creation: %v", err)
 		return nil, status.Error(codes.Internal, "Failed to construct device create response")
 	}
 	return response, nil
 }
 
 // DeleteVirtioBlk deletes a Virtio block device
-func (s *Server) DeleteVirtioBlk(_ context.Context, in *pb.DeleteVirtioBlkRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteVirtioBlk: Received from client: %v", in)
+func (s *Server) DeleteVirtioBlk(ctx context.Context, in *pb.DeleteVirtioBlkRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteVirtioBlk: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(blkCtrlsTable, in.Name))
+	defer unlock()
+	s.mu.RLock()
 	controller, ok := s.Virt.BlkCtrls[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		if in.AllowMissing {
 			return &emptypb.Empty{}, nil
 		}
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	params := spdk.VhostDeleteControllerParams{
 		Ctrlr: in.Name,
 	}
 	var result spdk.VhostDeleteControllerResult
-	err := s.rpc.Call("vhost_delete_controller", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_delete_controller", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
-		log.Printf("Could not delete: %v", in)
+		logging.FromContext(ctx).Errorf("Could not delete: %v", in)
 	}
+	for _, table := range []string{blkCtrlsTable, blkOptionsTable, activeBlkOptionsTable} {
+		if err := s.store.Delete(table, controller.Id.Value); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, server.StoreError(err)
+		}
+	}
+	s.mu.Lock()
 	delete(s.Virt.BlkCtrls, controller.Id.Value)
+	delete(s.Virt.BlkOptions, controller.Id.Value)
+	delete(s.Virt.ActiveBlkOptions, controller.Id.Value)
+	s.mu.Unlock()
 	return &emptypb.Empty{}, nil
 }
 
-// UpdateVirtioBlk updates a Virtio block device
-func (s *Server) UpdateVirtioBlk(_ context.Context, in *pb.UpdateVirtioBlkRequest) (*pb.VirtioBlk, error) {
-	log.Printf("UpdateVirtioBlk: Received from client: %v", in)
-	return nil, status.Errorf(codes.Unimplemented, "UpdateVirtioBlk method is not implemented")
+// UpdateVirtioBlk updates a Virtio block device. Its vhost controller is
+// re-created to swap the volume or to apply options set with
+// SetVirtioBlkOptions, so the guest device has to be unplugged meanwhile.
+func (s *Server) UpdateVirtioBlk(ctx context.Context, in *pb.UpdateVirtioBlkRequest) (*pb.VirtioBlk, error) {
+	logging.FromContext(ctx).Infof("UpdateVirtioBlk: Received from client: %v", logging.Redact(in))
+	if in.GetVirtioBlk().GetId().GetValue() == "" {
+		err := status.Error(codes.InvalidArgument, "virtio_blk.id cannot be empty")
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	fields, err := server.UpdatedFields(in.UpdateMask, "volume_id")
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	unlock := s.locks.Lock(server.LockKey(blkCtrlsTable, in.VirtioBlk.Id.Value))
+	defer unlock()
+	s.mu.RLock()
+	controller, ok := s.Virt.BlkCtrls[in.VirtioBlk.Id.Value]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.VirtioBlk.Id.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	response := proto.Clone(controller).(*pb.VirtioBlk)
+	if fields["volume_id"] && in.VirtioBlk.VolumeId.GetValue() != "" {
+		response.VolumeId = in.VirtioBlk.VolumeId
+	}
+	options := s.wantedBlkOptions(response.Id.Value)
+	active := s.activeBlkOptions(response.Id.Value)
+	if proto.Equal(response, controller) && options == active {
+		return response, nil
+	}
+	if err := s.replaceVhostBlk(ctx, response, &options, controller, &active); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if err := s.setActiveBlkOptions(response.Id.Value, &options); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if err := s.store.Set(blkCtrlsTable, response.Id.Value, response); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Virt.BlkCtrls[response.Id.Value] = response
+	s.mu.Unlock()
+	return response, nil
 }
 
 // ListVirtioBlks lists Virtio block devices
-func (s *Server) ListVirtioBlks(_ context.Context, in *pb.ListVirtioBlksRequest) (*pb.ListVirtioBlksResponse, error) {
-	log.Printf("ListVirtioBlks: Received from client: %v", in)
-	size, offset, perr := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+func (s *Server) ListVirtioBlks(ctx context.Context, in *pb.ListVirtioBlksRequest) (*pb.ListVirtioBlksResponse, error) {
+	logging.FromContext(ctx).Infof("ListVirtioBlks: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	size, offset, perr := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if perr != nil {
-		log.Printf("error: %v", perr)
+		logging.FromContext(ctx).Error(perr)
 		return nil, perr
 	}
 	var result []spdk.VhostGetControllersResult
-	err := s.rpc.Call("vhost_get_controllers", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_get_controllers", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	token := ""
-	log.Printf("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
+	logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
 	result, hasMoreElements := server.LimitPagination(result, offset, size)
 	if hasMoreElements {
 		token = uuid.New().String()
+		s.mu.Lock()
 		s.Pagination[token] = offset + size
+		s.mu.Unlock()
 	}
 	Blobarray := make([]*pb.VirtioBlk, len(result))
 	for i := range result {
@@ -127,27 +200,29 @@ func (s *Server) ListVirtioBlks(_ context.Context, in *pb.ListVirtioBlksRequest)
 }
 
 // GetVirtioBlk gets a Virtio block device
-func (s *Server) GetVirtioBlk(_ context.Context, in *pb.GetVirtioBlkRequest) (*pb.VirtioBlk, error) {
-	log.Printf("GetVirtioBlk: Received from client: %v", in)
+func (s *Server) GetVirtioBlk(ctx context.Context, in *pb.GetVirtioBlkRequest) (*pb.VirtioBlk, error) {
+	logging.FromContext(ctx).Infof("GetVirtioBlk: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
 	_, ok := s.Virt.BlkCtrls[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		msg := fmt.Sprintf("Could not find Controller: %s", in.Name)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	params := spdk.VhostGetControllersParams{
 		Name: in.Name,
 	}
 	var result []spdk.VhostGetControllersResult
-	err := s.rpc.Call("vhost_get_controllers", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_get_controllers", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	return &pb.VirtioBlk{
@@ -156,8 +231,22 @@ func (s *Server) GetVirtioBlk(_ context.Context, in *pb.GetVirtioBlkRequest) (*p
 		VolumeId: &pc.ObjectKey{Value: "TBD"}}, nil
 }
 
-// VirtioBlkStats gets a Virtio block device stats
-func (s *Server) VirtioBlkStats(_ context.Context, in *pb.VirtioBlkStatsRequest) (*pb.VirtioBlkStatsResponse, error) {
-	log.Printf("VirtioBlkStats: Received from client: %v", in)
-	return nil, status.Errorf(codes.Unimplemented, "VirtioBlkStats method is not implemented")
+// VirtioBlkStats gets a Virtio block device stats. They are the statistics
+// of its volume, SPDK does not count requests per virtqueue.
+func (s *Server) VirtioBlkStats(ctx context.Context, in *pb.VirtioBlkStatsRequest) (*pb.VirtioBlkStatsResponse, error) {
+	logging.FromContext(ctx).Infof("VirtioBlkStats: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	controller, ok := s.Virt.BlkCtrls[in.ControllerId.Value]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.ControllerId.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	stats, err := s.bdevStats(ctx, controller.VolumeId.Value)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	return &pb.VirtioBlkStatsResponse{Id: in.ControllerId, Stats: stats}, nil
 }
@@ -98,12 +98,52 @@ func TestFrontEnd_UpdateVirtioBlk(t *testing.T) {
 		errMsg  string
 		start   bool
 	}{
-		"unimplemented method": {
-			&pb.VirtioBlk{},
+		"valid request with volume swap": {
+			&pb.VirtioBlk{Id: testVirtioCtrl.Id, VolumeId: &pc.ObjectKey{Value: "Malloc43"}},
+			&pb.VirtioBlk{
+				Id:       testVirtioCtrl.Id,
+				PcieId:   testVirtioCtrl.PcieId,
+				VolumeId: &pc.ObjectKey{Value: "Malloc43"},
+				MaxIoQps: testVirtioCtrl.MaxIoQps,
+			},
+			[]string{
+				`{"id":%d,"error":{"code":0,"message":""},"result":true}`,
+				`{"id":%d,"error":{"code":0,"message":""},"result":true}`,
+			},
+			codes.OK,
+			"",
+			true,
+		},
+		"valid request with busy controller": {
+			&pb.VirtioBlk{Id: testVirtioCtrl.Id, VolumeId: &pc.ObjectKey{Value: "Malloc43"}},
 			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":false}`},
+			codes.FailedPrecondition,
+			fmt.Sprintf("Could not delete virtio-blk: %v", testVirtioCtrl.Id.Value),
+			true,
+		},
+		"valid request without changes": {
+			&pb.VirtioBlk{Id: testVirtioCtrl.Id, VolumeId: testVirtioCtrl.VolumeId},
+			&testVirtioCtrl,
 			[]string{""},
-			codes.Unimplemented,
-			fmt.Sprintf("%v method is not implemented", "UpdateVirtioBlk"),
+			codes.OK,
+			"",
+			false,
+		},
+		"valid request with unknown key": {
+			&pb.VirtioBlk{Id: &pc.ObjectKey{Value: "unknown-id"}},
+			nil,
+			[]string{""},
+			codes.NotFound,
+			fmt.Sprintf("unable to find key %v", "unknown-id"),
+			false,
+		},
+		"missing virtio-blk": {
+			nil,
+			nil,
+			[]string{""},
+			codes.InvalidArgument,
+			"virtio_blk.id cannot be empty",
 			false,
 		},
 	}
@@ -114,10 +154,12 @@ func TestFrontEnd_UpdateVirtioBlk(t *testing.T) {
 			testEnv := createTestEnvironment(tt.start, tt.spdk)
 			defer testEnv.Close()
 
+			testEnv.opiSpdkServer.Virt.BlkCtrls[testVirtioCtrl.Id.Value] = &testVirtioCtrl
+
 			request := &pb.UpdateVirtioBlkRequest{VirtioBlk: tt.in}
 			response, err := testEnv.client.UpdateVirtioBlk(testEnv.ctx, request)
-			if response != nil {
-				t.Error("response: expected", codes.Unimplemented, "received", response)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
 			}
 
 			if err != nil {
@@ -422,12 +464,37 @@ func TestFrontEnd_VirtioBlkStats(t *testing.T) {
 		errMsg  string
 		start   bool
 	}{
-		"unimplemented method": {
-			"test",
-			&pb.VolumeStats{},
+		"valid request": {
+			testVirtioCtrl.Id.Value,
+			&pb.VolumeStats{
+				ReadBytesCount:    36864,
+				ReadOpsCount:      9,
+				WriteBytesCount:   4096,
+				WriteOpsCount:     1,
+				ReadLatencyTicks:  3287,
+				WriteLatencyTicks: 1200,
+			},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":{"tick_rate":2490000000,"ticks":12345,` +
+				`"bdevs":[{"name":"Malloc42","bytes_read":36864,"num_read_ops":9,"bytes_written":4096,"num_write_ops":1,` +
+				`"bytes_unmapped":0,"num_unmap_ops":0,"read_latency_ticks":3287,"write_latency_ticks":1200,"unmap_latency_ticks":0}]}}`},
+			codes.OK,
+			"",
+			true,
+		},
+		"valid request with invalid SPDK response": {
+			testVirtioCtrl.Id.Value,
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":{"tick_rate":2490000000,"ticks":12345,"bdevs":[]}}`},
+			codes.InvalidArgument,
+			fmt.Sprintf("expecting exactly 1 result, got %d", 0),
+			true,
+		},
+		"valid request with unknown key": {
+			"unknown-id",
+			nil,
 			[]string{""},
-			codes.Unimplemented,
-			fmt.Sprintf("%v method is not implemented", "VirtioBlkStats"),
+			codes.NotFound,
+			fmt.Sprintf("unable to find key %v", "unknown-id"),
 			false,
 		},
 	}
@@ -442,8 +509,8 @@ func TestFrontEnd_VirtioBlkStats(t *testing.T) {
 
 			request := &pb.VirtioBlkStatsRequest{ControllerId: &pc.ObjectKey{Value: tt.in}}
 			response, err := testEnv.client.VirtioBlkStats(testEnv.ctx, request)
-			if response != nil {
-				t.Error("response: expected", codes.Unimplemented, "received", response)
+			if !proto.Equal(response.GetStats(), tt.out) {
+				t.Error("response: expected", tt.out, "received", response.GetStats())
 			}
 
 			if err != nil {
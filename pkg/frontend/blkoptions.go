@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/opiproject/gospdk/spdk"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxVirtioBlkQueues is the number of virtqueues SPDK serves per vhost
+// controller
+const maxVirtioBlkQueues = 256
+
+// blkName is the set of IDs accepted for virtio-blk devices. SPDK names the
+// vhost socket after the controller, so the ID has to be a file name.
+var blkName = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]{0,63}$`)
+
+// VirtioBlkOptions tunes the vhost controller of a virtio-blk device. It is
+// used by CreateVirtioBlk or, for created devices, UpdateVirtioBlk.
+type VirtioBlkOptions struct {
+	// Cpumask is the hexadecimal mask of the SPDK reactors the controller
+	// can be scheduled on, all reactors if empty. It has to be a subset of
+	// the SPDK core mask.
+	Cpumask string `json:"cpumask,omitempty"`
+	// NumQueues is the number of request queues of the guest device. SPDK
+	// serves as many queues as the driver sets up, so it is used when the
+	// device is plugged to QEMU. 0 keeps the default of QEMU.
+	NumQueues int `json:"num_queues,omitempty"`
+	// ReadOnly makes the device reject writes
+	ReadOnly bool `json:"readonly,omitempty"`
+	// PackedRing offers packed virtqueues to the driver
+	PackedRing bool `json:"packed_ring,omitempty"`
+}
+
+// SetVirtioBlkOptionsRequest sets the options of a virtio-blk device
+type SetVirtioBlkOptionsRequest struct {
+	// Name is the ID of the virtio-blk device
+	Name    string
+	Options *VirtioBlkOptions
+}
+
+// GetVirtioBlkOptionsRequest reads the options of a virtio-blk device
+type GetVirtioBlkOptionsRequest struct {
+	// Name is the ID of the virtio-blk device
+	Name string
+}
+
+// SetVirtioBlkOptions sets the options of a virtio-blk device. They take
+// effect when the device is created or, if it already exists, when it is
+// updated with UpdateVirtioBlk. Options are kept until the device is
+// deleted, options set for a device that is never created are dropped by
+// setting the default options.
+func (s *Server) SetVirtioBlkOptions(ctx context.Context, in *SetVirtioBlkOptionsRequest) (*VirtioBlkOptions, error) {
+	logging.FromContext(ctx).Infof("SetVirtioBlkOptions: Received from client: %v %+v", in.Name, in.Options)
+	if err := validateBlkName(in.Name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := validateBlkOptions(in.Options); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := s.checkCoreMask(ctx, in.Options.Cpumask); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	unlock := s.locks.Lock(server.LockKey(blkCtrlsTable, in.Name))
+	defer unlock()
+	options := *in.Options
+	if err := s.setWantedBlkOptions(in.Name, &options); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	response := options
+	return &response, nil
+}
+
+// GetVirtioBlkOptions reports the options the vhost controller of a
+// virtio-blk device runs with. The cpumask and read-only mode are read from
+// SPDK.
+func (s *Server) GetVirtioBlkOptions(ctx context.Context, in *GetVirtioBlkOptionsRequest) (*VirtioBlkOptions, error) {
+	logging.FromContext(ctx).Infof("GetVirtioBlkOptions: Received from client: %v", in.Name)
+	s.mu.RLock()
+	_, ok := s.Virt.BlkCtrls[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	params := spdk.VhostGetControllersParams{
+		Name: in.Name,
+	}
+	var result []vhostController
+	err := tracing.Call(ctx, s.rpc, "vhost_get_controllers", &params, &result)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if len(result) != 1 {
+		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
+		logging.FromContext(ctx).Error(msg)
+		return nil, status.Errorf(codes.InvalidArgument, msg)
+	}
+	if result[0].BackendSpecific.Block == nil {
+		err := status.Errorf(codes.FailedPrecondition, "vhost controller %s is not a virtio-blk controller", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	response := s.activeBlkOptions(in.Name)
+	response.Cpumask = result[0].Cpumask
+	response.ReadOnly = result[0].BackendSpecific.Block.Readonly
+	return &response, nil
+}
+
+// VirtioBlkQueues returns the number of request queues virtio-blk device
+// id is plugged with, 0 for the default of QEMU
+func (s *Server) VirtioBlkQueues(id string) int {
+	return s.activeBlkOptions(id).NumQueues
+}
+
+// createVhostBlk creates the vhost controller of blk with options
+func (s *Server) createVhostBlk(ctx context.Context, blk *pb.VirtioBlk, options *VirtioBlkOptions) error {
+	params := vhostCreateBlkControllerParams{
+		Ctrlr:      blk.Id.Value,
+		DevName:    blk.VolumeId.Value,
+		Cpumask:    options.Cpumask,
+		Readonly:   options.ReadOnly,
+		PackedRing: options.PackedRing,
+	}
+	var result spdk.VhostCreateBlkControllerResult
+	err := tracing.Call(ctx, s.rpc, "vhost_create_blk_controller", &params, &result)
+	if err != nil {
+		return fmt.Errorf("%w for %v: %v", spdk.ErrFailedSpdkCall, blk.Id.Value, err)
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if !result {
+		return fmt.Errorf("%w for %v", spdk.ErrUnexpectedSpdkCallResult, blk.Id.Value)
+	}
+	return nil
+}
+
+// replaceVhostBlk re-creates the vhost controller of old as blk with
+// options. SPDK refuses to delete a controller a driver is connected to,
+// so the guest device has to be unplugged before. If the new controller
+// cannot be created, the old one is restored with oldOptions.
+func (s *Server) replaceVhostBlk(ctx context.Context, blk *pb.VirtioBlk, options *VirtioBlkOptions, old *pb.VirtioBlk, oldOptions *VirtioBlkOptions) error {
+	params := spdk.VhostDeleteControllerParams{
+		Ctrlr: old.Id.Value,
+	}
+	var result spdk.VhostDeleteControllerResult
+	err := tracing.Call(ctx, s.rpc, "vhost_delete_controller", &params, &result)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if !result {
+		return status.Errorf(codes.FailedPrecondition, "Could not delete virtio-blk: %s", old.Id.Value)
+	}
+	err = s.createVhostBlk(ctx, blk, options)
+	if err == nil {
+		return nil
+	}
+	if rerr := s.createVhostBlk(ctx, old, oldOptions); rerr != nil {
+		return status.Errorf(codes.Internal, "virtio-blk %s is lost: %v, cannot restore it: %v", old.Id.Value, err, rerr)
+	}
+	return err
+}
+
+// setWantedBlkOptions records the options the vhost controller of
+// virtio-blk device id is going to be created with. The default options
+// are not stored, so they drop the options of devices that do not exist.
+func (s *Server) setWantedBlkOptions(id string, options *VirtioBlkOptions) error {
+	s.mu.RLock()
+	_, exists := s.Virt.BlkCtrls[id]
+	s.mu.RUnlock()
+	if *options == (VirtioBlkOptions{}) && !exists {
+		if err := s.store.Delete(blkOptionsTable, id); err != nil {
+			return server.StoreError(err)
+		}
+		s.mu.Lock()
+		delete(s.Virt.BlkOptions, id)
+		s.mu.Unlock()
+		return nil
+	}
+	if err := store.SetJSON(s.store, blkOptionsTable, id, options); err != nil {
+		return server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Virt.BlkOptions[id] = options
+	s.mu.Unlock()
+	return nil
+}
+
+// setActiveBlkOptions records the options the vhost controller of
+// virtio-blk device id was created with
+func (s *Server) setActiveBlkOptions(id string, options *VirtioBlkOptions) error {
+	active := *options
+	if err := store.SetJSON(s.store, activeBlkOptionsTable, id, &active); err != nil {
+		return server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Virt.ActiveBlkOptions[id] = &active
+	s.mu.Unlock()
+	return nil
+}
+
+// activeBlkOptions returns the options the vhost controller of virtio-blk
+// device id was created with
+func (s *Server) activeBlkOptions(id string) VirtioBlkOptions {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if options, ok := s.Virt.ActiveBlkOptions[id]; ok {
+		return *options
+	}
+	return VirtioBlkOptions{}
+}
+
+// wantedBlkOptions returns the options the vhost controller of virtio-blk
+// device id is going to be created with
+func (s *Server) wantedBlkOptions(id string) VirtioBlkOptions {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if options, ok := s.Virt.BlkOptions[id]; ok {
+		return *options
+	}
+	return VirtioBlkOptions{}
+}
+
+// checkCoreMask fails if cpumask selects cores SPDK runs no reactor on
+func (s *Server) checkCoreMask(ctx context.Context, cpumask string) error {
+	if cpumask == "" {
+		return nil
+	}
+	var result frameworkGetReactorsResult
+	err := tracing.Call(ctx, s.rpc, "framework_get_reactors", nil, &result)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	coreMask := new(big.Int)
+	for _, reactor := range result.Reactors {
+		coreMask.SetBit(coreMask, reactor.Lcore, 1)
+	}
+	mask, _ := parseCpumask(cpumask)
+	if new(big.Int).AndNot(mask, coreMask).Sign() != 0 {
+		return status.Errorf(codes.InvalidArgument, "cpumask %s selects cores out of the SPDK core mask 0x%x", cpumask, coreMask)
+	}
+	return nil
+}
+
+// parseCpumask parses a hexadecimal mask with an optional 0x prefix
+func parseCpumask(cpumask string) (*big.Int, bool) {
+	return new(big.Int).SetString(strings.TrimPrefix(strings.ToLower(cpumask), "0x"), 16)
+}
+
+func validateBlkName(name string) error {
+	if !blkName.MatchString(name) {
+		return fmt.Errorf("name %q must be 1 to 64 letters, digits, '_', '.' or '-' and cannot start with '.'", name)
+	}
+	return nil
+}
+
+func validateBlkOptions(options *VirtioBlkOptions) error {
+	if options == nil {
+		return fmt.Errorf("options cannot be empty")
+	}
+	if options.Cpumask != "" {
+		mask, ok := parseCpumask(options.Cpumask)
+		if !ok || mask.Sign() <= 0 {
+			return fmt.Errorf("cpumask %q must be a non-zero hexadecimal mask", options.Cpumask)
+		}
+	}
+	if options.NumQueues < 0 || options.NumQueues > maxVirtioBlkQueues {
+		return fmt.Errorf("num_queues must be between 0 and %d, got %d", maxVirtioBlkQueues, options.NumQueues)
+	}
+	return nil
+}
@@ -0,0 +1,365 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/opiproject/gospdk/spdk"
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// vhostSpdkStub records the vhost controllers SPDK is asked to create and
+// fails to create the ones of failingBdev
+type vhostSpdkStub struct {
+	spdk.JSONRPC
+	created     []vhostCreateBlkControllerParams
+	failingBdev string
+}
+
+func (s *vhostSpdkStub) Call(method string, params, result interface{}) error {
+	if method == "vhost_create_blk_controller" {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		var ctrlr vhostCreateBlkControllerParams
+		if err := json.Unmarshal(data, &ctrlr); err != nil {
+			return err
+		}
+		s.created = append(s.created, ctrlr)
+		if ctrlr.DevName == s.failingBdev {
+			return errors.New("bdev not found")
+		}
+	}
+	return s.JSONRPC.Call(method, params, result)
+}
+
+func newVhostSpdkStub(failingBdev string) *vhostSpdkStub {
+	return &vhostSpdkStub{
+		JSONRPC: server.CreateTestSpdkStub(map[string]string{
+			"vhost_create_blk_controller": `true`,
+			"vhost_delete_controller":     `true`,
+			"framework_get_reactors":      `{"tick_rate":2490000000,"reactors":[{"lcore":0},{"lcore":1}]}`,
+		}),
+		failingBdev: failingBdev,
+	}
+}
+
+func TestFrontEnd_SetVirtioBlkOptions(t *testing.T) {
+	tests := map[string]struct {
+		in      *VirtioBlkOptions
+		errCode codes.Code
+		errMsg  string
+	}{
+		"valid options": {
+			&VirtioBlkOptions{Cpumask: "0x3", NumQueues: 4},
+			codes.OK,
+			"",
+		},
+		"missing options": {
+			nil,
+			codes.InvalidArgument,
+			"options cannot be empty",
+		},
+		"invalid cpumask": {
+			&VirtioBlkOptions{Cpumask: "[0-3]"},
+			codes.InvalidArgument,
+			`cpumask "[0-3]" must be a non-zero hexadecimal mask`,
+		},
+		"zero cpumask": {
+			&VirtioBlkOptions{Cpumask: "0x0"},
+			codes.InvalidArgument,
+			`cpumask "0x0" must be a non-zero hexadecimal mask`,
+		},
+		"cpumask out of core mask": {
+			&VirtioBlkOptions{Cpumask: "0x6"},
+			codes.InvalidArgument,
+			"cpumask 0x6 selects cores out of the SPDK core mask 0x3",
+		},
+		"too many queues": {
+			&VirtioBlkOptions{NumQueues: 257},
+			codes.InvalidArgument,
+			"num_queues must be between 0 and 256, got 257",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(newVhostSpdkStub(""))
+
+			response, err := s.SetVirtioBlkOptions(context.Background(),
+				&SetVirtioBlkOptionsRequest{Name: testVirtioCtrl.Id.Value, Options: tt.in})
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+			if tt.errCode == codes.OK && !reflect.DeepEqual(response, tt.in) {
+				t.Error("response: expected", tt.in, "received", response)
+			}
+			if _, ok := s.Virt.BlkOptions[testVirtioCtrl.Id.Value]; ok != (tt.errCode == codes.OK) {
+				t.Error("options stored:", ok)
+			}
+		})
+	}
+}
+
+func TestFrontEnd_SetVirtioBlkOptionsName(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		options *VirtioBlkOptions
+		exists  bool
+		stored  bool
+		errCode codes.Code
+		errMsg  string
+	}{
+		"malformed name": {
+			"../virtio-blk-42",
+			&VirtioBlkOptions{NumQueues: 4},
+			false,
+			false,
+			codes.InvalidArgument,
+			`name "../virtio-blk-42" must be 1 to 64 letters, digits, '_', '.' or '-' and cannot start with '.'`,
+		},
+		"empty name": {
+			"",
+			&VirtioBlkOptions{NumQueues: 4},
+			false,
+			false,
+			codes.InvalidArgument,
+			`name "" must be 1 to 64 letters, digits, '_', '.' or '-' and cannot start with '.'`,
+		},
+		"default options of missing device": {
+			testVirtioCtrl.Id.Value,
+			&VirtioBlkOptions{},
+			false,
+			false,
+			codes.OK,
+			"",
+		},
+		"default options of existing device": {
+			testVirtioCtrl.Id.Value,
+			&VirtioBlkOptions{},
+			true,
+			true,
+			codes.OK,
+			"",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(newVhostSpdkStub(""))
+			s.Virt.BlkOptions[testVirtioCtrl.Id.Value] = &VirtioBlkOptions{NumQueues: 2}
+			if tt.exists {
+				s.Virt.BlkCtrls[testVirtioCtrl.Id.Value] = &testVirtioCtrl
+			}
+
+			_, err := s.SetVirtioBlkOptions(context.Background(),
+				&SetVirtioBlkOptionsRequest{Name: tt.name, Options: tt.options})
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+			if _, ok := s.Virt.BlkOptions[tt.name]; ok != tt.stored {
+				t.Error("options stored: expected", tt.stored, "received", ok)
+			}
+		})
+	}
+}
+
+func TestFrontEnd_ApplyVirtioBlkOptions(t *testing.T) {
+	options := &VirtioBlkOptions{Cpumask: "0x3", NumQueues: 4}
+	tests := map[string]struct {
+		volume  string
+		failing string
+		created []vhostCreateBlkControllerParams
+		active  VirtioBlkOptions
+		errCode codes.Code
+	}{
+		"options applied": {
+			"",
+			"",
+			[]vhostCreateBlkControllerParams{{Ctrlr: "virtio-blk-42", DevName: "Malloc42", Cpumask: "0x3"}},
+			*options,
+			codes.OK,
+		},
+		"volume swapped": {
+			"Malloc43",
+			"",
+			[]vhostCreateBlkControllerParams{{Ctrlr: "virtio-blk-42", DevName: "Malloc43", Cpumask: "0x3"}},
+			*options,
+			codes.OK,
+		},
+		"old controller restored": {
+			"Malloc43",
+			"Malloc43",
+			[]vhostCreateBlkControllerParams{
+				{Ctrlr: "virtio-blk-42", DevName: "Malloc43", Cpumask: "0x3"},
+				{Ctrlr: "virtio-blk-42", DevName: "Malloc42"},
+			},
+			VirtioBlkOptions{},
+			codes.Unknown,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			stub := newVhostSpdkStub(tt.failing)
+			s := NewServer(stub)
+			s.Virt.BlkCtrls[testVirtioCtrl.Id.Value] = &testVirtioCtrl
+			if _, err := s.SetVirtioBlkOptions(context.Background(),
+				&SetVirtioBlkOptionsRequest{Name: testVirtioCtrl.Id.Value, Options: options}); err != nil {
+				t.Fatal(err)
+			}
+
+			blk := &pb.VirtioBlk{Id: testVirtioCtrl.Id, VolumeId: &pc.ObjectKey{Value: tt.volume}}
+			_, err := s.UpdateVirtioBlk(context.Background(), &pb.UpdateVirtioBlkRequest{VirtioBlk: blk})
+			if er, _ := status.FromError(err); er.Code() != tt.errCode {
+				t.Error("error code: expected", tt.errCode, "received", err)
+			}
+			if !reflect.DeepEqual(stub.created, tt.created) {
+				t.Error("created: expected", tt.created, "received", stub.created)
+			}
+			if active := s.activeBlkOptions(testVirtioCtrl.Id.Value); active != tt.active {
+				t.Error("active options: expected", tt.active, "received", active)
+			}
+			if queues := s.VirtioBlkQueues(testVirtioCtrl.Id.Value); queues != tt.active.NumQueues {
+				t.Error("queues: expected", tt.active.NumQueues, "received", queues)
+			}
+			volume := s.Virt.BlkCtrls[testVirtioCtrl.Id.Value].VolumeId.Value
+			if want := tt.created[0].DevName; tt.errCode == codes.OK && volume != want {
+				t.Error("volume: expected", want, "received", volume)
+			}
+		})
+	}
+}
+
+func TestFrontEnd_CreateVirtioBlkWithOptions(t *testing.T) {
+	reactors := `{"id":%d,"error":{"code":0,"message":""},"result":{"tick_rate":2490000000,"reactors":[{"lcore":0},{"lcore":1}]}}`
+	tests := map[string]struct {
+		options *VirtioBlkOptions
+		spdk    []string
+		request string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"all options": {
+			&VirtioBlkOptions{Cpumask: "0x2", NumQueues: 4, ReadOnly: true, PackedRing: true},
+			[]string{reactors, `{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			`"params":{"ctrlr":"virtio-blk-42","dev_name":"Malloc42","cpumask":"0x2","readonly":true,"packed_ring":true}`,
+			codes.OK,
+			"",
+		},
+		"default options": {
+			&VirtioBlkOptions{},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			`"params":{"ctrlr":"virtio-blk-42","dev_name":"Malloc42"}`,
+			codes.OK,
+			"",
+		},
+		"cpumask out of core mask": {
+			&VirtioBlkOptions{Cpumask: "0x4"},
+			[]string{reactors},
+			`"method":"framework_get_reactors"`,
+			codes.InvalidArgument,
+			"cpumask 0x4 selects cores out of the SPDK core mask 0x3",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+
+			_, err := testEnv.opiSpdkServer.SetVirtioBlkOptions(testEnv.ctx,
+				&SetVirtioBlkOptionsRequest{Name: testVirtioCtrl.Id.Value, Options: tt.options})
+			if err == nil {
+				_, err = testEnv.opiSpdkServer.CreateVirtioBlk(testEnv.ctx, &pb.CreateVirtioBlkRequest{VirtioBlk: &testVirtioCtrl})
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+			var request string
+			for len(testEnv.requests) > 0 {
+				request = <-testEnv.requests
+			}
+			if !strings.Contains(request, tt.request) {
+				t.Error("request: expected", tt.request, "received", request)
+			}
+			active := testEnv.opiSpdkServer.activeBlkOptions(testVirtioCtrl.Id.Value)
+			if tt.errCode == codes.OK && active != *tt.options {
+				t.Error("active options: expected", *tt.options, "received", active)
+			}
+		})
+	}
+}
+
+func TestFrontEnd_GetVirtioBlkOptions(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		out     *VirtioBlkOptions
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"valid request": {
+			testVirtioCtrl.Id.Value,
+			&VirtioBlkOptions{Cpumask: "0x2", NumQueues: 4, ReadOnly: true, PackedRing: true},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[{"ctrlr":"virtio-blk-42","cpumask":"0x2",` +
+				`"socket":"/var/tmp/virtio-blk-42","backend_specific":{"block":{"readonly":true,"bdev":"Malloc42"}}}]}`},
+			codes.OK,
+			"",
+		},
+		"valid request with virtio-scsi controller": {
+			testVirtioCtrl.Id.Value,
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[{"ctrlr":"virtio-blk-42","cpumask":"0x2",` +
+				`"socket":"/var/tmp/virtio-blk-42","backend_specific":{"scsi":[]}}]}`},
+			codes.FailedPrecondition,
+			"vhost controller virtio-blk-42 is not a virtio-blk controller",
+		},
+		"valid request with invalid SPDK response": {
+			testVirtioCtrl.Id.Value,
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[]}`},
+			codes.InvalidArgument,
+			"expecting exactly 1 result, got 0",
+		},
+		"valid request with unknown key": {
+			"unknown-id",
+			nil,
+			[]string{},
+			codes.NotFound,
+			"unable to find key unknown-id",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.opiSpdkServer.Virt.BlkCtrls[testVirtioCtrl.Id.Value] = &testVirtioCtrl
+			testEnv.opiSpdkServer.Virt.ActiveBlkOptions[testVirtioCtrl.Id.Value] =
+				&VirtioBlkOptions{Cpumask: "0x2", NumQueues: 4, ReadOnly: true, PackedRing: true}
+
+			response, err := testEnv.opiSpdkServer.GetVirtioBlkOptions(testEnv.ctx, &GetVirtioBlkOptionsRequest{Name: tt.in})
+			if !reflect.DeepEqual(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
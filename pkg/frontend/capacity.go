@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opiproject/gospdk/spdk"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// VolumeCapacity is the size of the volume exposed by a frontend object
+type VolumeCapacity struct {
+	BlockSize   int64 `json:"block_size"`
+	BlocksCount int64 `json:"blocks_count"`
+}
+
+// GetCapacityRequest reads the capacity of a frontend object
+type GetCapacityRequest struct {
+	// Name is the ID of the NVMe namespace, virtio-blk controller or
+	// virtio-scsi LUN
+	Name string
+}
+
+// GetNVMeNamespaceCapacity reports the current size of the volume of an
+// NVMe namespace, so a grown volume can be checked before hosts rescan it
+func (s *Server) GetNVMeNamespaceCapacity(ctx context.Context, in *GetCapacityRequest) (*VolumeCapacity, error) {
+	logging.FromContext(ctx).Infof("GetNVMeNamespaceCapacity: Received from client: %v", in.Name)
+	s.mu.RLock()
+	namespace, ok := s.Nvme.Namespaces[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	return s.capacity(ctx, namespace.Spec.VolumeId.Value)
+}
+
+// GetVirtioBlkCapacity reports the current size of the volume of a
+// virtio-blk controller
+func (s *Server) GetVirtioBlkCapacity(ctx context.Context, in *GetCapacityRequest) (*VolumeCapacity, error) {
+	logging.FromContext(ctx).Infof("GetVirtioBlkCapacity: Received from client: %v", in.Name)
+	s.mu.RLock()
+	controller, ok := s.Virt.BlkCtrls[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	return s.capacity(ctx, controller.VolumeId.Value)
+}
+
+// GetVirtioScsiLunCapacity reports the current size of the volume of a
+// virtio-scsi LUN
+func (s *Server) GetVirtioScsiLunCapacity(ctx context.Context, in *GetCapacityRequest) (*VolumeCapacity, error) {
+	logging.FromContext(ctx).Infof("GetVirtioScsiLunCapacity: Received from client: %v", in.Name)
+	s.mu.RLock()
+	lun, ok := s.Virt.ScsiLuns[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	return s.capacity(ctx, lun.VolumeId.Value)
+}
+
+func (s *Server) capacity(ctx context.Context, bdev string) (*VolumeCapacity, error) {
+	params := spdk.BdevGetBdevsParams{
+		Name: bdev,
+	}
+	var result []spdk.BdevGetBdevsResult
+	err := tracing.Call(ctx, s.rpc, "bdev_get_bdevs", &params, &result)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if len(result) != 1 {
+		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
+		logging.FromContext(ctx).Error(msg)
+		return nil, status.Errorf(codes.InvalidArgument, msg)
+	}
+	return &VolumeCapacity{BlockSize: result[0].BlockSize, BlocksCount: result[0].NumBlocks}, nil
+}
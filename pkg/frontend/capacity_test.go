@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFrontEnd_GetCapacity(t *testing.T) {
+	s := NewServer(server.CreateTestSpdkStub(map[string]string{
+		"bdev_get_bdevs": `[{"name":"Malloc1","product_name":"Logical Volume","block_size":4096,"num_blocks":262144}]`,
+	}))
+	s.Nvme.Namespaces["namespace-test"] = &pb.NVMeNamespace{Spec: &pb.NVMeNamespaceSpec{
+		Id:       &pc.ObjectKey{Value: "namespace-test"},
+		VolumeId: &pc.ObjectKey{Value: "Malloc1"},
+	}}
+	s.Virt.BlkCtrls["virtio-blk-test"] = &pb.VirtioBlk{
+		Id:       &pc.ObjectKey{Value: "virtio-blk-test"},
+		VolumeId: &pc.ObjectKey{Value: "Malloc1"},
+	}
+	s.Virt.ScsiLuns["lun-test"] = &pb.VirtioScsiLun{
+		Id:       &pc.ObjectKey{Value: "lun-test"},
+		VolumeId: &pc.ObjectKey{Value: "Malloc1"},
+	}
+	getters := map[string]func(context.Context, *GetCapacityRequest) (*VolumeCapacity, error){
+		"namespace-test":  s.GetNVMeNamespaceCapacity,
+		"virtio-blk-test": s.GetVirtioBlkCapacity,
+		"lun-test":        s.GetVirtioScsiLunCapacity,
+	}
+	want := &VolumeCapacity{BlockSize: 4096, BlocksCount: 262144}
+
+	for name, get := range getters {
+		t.Run(name, func(t *testing.T) {
+			response, err := get(context.Background(), &GetCapacityRequest{Name: name})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(response, want) {
+				t.Error("response: expected", want, "received", response)
+			}
+			_, err = get(context.Background(), &GetCapacityRequest{Name: "unknown-id"})
+			if status.Code(err) != codes.NotFound {
+				t.Error("expected NotFound for unknown object, received", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+func TestFrontEnd_Concurrency(t *testing.T) {
+	jsonRPC := server.CreateTestSpdkStub(map[string]string{
+		"nvmf_create_subsystem":          `true`,
+		"spdk_get_version":               `{"version":"SPDK v20.10"}`,
+		"nvmf_subsystem_add_ns":          `1`,
+		"nvmf_subsystem_remove_ns":       `true`,
+		"nvmf_subsystem_add_listener":    `true`,
+		"nvmf_subsystem_remove_listener": `true`,
+		"nvmf_get_subsystems":            `[]`,
+		"vhost_create_blk_controller":    `true`,
+		"vhost_delete_controller":        `true`,
+	})
+	opiSpdkServer := NewServer(jsonRPC)
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx,
+		"",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer(opiSpdkServer)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.CloseGrpcConnection(conn)
+	nvmeClient := pb.NewFrontendNvmeServiceClient(conn)
+	blkClient := pb.NewFrontendVirtioBlkServiceClient(conn)
+
+	_, err = nvmeClient.CreateNVMeSubsystem(ctx, &pb.CreateNVMeSubsystemRequest{
+		NvMeSubsystem: &pb.NVMeSubsystem{Spec: &pb.NVMeSubsystemSpec{
+			Id:  testSubsystem.Spec.Id,
+			Nqn: testSubsystem.Spec.Nqn,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			namespaceID := fmt.Sprintf("namespace-%d", i)
+			controllerID := fmt.Sprintf("controller-%d", i)
+			blkID := fmt.Sprintf("virtio-blk-%d", i)
+			for j := 0; j < 2; j++ {
+				// second round checks idempotency under concurrency
+				_, err := nvmeClient.CreateNVMeNamespace(ctx, &pb.CreateNVMeNamespaceRequest{
+					NvMeNamespace: &pb.NVMeNamespace{Spec: &pb.NVMeNamespaceSpec{
+						Id:          &pc.ObjectKey{Value: namespaceID},
+						SubsystemId: testSubsystem.Spec.Id,
+						VolumeId:    &pc.ObjectKey{Value: fmt.Sprintf("Malloc%d", i)},
+						HostNsid:    int32(i + 1),
+					}},
+				})
+				if err != nil {
+					t.Error(err)
+				}
+				_, err = nvmeClient.CreateNVMeController(ctx, &pb.CreateNVMeControllerRequest{
+					NvMeController: &pb.NVMeController{Spec: &pb.NVMeControllerSpec{
+						Id:          &pc.ObjectKey{Value: controllerID},
+						SubsystemId: testSubsystem.Spec.Id,
+					}},
+				})
+				if err != nil {
+					t.Error(err)
+				}
+				_, err = blkClient.CreateVirtioBlk(ctx, &pb.CreateVirtioBlkRequest{
+					VirtioBlk: &pb.VirtioBlk{
+						Id:       &pc.ObjectKey{Value: blkID},
+						VolumeId: &pc.ObjectKey{Value: fmt.Sprintf("Malloc%d", i)},
+					},
+				})
+				if err != nil {
+					t.Error(err)
+				}
+			}
+			if _, err := nvmeClient.ListNVMeControllers(ctx, &pb.ListNVMeControllersRequest{}); err != nil {
+				t.Error(err)
+			}
+			if _, err := nvmeClient.GetNVMeSubsystem(ctx, &pb.GetNVMeSubsystemRequest{Name: testSubsystem.Spec.Id.Value}); err == nil {
+				t.Error("expected error for subsystem missing in SPDK")
+			}
+			// odd workers clean up, so creations and deletions are interleaved
+			if i%2 == 0 {
+				return
+			}
+			if _, err := nvmeClient.DeleteNVMeNamespace(ctx, &pb.DeleteNVMeNamespaceRequest{Name: namespaceID}); err != nil {
+				t.Error(err)
+			}
+			if _, err := nvmeClient.DeleteNVMeController(ctx, &pb.DeleteNVMeControllerRequest{Name: controllerID}); err != nil {
+				t.Error(err)
+			}
+			if _, err := blkClient.DeleteVirtioBlk(ctx, &pb.DeleteVirtioBlkRequest{Name: blkID}); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(opiSpdkServer.Nvme.Namespaces) != workers/2 {
+		t.Error("expected", workers/2, "namespaces, received", len(opiSpdkServer.Nvme.Namespaces))
+	}
+	if len(opiSpdkServer.Nvme.Controllers) != workers/2 {
+		t.Error("expected", workers/2, "controllers, received", len(opiSpdkServer.Nvme.Controllers))
+	}
+	if len(opiSpdkServer.Virt.BlkCtrls) != workers/2 {
+		t.Error("expected", workers/2, "virtio-blk controllers, received", len(opiSpdkServer.Virt.BlkCtrls))
+	}
+	entries, err := opiSpdkServer.store.Entries(namespacesTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != workers/2 {
+		t.Error("expected", workers/2, "persisted namespaces, received", len(entries))
+	}
+}
@@ -0,0 +1,300 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NVMeDiscoveryListener is an address the SPDK discovery subsystem listens
+// on, so hosts can find the subsystems of the bridge with nvme discover
+type NVMeDiscoveryListener struct {
+	// Name the listener is referenced with
+	Name string `json:"name"`
+	// Transport is tcp or rdma, a SubsystemListener of it must be registered
+	Transport string `json:"transport"`
+	// Traddr is the IP address to listen on instead of the address of the
+	// SubsystemListener of the transport
+	Traddr string `json:"traddr,omitempty"`
+	// Trsvcid is the port to listen on instead of the port of the
+	// SubsystemListener of the transport, 8009 is the well known port
+	Trsvcid string `json:"trsvcid,omitempty"`
+}
+
+// NVMeDiscoveryReferral points hosts using the discovery service to another
+// discovery service or subsystem, e.g. of another DPU
+type NVMeDiscoveryReferral struct {
+	// Name the referral is referenced with
+	Name string `json:"name"`
+	// Transport is tcp or rdma
+	Transport string `json:"transport"`
+	// Traddr is the IP address of the referred discovery service
+	Traddr string `json:"traddr"`
+	// Trsvcid is the port of the referred discovery service
+	Trsvcid string `json:"trsvcid,omitempty"`
+	// Subnqn is the NQN of the referred subsystem, the discovery NQN if empty
+	Subnqn string `json:"subnqn,omitempty"`
+	// SecureChannel requires hosts to connect to the referral with TLS
+	SecureChannel bool `json:"secure_channel,omitempty"`
+}
+
+// AddNVMeDiscoveryListener makes the SPDK discovery subsystem listen on an
+// address
+func (s *Server) AddNVMeDiscoveryListener(ctx context.Context, in *NVMeDiscoveryListener) (*NVMeDiscoveryListener, error) {
+	logging.FromContext(ctx).Infof("AddNVMeDiscoveryListener: Received from client: %+v", in)
+	if err := s.validateDiscoveryListener(in); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	unlock := s.locks.Lock(server.LockKey(discoveryListenersTable, in.Name))
+	defer unlock()
+	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
+	listener, ok := s.Nvme.DiscoveryListeners[in.Name]
+	s.mu.RUnlock()
+	if ok {
+		if *listener != *in {
+			err := status.Errorf(codes.AlreadyExists, "discovery listener %s already exists with %+v", in.Name, *listener)
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		logging.FromContext(ctx).Infof("Already existing discovery listener with name %v", in.Name)
+		response := *listener
+		return &response, nil
+	}
+	params, err := s.discoveryListenerParams(in)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if err := s.callSpdk(ctx, "nvmf_subsystem_add_listener", &params,
+		"Could not add discovery listener: "+in.Name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	listener = &NVMeDiscoveryListener{}
+	*listener = *in
+	if err := store.SetJSON(s.store, discoveryListenersTable, in.Name, listener); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.DiscoveryListeners[in.Name] = listener
+	s.mu.Unlock()
+	response := *listener
+	return &response, nil
+}
+
+// DeleteNVMeDiscoveryListener stops the SPDK discovery subsystem listening on
+// the address of a listener
+func (s *Server) DeleteNVMeDiscoveryListener(ctx context.Context, name string, allowMissing bool) error {
+	logging.FromContext(ctx).Infof("DeleteNVMeDiscoveryListener: Received from client: %v", name)
+	unlock := s.locks.Lock(server.LockKey(discoveryListenersTable, name))
+	defer unlock()
+	s.mu.RLock()
+	listener, ok := s.Nvme.DiscoveryListeners[name]
+	s.mu.RUnlock()
+	if !ok {
+		if allowMissing {
+			return nil
+		}
+		err := status.Errorf(codes.NotFound, "unable to find key %s", name)
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	params, err := s.discoveryListenerParams(listener)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	if err := s.callSpdk(ctx, "nvmf_subsystem_remove_listener", &params.NvmfSubsystemAddListenerParams,
+		"Could not remove discovery listener: "+name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	if err := s.store.Delete(discoveryListenersTable, name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return server.StoreError(err)
+	}
+	s.mu.Lock()
+	delete(s.Nvme.DiscoveryListeners, name)
+	s.mu.Unlock()
+	return nil
+}
+
+// ListNVMeDiscoveryListeners returns the discovery listeners sorted by name
+func (s *Server) ListNVMeDiscoveryListeners(ctx context.Context) ([]*NVMeDiscoveryListener, error) {
+	logging.FromContext(ctx).Infof("ListNVMeDiscoveryListeners: Received from client")
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	listeners := make([]*NVMeDiscoveryListener, 0, len(s.Nvme.DiscoveryListeners))
+	for _, name := range server.SortedKeys(s.Nvme.DiscoveryListeners) {
+		listener := *s.Nvme.DiscoveryListeners[name]
+		listeners = append(listeners, &listener)
+	}
+	return listeners, nil
+}
+
+// AddNVMeDiscoveryReferral adds a referral to the discovery log page of the
+// SPDK discovery subsystem
+func (s *Server) AddNVMeDiscoveryReferral(ctx context.Context, in *NVMeDiscoveryReferral) (*NVMeDiscoveryReferral, error) {
+	logging.FromContext(ctx).Infof("AddNVMeDiscoveryReferral: Received from client: %+v", in)
+	if err := validateDiscoveryReferral(in); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	unlock := s.locks.Lock(server.LockKey(discoveryReferralsTable, in.Name))
+	defer unlock()
+	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
+	referral, ok := s.Nvme.DiscoveryReferrals[in.Name]
+	s.mu.RUnlock()
+	if ok {
+		if *referral != *in {
+			err := status.Errorf(codes.AlreadyExists, "discovery referral %s already exists with %+v", in.Name, *referral)
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		logging.FromContext(ctx).Infof("Already existing discovery referral with name %v", in.Name)
+		response := *referral
+		return &response, nil
+	}
+	if err := s.addReferral(ctx, in); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	referral = &NVMeDiscoveryReferral{}
+	*referral = *in
+	if err := store.SetJSON(s.store, discoveryReferralsTable, in.Name, referral); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.DiscoveryReferrals[in.Name] = referral
+	s.mu.Unlock()
+	response := *referral
+	return &response, nil
+}
+
+// DeleteNVMeDiscoveryReferral removes a referral from the discovery log page
+func (s *Server) DeleteNVMeDiscoveryReferral(ctx context.Context, name string, allowMissing bool) error {
+	logging.FromContext(ctx).Infof("DeleteNVMeDiscoveryReferral: Received from client: %v", name)
+	unlock := s.locks.Lock(server.LockKey(discoveryReferralsTable, name))
+	defer unlock()
+	s.mu.RLock()
+	referral, ok := s.Nvme.DiscoveryReferrals[name]
+	s.mu.RUnlock()
+	if !ok {
+		if allowMissing {
+			return nil
+		}
+		err := status.Errorf(codes.NotFound, "unable to find key %s", name)
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	params := referralParams(referral)
+	if err := s.callSpdk(ctx, "nvmf_discovery_remove_referral", &params,
+		"Could not remove discovery referral: "+name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	if err := s.store.Delete(discoveryReferralsTable, name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return server.StoreError(err)
+	}
+	s.mu.Lock()
+	delete(s.Nvme.DiscoveryReferrals, name)
+	s.mu.Unlock()
+	return nil
+}
+
+// ListNVMeDiscoveryReferrals returns the discovery referrals sorted by name
+func (s *Server) ListNVMeDiscoveryReferrals(ctx context.Context) ([]*NVMeDiscoveryReferral, error) {
+	logging.FromContext(ctx).Infof("ListNVMeDiscoveryReferrals: Received from client")
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	referrals := make([]*NVMeDiscoveryReferral, 0, len(s.Nvme.DiscoveryReferrals))
+	for _, name := range server.SortedKeys(s.Nvme.DiscoveryReferrals) {
+		referral := *s.Nvme.DiscoveryReferrals[name]
+		referrals = append(referrals, &referral)
+	}
+	return referrals, nil
+}
+
+// discoveryListenerParams returns params of nvmf_subsystem_add_listener for
+// the discovery subsystem listening on the address of listener
+func (s *Server) discoveryListenerParams(listener *NVMeDiscoveryListener) (nvmfSubsystemAddListenerParams, error) {
+	s.mu.RLock()
+	transport, ok := s.listeners[listener.Transport]
+	s.mu.RUnlock()
+	if !ok {
+		return nvmfSubsystemAddListenerParams{}, status.Errorf(codes.FailedPrecondition,
+			"no SubsystemListener registered for transport %s of discovery listener %s", listener.Transport, listener.Name)
+	}
+	// listeners of IP based transports do not depend on the controller
+	params := nvmfSubsystemAddListenerParams{NvmfSubsystemAddListenerParams: transport.Params(nil, discoveryNqn)}
+	overrideListenAddress(&params.NvmfSubsystemAddListenerParams, listener.Traddr, listener.Trsvcid)
+	return params, nil
+}
+
+func (s *Server) addReferral(ctx context.Context, referral *NVMeDiscoveryReferral) error {
+	params := nvmfDiscoveryAddReferralParams{
+		nvmfDiscoveryReferralParams: referralParams(referral),
+		SecureChannel:               referral.SecureChannel,
+	}
+	return s.callSpdk(ctx, "nvmf_discovery_add_referral", &params,
+		"Could not add discovery referral: "+referral.Name)
+}
+
+func referralParams(referral *NVMeDiscoveryReferral) nvmfDiscoveryReferralParams {
+	return nvmfDiscoveryReferralParams{
+		Trtype:  referral.Transport,
+		Adrfam:  addressFamily(net.ParseIP(referral.Traddr)),
+		Traddr:  referral.Traddr,
+		Trsvcid: referral.Trsvcid,
+		Subnqn:  referral.Subnqn,
+	}
+}
+
+func (s *Server) validateDiscoveryListener(listener *NVMeDiscoveryListener) error {
+	if listener == nil || listener.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if listener.Transport != TransportTCP && listener.Transport != TransportRDMA {
+		return fmt.Errorf("transport %q of discovery listener must be tcp or rdma", listener.Transport)
+	}
+	s.mu.RLock()
+	_, ok := s.listeners[listener.Transport]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no SubsystemListener registered for transport %q", listener.Transport)
+	}
+	return validateAddress(listener.Traddr, listener.Trsvcid)
+}
+
+func validateDiscoveryReferral(referral *NVMeDiscoveryReferral) error {
+	if referral == nil || referral.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if referral.Transport != TransportTCP && referral.Transport != TransportRDMA {
+		return fmt.Errorf("transport %q of discovery referral must be tcp or rdma", referral.Transport)
+	}
+	if referral.Traddr == "" {
+		return fmt.Errorf("traddr cannot be empty")
+	}
+	if referral.SecureChannel && referral.Transport != TransportTCP {
+		return fmt.Errorf("secure_channel requires tcp transport")
+	}
+	return validateAddress(referral.Traddr, referral.Trsvcid)
+}
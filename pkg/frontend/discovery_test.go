@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFrontEnd_AddNVMeDiscoveryListener(t *testing.T) {
+	tests := map[string]struct {
+		in      *NVMeDiscoveryListener
+		want    []nvmfListenAddress
+		errCode codes.Code
+		errMsg  string
+	}{
+		"address of the transport": {
+			&NVMeDiscoveryListener{Name: "discovery-tcp", Transport: TransportTCP},
+			[]nvmfListenAddress{{Trtype: TransportTCP, Adrfam: ipv4NvmeTCPProtocol, Traddr: "10.10.10.10", Trsvcid: "4420"}},
+			codes.OK,
+			"",
+		},
+		"well known port": {
+			&NVMeDiscoveryListener{Name: "discovery-tcp", Transport: TransportTCP, Trsvcid: "8009"},
+			[]nvmfListenAddress{{Trtype: TransportTCP, Adrfam: ipv4NvmeTCPProtocol, Traddr: "10.10.10.10", Trsvcid: "8009"}},
+			codes.OK,
+			"",
+		},
+		"vfiouser transport": {
+			&NVMeDiscoveryListener{Name: "discovery-vfiouser", Transport: TransportVfioUser},
+			nil,
+			codes.InvalidArgument,
+			`transport "vfiouser" of discovery listener must be tcp or rdma`,
+		},
+		"unregistered transport": {
+			&NVMeDiscoveryListener{Name: "discovery-rdma", Transport: TransportRDMA},
+			nil,
+			codes.InvalidArgument,
+			`no SubsystemListener registered for transport "rdma"`,
+		},
+		"invalid port": {
+			&NVMeDiscoveryListener{Name: "discovery-tcp", Transport: TransportTCP, Trsvcid: "discovery"},
+			nil,
+			codes.InvalidArgument,
+			`trsvcid "discovery" is not a port number`,
+		},
+		"empty name": {
+			&NVMeDiscoveryListener{Transport: TransportTCP},
+			nil,
+			codes.InvalidArgument,
+			"name cannot be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rpc := newListenerSpdkStub()
+			s := NewServerWithSubsystemListener(rpc, NewTCPSubsystemListener("10.10.10.10:4420"))
+
+			response, err := s.AddNVMeDiscoveryListener(context.Background(), tt.in)
+			if tt.errCode == codes.OK && !reflect.DeepEqual(response, tt.in) {
+				t.Error("response: expected", tt.in, "received", response)
+			}
+			if !reflect.DeepEqual(rpc.added, tt.want) {
+				t.Error("expected listeners", tt.want, "received", rpc.added)
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestFrontEnd_DeleteNVMeDiscoveryListener(t *testing.T) {
+	rpc := newListenerSpdkStub()
+	s := NewServerWithSubsystemListener(rpc, NewTCPSubsystemListener("10.10.10.10:4420"))
+	ctx := context.Background()
+	listener := &NVMeDiscoveryListener{Name: "discovery-tcp", Transport: TransportTCP, Trsvcid: "8009"}
+	if _, err := s.AddNVMeDiscoveryListener(ctx, listener); err != nil {
+		t.Fatal(err)
+	}
+	other := &NVMeDiscoveryListener{Name: "discovery-tcp", Transport: TransportTCP}
+	if _, err := s.AddNVMeDiscoveryListener(ctx, other); status.Code(err) != codes.AlreadyExists {
+		t.Error("expected AlreadyExists for another address, received", err)
+	}
+	listeners, _ := s.ListNVMeDiscoveryListeners(ctx)
+	if !reflect.DeepEqual(listeners, []*NVMeDiscoveryListener{listener}) {
+		t.Error("expected listener", listener, "received", listeners)
+	}
+
+	if err := s.DeleteNVMeDiscoveryListener(ctx, "discovery-tcp", false); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rpc.removed, rpc.added) {
+		t.Error("expected listener", rpc.added, "to be removed, received", rpc.removed)
+	}
+	if err := s.DeleteNVMeDiscoveryListener(ctx, "discovery-tcp", false); status.Code(err) != codes.NotFound {
+		t.Error("expected NotFound for deleted listener, received", err)
+	}
+	if err := s.DeleteNVMeDiscoveryListener(ctx, "discovery-tcp", true); err != nil {
+		t.Error("expected missing listener to be allowed, received", err)
+	}
+}
+
+func TestFrontEnd_AddNVMeDiscoveryReferral(t *testing.T) {
+	tests := map[string]struct {
+		in      *NVMeDiscoveryReferral
+		spdk    string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"referral to another dpu": {
+			&NVMeDiscoveryReferral{Name: "dpu-2", Transport: TransportTCP, Traddr: "10.10.10.20", Trsvcid: "8009"},
+			`true`,
+			codes.OK,
+			"",
+		},
+		"secure referral": {
+			&NVMeDiscoveryReferral{Name: "dpu-2", Transport: TransportTCP, Traddr: "fd00::20", SecureChannel: true},
+			`true`,
+			codes.OK,
+			"",
+		},
+		"secure referral over rdma": {
+			&NVMeDiscoveryReferral{Name: "dpu-2", Transport: TransportRDMA, Traddr: "10.10.10.20", SecureChannel: true},
+			`true`,
+			codes.InvalidArgument,
+			"secure_channel requires tcp transport",
+		},
+		"missing address": {
+			&NVMeDiscoveryReferral{Name: "dpu-2", Transport: TransportTCP},
+			`true`,
+			codes.InvalidArgument,
+			"traddr cannot be empty",
+		},
+		"spdk failure": {
+			&NVMeDiscoveryReferral{Name: "dpu-2", Transport: TransportTCP, Traddr: "10.10.10.20"},
+			`false`,
+			codes.InvalidArgument,
+			"Could not add discovery referral: dpu-2",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{
+				"nvmf_discovery_add_referral": tt.spdk,
+			}))
+
+			response, err := s.AddNVMeDiscoveryReferral(context.Background(), tt.in)
+			if tt.errCode == codes.OK {
+				if !reflect.DeepEqual(response, tt.in) {
+					t.Error("response: expected", tt.in, "received", response)
+				}
+				if !reflect.DeepEqual(s.Nvme.DiscoveryReferrals[tt.in.Name], tt.in) {
+					t.Error("expected referral to be kept", tt.in)
+				}
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestFrontEnd_ReconcileDiscovery(t *testing.T) {
+	rpc := server.CreateTestSpdkStub(map[string]string{
+		"nvmf_get_subsystems":          `[{"nqn":"nqn.2014-08.org.nvmexpress.discovery","subtype":"Discovery","listen_addresses":[]}]`,
+		"vhost_get_controllers":        `[]`,
+		"nvmf_discovery_get_referrals": `[{"address":{"trtype":"TCP","adrfam":"IPv4","traddr":"10.10.10.20","trsvcid":"8009"},"subnqn":"nqn.2014-08.org.nvmexpress.discovery"}]`,
+		"nvmf_discovery_add_referral":  `true`,
+		"nvmf_subsystem_add_listener":  `true`,
+	})
+	s := NewServerWithSubsystemListener(rpc, NewTCPSubsystemListener("10.10.10.10:4420"))
+	s.Nvme.DiscoveryListeners["discovery-tcp"] = &NVMeDiscoveryListener{Name: "discovery-tcp", Transport: TransportTCP}
+	s.Nvme.DiscoveryReferrals["dpu-2"] = &NVMeDiscoveryReferral{Name: "dpu-2", Transport: TransportTCP, Traddr: "10.10.10.20"}
+	s.Nvme.DiscoveryReferrals["dpu-3"] = &NVMeDiscoveryReferral{Name: "dpu-3", Transport: TransportTCP, Traddr: "10.10.10.30"}
+
+	drifts, err := s.Reconcile(server.ReconcilePolicy{Recreate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []server.Drift{
+		{Object: "NVMeDiscoveryListener", ID: "discovery-tcp", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+		{Object: "NVMeDiscoveryReferral", ID: "dpu-3", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+	}
+	if !reflect.DeepEqual(drifts, want) {
+		t.Error("expected drifts", want, "received", drifts)
+	}
+}
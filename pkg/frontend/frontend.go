@@ -6,31 +6,69 @@
 package frontend
 
 import (
+	"errors"
 	"log"
+	"sync"
 
 	"github.com/opiproject/gospdk/spdk"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+)
+
+// tables used to persist frontend objects
+const (
+	subsystemsTable            = "nvme_subsystems"
+	subsystemHostsTable        = "nvme_subsystem_hosts"
+	subsystemAnaTable          = "nvme_subsystem_ana"
+	controllersTable           = "nvme_controllers"
+	controllerTransportsTable  = "nvme_controller_transports"
+	controllerAnaTable         = "nvme_controller_ana"
+	namespacesTable            = "nvme_namespaces"
+	namespaceReservationsTable = "nvme_namespace_reservations"
+	activeReservationsTable    = "nvme_namespace_active_reservations"
+	namespaceVisibilityTable   = "nvme_namespace_visibility"
+	discoveryListenersTable    = "nvme_discovery_listeners"
+	discoveryReferralsTable    = "nvme_discovery_referrals"
+	blkCtrlsTable              = "virtio_blk_controllers"
+	blkOptionsTable            = "virtio_blk_options"
+	activeBlkOptionsTable      = "virtio_blk_active_options"
 )
 
 // SubsystemListener interface is used to provide SPDK call params to create/delete
 // NVMe controllers depending on used transport type.
 type SubsystemListener interface {
+	Transport() string
 	Params(ctrlr *pb.NVMeController, nqn string) spdk.NvmfSubsystemAddListenerParams
 }
 
 // NvmeParameters contains all NVMe related structures
 type NvmeParameters struct {
-	Subsystems     map[string]*pb.NVMeSubsystem
-	Controllers    map[string]*pb.NVMeController
-	Namespaces     map[string]*pb.NVMeNamespace
-	subsysListener SubsystemListener
+	Subsystems           map[string]*pb.NVMeSubsystem
+	SubsystemHosts       map[string]*NVMeSubsystemHosts
+	SubsystemAna         map[string]*NVMeSubsystemAna
+	Controllers          map[string]*pb.NVMeController
+	ControllerTransports map[string]*NVMeControllerTransport
+	ControllerAna        map[string]*NVMeControllerAna
+	Namespaces           map[string]*pb.NVMeNamespace
+	// NamespaceReservations are set by clients, ActiveReservations are the
+	// ones namespaces were added to SPDK with
+	NamespaceReservations map[string]*NVMeNamespaceReservation
+	ActiveReservations    map[string]*NVMeNamespaceReservation
+	NamespaceVisibility   map[string]*NVMeNamespaceVisibility
+	DiscoveryListeners    map[string]*NVMeDiscoveryListener
+	DiscoveryReferrals    map[string]*NVMeDiscoveryReferral
 }
 
 // VirtioParameters contains all VirtIO related structures
 type VirtioParameters struct {
-	BlkCtrls  map[string]*pb.VirtioBlk
-	ScsiCtrls map[string]*pb.VirtioScsiController
-	ScsiLuns  map[string]*pb.VirtioScsiLun
+	BlkCtrls map[string]*pb.VirtioBlk
+	// BlkOptions are set by clients, ActiveBlkOptions are the ones vhost
+	// controllers were created with
+	BlkOptions       map[string]*VirtioBlkOptions
+	ActiveBlkOptions map[string]*VirtioBlkOptions
+	ScsiCtrls        map[string]*pb.VirtioScsiController
+	ScsiLuns         map[string]*pb.VirtioScsiLun
 }
 
 // Server contains frontend related OPI services
@@ -40,38 +78,158 @@ type Server struct {
 	pb.UnimplementedFrontendVirtioScsiServiceServer
 
 	rpc        spdk.JSONRPC
+	store      store.Store
 	Nvme       NvmeParameters
 	Virt       VirtioParameters
 	Pagination map[string]int
+	pageLimits server.PaginationLimits
+	// allowAnyHost and anaReporting are used for new subsystems
+	allowAnyHost bool
+	anaReporting bool
+	// listeners are keyed by transport, defaultTransport is used for
+	// controllers created without SetNVMeControllerTransport
+	listeners        map[string]SubsystemListener
+	defaultTransport string
+
+	// mu guards the object maps and Pagination, locks serializes operations
+	// on the same objects while SPDK is called
+	mu    sync.RWMutex
+	locks *server.KeyLocker
 }
 
 // NewServer creates initialized instance of FrontEnd server communicating
 // with provided jsonRPC
 func NewServer(jsonRPC spdk.JSONRPC) *Server {
 	return &Server{
-		rpc: jsonRPC,
+		rpc:   jsonRPC,
+		store: store.NewMemoryStore(),
 		Nvme: NvmeParameters{
-			Subsystems:     make(map[string]*pb.NVMeSubsystem),
-			Controllers:    make(map[string]*pb.NVMeController),
-			Namespaces:     make(map[string]*pb.NVMeNamespace),
-			subsysListener: NewTCPSubsystemListener("127.0.0.1:4420"),
+			Subsystems:            make(map[string]*pb.NVMeSubsystem),
+			SubsystemHosts:        make(map[string]*NVMeSubsystemHosts),
+			SubsystemAna:          make(map[string]*NVMeSubsystemAna),
+			Controllers:           make(map[string]*pb.NVMeController),
+			ControllerTransports:  make(map[string]*NVMeControllerTransport),
+			ControllerAna:         make(map[string]*NVMeControllerAna),
+			Namespaces:            make(map[string]*pb.NVMeNamespace),
+			NamespaceReservations: make(map[string]*NVMeNamespaceReservation),
+			ActiveReservations:    make(map[string]*NVMeNamespaceReservation),
+			NamespaceVisibility:   make(map[string]*NVMeNamespaceVisibility),
+			DiscoveryListeners:    make(map[string]*NVMeDiscoveryListener),
+			DiscoveryReferrals:    make(map[string]*NVMeDiscoveryReferral),
 		},
 		Virt: VirtioParameters{
-			BlkCtrls:  make(map[string]*pb.VirtioBlk),
-			ScsiCtrls: make(map[string]*pb.VirtioScsiController),
-			ScsiLuns:  make(map[string]*pb.VirtioScsiLun),
+			BlkCtrls:         make(map[string]*pb.VirtioBlk),
+			BlkOptions:       make(map[string]*VirtioBlkOptions),
+			ActiveBlkOptions: make(map[string]*VirtioBlkOptions),
+			ScsiCtrls:        make(map[string]*pb.VirtioScsiController),
+			ScsiLuns:         make(map[string]*pb.VirtioScsiLun),
+		},
+		Pagination:   make(map[string]int),
+		pageLimits:   server.DefaultPaginationLimits(),
+		allowAnyHost: true,
+		listeners: map[string]SubsystemListener{
+			TransportTCP: NewTCPSubsystemListener("127.0.0.1:4420"),
 		},
-		Pagination: make(map[string]int),
+		defaultTransport: TransportTCP,
+		locks:            server.NewKeyLocker(),
 	}
 }
 
 // NewServerWithSubsystemListener creates initialized instance of FrontEnd server communicating
-// with provided jsonRPC and externally created SubsystemListener instead default one.
+// with provided jsonRPC and externally created SubsystemListener used by default.
+// Listeners of other transports can be added with RegisterSubsystemListener.
 func NewServerWithSubsystemListener(jsonRPC spdk.JSONRPC, sysListener SubsystemListener) *Server {
 	if sysListener == nil {
 		log.Panic("nil for SubsystemListener is not allowed")
 	}
 	server := NewServer(jsonRPC)
-	server.Nvme.subsysListener = sysListener
+	server.listeners[sysListener.Transport()] = sysListener
+	server.defaultTransport = sysListener.Transport()
 	return server
 }
+
+// UseStore makes the server persist its objects in the provided store and
+// restores all objects previously saved there
+func (s *Server) UseStore(st store.Store) error {
+	if st == nil {
+		return errors.New("nil store is not allowed")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	newSubsystem := func() *pb.NVMeSubsystem { return &pb.NVMeSubsystem{} }
+	if err := store.Load(st, subsystemsTable, s.Nvme.Subsystems, newSubsystem); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, subsystemHostsTable, s.Nvme.SubsystemHosts); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, subsystemAnaTable, s.Nvme.SubsystemAna); err != nil {
+		return err
+	}
+	newController := func() *pb.NVMeController { return &pb.NVMeController{} }
+	if err := store.Load(st, controllersTable, s.Nvme.Controllers, newController); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, controllerTransportsTable, s.Nvme.ControllerTransports); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, controllerAnaTable, s.Nvme.ControllerAna); err != nil {
+		return err
+	}
+	newNamespace := func() *pb.NVMeNamespace { return &pb.NVMeNamespace{} }
+	if err := store.Load(st, namespacesTable, s.Nvme.Namespaces, newNamespace); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, namespaceReservationsTable, s.Nvme.NamespaceReservations); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, activeReservationsTable, s.Nvme.ActiveReservations); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, namespaceVisibilityTable, s.Nvme.NamespaceVisibility); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, discoveryListenersTable, s.Nvme.DiscoveryListeners); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, discoveryReferralsTable, s.Nvme.DiscoveryReferrals); err != nil {
+		return err
+	}
+	newBlk := func() *pb.VirtioBlk { return &pb.VirtioBlk{} }
+	if err := store.Load(st, blkCtrlsTable, s.Virt.BlkCtrls, newBlk); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, blkOptionsTable, s.Virt.BlkOptions); err != nil {
+		return err
+	}
+	if err := store.LoadJSON(st, activeBlkOptionsTable, s.Virt.ActiveBlkOptions); err != nil {
+		return err
+	}
+	log.Printf("Restored %d subsystems, %d controllers, %d namespaces, %d virtio-blk controllers",
+		len(s.Nvme.Subsystems), len(s.Nvme.Controllers), len(s.Nvme.Namespaces), len(s.Virt.BlkCtrls))
+	s.store = st
+	return nil
+}
+
+// SetPaginationLimits changes the page sizes used by List calls
+func (s *Server) SetPaginationLimits(limits server.PaginationLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageLimits = limits
+	return nil
+}
+
+// ObjectCounts returns the number of objects managed by the server per type
+func (s *Server) ObjectCounts() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return map[string]int{
+		"nvme_subsystem":  len(s.Nvme.Subsystems),
+		"nvme_controller": len(s.Nvme.Controllers),
+		"nvme_namespace":  len(s.Nvme.Namespaces),
+		"virtio_blk":      len(s.Virt.BlkCtrls),
+	}
+}
@@ -10,14 +10,17 @@ import (
 	"log"
 	"net"
 	"os"
+	"testing"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/opiproject/gospdk/spdk"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
 )
 
 // TODO: move test infrastructure code to a separate (test/server) package to avoid duplication
@@ -36,6 +39,8 @@ type testEnv struct {
 	ctx           context.Context
 	conn          *grpc.ClientConn
 	jsonRPC       spdk.JSONRPC
+	// requests are the ones received by the mock SPDK server
+	requests <-chan string
 }
 
 func (e *testEnv) Close() {
@@ -49,7 +54,7 @@ func (e *testEnv) Close() {
 func createTestEnvironment(startSpdkServer bool, spdkResponses []string) *testEnv {
 	env := &testEnv{}
 	env.testSocket = server.GenerateSocketName("frontend")
-	env.ln, env.jsonRPC = server.CreateTestSpdkServer(env.testSocket, startSpdkServer, spdkResponses)
+	env.ln, env.jsonRPC, env.requests = server.CreateTestSpdkRecordingServer(env.testSocket, startSpdkServer, spdkResponses)
 	env.opiSpdkServer = NewServer(env.jsonRPC)
 
 	ctx := context.Background()
@@ -89,3 +94,47 @@ func dialer(opiSpdkServer *Server) func(context.Context, string) (net.Conn, erro
 		return listener.Dial()
 	}
 }
+
+func TestFrontEnd_UseStore(t *testing.T) {
+	st := store.NewMemoryStore()
+	if err := st.Set(subsystemsTable, testSubsystem.Spec.Id.Value, &testSubsystem); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Set(controllersTable, testController.Spec.Id.Value, &testController); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.Set(namespacesTable, testNamespace.Spec.Id.Value, &testNamespace); err != nil {
+		t.Fatal(err)
+	}
+
+	testEnv := createTestEnvironment(true, []string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`})
+	defer testEnv.Close()
+
+	if err := testEnv.opiSpdkServer.UseStore(nil); err == nil {
+		t.Error("expected error for nil store")
+	}
+	if err := testEnv.opiSpdkServer.UseStore(st); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(testEnv.opiSpdkServer.Nvme.Subsystems[testSubsystem.Spec.Id.Value], &testSubsystem) {
+		t.Error("expected restored subsystem", &testSubsystem, "received", testEnv.opiSpdkServer.Nvme.Subsystems)
+	}
+	if !proto.Equal(testEnv.opiSpdkServer.Nvme.Controllers[testController.Spec.Id.Value], &testController) {
+		t.Error("expected restored controller", &testController, "received", testEnv.opiSpdkServer.Nvme.Controllers)
+	}
+	if !proto.Equal(testEnv.opiSpdkServer.Nvme.Namespaces[testNamespace.Spec.Id.Value], &testNamespace) {
+		t.Error("expected restored namespace", &testNamespace, "received", testEnv.opiSpdkServer.Nvme.Namespaces)
+	}
+
+	request := &pb.DeleteNVMeNamespaceRequest{Name: testNamespace.Spec.Id.Value}
+	if _, err := testEnv.client.DeleteNVMeNamespace(testEnv.ctx, request); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := st.Entries(namespacesTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Error("expected deleted namespace to be removed from store, received", entries)
+	}
+}
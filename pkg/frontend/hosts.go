@@ -0,0 +1,296 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/keyring"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// maxNqnLength is the maximum length of an NQN in bytes defined by the
+// NVMe base specification
+const maxNqnLength = 223
+
+// NVMeHost is a host allowed to connect to a subsystem
+type NVMeHost struct {
+	// Nqn is the NQN the host connects with
+	Nqn string `json:"nqn"`
+	// Psk is the name of the keyring key with the TLS pre-shared key the
+	// host connects with to listeners requiring a secure channel
+	Psk string `json:"psk,omitempty"`
+	// DhchapKey is the name of the keyring key the host must authenticate
+	// with using DH-HMAC-CHAP. No authentication is required if empty.
+	DhchapKey string `json:"dhchap_key,omitempty"`
+	// DhchapCtrlrKey is the name of the keyring key the subsystem
+	// authenticates to the host with (bidirectional authentication)
+	DhchapCtrlrKey string `json:"dhchap_ctrlr_key,omitempty"`
+}
+
+// NVMeSubsystemHosts is the access control list of a subsystem
+type NVMeSubsystemHosts struct {
+	// AllowAnyHost lets every host connect, regardless of Hosts
+	AllowAnyHost bool `json:"allow_any_host"`
+	// Hosts are the hosts allowed to connect
+	Hosts []NVMeHost `json:"hosts"`
+}
+
+// UpdateNVMeSubsystemHostsRequest changes the access control list of a
+// subsystem
+type UpdateNVMeSubsystemHostsRequest struct {
+	// Name is the ID of the subsystem
+	Name  string
+	Hosts *NVMeSubsystemHosts
+	// UpdateMask selects allow_any_host, hosts or both, which are replaced
+	// if it is empty
+	UpdateMask *fieldmaskpb.FieldMask
+}
+
+// GetNVMeSubsystemHostsRequest reads the access control list of a subsystem
+type GetNVMeSubsystemHostsRequest struct {
+	// Name is the ID of the subsystem
+	Name string
+}
+
+// SetAllowAnyHostByDefault defines if subsystems created by CreateNVMeSubsystem
+// accept any host or no host until hosts are added with UpdateNVMeSubsystemHosts
+func (s *Server) SetAllowAnyHostByDefault(allow bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.allowAnyHost = allow
+}
+
+// UpdateNVMeSubsystemHosts changes which hosts can connect to a subsystem.
+// allow_any_host can be toggled without sending the hosts and the other way
+// round.
+func (s *Server) UpdateNVMeSubsystemHosts(ctx context.Context, in *UpdateNVMeSubsystemHostsRequest) (*NVMeSubsystemHosts, error) {
+	logging.FromContext(ctx).Infof("UpdateNVMeSubsystemHosts: Received from client: %v %+v %v", in.Name, in.Hosts, in.UpdateMask)
+	if in.Hosts == nil {
+		err := status.Error(codes.InvalidArgument, "hosts cannot be empty")
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	fields, err := server.UpdatedFields(in.UpdateMask, "allow_any_host", "hosts")
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	unlock := s.locks.Lock(server.LockKey(subsystemsTable, in.Name))
+	defer unlock()
+	s.mu.RLock()
+	subsys, ok := s.Nvme.Subsystems[in.Name]
+	hosts := copyHosts(s.subsystemHosts(in.Name))
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if fields["allow_any_host"] {
+		hosts.AllowAnyHost = in.Hosts.AllowAnyHost
+	}
+	if fields["hosts"] {
+		hosts.Hosts = append([]NVMeHost{}, in.Hosts.Hosts...)
+	}
+	if err := validateHosts(hosts); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := keyring.Check(ctx, s.rpc, hostKeys(hosts)...); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	spdkSubsys, err := s.spdkSubsystem(ctx, subsys.Spec.Nqn)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if err := s.applyHosts(ctx, spdkSubsys, hosts); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if err := store.SetJSON(s.store, subsystemHostsTable, in.Name, hosts); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.SubsystemHosts[in.Name] = hosts
+	s.mu.Unlock()
+	return copyHosts(hosts), nil
+}
+
+// GetNVMeSubsystemHosts reports which hosts can connect to a subsystem
+// according to SPDK
+func (s *Server) GetNVMeSubsystemHosts(ctx context.Context, in *GetNVMeSubsystemHostsRequest) (*NVMeSubsystemHosts, error) {
+	logging.FromContext(ctx).Infof("GetNVMeSubsystemHosts: Received from client: %v", in.Name)
+	s.mu.RLock()
+	subsys, ok := s.Nvme.Subsystems[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	spdkSubsys, err := s.spdkSubsystem(ctx, subsys.Spec.Nqn)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	hosts := &NVMeSubsystemHosts{AllowAnyHost: spdkSubsys.AllowAnyHost, Hosts: []NVMeHost{}}
+	for _, host := range spdkSubsys.Hosts {
+		hosts.Hosts = append(hosts.Hosts, hostFromSpdk(host))
+	}
+	return hosts, nil
+}
+
+// applyHosts changes the hosts of spdkSubsys in SPDK to hosts. New hosts are
+// added before any host is removed or any host is rejected. Hosts with
+// changed keys are removed and added again, since SPDK cannot change them.
+func (s *Server) applyHosts(ctx context.Context, spdkSubsys *nvmfSubsystem, hosts *NVMeSubsystemHosts) error {
+	present := make(map[string]NVMeHost, len(spdkSubsys.Hosts))
+	for _, host := range spdkSubsys.Hosts {
+		present[host.Nqn] = hostFromSpdk(host)
+	}
+	wanted := make(map[string]NVMeHost, len(hosts.Hosts))
+	for _, host := range hosts.Hosts {
+		wanted[host.Nqn] = host
+		old, ok := present[host.Nqn]
+		if ok && old == host {
+			continue
+		}
+		if ok {
+			if err := s.removeHost(ctx, spdkSubsys.Nqn, host.Nqn); err != nil {
+				return err
+			}
+		}
+		if err := s.addHost(ctx, spdkSubsys.Nqn, host); err != nil {
+			return err
+		}
+	}
+	for _, host := range spdkSubsys.Hosts {
+		if _, ok := wanted[host.Nqn]; ok {
+			continue
+		}
+		if err := s.removeHost(ctx, spdkSubsys.Nqn, host.Nqn); err != nil {
+			return err
+		}
+	}
+	if spdkSubsys.AllowAnyHost != hosts.AllowAnyHost {
+		params := nvmfSubsystemAllowAnyHostParams{Nqn: spdkSubsys.Nqn, AllowAnyHost: hosts.AllowAnyHost}
+		if err := s.callSpdk(ctx, "nvmf_subsystem_allow_any_host", &params,
+			fmt.Sprintf("Could not set allow_any_host to %v", hosts.AllowAnyHost)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addHost allows host to connect to the subsystem with nqn
+func (s *Server) addHost(ctx context.Context, nqn string, host NVMeHost) error {
+	params := nvmfSubsystemHostParams{
+		Nqn:            nqn,
+		Host:           host.Nqn,
+		Psk:            host.Psk,
+		DhchapKey:      host.DhchapKey,
+		DhchapCtrlrKey: host.DhchapCtrlrKey,
+	}
+	return s.callSpdk(ctx, "nvmf_subsystem_add_host", &params, "Could not add host: "+host.Nqn)
+}
+
+// removeHost rejects connections of host to the subsystem with nqn
+func (s *Server) removeHost(ctx context.Context, nqn string, host string) error {
+	params := nvmfSubsystemHostParams{Nqn: nqn, Host: host}
+	return s.callSpdk(ctx, "nvmf_subsystem_remove_host", &params, "Could not remove host: "+host)
+}
+
+// spdkSubsystem returns the subsystem with nqn from SPDK
+func (s *Server) spdkSubsystem(ctx context.Context, nqn string) (*nvmfSubsystem, error) {
+	var subsystems []nvmfSubsystem
+	err := tracing.Call(ctx, s.rpc, "nvmf_get_subsystems", nil, &subsystems)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", subsystems)
+	for i := range subsystems {
+		if subsystems[i].Nqn == nqn {
+			return &subsystems[i], nil
+		}
+	}
+	return nil, status.Errorf(codes.FailedPrecondition, "Could not find NQN: %s", nqn)
+}
+
+// subsystemHosts returns the access control list of the subsystem with id.
+// Subsystems created before access control lists were kept accept any host.
+func (s *Server) subsystemHosts(id string) *NVMeSubsystemHosts {
+	if hosts, ok := s.Nvme.SubsystemHosts[id]; ok {
+		return hosts
+	}
+	return &NVMeSubsystemHosts{AllowAnyHost: true}
+}
+
+func validateHosts(hosts *NVMeSubsystemHosts) error {
+	if hosts == nil {
+		return fmt.Errorf("hosts cannot be empty")
+	}
+	seen := make(map[string]bool, len(hosts.Hosts))
+	for _, host := range hosts.Hosts {
+		if err := validateNqn(host.Nqn); err != nil {
+			return fmt.Errorf("invalid host: %w", err)
+		}
+		if seen[host.Nqn] {
+			return fmt.Errorf("duplicate host %s", host.Nqn)
+		}
+		seen[host.Nqn] = true
+		if host.DhchapCtrlrKey != "" && host.DhchapKey == "" {
+			return fmt.Errorf("dhchap_ctrlr_key of host %s requires dhchap_key", host.Nqn)
+		}
+		for _, key := range []string{host.Psk, host.DhchapKey, host.DhchapCtrlrKey} {
+			if err := keyring.ValidateName(key); key != "" && err != nil {
+				return fmt.Errorf("invalid key of host %s: %w", host.Nqn, err)
+			}
+		}
+	}
+	return nil
+}
+
+// hostKeys returns the names of all keys used by hosts
+func hostKeys(hosts *NVMeSubsystemHosts) []string {
+	var keys []string
+	for _, host := range hosts.Hosts {
+		keys = append(keys, host.Psk, host.DhchapKey, host.DhchapCtrlrKey)
+	}
+	return keys
+}
+
+func hostFromSpdk(host nvmfHost) NVMeHost {
+	return NVMeHost{Nqn: host.Nqn, Psk: host.Psk, DhchapKey: host.DhchapKey, DhchapCtrlrKey: host.DhchapCtrlrKey}
+}
+
+func validateNqn(nqn string) error {
+	if !strings.HasPrefix(nqn, "nqn.") {
+		return fmt.Errorf("NQN %q must start with \"nqn.\"", nqn)
+	}
+	if len(nqn) > maxNqnLength {
+		return fmt.Errorf("NQN %q exceeds %d bytes", nqn, maxNqnLength)
+	}
+	return nil
+}
+
+func copyHosts(hosts *NVMeSubsystemHosts) *NVMeSubsystemHosts {
+	return &NVMeSubsystemHosts{
+		AllowAnyHost: hosts.AllowAnyHost,
+		Hosts:        append([]NVMeHost{}, hosts.Hosts...),
+	}
+}
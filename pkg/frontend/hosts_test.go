@@ -0,0 +1,320 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestFrontEnd_UpdateNVMeSubsystemHosts(t *testing.T) {
+	subsystem := &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{
+			Id:  &pc.ObjectKey{Value: "subsystem-test"},
+			Nqn: "nqn.2022-09.io.spdk:opi3",
+		},
+	}
+	host1 := NVMeHost{Nqn: "nqn.2014-08.org.nvmexpress:uuid:host1"}
+	host2 := NVMeHost{Nqn: "nqn.2014-08.org.nvmexpress:uuid:host2"}
+	spdkSubsystems := `{"id":%d,"error":{"code":0,"message":""},"result":[{"nqn":"nqn.2022-09.io.spdk:opi3",` +
+		`"allow_any_host":true,"hosts":[{"nqn":"nqn.2014-08.org.nvmexpress:uuid:host1"}]}]}`
+	spdkTrue := `{"id":%d,"error":{"code":0,"message":""},"result":true}`
+	spdkFalse := `{"id":%d,"error":{"code":0,"message":""},"result":false}`
+	tests := map[string]struct {
+		in      *NVMeSubsystemHosts
+		out     *NVMeSubsystemHosts
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+		start   bool
+		exist   bool
+	}{
+		"replace host and reject other hosts": {
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{host2}},
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{host2}},
+			[]string{spdkSubsystems, spdkTrue, spdkTrue, spdkTrue},
+			codes.OK,
+			"",
+			true,
+			true,
+		},
+		"unchanged hosts": {
+			&NVMeSubsystemHosts{AllowAnyHost: true, Hosts: []NVMeHost{host1}},
+			&NVMeSubsystemHosts{AllowAnyHost: true, Hosts: []NVMeHost{host1}},
+			[]string{spdkSubsystems},
+			codes.OK,
+			"",
+			true,
+			true,
+		},
+		"host with dh-hmac-chap keys": {
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{{Nqn: host1.Nqn, DhchapKey: "key0", DhchapCtrlrKey: "ckey0"}}},
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{{Nqn: host1.Nqn, DhchapKey: "key0", DhchapCtrlrKey: "ckey0"}}},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[{"name":"key0","path":"/etc/opi/key0"},` +
+				`{"name":"ckey0","path":"/etc/opi/ckey0"}]}`, spdkSubsystems, spdkTrue, spdkTrue, spdkTrue},
+			codes.OK,
+			"",
+			true,
+			true,
+		},
+		"key missing in keyring": {
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{{Nqn: host1.Nqn, DhchapKey: "key0"}}},
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[]}`},
+			codes.InvalidArgument,
+			"key key0 not found in keyring",
+			true,
+			true,
+		},
+		"controller key without host key": {
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{{Nqn: host1.Nqn, DhchapCtrlrKey: "ckey0"}}},
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			fmt.Sprintf("dhchap_ctrlr_key of host %v requires dhchap_key", host1.Nqn),
+			false,
+			true,
+		},
+		"host cannot be added": {
+			&NVMeSubsystemHosts{AllowAnyHost: true, Hosts: []NVMeHost{host1, host2}},
+			nil,
+			[]string{spdkSubsystems, spdkFalse},
+			codes.InvalidArgument,
+			fmt.Sprintf("Could not add host: %v", host2.Nqn),
+			true,
+			true,
+		},
+		"subsystem missing in SPDK": {
+			&NVMeSubsystemHosts{},
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[]}`},
+			codes.FailedPrecondition,
+			fmt.Sprintf("Could not find NQN: %v", subsystem.Spec.Nqn),
+			true,
+			true,
+		},
+		"host is not an nqn": {
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{{Nqn: "host1"}}},
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			`invalid host: NQN "host1" must start with "nqn."`,
+			false,
+			true,
+		},
+		"host nqn too long": {
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{{Nqn: "nqn." + strings.Repeat("a", maxNqnLength)}}},
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			fmt.Sprintf("invalid host: NQN %q exceeds %d bytes", "nqn."+strings.Repeat("a", maxNqnLength), maxNqnLength),
+			false,
+			true,
+		},
+		"duplicate host": {
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{host1, host1}},
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			fmt.Sprintf("duplicate host %v", host1.Nqn),
+			false,
+			true,
+		},
+		"empty hosts": {
+			nil,
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			"hosts cannot be empty",
+			false,
+			true,
+		},
+		"unknown key": {
+			&NVMeSubsystemHosts{},
+			nil,
+			[]string{},
+			codes.NotFound,
+			fmt.Sprintf("unable to find key %v", subsystem.Spec.Id.Value),
+			false,
+			false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(tt.start, tt.spdk)
+			defer testEnv.Close()
+
+			if tt.exist {
+				testEnv.opiSpdkServer.Nvme.Subsystems[subsystem.Spec.Id.Value] = subsystem
+			}
+
+			request := &UpdateNVMeSubsystemHostsRequest{Name: subsystem.Spec.Id.Value, Hosts: tt.in}
+			response, err := testEnv.opiSpdkServer.UpdateNVMeSubsystemHosts(testEnv.ctx, request)
+			if !reflect.DeepEqual(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if tt.out != nil && !reflect.DeepEqual(testEnv.opiSpdkServer.Nvme.SubsystemHosts[subsystem.Spec.Id.Value], tt.out) {
+				t.Error("expected hosts to be updated to", tt.out)
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestFrontEnd_UpdateNVMeSubsystemHostsMask(t *testing.T) {
+	subsystem := &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{
+			Id:  &pc.ObjectKey{Value: "subsystem-test"},
+			Nqn: "nqn.2022-09.io.spdk:opi3",
+		},
+	}
+	host1 := NVMeHost{Nqn: "nqn.2014-08.org.nvmexpress:uuid:host1"}
+	host2 := NVMeHost{Nqn: "nqn.2014-08.org.nvmexpress:uuid:host2"}
+	spdkSubsystems := `{"id":%d,"error":{"code":0,"message":""},"result":[{"nqn":"nqn.2022-09.io.spdk:opi3",` +
+		`"allow_any_host":false,"hosts":[{"nqn":"nqn.2014-08.org.nvmexpress:uuid:host1"}]}]}`
+	spdkTrue := `{"id":%d,"error":{"code":0,"message":""},"result":true}`
+	tests := map[string]struct {
+		in      *NVMeSubsystemHosts
+		mask    []string
+		out     *NVMeSubsystemHosts
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"toggle allow any host": {
+			&NVMeSubsystemHosts{AllowAnyHost: true},
+			[]string{"allow_any_host"},
+			&NVMeSubsystemHosts{AllowAnyHost: true, Hosts: []NVMeHost{host1}},
+			[]string{spdkSubsystems, spdkTrue},
+			codes.OK,
+			"",
+		},
+		"replace hosts": {
+			&NVMeSubsystemHosts{AllowAnyHost: true, Hosts: []NVMeHost{host2}},
+			[]string{"hosts"},
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{host2}},
+			[]string{spdkSubsystems, spdkTrue, spdkTrue},
+			codes.OK,
+			"",
+		},
+		"field not updatable": {
+			&NVMeSubsystemHosts{},
+			[]string{"hosts.psk"},
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			`field "hosts.psk" cannot be updated, updatable fields: allow_any_host, hosts`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+			testEnv.opiSpdkServer.Nvme.Subsystems[subsystem.Spec.Id.Value] = subsystem
+			testEnv.opiSpdkServer.Nvme.SubsystemHosts[subsystem.Spec.Id.Value] = &NVMeSubsystemHosts{Hosts: []NVMeHost{host1}}
+
+			request := &UpdateNVMeSubsystemHostsRequest{Name: subsystem.Spec.Id.Value, Hosts: tt.in,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: tt.mask}}
+			response, err := testEnv.opiSpdkServer.UpdateNVMeSubsystemHosts(testEnv.ctx, request)
+			if !reflect.DeepEqual(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if er, _ := status.FromError(err); er.Code() != tt.errCode || er.Message() != tt.errMsg {
+				t.Error("error: expected", tt.errCode, tt.errMsg, "received", err)
+			}
+		})
+	}
+}
+
+func TestFrontEnd_GetNVMeSubsystemHosts(t *testing.T) {
+	subsystem := &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{
+			Id:  &pc.ObjectKey{Value: "subsystem-test"},
+			Nqn: "nqn.2022-09.io.spdk:opi3",
+		},
+	}
+	tests := map[string]struct {
+		out     *NVMeSubsystemHosts
+		spdk    []string
+		errCode codes.Code
+		errMsg  string
+		start   bool
+		exist   bool
+	}{
+		"valid request with valid SPDK response": {
+			&NVMeSubsystemHosts{Hosts: []NVMeHost{{Nqn: "nqn.2014-08.org.nvmexpress:uuid:host1"}}},
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[{"nqn":"nqn.2022-09.io.spdk:opi3",` +
+				`"allow_any_host":false,"hosts":[{"nqn":"nqn.2014-08.org.nvmexpress:uuid:host1"}]}]}`},
+			codes.OK,
+			"",
+			true,
+			true,
+		},
+		"subsystem missing in SPDK": {
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[]}`},
+			codes.FailedPrecondition,
+			fmt.Sprintf("Could not find NQN: %v", subsystem.Spec.Nqn),
+			true,
+			true,
+		},
+		"unknown key": {
+			nil,
+			[]string{},
+			codes.NotFound,
+			fmt.Sprintf("unable to find key %v", subsystem.Spec.Id.Value),
+			false,
+			false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(tt.start, tt.spdk)
+			defer testEnv.Close()
+
+			if tt.exist {
+				testEnv.opiSpdkServer.Nvme.Subsystems[subsystem.Spec.Id.Value] = subsystem
+			}
+
+			request := &GetNVMeSubsystemHostsRequest{Name: subsystem.Spec.Id.Value}
+			response, err := testEnv.opiSpdkServer.GetNVMeSubsystemHosts(testEnv.ctx, request)
+			if !reflect.DeepEqual(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+
+	"github.com/google/uuid"
+)
+
+// fillNamespaceIdentity sets the UUID, NGUID and EUI64 missing in spec, a
+// copy of the requested one, to values derived from the NQN of its
+// subsystem and its ID, so a namespace keeps its identifiers when it is
+// re-created, e.g. after a migration
+func fillNamespaceIdentity(nqn string, spec *pb.NVMeNamespaceSpec) {
+	name := []byte(nqn + "/" + spec.Id.Value)
+	derived := uuid.NewSHA1(uuid.NameSpaceURL, name)
+	if spec.Uuid == nil || spec.Uuid.Value == "" {
+		spec.Uuid = &pc.Uuid{Value: derived.String()}
+	}
+	if spec.Nguid == "" {
+		spec.Nguid = hex.EncodeToString(derived[:])
+	}
+	if spec.Eui64 == 0 {
+		eui := uuid.NewSHA1(uuid.NameSpaceURL, append(name, "/eui64"...))
+		// a locally administered unicast EUI-64, no IEEE OUI is owned
+		eui[0] = eui[0]&^0x01 | 0x02
+		spec.Eui64 = int64(binary.BigEndian.Uint64(eui[:8]))
+	}
+}
+
+// keepNamespaceIdentity sets the NSID, UUID, NGUID and EUI64 missing in
+// spec to the ones of the namespace with old, which SPDK still uses
+func keepNamespaceIdentity(spec *pb.NVMeNamespaceSpec, old *pb.NVMeNamespaceSpec) {
+	if spec.HostNsid == 0 {
+		spec.HostNsid = old.HostNsid
+	}
+	if spec.Uuid == nil || spec.Uuid.Value == "" {
+		spec.Uuid = old.Uuid
+	}
+	if spec.Nguid == "" {
+		spec.Nguid = old.Nguid
+	}
+	if spec.Eui64 == 0 {
+		spec.Eui64 = old.Eui64
+	}
+}
+
+// setIdentity sets the UUID, NGUID and EUI64 of the namespace with spec,
+// if they are set, in the nvmf_subsystem_add_ns params of it
+func setIdentity(params *nvmfNamespaceSpec, spec *pb.NVMeNamespaceSpec) error {
+	if spec.Uuid != nil && spec.Uuid.Value != "" {
+		parsed, err := uuid.Parse(spec.Uuid.Value)
+		if err != nil {
+			return fmt.Errorf("invalid uuid %q: %w", spec.Uuid.Value, err)
+		}
+		params.UUID = parsed.String()
+	}
+	if spec.Nguid != "" {
+		nguid := strings.ReplaceAll(spec.Nguid, "-", "")
+		if _, err := hex.DecodeString(nguid); err != nil || len(nguid) != 32 {
+			return fmt.Errorf("nguid %q must be 32 hexadecimal digits", spec.Nguid)
+		}
+		params.Nguid = strings.ToUpper(nguid)
+	}
+	if spec.Eui64 != 0 {
+		params.Eui64 = fmt.Sprintf("%016X", uint64(spec.Eui64))
+	}
+	return nil
+}
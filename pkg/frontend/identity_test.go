@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/opiproject/gospdk/spdk"
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// addNsSpdkStub records the namespaces SPDK is asked to add
+type addNsSpdkStub struct {
+	spdk.JSONRPC
+	added []nvmfNamespaceSpec
+}
+
+func (s *addNsSpdkStub) Call(method string, params, result interface{}) error {
+	if method == "nvmf_subsystem_add_ns" {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		var ns nvmfSubsystemAddNsParams
+		if err := json.Unmarshal(data, &ns); err != nil {
+			return err
+		}
+		s.added = append(s.added, ns.Namespace)
+	}
+	return s.JSONRPC.Call(method, params, result)
+}
+
+func TestFrontEnd_NamespaceIdentity(t *testing.T) {
+	tests := map[string]struct {
+		in      *pb.NVMeNamespaceSpec
+		out     *pb.NVMeNamespaceSpec
+		added   []nvmfNamespaceSpec
+		errCode codes.Code
+		errMsg  string
+	}{
+		"identifiers of the caller": {
+			&pb.NVMeNamespaceSpec{
+				HostNsid: 22,
+				Uuid:     &pc.Uuid{Value: "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb"},
+				Nguid:    "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
+				Eui64:    1967554867335598546,
+			},
+			&pb.NVMeNamespaceSpec{
+				HostNsid: 7,
+				Uuid:     &pc.Uuid{Value: "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb"},
+				Nguid:    "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
+				Eui64:    1967554867335598546,
+			},
+			[]nvmfNamespaceSpec{{
+				Nsid: 22, BdevName: "Malloc1", UUID: "1b4e28ba-2fa1-11d2-883f-b9a761bde3fb",
+				Nguid: "1B4E28BA2FA111D2883FB9A761BDE3FB", Eui64: "1B4E28BA2FA111D2",
+			}},
+			codes.OK,
+			"",
+		},
+		"derived identifiers and allocated nsid": {
+			&pb.NVMeNamespaceSpec{},
+			&pb.NVMeNamespaceSpec{
+				HostNsid: 7,
+				Uuid:     &pc.Uuid{Value: "13dba938-1b13-51ff-85d3-9845b163c6d8"},
+				Nguid:    "13dba9381b1351ff85d39845b163c6d8",
+				Eui64:    -378686420338257665,
+			},
+			[]nvmfNamespaceSpec{{
+				BdevName: "Malloc1", UUID: "13dba938-1b13-51ff-85d3-9845b163c6d8",
+				Nguid: "13DBA9381B1351FF85D39845B163C6D8", Eui64: "FABEA2B5027950FF",
+			}},
+			codes.OK,
+			"",
+		},
+		"invalid nguid": {
+			&pb.NVMeNamespaceSpec{Nguid: "1b4e28ba-2fa1"},
+			nil,
+			nil,
+			codes.InvalidArgument,
+			`nguid "1b4e28ba-2fa1" must be 32 hexadecimal digits`,
+		},
+		"invalid uuid": {
+			&pb.NVMeNamespaceSpec{Uuid: &pc.Uuid{Value: "namespace-test"}},
+			nil,
+			nil,
+			codes.InvalidArgument,
+			`invalid uuid "namespace-test": invalid UUID length: 14`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rpc := &addNsSpdkStub{JSONRPC: server.CreateTestSpdkStub(map[string]string{
+				"nvmf_subsystem_add_ns": `7`,
+			})}
+			s := NewServer(rpc)
+			s.Nvme.Subsystems["subsystem-test"] = &pb.NVMeSubsystem{
+				Spec: &pb.NVMeSubsystemSpec{Id: &pc.ObjectKey{Value: "subsystem-test"}, Nqn: "nqn.2022-09.io.spdk:opi3"},
+			}
+			spec := tt.in
+			spec.Id = &pc.ObjectKey{Value: "namespace-test"}
+			spec.SubsystemId = &pc.ObjectKey{Value: "subsystem-test"}
+			spec.VolumeId = &pc.ObjectKey{Value: "Malloc1"}
+
+			request := &pb.CreateNVMeNamespaceRequest{NvMeNamespace: &pb.NVMeNamespace{Spec: spec}}
+			sent := proto.Clone(request)
+			response, err := s.CreateNVMeNamespace(context.Background(), request)
+			if tt.out != nil {
+				tt.out.Id, tt.out.SubsystemId, tt.out.VolumeId = spec.Id, spec.SubsystemId, spec.VolumeId
+				if response == nil || !proto.Equal(response.Spec, tt.out) {
+					t.Error("response: expected", tt.out, "received", response)
+				}
+				if !proto.Equal(s.Nvme.Namespaces["namespace-test"].Spec, tt.out) {
+					t.Error("expected namespace to be kept with", tt.out)
+				}
+			}
+			if !proto.Equal(request, sent) {
+				t.Error("expected request to be kept as", sent, "received", request)
+			}
+			if !reflect.DeepEqual(rpc.added, tt.added) {
+				t.Error("expected SPDK namespaces", tt.added, "received", rpc.added)
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestFrontEnd_KeepNamespaceIdentity(t *testing.T) {
+	old := &pb.NVMeNamespaceSpec{
+		HostNsid: 7,
+		Uuid:     &pc.Uuid{Value: "91f6f0a8-a0d5-5d8c-8e37-2e8bbd4fdd87"},
+		Nguid:    "91f6f0a8a0d55d8c8e372e8bbd4fdd87",
+		Eui64:    1967554867335598546,
+	}
+	spec := &pb.NVMeNamespaceSpec{Nguid: "1b4e28ba2fa111d2883fb9a761bde3fb"}
+	keepNamespaceIdentity(spec, old)
+	want := &pb.NVMeNamespaceSpec{HostNsid: 7, Uuid: old.Uuid, Nguid: "1b4e28ba2fa111d2883fb9a761bde3fb", Eui64: 1967554867335598546}
+	if !proto.Equal(spec, want) {
+		t.Error("expected identity", want, "received", spec)
+	}
+}
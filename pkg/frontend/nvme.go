@@ -10,16 +10,21 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"strings"
 
 	"github.com/opiproject/gospdk/spdk"
 	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 
 	"github.com/google/uuid"
 	"github.com/ulule/deepcopier"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
@@ -30,44 +35,79 @@ const (
 
 // TODO: consider using https://pkg.go.dev/net#TCPAddr
 type tcpSubsystemListener struct {
-	listenAddr net.IP
-	listenPort string
-	protocol   string
+	listenAddr    net.IP
+	listenPort    string
+	protocol      string
+	secureChannel bool
+}
+
+// secureChannelListener is implemented by listeners which can require
+// connections to be secured with TLS
+type secureChannelListener interface {
+	SecureChannel() bool
 }
 
 // NewTCPSubsystemListener creates a new instance of tcpSubsystemListener
 func NewTCPSubsystemListener(listenAddr string) SubsystemListener {
-	host, port, err := net.SplitHostPort(listenAddr)
-	if err != nil {
-		log.Panicf("Invalid ip:port tuple: %v", listenAddr)
+	parsedAddr, port, protocol := parseListenAddr(listenAddr)
+	return &tcpSubsystemListener{
+		listenAddr: parsedAddr,
+		listenPort: port,
+		protocol:   protocol,
 	}
+}
 
-	parsedAddr := net.ParseIP(host)
-	if parsedAddr == nil {
-		log.Panicf("Invalid ip address: %v", host)
-	}
+// NewSecureTCPSubsystemListener creates a new instance of tcpSubsystemListener
+// which accepts only connections secured with TLS using a PSK of the host
+func NewSecureTCPSubsystemListener(listenAddr string) SubsystemListener {
+	listener := NewTCPSubsystemListener(listenAddr).(*tcpSubsystemListener)
+	listener.secureChannel = true
+	return listener
+}
 
-	var protocol string
-	switch {
-	case parsedAddr.To4() != nil:
-		protocol = ipv4NvmeTCPProtocol
-	case parsedAddr.To16() != nil:
-		protocol = ipv6NvmeTCPProtocol
-	default:
-		log.Panicf("Not supported protocol for: %v", listenAddr)
-	}
+func (c *tcpSubsystemListener) Transport() string {
+	return TransportTCP
+}
 
-	return &tcpSubsystemListener{
+func (c *tcpSubsystemListener) SecureChannel() bool {
+	return c.secureChannel
+}
+
+func (c *tcpSubsystemListener) Params(_ *pb.NVMeController, nqn string) spdk.NvmfSubsystemAddListenerParams {
+	result := spdk.NvmfSubsystemAddListenerParams{}
+	result.Nqn = nqn
+	result.ListenAddress.Trtype = "tcp"
+	result.ListenAddress.Traddr = c.listenAddr.String()
+	result.ListenAddress.Trsvcid = c.listenPort
+	result.ListenAddress.Adrfam = c.protocol
+
+	return result
+}
+
+type rdmaSubsystemListener struct {
+	listenAddr net.IP
+	listenPort string
+	protocol   string
+}
+
+// NewRDMASubsystemListener creates a new instance of rdmaSubsystemListener
+func NewRDMASubsystemListener(listenAddr string) SubsystemListener {
+	parsedAddr, port, protocol := parseListenAddr(listenAddr)
+	return &rdmaSubsystemListener{
 		listenAddr: parsedAddr,
 		listenPort: port,
 		protocol:   protocol,
 	}
 }
 
-func (c *tcpSubsystemListener) Params(_ *pb.NVMeController, nqn string) spdk.NvmfSubsystemAddListenerParams {
+func (c *rdmaSubsystemListener) Transport() string {
+	return TransportRDMA
+}
+
+func (c *rdmaSubsystemListener) Params(_ *pb.NVMeController, nqn string) spdk.NvmfSubsystemAddListenerParams {
 	result := spdk.NvmfSubsystemAddListenerParams{}
 	result.Nqn = nqn
-	result.ListenAddress.Trtype = "tcp"
+	result.ListenAddress.Trtype = "rdma"
 	result.ListenAddress.Traddr = c.listenAddr.String()
 	result.ListenAddress.Trsvcid = c.listenPort
 	result.ListenAddress.Adrfam = c.protocol
@@ -75,111 +115,400 @@ func (c *tcpSubsystemListener) Params(_ *pb.NVMeController, nqn string) spdk.Nvm
 	return result
 }
 
+// parseListenAddr splits ip:port tuple of IP based transports and finds its
+// address family
+func parseListenAddr(listenAddr string) (net.IP, string, string) {
+	host, port, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		log.Panicf("Invalid ip:port tuple: %v", listenAddr)
+	}
+
+	parsedAddr := net.ParseIP(host)
+	if parsedAddr == nil {
+		log.Panicf("Invalid ip address: %v", host)
+	}
+
+	protocol := addressFamily(parsedAddr)
+	if protocol == "" {
+		log.Panicf("Not supported protocol for: %v", listenAddr)
+	}
+	return parsedAddr, port, protocol
+}
+
+// addressFamily returns adrfam of an IP address or empty string if the
+// family is not supported
+func addressFamily(addr net.IP) string {
+	switch {
+	case addr.To4() != nil:
+		return ipv4NvmeTCPProtocol
+	case addr.To16() != nil:
+		return ipv6NvmeTCPProtocol
+	default:
+		return ""
+	}
+}
+
 // CreateNVMeSubsystem creates an NVMe Subsystem
-func (s *Server) CreateNVMeSubsystem(_ context.Context, in *pb.CreateNVMeSubsystemRequest) (*pb.NVMeSubsystem, error) {
-	log.Printf("CreateNVMeSubsystem: Received from client: %v", in)
+func (s *Server) CreateNVMeSubsystem(ctx context.Context, in *pb.CreateNVMeSubsystemRequest) (*pb.NVMeSubsystem, error) {
+	logging.FromContext(ctx).Infof("CreateNVMeSubsystem: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(subsystemsTable, in.NvMeSubsystem.Spec.Id.Value))
+	defer unlock()
 	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
 	subsys, ok := s.Nvme.Subsystems[in.NvMeSubsystem.Spec.Id.Value]
+	s.mu.RUnlock()
 	if ok {
-		log.Printf("Already existing NVMeSubsystem with id %v", in.NvMeSubsystem.Spec.Id.Value)
+		logging.FromContext(ctx).Infof("Already existing NVMeSubsystem with id %v", in.NvMeSubsystem.Spec.Id.Value)
 		return subsys, nil
 	}
 	// not found, so create a new one
-	params := spdk.NvmfCreateSubsystemParams{
-		Nqn:           in.NvMeSubsystem.Spec.Nqn,
-		SerialNumber:  in.NvMeSubsystem.Spec.SerialNumber,
-		ModelNumber:   in.NvMeSubsystem.Spec.ModelNumber,
-		AllowAnyHost:  true,
-		MaxNamespaces: int(in.NvMeSubsystem.Spec.MaxNamespaces),
+	s.mu.RLock()
+	hosts := &NVMeSubsystemHosts{AllowAnyHost: s.allowAnyHost, Hosts: []NVMeHost{}}
+	ana := &NVMeSubsystemAna{Reporting: s.anaReporting}
+	s.mu.RUnlock()
+	params := nvmfCreateSubsystemParams{
+		NvmfCreateSubsystemParams: spdk.NvmfCreateSubsystemParams{
+			Nqn:           in.NvMeSubsystem.Spec.Nqn,
+			SerialNumber:  in.NvMeSubsystem.Spec.SerialNumber,
+			ModelNumber:   in.NvMeSubsystem.Spec.ModelNumber,
+			AllowAnyHost:  hosts.AllowAnyHost,
+			MaxNamespaces: int(in.NvMeSubsystem.Spec.MaxNamespaces),
+		},
+		AnaReporting: ana.Reporting,
 	}
 	var result spdk.NvmfCreateSubsystemResult
-	err := s.rpc.Call("nvmf_create_subsystem", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "nvmf_create_subsystem", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
 		msg := fmt.Sprintf("Could not create NQN: %s", in.NvMeSubsystem.Spec.Nqn)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	var ver spdk.GetVersionResult
-	err = s.rpc.Call("spdk_get_version", nil, &ver)
+	err = tracing.Call(ctx, s.rpc, "spdk_get_version", nil, &ver)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", ver)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", ver)
 	response := &pb.NVMeSubsystem{}
 	err = deepcopier.Copy(in.NvMeSubsystem).To(response)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	response.Status = &pb.NVMeSubsystemStatus{FirmwareRevision: ver.Version}
+	if err := store.SetJSON(s.store, subsystemHostsTable, in.NvMeSubsystem.Spec.Id.Value, hosts); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	if err := store.SetJSON(s.store, subsystemAnaTable, in.NvMeSubsystem.Spec.Id.Value, ana); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	if err := s.store.Set(subsystemsTable, in.NvMeSubsystem.Spec.Id.Value, response); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.Nvme.Subsystems[in.NvMeSubsystem.Spec.Id.Value] = response
+	s.Nvme.SubsystemHosts[in.NvMeSubsystem.Spec.Id.Value] = hosts
+	s.Nvme.SubsystemAna[in.NvMeSubsystem.Spec.Id.Value] = ana
+	s.mu.Unlock()
 	return response, nil
 }
 
 // DeleteNVMeSubsystem deletes an NVMe Subsystem
-func (s *Server) DeleteNVMeSubsystem(_ context.Context, in *pb.DeleteNVMeSubsystemRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteNVMeSubsystem: Received from client: %v", in)
+func (s *Server) DeleteNVMeSubsystem(ctx context.Context, in *pb.DeleteNVMeSubsystemRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteNVMeSubsystem: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(subsystemsTable, in.Name))
+	defer unlock()
+	s.mu.RLock()
 	subsys, ok := s.Nvme.Subsystems[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		if in.AllowMissing {
 			return &emptypb.Empty{}, nil
 		}
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	params := spdk.NvmfDeleteSubsystemParams{
 		Nqn: subsys.Spec.Nqn,
 	}
 	var result spdk.NvmfDeleteSubsystemResult
-	err := s.rpc.Call("nvmf_delete_subsystem", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "nvmf_delete_subsystem", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
 		msg := fmt.Sprintf("Could not delete NQN: %s", subsys.Spec.Nqn)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
+	if err := s.store.Delete(subsystemsTable, subsys.Spec.Id.Value); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	if err := s.store.Delete(subsystemHostsTable, subsys.Spec.Id.Value); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	if err := s.store.Delete(subsystemAnaTable, subsys.Spec.Id.Value); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	delete(s.Nvme.Subsystems, subsys.Spec.Id.Value)
+	delete(s.Nvme.SubsystemHosts, subsys.Spec.Id.Value)
+	delete(s.Nvme.SubsystemAna, subsys.Spec.Id.Value)
+	s.mu.Unlock()
 	return &emptypb.Empty{}, nil
 }
 
 // UpdateNVMeSubsystem updates an NVMe Subsystem
-func (s *Server) UpdateNVMeSubsystem(_ context.Context, in *pb.UpdateNVMeSubsystemRequest) (*pb.NVMeSubsystem, error) {
-	log.Printf("UpdateNVMeSubsystem: Received from client: %v", in)
-	return nil, status.Errorf(codes.Unimplemented, "UpdateNVMeSubsystem method is not implemented")
+func (s *Server) UpdateNVMeSubsystem(ctx context.Context, in *pb.UpdateNVMeSubsystemRequest) (*pb.NVMeSubsystem, error) {
+	logging.FromContext(ctx).Infof("UpdateNVMeSubsystem: Received from client: %v", logging.Redact(in))
+	fields, err := server.UpdatedFields(in.UpdateMask, "spec.serial_number", "spec.model_number", "spec.max_namespaces")
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	unlock := s.locks.Lock(server.LockKey(subsystemsTable, in.NvMeSubsystem.Spec.Id.Value))
+	defer unlock()
+	s.mu.RLock()
+	subsys, ok := s.Nvme.Subsystems[in.NvMeSubsystem.Spec.Id.Value]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.NvMeSubsystem.Spec.Id.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if in.NvMeSubsystem.Spec.Nqn != "" && in.NvMeSubsystem.Spec.Nqn != subsys.Spec.Nqn {
+		err := status.Errorf(codes.InvalidArgument, "NQN of subsystem %s cannot be changed", subsys.Spec.Id.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+
+	response := proto.Clone(subsys).(*pb.NVMeSubsystem)
+	if fields["spec.serial_number"] {
+		response.Spec.SerialNumber = in.NvMeSubsystem.Spec.SerialNumber
+	}
+	if fields["spec.model_number"] {
+		response.Spec.ModelNumber = in.NvMeSubsystem.Spec.ModelNumber
+	}
+	if fields["spec.max_namespaces"] {
+		response.Spec.MaxNamespaces = in.NvMeSubsystem.Spec.MaxNamespaces
+	}
+	if proto.Equal(response.Spec, subsys.Spec) {
+		return response, nil
+	}
+	// SPDK cannot change any of the fields of an existing subsystem
+	if err := s.replaceSubsystem(ctx, response.Spec, subsys.Spec); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if err := s.store.Set(subsystemsTable, response.Spec.Id.Value, response); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.Subsystems[response.Spec.Id.Value] = response
+	s.mu.Unlock()
+	return response, nil
+}
+
+// replaceSubsystem re-creates the subsystem with the NQN of spec in SPDK
+// with the properties of spec. Hosts, namespaces and listeners of the old
+// subsystem are added to the new one. If the new subsystem cannot be
+// created, the old one is restored with oldSpec.
+func (s *Server) replaceSubsystem(ctx context.Context, spec *pb.NVMeSubsystemSpec, oldSpec *pb.NVMeSubsystemSpec) error {
+	old, err := s.spdkSubsystem(ctx, spec.Nqn)
+	if err != nil {
+		return err
+	}
+	for _, ns := range old.Namespaces {
+		if spec.MaxNamespaces > 0 && int64(ns.Nsid) > spec.MaxNamespaces {
+			return status.Errorf(codes.InvalidArgument, "max_namespaces %d is less than NSID %d of existing namespace",
+				spec.MaxNamespaces, ns.Nsid)
+		}
+	}
+
+	params := spdk.NvmfDeleteSubsystemParams{
+		Nqn: spec.Nqn,
+	}
+	var result spdk.NvmfDeleteSubsystemResult
+	err = tracing.Call(ctx, s.rpc, "nvmf_delete_subsystem", &params, &result)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if !result {
+		return status.Errorf(codes.InvalidArgument, "Could not delete NQN: %s", spec.Nqn)
+	}
+	err = s.restoreSubsystem(ctx, spec, old)
+	if err == nil {
+		return nil
+	}
+	// the new subsystem can be partially created, it is missing if its
+	// creation failed
+	if derr := tracing.Call(ctx, s.rpc, "nvmf_delete_subsystem", &params, &result); derr != nil {
+		logging.FromContext(ctx).Infof("Could not delete NQN %s: %v", spec.Nqn, derr)
+	}
+	if rerr := s.restoreSubsystem(ctx, oldSpec, old); rerr != nil {
+		return status.Errorf(codes.Internal, "subsystem %s is lost: %v, cannot restore it: %v", spec.Id.Value, err, rerr)
+	}
+	return err
+}
+
+// restoreSubsystem creates a subsystem with spec and adds hosts, namespaces
+// and listeners of old to it
+func (s *Server) restoreSubsystem(ctx context.Context, spec *pb.NVMeSubsystemSpec, old *nvmfSubsystem) error {
+	params := nvmfCreateSubsystemParams{
+		NvmfCreateSubsystemParams: spdk.NvmfCreateSubsystemParams{
+			Nqn:           spec.Nqn,
+			SerialNumber:  spec.SerialNumber,
+			ModelNumber:   spec.ModelNumber,
+			AllowAnyHost:  old.AllowAnyHost,
+			MaxNamespaces: int(spec.MaxNamespaces),
+		},
+		AnaReporting: s.subsystemAna(spec.Id.Value).Reporting,
+	}
+	if err := s.callSpdk(ctx, "nvmf_create_subsystem", &params, "Could not create NQN: "+spec.Nqn); err != nil {
+		return err
+	}
+	for _, host := range old.Hosts {
+		if err := s.addHost(ctx, spec.Nqn, hostFromSpdk(host)); err != nil {
+			return err
+		}
+	}
+	for i := range old.Namespaces {
+		ns := &old.Namespaces[i]
+		id := s.namespaceID(spec.Id.Value, ns)
+		if err := s.addNamespace(ctx, spec.Nqn, id, nvmfNamespaceSpec{
+			Nsid: ns.Nsid, BdevName: ns.BdevName, Nguid: ns.Nguid, Eui64: ns.Eui64, UUID: ns.UUID,
+			PtplFile: s.activeReservation(id).PtplFile,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, addr := range old.ListenAddresses {
+		params := nvmfSubsystemAddListenerParams{}
+		params.Nqn = spec.Nqn
+		params.ListenAddress.Trtype = addr.Trtype
+		params.ListenAddress.Traddr = addr.Traddr
+		params.ListenAddress.Trsvcid = addr.Trsvcid
+		params.ListenAddress.Adrfam = addr.Adrfam
+		params.SecureChannel = strings.EqualFold(addr.Trtype, TransportTCP) && s.secureChannel(TransportTCP)
+		if err := s.callSpdk(ctx, "nvmf_subsystem_add_listener", &params, "Could not add listener: "+addr.Traddr); err != nil {
+			return err
+		}
+	}
+	return s.restoreAnaStates(ctx, spec)
+}
+
+// restoreAnaStates sets the ANA states of the controllers of the subsystem
+// with spec again after its listeners were re-created
+func (s *Server) restoreAnaStates(ctx context.Context, spec *pb.NVMeSubsystemSpec) error {
+	s.mu.RLock()
+	var controllers []*pb.NVMeController
+	for _, id := range server.SortedKeys(s.Nvme.Controllers) {
+		if controller := s.Nvme.Controllers[id]; controller.Spec.SubsystemId.Value == spec.Id.Value {
+			controllers = append(controllers, controller)
+		}
+	}
+	s.mu.RUnlock()
+	for _, controller := range controllers {
+		params, err := s.listenerParams(controller, s.controllerTransport(controller.Spec.Id.Value), spec.Nqn)
+		if err != nil {
+			return err
+		}
+		if err := s.restoreAnaState(ctx, controller.Spec.Id.Value, &params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addNamespace adds namespace id with spec to the subsystem nqn in SPDK
+// and attaches it to the hosts it is visible to
+func (s *Server) addNamespace(ctx context.Context, nqn string, id string, spec nvmfNamespaceSpec) error {
+	spec.NoAutoVisible = s.visibility(id).Hidden
+	params := nvmfSubsystemAddNsParams{Nqn: nqn, Namespace: spec}
+	var result spdk.NvmfSubsystemAddNsResult
+	err := tracing.Call(ctx, s.rpc, "nvmf_subsystem_add_ns", &params, &result)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if result < 0 {
+		return status.Errorf(codes.InvalidArgument, "Could not create NS: %d", spec.Nsid)
+	}
+	return s.addVisibleHosts(ctx, id, nqn, int(result))
+}
+
+// namespaceID returns the ID of the namespace of subsystem subsysID which
+// is spdkNs in SPDK or empty string
+func (s *Server) namespaceID(subsysID string, spdkNs *nvmfNamespace) string {
+	spdkSubsys := &nvmfSubsystem{Namespaces: []nvmfNamespace{*spdkNs}}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, id := range server.SortedKeys(s.Nvme.Namespaces) {
+		namespace := s.Nvme.Namespaces[id]
+		if namespace.Spec.SubsystemId.Value == subsysID && findNamespace(spdkSubsys, namespace) != nil {
+			return id
+		}
+	}
+	return ""
+}
+
+// callSpdk calls an SPDK method returning a boolean result. A false result
+// is reported as InvalidArgument with msg.
+func (s *Server) callSpdk(ctx context.Context, method string, params interface{}, msg string) error {
+	var result bool
+	err := tracing.Call(ctx, s.rpc, method, params, &result)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if !result {
+		return status.Errorf(codes.InvalidArgument, msg)
+	}
+	return nil
 }
 
 // ListNVMeSubsystems lists NVMe Subsystems
-func (s *Server) ListNVMeSubsystems(_ context.Context, in *pb.ListNVMeSubsystemsRequest) (*pb.ListNVMeSubsystemsResponse, error) {
-	log.Printf("ListNVMeSubsystems: Received from client: %v", in)
-	size, offset, perr := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+func (s *Server) ListNVMeSubsystems(ctx context.Context, in *pb.ListNVMeSubsystemsRequest) (*pb.ListNVMeSubsystemsResponse, error) {
+	logging.FromContext(ctx).Infof("ListNVMeSubsystems: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	size, offset, perr := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if perr != nil {
-		log.Printf("error: %v", perr)
+		logging.FromContext(ctx).Error(perr)
 		return nil, perr
 	}
 	var result []spdk.NvmfGetSubsystemsResult
-	err := s.rpc.Call("nvmf_get_subsystems", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "nvmf_get_subsystems", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	token := ""
-	log.Printf("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
+	logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
 	result, hasMoreElements := server.LimitPagination(result, offset, size)
 	if hasMoreElements {
 		token = uuid.New().String()
+		s.mu.Lock()
 		s.Pagination[token] = offset + size
+		s.mu.Unlock()
 	}
 	Blobarray := make([]*pb.NVMeSubsystem, len(result))
 	for i := range result {
@@ -190,22 +519,24 @@ func (s *Server) ListNVMeSubsystems(_ context.Context, in *pb.ListNVMeSubsystems
 }
 
 // GetNVMeSubsystem gets NVMe Subsystems
-func (s *Server) GetNVMeSubsystem(_ context.Context, in *pb.GetNVMeSubsystemRequest) (*pb.NVMeSubsystem, error) {
-	log.Printf("GetNVMeSubsystem: Received from client: %v", in)
+func (s *Server) GetNVMeSubsystem(ctx context.Context, in *pb.GetNVMeSubsystemRequest) (*pb.NVMeSubsystem, error) {
+	logging.FromContext(ctx).Infof("GetNVMeSubsystem: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
 	subsys, ok := s.Nvme.Subsystems[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
 	var result []spdk.NvmfGetSubsystemsResult
-	err := s.rpc.Call("nvmf_get_subsystems", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "nvmf_get_subsystems", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 
 	for i := range result {
 		r := &result[i]
@@ -214,188 +545,329 @@ func (s *Server) GetNVMeSubsystem(_ context.Context, in *pb.GetNVMeSubsystemRequ
 		}
 	}
 	msg := fmt.Sprintf("Could not find NQN: %s", subsys.Spec.Nqn)
-	log.Print(msg)
+	logging.FromContext(ctx).Error(msg)
 	return nil, status.Errorf(codes.InvalidArgument, msg)
 }
 
 // NVMeSubsystemStats gets NVMe Subsystem stats
-func (s *Server) NVMeSubsystemStats(_ context.Context, in *pb.NVMeSubsystemStatsRequest) (*pb.NVMeSubsystemStatsResponse, error) {
-	log.Printf("NVMeSubsystemStats: Received from client: %v", in)
-	var result spdk.NvmfGetSubsystemStatsResult
-	err := s.rpc.Call("nvmf_get_stats", nil, &result)
+func (s *Server) NVMeSubsystemStats(ctx context.Context, in *pb.NVMeSubsystemStatsRequest) (*pb.NVMeSubsystemStatsResponse, error) {
+	logging.FromContext(ctx).Infof("NVMeSubsystemStats: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	subsys, ok := s.Nvme.Subsystems[in.SubsystemId.Value]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.SubsystemId.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	stats, err := s.pollGroupStats(ctx, subsys.Spec.Nqn, anyQpair)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
-	return &pb.NVMeSubsystemStatsResponse{Stats: &pb.VolumeStats{ReadOpsCount: -1, WriteOpsCount: -1}}, nil
+	return &pb.NVMeSubsystemStatsResponse{Stats: stats}, nil
 }
 
 // CreateNVMeController creates an NVMe controller
-func (s *Server) CreateNVMeController(_ context.Context, in *pb.CreateNVMeControllerRequest) (*pb.NVMeController, error) {
-	log.Printf("Received from client: %v", in.NvMeController)
+func (s *Server) CreateNVMeController(ctx context.Context, in *pb.CreateNVMeControllerRequest) (*pb.NVMeController, error) {
+	logging.FromContext(ctx).Infof("Received from client: %v", in.NvMeController)
+	unlock := s.locks.Lock(server.LockKey(controllersTable, in.NvMeController.Spec.Id.Value),
+		server.LockKey(subsystemsTable, in.NvMeController.Spec.SubsystemId.Value))
+	defer unlock()
 	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
 	controller, ok := s.Nvme.Controllers[in.NvMeController.Spec.Id.Value]
+	s.mu.RUnlock()
 	if ok {
-		log.Printf("Already existing NVMeController with id %v", in.NvMeController.Spec.Id.Value)
+		logging.FromContext(ctx).Infof("Already existing NVMeController with id %v", in.NvMeController.Spec.Id.Value)
 		return controller, nil
 	}
 	// not found, so create a new one
+	s.mu.RLock()
 	subsys, ok := s.Nvme.Subsystems[in.NvMeController.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
 	if !ok {
 		err := fmt.Errorf("unable to find subsystem %s", in.NvMeController.Spec.SubsystemId.Value)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
-	params := s.Nvme.subsysListener.Params(in.NvMeController, subsys.Spec.Nqn)
+	transport := s.controllerTransport(in.NvMeController.Spec.Id.Value)
+	params, err := s.listenerParams(in.NvMeController, transport, subsys.Spec.Nqn)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if other := s.conflictingController(in.NvMeController, transport, &params); other != "" {
+		err := status.Errorf(codes.AlreadyExists, "NVMeController %s already listens on %s %s:%s for %s", other,
+			params.ListenAddress.Trtype, params.ListenAddress.Traddr, params.ListenAddress.Trsvcid, params.Nqn)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
 	var result spdk.NvmfSubsystemAddListenerResult
-	err := s.rpc.Call("nvmf_subsystem_add_listener", &params, &result)
+	err = tracing.Call(ctx, s.rpc, "nvmf_subsystem_add_listener", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
 		msg := fmt.Sprintf("Could not create CTRL: %s", in.NvMeController.Spec.Id.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
+	in.NvMeController.Spec.NvmeControllerId = -1
+	in.NvMeController.Status = &pb.NVMeControllerStatus{Active: true}
+	// the transport is kept to remove the same listener if the default changes
+	if err := store.SetJSON(s.store, controllerTransportsTable, in.NvMeController.Spec.Id.Value, &transport); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	if err := s.store.Set(controllersTable, in.NvMeController.Spec.Id.Value, in.NvMeController); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.Nvme.Controllers[in.NvMeController.Spec.Id.Value] = in.NvMeController
-	s.Nvme.Controllers[in.NvMeController.Spec.Id.Value].Spec.NvmeControllerId = -1
-	s.Nvme.Controllers[in.NvMeController.Spec.Id.Value].Status = &pb.NVMeControllerStatus{Active: true}
+	s.Nvme.ControllerTransports[in.NvMeController.Spec.Id.Value] = &transport
+	s.mu.Unlock()
 	response := &pb.NVMeController{Spec: &pb.NVMeControllerSpec{Id: &pc.ObjectKey{Value: "TBD"}}}
 	err = deepcopier.Copy(in.NvMeController).To(response)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	return response, nil
 }
 
 // DeleteNVMeController deletes an NVMe controller
-func (s *Server) DeleteNVMeController(_ context.Context, in *pb.DeleteNVMeControllerRequest) (*emptypb.Empty, error) {
-	log.Printf("Received from client: %v", in.Name)
+func (s *Server) DeleteNVMeController(ctx context.Context, in *pb.DeleteNVMeControllerRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("Received from client: %v", in.Name)
+	unlock := s.lockWithSubsystem(controllersTable, in.Name)
+	defer unlock()
+	s.mu.RLock()
 	controller, ok := s.Nvme.Controllers[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		if in.AllowMissing {
 			return &emptypb.Empty{}, nil
 		}
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
+	s.mu.RLock()
 	subsys, ok := s.Nvme.Subsystems[controller.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
 	if !ok {
 		err := fmt.Errorf("unable to find subsystem %s", controller.Spec.SubsystemId.Value)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
-	params := s.Nvme.subsysListener.Params(controller, subsys.Spec.Nqn)
+	// only the listener of the controller transport is removed
+	params, err := s.listenerParams(controller, s.controllerTransport(in.Name), subsys.Spec.Nqn)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
 	var result spdk.NvmfSubsystemAddListenerResult
-	err := s.rpc.Call("nvmf_subsystem_remove_listener", &params, &result)
+	err = tracing.Call(ctx, s.rpc, "nvmf_subsystem_remove_listener", &params.NvmfSubsystemAddListenerParams, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
 		msg := fmt.Sprintf("Could not delete NQN:ID %s:%d", subsys.Spec.Nqn, controller.Spec.NvmeControllerId)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
+	if err := s.store.Delete(controllersTable, controller.Spec.Id.Value); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	if err := s.store.Delete(controllerTransportsTable, controller.Spec.Id.Value); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	if err := s.store.Delete(controllerAnaTable, controller.Spec.Id.Value); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	delete(s.Nvme.Controllers, controller.Spec.Id.Value)
+	delete(s.Nvme.ControllerTransports, controller.Spec.Id.Value)
+	delete(s.Nvme.ControllerAna, controller.Spec.Id.Value)
+	s.mu.Unlock()
 	return &emptypb.Empty{}, nil
 }
 
 // UpdateNVMeController updates an NVMe controller
-func (s *Server) UpdateNVMeController(_ context.Context, in *pb.UpdateNVMeControllerRequest) (*pb.NVMeController, error) {
-	log.Printf("UpdateNVMeController: Received from client: %v", in)
+func (s *Server) UpdateNVMeController(ctx context.Context, in *pb.UpdateNVMeControllerRequest) (*pb.NVMeController, error) {
+	logging.FromContext(ctx).Infof("UpdateNVMeController: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(controllersTable, in.NvMeController.Spec.Id.Value),
+		server.LockKey(subsystemsTable, in.NvMeController.Spec.SubsystemId.Value))
+	defer unlock()
+	in.NvMeController.Status = &pb.NVMeControllerStatus{Active: true}
+	if err := s.store.Set(controllersTable, in.NvMeController.Spec.Id.Value, in.NvMeController); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.Nvme.Controllers[in.NvMeController.Spec.Id.Value] = in.NvMeController
-	s.Nvme.Controllers[in.NvMeController.Spec.Id.Value].Status = &pb.NVMeControllerStatus{Active: true}
+	s.mu.Unlock()
 	response := &pb.NVMeController{}
 	err := deepcopier.Copy(in.NvMeController).To(response)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	return response, nil
 }
 
 // ListNVMeControllers lists NVMe controllers
-func (s *Server) ListNVMeControllers(_ context.Context, in *pb.ListNVMeControllersRequest) (*pb.ListNVMeControllersResponse, error) {
-	log.Printf("Received from client: %v", in.Parent)
+func (s *Server) ListNVMeControllers(ctx context.Context, in *pb.ListNVMeControllersRequest) (*pb.ListNVMeControllersResponse, error) {
+	logging.FromContext(ctx).Infof("Received from client: %v", in.Parent)
 	Blobarray := []*pb.NVMeController{}
+	token := uuid.New().String()
+	s.mu.Lock()
 	for _, controller := range s.Nvme.Controllers {
 		Blobarray = append(Blobarray, controller)
 	}
-	token := uuid.New().String()
 	s.Pagination[token] = int(in.PageSize)
+	s.mu.Unlock()
 	return &pb.ListNVMeControllersResponse{NvMeControllers: Blobarray, NextPageToken: token}, nil
 }
 
 // GetNVMeController gets an NVMe controller
-func (s *Server) GetNVMeController(_ context.Context, in *pb.GetNVMeControllerRequest) (*pb.NVMeController, error) {
-	log.Printf("Received from client: %v", in.Name)
+func (s *Server) GetNVMeController(ctx context.Context, in *pb.GetNVMeControllerRequest) (*pb.NVMeController, error) {
+	logging.FromContext(ctx).Infof("Received from client: %v", in.Name)
+	s.mu.RLock()
 	controller, ok := s.Nvme.Controllers[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	return &pb.NVMeController{Spec: &pb.NVMeControllerSpec{Id: &pc.ObjectKey{Value: in.Name}, NvmeControllerId: controller.Spec.NvmeControllerId}, Status: &pb.NVMeControllerStatus{Active: true}}, nil
+	// the OPI API has no ANA state, hosts cannot use inaccessible paths
+	active := s.controllerAna(in.Name).AnaState != AnaStateInaccessible
+	return &pb.NVMeController{Spec: &pb.NVMeControllerSpec{Id: &pc.ObjectKey{Value: in.Name}, NvmeControllerId: controller.Spec.NvmeControllerId}, Status: &pb.NVMeControllerStatus{Active: active}}, nil
 }
 
 // NVMeControllerStats gets an NVMe controller stats
-func (s *Server) NVMeControllerStats(_ context.Context, in *pb.NVMeControllerStatsRequest) (*pb.NVMeControllerStatsResponse, error) {
-	log.Printf("NVMeControllerStats: Received from client: %v", in)
-	return &pb.NVMeControllerStatsResponse{Stats: &pb.VolumeStats{ReadOpsCount: -1, WriteOpsCount: -1}}, nil
+func (s *Server) NVMeControllerStats(ctx context.Context, in *pb.NVMeControllerStatsRequest) (*pb.NVMeControllerStatsResponse, error) {
+	logging.FromContext(ctx).Infof("NVMeControllerStats: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	controller, ok := s.Nvme.Controllers[in.Id.Value]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Id.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	s.mu.RLock()
+	subsys, ok := s.Nvme.Subsystems[controller.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
+	if !ok {
+		err := fmt.Errorf("unable to find subsystem %s", controller.Spec.SubsystemId.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	// every controller has its own listener, so its queue pairs are the
+	// ones connected through it
+	params, err := s.listenerParams(controller, s.controllerTransport(in.Id.Value), subsys.Spec.Nqn)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	stats, err := s.pollGroupStats(ctx, subsys.Spec.Nqn, onListener(&params))
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	return &pb.NVMeControllerStatsResponse{Stats: stats}, nil
 }
 
 // CreateNVMeNamespace creates an NVMe namespace
-func (s *Server) CreateNVMeNamespace(_ context.Context, in *pb.CreateNVMeNamespaceRequest) (*pb.NVMeNamespace, error) {
-	log.Printf("CreateNVMeNamespace: Received from client: %v", in)
+func (s *Server) CreateNVMeNamespace(ctx context.Context, in *pb.CreateNVMeNamespaceRequest) (*pb.NVMeNamespace, error) {
+	logging.FromContext(ctx).Infof("CreateNVMeNamespace: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(namespacesTable, in.NvMeNamespace.Spec.Id.Value),
+		server.LockKey(subsystemsTable, in.NvMeNamespace.Spec.SubsystemId.Value))
+	defer unlock()
 	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
 	namespace, ok := s.Nvme.Namespaces[in.NvMeNamespace.Spec.Id.Value]
+	s.mu.RUnlock()
 	if ok {
-		log.Printf("Already existing NVMeNamespace with id %v", in.NvMeNamespace.Spec.Id.Value)
+		logging.FromContext(ctx).Infof("Already existing NVMeNamespace with id %v", in.NvMeNamespace.Spec.Id.Value)
 		return namespace, nil
 	}
 	// not found, so create a new one
+	s.mu.RLock()
 	subsys, ok := s.Nvme.Subsystems[in.NvMeNamespace.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
 	if !ok {
 		err := fmt.Errorf("unable to find subsystem %s", in.NvMeNamespace.Spec.SubsystemId.Value)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
-	params := spdk.NvmfSubsystemAddNsParams{
+	// the identifiers and NSID are filled in a copy, the request is kept
+	namespace = proto.Clone(in.NvMeNamespace).(*pb.NVMeNamespace)
+	reservation := s.wantedReservation(namespace.Spec.Id.Value)
+	params := nvmfSubsystemAddNsParams{
 		Nqn: subsys.Spec.Nqn,
 	}
 
 	// TODO: using bdev for volume id as a middle end handle for now
-	params.Namespace.Nsid = int(in.NvMeNamespace.Spec.HostNsid)
-	params.Namespace.BdevName = in.NvMeNamespace.Spec.VolumeId.Value
+	params.Namespace.Nsid = int(namespace.Spec.HostNsid)
+	params.Namespace.BdevName = namespace.Spec.VolumeId.Value
+	params.Namespace.PtplFile = reservation.PtplFile
+	params.Namespace.NoAutoVisible = s.visibility(namespace.Spec.Id.Value).Hidden
+	fillNamespaceIdentity(subsys.Spec.Nqn, namespace.Spec)
+	if err := setIdentity(&params.Namespace, namespace.Spec); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	var result spdk.NvmfSubsystemAddNsResult
-	err := s.rpc.Call("nvmf_subsystem_add_ns", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "nvmf_subsystem_add_ns", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if result < 0 {
-		msg := fmt.Sprintf("Could not create NS: %s", in.NvMeNamespace.Spec.Id.Value)
-		log.Print(msg)
+		msg := fmt.Sprintf("Could not create NS: %s", namespace.Spec.Id.Value)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
-	s.Nvme.Namespaces[in.NvMeNamespace.Spec.Id.Value] = in.NvMeNamespace
+	// the NSID allocated by SPDK identifies the namespace from now on
+	namespace.Spec.HostNsid = int32(result)
+	if err := s.addVisibleHosts(ctx, namespace.Spec.Id.Value, subsys.Spec.Nqn, int(result)); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	if reservation.PtplFile != "" {
+		if err := s.setActiveReservation(namespace.Spec.Id.Value, &reservation); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+	}
+	if err := s.store.Set(namespacesTable, namespace.Spec.Id.Value, namespace); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.Namespaces[namespace.Spec.Id.Value] = namespace
+	s.mu.Unlock()
 
 	response := &pb.NVMeNamespace{}
-	err = deepcopier.Copy(in.NvMeNamespace).To(response)
+	err = deepcopier.Copy(namespace).To(response)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	response.Status = &pb.NVMeNamespaceStatus{PciState: 2, PciOperState: 1}
@@ -403,21 +875,27 @@ func (s *Server) CreateNVMeNamespace(_ context.Context, in *pb.CreateNVMeNamespa
 }
 
 // DeleteNVMeNamespace deletes an NVMe namespace
-func (s *Server) DeleteNVMeNamespace(_ context.Context, in *pb.DeleteNVMeNamespaceRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteNVMeNamespace: Received from client: %v", in)
+func (s *Server) DeleteNVMeNamespace(ctx context.Context, in *pb.DeleteNVMeNamespaceRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteNVMeNamespace: Received from client: %v", logging.Redact(in))
+	unlock := s.lockWithSubsystem(namespacesTable, in.Name)
+	defer unlock()
+	s.mu.RLock()
 	namespace, ok := s.Nvme.Namespaces[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		if in.AllowMissing {
 			return &emptypb.Empty{}, nil
 		}
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
+	s.mu.RLock()
 	subsys, ok := s.Nvme.Subsystems[namespace.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
 	if !ok {
 		err := fmt.Errorf("unable to find subsystem %s", namespace.Spec.SubsystemId.Value)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
@@ -426,72 +904,113 @@ func (s *Server) DeleteNVMeNamespace(_ context.Context, in *pb.DeleteNVMeNamespa
 		Nsid: int(namespace.Spec.HostNsid),
 	}
 	var result spdk.NvmfSubsystemRemoveNsResult
-	err := s.rpc.Call("nvmf_subsystem_remove_ns", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "nvmf_subsystem_remove_ns", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
 		msg := fmt.Sprintf("Could not delete NS: %s", in.Name)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
+	if err := s.store.Delete(namespacesTable, namespace.Spec.Id.Value); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	for _, table := range []string{namespaceReservationsTable, activeReservationsTable, namespaceVisibilityTable} {
+		if err := s.store.Delete(table, namespace.Spec.Id.Value); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, server.StoreError(err)
+		}
+	}
+	s.mu.Lock()
 	delete(s.Nvme.Namespaces, namespace.Spec.Id.Value)
+	delete(s.Nvme.NamespaceReservations, namespace.Spec.Id.Value)
+	delete(s.Nvme.ActiveReservations, namespace.Spec.Id.Value)
+	delete(s.Nvme.NamespaceVisibility, namespace.Spec.Id.Value)
+	s.mu.Unlock()
 	return &emptypb.Empty{}, nil
 }
 
 // UpdateNVMeNamespace updates an NVMe namespace
-func (s *Server) UpdateNVMeNamespace(_ context.Context, in *pb.UpdateNVMeNamespaceRequest) (*pb.NVMeNamespace, error) {
-	log.Printf("UpdateNVMeNamespace: Received from client: %v", in)
-	s.Nvme.Namespaces[in.NvMeNamespace.Spec.Id.Value] = in.NvMeNamespace
-	s.Nvme.Namespaces[in.NvMeNamespace.Spec.Id.Value].Status = &pb.NVMeNamespaceStatus{PciState: 2, PciOperState: 1}
+func (s *Server) UpdateNVMeNamespace(ctx context.Context, in *pb.UpdateNVMeNamespaceRequest) (*pb.NVMeNamespace, error) {
+	logging.FromContext(ctx).Infof("UpdateNVMeNamespace: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(namespacesTable, in.NvMeNamespace.Spec.Id.Value),
+		server.LockKey(subsystemsTable, in.NvMeNamespace.Spec.SubsystemId.Value))
+	defer unlock()
+	s.mu.RLock()
+	namespace, ok := s.Nvme.Namespaces[in.NvMeNamespace.Spec.Id.Value]
+	s.mu.RUnlock()
+	updated := proto.Clone(in.NvMeNamespace).(*pb.NVMeNamespace)
+	if ok {
+		if err := s.applyReservation(ctx, namespace); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		keepNamespaceIdentity(updated.Spec, namespace.Spec)
+	}
+	updated.Status = &pb.NVMeNamespaceStatus{PciState: 2, PciOperState: 1}
+	if err := s.store.Set(namespacesTable, updated.Spec.Id.Value, updated); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.Namespaces[updated.Spec.Id.Value] = updated
+	s.mu.Unlock()
 
 	response := &pb.NVMeNamespace{}
-	err := deepcopier.Copy(in.NvMeNamespace).To(response)
+	err := deepcopier.Copy(updated).To(response)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	return response, nil
 }
 
 // ListNVMeNamespaces lists NVMe namespaces
-func (s *Server) ListNVMeNamespaces(_ context.Context, in *pb.ListNVMeNamespacesRequest) (*pb.ListNVMeNamespacesResponse, error) {
-	log.Printf("ListNVMeNamespaces: Received from client: %v", in)
-	size, offset, perr := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+func (s *Server) ListNVMeNamespaces(ctx context.Context, in *pb.ListNVMeNamespacesRequest) (*pb.ListNVMeNamespacesResponse, error) {
+	logging.FromContext(ctx).Infof("ListNVMeNamespaces: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	size, offset, perr := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if perr != nil {
-		log.Printf("error: %v", perr)
+		logging.FromContext(ctx).Error(perr)
 		return nil, perr
 	}
 	nqn := ""
 	if in.Parent != "" {
+		s.mu.RLock()
 		subsys, ok := s.Nvme.Subsystems[in.Parent]
+		s.mu.RUnlock()
 		if !ok {
 			err := fmt.Errorf("unable to find subsystem %s", in.Parent)
-			log.Printf("error: %v", err)
+			logging.FromContext(ctx).Error(err)
 			return nil, err
 		}
 		nqn = subsys.Spec.Nqn
 	}
 	var result []spdk.NvmfGetSubsystemsResult
-	err := s.rpc.Call("nvmf_get_subsystems", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "nvmf_get_subsystems", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	token := ""
 	Blobarray := []*pb.NVMeNamespace{}
 	for i := range result {
 		rr := &result[i]
 		if rr.Nqn == nqn || nqn == "" {
-			log.Printf("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
+			logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
 			hasMoreElements := false
 			rr.Namespaces, hasMoreElements = server.LimitPagination(rr.Namespaces, offset, size)
 			if hasMoreElements {
 				token = uuid.New().String()
+				s.mu.Lock()
 				s.Pagination[token] = offset + size
+				s.mu.Unlock()
 			}
 			for j := range rr.Namespaces {
 				r := &rr.Namespaces[j]
@@ -504,37 +1023,41 @@ func (s *Server) ListNVMeNamespaces(_ context.Context, in *pb.ListNVMeNamespaces
 	}
 
 	msg := fmt.Sprintf("Could not find any namespaces for NQN: %s", nqn)
-	log.Print(msg)
+	logging.FromContext(ctx).Error(msg)
 	return nil, status.Errorf(codes.InvalidArgument, msg)
 }
 
 // GetNVMeNamespace gets an NVMe namespace
-func (s *Server) GetNVMeNamespace(_ context.Context, in *pb.GetNVMeNamespaceRequest) (*pb.NVMeNamespace, error) {
-	log.Printf("GetNVMeNamespace: Received from client: %v", in)
+func (s *Server) GetNVMeNamespace(ctx context.Context, in *pb.GetNVMeNamespaceRequest) (*pb.NVMeNamespace, error) {
+	logging.FromContext(ctx).Infof("GetNVMeNamespace: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
 	namespace, ok := s.Nvme.Namespaces[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	// TODO: do we even query SPDK to confirm if namespace is present?
 	// return namespace, nil
 
 	// fetch subsystems -> namespaces from Server, match the nsid to find the corresponding namespace
+	s.mu.RLock()
 	subsys, ok := s.Nvme.Subsystems[namespace.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
 	if !ok {
 		err := fmt.Errorf("unable to find subsystem %s", namespace.Spec.SubsystemId.Value)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
 	var result []spdk.NvmfGetSubsystemsResult
-	err := s.rpc.Call("nvmf_get_subsystems", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "nvmf_get_subsystems", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	for i := range result {
 		rr := &result[i]
 		if rr.Nqn == subsys.Spec.Nqn {
@@ -548,17 +1071,61 @@ func (s *Server) GetNVMeNamespace(_ context.Context, in *pb.GetNVMeNamespaceRequ
 				}
 			}
 			msg := fmt.Sprintf("Could not find NSID: %d", namespace.Spec.HostNsid)
-			log.Print(msg)
+			logging.FromContext(ctx).Error(msg)
 			return nil, status.Errorf(codes.InvalidArgument, msg)
 		}
 	}
 	msg := fmt.Sprintf("Could not find NQN: %s", subsys.Spec.Nqn)
-	log.Print(msg)
+	logging.FromContext(ctx).Error(msg)
 	return nil, status.Errorf(codes.InvalidArgument, msg)
 }
 
 // NVMeNamespaceStats gets an NVMe namespace stats
-func (s *Server) NVMeNamespaceStats(_ context.Context, in *pb.NVMeNamespaceStatsRequest) (*pb.NVMeNamespaceStatsResponse, error) {
-	log.Printf("NVMeNamespaceStats: Received from client: %v", in)
-	return &pb.NVMeNamespaceStatsResponse{Stats: &pb.VolumeStats{ReadOpsCount: -1, WriteOpsCount: -1}}, nil
+func (s *Server) NVMeNamespaceStats(ctx context.Context, in *pb.NVMeNamespaceStatsRequest) (*pb.NVMeNamespaceStatsResponse, error) {
+	logging.FromContext(ctx).Infof("NVMeNamespaceStats: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	namespace, ok := s.Nvme.Namespaces[in.NamespaceId.Value]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.NamespaceId.Value)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	stats, err := s.bdevStats(ctx, namespace.Spec.VolumeId.Value)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	return &pb.NVMeNamespaceStatsResponse{Stats: stats}, nil
+}
+
+// lockWithSubsystem locks the controller or namespace with id from table
+// together with the subsystem it belongs to
+func (s *Server) lockWithSubsystem(table string, id string) (unlock func()) {
+	for {
+		subsysID := s.subsystemOf(table, id)
+		unlock := s.locks.Lock(server.LockKey(table, id), server.LockKey(subsystemsTable, subsysID))
+		// the object could have been re-created for another subsystem
+		// before the lock was taken
+		if s.subsystemOf(table, id) == subsysID {
+			return unlock
+		}
+		unlock()
+	}
+}
+
+func (s *Server) subsystemOf(table string, id string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	switch table {
+	case controllersTable:
+		if controller, ok := s.Nvme.Controllers[id]; ok {
+			return controller.Spec.SubsystemId.Value
+		}
+	case namespacesTable:
+		if namespace, ok := s.Nvme.Namespaces[id]; ok {
+			return namespace.Spec.SubsystemId.Value
+		}
+	}
+	return ""
 }
@@ -17,9 +17,11 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
 )
 
 var (
@@ -192,20 +194,142 @@ func TestFrontEnd_CreateNVMeSubsystem(t *testing.T) {
 }
 
 func TestFrontEnd_UpdateNVMeSubsystem(t *testing.T) {
+	existing := &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{
+			Id:           &pc.ObjectKey{Value: "subsystem-test"},
+			Nqn:          "nqn.2022-09.io.spdk:opi3",
+			SerialNumber: "OpiSerialNumber",
+			ModelNumber:  "OpiModelNumber",
+		},
+		Status: &pb.NVMeSubsystemStatus{FirmwareRevision: "SPDK v20.10"},
+	}
+	updated := &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{
+			Id:            &pc.ObjectKey{Value: "subsystem-test"},
+			Nqn:           "nqn.2022-09.io.spdk:opi3",
+			SerialNumber:  "OpiSerialNumber",
+			ModelNumber:   "OpiModelNumber2",
+			MaxNamespaces: 32,
+		},
+		Status: &pb.NVMeSubsystemStatus{FirmwareRevision: "SPDK v20.10"},
+	}
+	spdkSubsystems := `{"id":%d,"error":{"code":0,"message":""},"result":[{"nqn":"nqn.2022-09.io.spdk:opi3",` +
+		`"allow_any_host":false,"hosts":[{"nqn":"nqn.2014-08.org.nvmexpress:uuid:host1"}],` +
+		`"listen_addresses":[{"trtype":"TCP","adrfam":"IPv4","traddr":"127.0.0.1","trsvcid":"4420"}],` +
+		`"namespaces":[{"nsid":22,"bdev_name":"Malloc1","nguid":"0123456789ABCDEF0123456789ABCDEF"}]}]}`
+	spdkTrue := `{"id":%d,"error":{"code":0,"message":""},"result":true}`
 	tests := map[string]struct {
 		in      *pb.NVMeSubsystem
+		mask    *fieldmaskpb.FieldMask
 		out     *pb.NVMeSubsystem
 		spdk    []string
 		errCode codes.Code
 		errMsg  string
 		start   bool
+		exist   bool
 	}{
-		"unimplemented method": {
-			&pb.NVMeSubsystem{},
+		"valid request with valid SPDK response": {
+			updated,
+			&fieldmaskpb.FieldMask{Paths: []string{"spec.model_number", "spec.max_namespaces"}},
+			updated,
+			[]string{spdkSubsystems, spdkTrue, spdkTrue, spdkTrue, `{"id":%d,"error":{"code":0,"message":""},"result":22}`, spdkTrue},
+			codes.OK,
+			"",
+			true,
+			true,
+		},
+		"unchanged fields": {
+			updated,
+			&fieldmaskpb.FieldMask{Paths: []string{"spec.serial_number"}},
+			existing,
+			[]string{},
+			codes.OK,
+			"",
+			false,
+			true,
+		},
+		"valid request with invalid SPDK response": {
+			updated,
 			nil,
-			[]string{""},
-			codes.Unimplemented,
-			fmt.Sprintf("%v method is not implemented", "UpdateNVMeSubsystem"),
+			nil,
+			[]string{spdkSubsystems, `{"id":%d,"error":{"code":0,"message":""},"result":false}`},
+			codes.InvalidArgument,
+			fmt.Sprintf("Could not delete NQN: %v", "nqn.2022-09.io.spdk:opi3"),
+			true,
+			true,
+		},
+		"old subsystem restored": {
+			updated,
+			nil,
+			nil,
+			[]string{spdkSubsystems, spdkTrue, `{"id":%d,"error":{"code":0,"message":""},"result":false}`,
+				`{"id":%d,"error":{"code":-32602,"message":"Invalid parameters"},"result":false}`,
+				spdkTrue, spdkTrue, `{"id":%d,"error":{"code":0,"message":""},"result":22}`, spdkTrue},
+			codes.InvalidArgument,
+			fmt.Sprintf("Could not create NQN: %v", "nqn.2022-09.io.spdk:opi3"),
+			true,
+			true,
+		},
+		"old subsystem lost": {
+			updated,
+			nil,
+			nil,
+			[]string{spdkSubsystems, spdkTrue, `{"id":%d,"error":{"code":0,"message":""},"result":false}`,
+				spdkTrue, `{"id":%d,"error":{"code":0,"message":""},"result":false}`},
+			codes.Internal,
+			"subsystem subsystem-test is lost: rpc error: code = InvalidArgument desc = Could not create NQN: nqn.2022-09.io.spdk:opi3, " +
+				"cannot restore it: rpc error: code = InvalidArgument desc = Could not create NQN: nqn.2022-09.io.spdk:opi3",
+			true,
+			true,
+		},
+		"max namespaces less than NSID": {
+			&pb.NVMeSubsystem{Spec: &pb.NVMeSubsystemSpec{Id: existing.Spec.Id, MaxNamespaces: 16}},
+			&fieldmaskpb.FieldMask{Paths: []string{"spec.max_namespaces"}},
+			nil,
+			[]string{spdkSubsystems},
+			codes.InvalidArgument,
+			"max_namespaces 16 is less than NSID 22 of existing namespace",
+			true,
+			true,
+		},
+		"subsystem missing in SPDK": {
+			updated,
+			nil,
+			nil,
+			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[]}`},
+			codes.FailedPrecondition,
+			fmt.Sprintf("Could not find NQN: %v", "nqn.2022-09.io.spdk:opi3"),
+			true,
+			true,
+		},
+		"nqn cannot be changed": {
+			&pb.NVMeSubsystem{Spec: &pb.NVMeSubsystemSpec{Id: existing.Spec.Id, Nqn: "nqn.2022-09.io.spdk:opi4"}},
+			nil,
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			fmt.Sprintf("NQN of subsystem %v cannot be changed", "subsystem-test"),
+			false,
+			true,
+		},
+		"immutable field in mask": {
+			updated,
+			&fieldmaskpb.FieldMask{Paths: []string{"spec.nqn"}},
+			nil,
+			[]string{},
+			codes.InvalidArgument,
+			`field "spec.nqn" cannot be updated, updatable fields: spec.serial_number, spec.model_number, spec.max_namespaces`,
+			false,
+			true,
+		},
+		"unknown key": {
+			updated,
+			nil,
+			nil,
+			[]string{},
+			codes.NotFound,
+			fmt.Sprintf("unable to find key %v", "subsystem-test"),
+			false,
 			false,
 		},
 	}
@@ -216,30 +340,28 @@ func TestFrontEnd_UpdateNVMeSubsystem(t *testing.T) {
 			testEnv := createTestEnvironment(tt.start, tt.spdk)
 			defer testEnv.Close()
 
-			request := &pb.UpdateNVMeSubsystemRequest{NvMeSubsystem: tt.in}
-			response, err := testEnv.client.UpdateNVMeSubsystem(testEnv.ctx, request)
-			if response != nil {
-				// Marshall the request and response, so we can just compare the contained data
-				mtt, _ := proto.Marshal(tt.out.Spec)
-				mResponse, _ := proto.Marshal(response.Spec)
+			if tt.exist {
+				testEnv.opiSpdkServer.Nvme.Subsystems[existing.Spec.Id.Value] = proto.Clone(existing).(*pb.NVMeSubsystem)
+			}
 
-				// Compare the marshalled messages
-				if !bytes.Equal(mtt, mResponse) {
-					t.Error("response: expected", tt.out.GetSpec(), "received", response.GetSpec())
-				}
-				if !reflect.DeepEqual(response.Status, tt.out.Status) {
-					t.Error("response: expected", tt.out.GetStatus(), "received", response.GetStatus())
-				}
+			request := &pb.UpdateNVMeSubsystemRequest{NvMeSubsystem: tt.in, UpdateMask: tt.mask}
+			response, err := testEnv.client.UpdateNVMeSubsystem(testEnv.ctx, request)
+			if !proto.Equal(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if tt.out != nil && !proto.Equal(testEnv.opiSpdkServer.Nvme.Subsystems[existing.Spec.Id.Value], tt.out) {
+				t.Error("expected subsystem to be updated to", tt.out)
+			}
+			if tt.out == nil && tt.exist && !proto.Equal(testEnv.opiSpdkServer.Nvme.Subsystems[existing.Spec.Id.Value], existing) {
+				t.Error("expected subsystem to be kept as", existing)
 			}
 
-			if err != nil {
-				if er, ok := status.FromError(err); ok {
-					if er.Code() != tt.errCode {
-						t.Error("error code: expected", codes.InvalidArgument, "received", er.Code())
-					}
-					if er.Message() != tt.errMsg {
-						t.Error("error message: expected", tt.errMsg, "received", er.Message())
-					}
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
 				}
 			}
 		})
@@ -528,7 +650,7 @@ func TestFrontEnd_NVMeSubsystemStats(t *testing.T) {
 			nil,
 			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":[]}`},
 			codes.Unknown,
-			fmt.Sprintf("nvmf_get_stats: %v", "json: cannot unmarshal array into Go value of type spdk.NvmfGetSubsystemStatsResult"),
+			fmt.Sprintf("nvmf_get_stats: %v", "json: cannot unmarshal array into Go value of type frontend.nvmfGetStatsResult"),
 			true,
 		},
 		"valid request with empty SPDK response": {
@@ -558,14 +680,26 @@ func TestFrontEnd_NVMeSubsystemStats(t *testing.T) {
 		"valid request with valid SPDK response": {
 			"subsystem-test",
 			&pb.VolumeStats{
-				ReadOpsCount:  -1,
-				WriteOpsCount: -1,
+				ReadOpsCount: 1532,
+			},
+			[]string{
+				`{"jsonrpc":"2.0","id":%d,"result":{"tick_rate":2490000000,"poll_groups":[` +
+					`{"name":"nvmf_tgt_poll_group_000","admin_qpairs":1,"io_qpairs":2,"current_admin_qpairs":1,"current_io_qpairs":2,"pending_bdev_io":0,"completed_nvme_io":1532,"transports":[{"trtype":"TCP"},{"trtype":"VFIOUSER"}]},` +
+					`{"name":"nvmf_tgt_poll_group_001","admin_qpairs":0,"io_qpairs":1,"current_admin_qpairs":0,"current_io_qpairs":1,"pending_bdev_io":0,"completed_nvme_io":99,"transports":[{"trtype":"TCP"},{"trtype":"VFIOUSER"}]}]}}`,
+				`{"jsonrpc":"2.0","id":%d,"result":[{"cntlid":1,"qid":0,"state":"active","thread":"nvmf_tgt_poll_group_000","listen_address":{"trtype":"TCP","adrfam":"IPv4","traddr":"127.0.0.1","trsvcid":"4420"}}]}`,
 			},
-			[]string{`{"jsonrpc":"2.0","id":%d,"result":{"tick_rate":2490000000,"poll_groups":[{"name":"nvmf_tgt_poll_group_0","admin_qpairs":0,"io_qpairs":0,"current_admin_qpairs":0,"current_io_qpairs":0,"pending_bdev_io":0,"transports":[{"trtype":"TCP"},{"trtype":"VFIOUSER"}]}]}}`},
 			codes.OK,
 			"",
 			true,
 		},
+		"valid request with unknown key": {
+			"unknown-id",
+			nil,
+			[]string{""},
+			codes.NotFound,
+			fmt.Sprintf("unable to find key %v", "unknown-id"),
+			false,
+		},
 	}
 
 	// run tests
@@ -961,15 +1095,12 @@ func TestFrontEnd_NVMeControllerStats(t *testing.T) {
 		errMsg  string
 		start   bool
 	}{
-		"valid request with valid SPDK response": {
-			"subsystem-test",
-			&pb.VolumeStats{
-				ReadOpsCount:  -1,
-				WriteOpsCount: -1,
-			},
+		"valid request with unknown key": {
+			"unknown-id",
+			nil,
 			[]string{""},
-			codes.OK,
-			"",
+			codes.NotFound,
+			fmt.Sprintf("unable to find key %v", "unknown-id"),
 			false,
 		},
 	}
@@ -1531,15 +1662,12 @@ func TestFrontEnd_NVMeNamespaceStats(t *testing.T) {
 		errMsg  string
 		start   bool
 	}{
-		"valid request with valid SPDK response": {
-			"subsystem-test",
-			&pb.VolumeStats{
-				ReadOpsCount:  -1,
-				WriteOpsCount: -1,
-			},
+		"valid request with unknown key": {
+			"unknown-id",
+			nil,
 			[]string{""},
-			codes.OK,
-			"",
+			codes.NotFound,
+			fmt.Sprintf("unable to find key %v", "unknown-id"),
 			false,
 		},
 	}
@@ -1939,3 +2067,116 @@ func TestFrontEnd_NewTcpSubsystemListener(t *testing.T) {
 		})
 	}
 }
+
+func TestFrontEnd_NewRdmaSubsystemListener(t *testing.T) {
+	tests := map[string]struct {
+		listenAddress string
+		wantPanic     bool
+		protocol      string
+	}{
+		"ipv4 valid address": {
+			listenAddress: "10.10.10.10:12345",
+			wantPanic:     false,
+			protocol:      ipv4NvmeTCPProtocol,
+		},
+		"valid ipv6 addresses": {
+			listenAddress: "[2002:0db0:8833:0000:0000:8a8a:0330:7337]:54321",
+			wantPanic:     false,
+			protocol:      ipv6NvmeTCPProtocol,
+		},
+		"empty string as listen address": {
+			listenAddress: "",
+			wantPanic:     true,
+			protocol:      "",
+		},
+		"missing port": {
+			listenAddress: "10.10.10.10",
+			wantPanic:     true,
+			protocol:      "",
+		},
+		"valid port invalid ip": {
+			listenAddress: "wrong:12345",
+			wantPanic:     true,
+			protocol:      "",
+		},
+		"meaningless listen address": {
+			listenAddress: "some string which is not ip address",
+			wantPanic:     true,
+			protocol:      "",
+		},
+	}
+
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if (r != nil) != tt.wantPanic {
+					t.Errorf("NewRDMASubsystemListener() recover = %v, wantPanic = %v", r, tt.wantPanic)
+				}
+			}()
+
+			gotSubsysListener := NewRDMASubsystemListener(tt.listenAddress)
+			host, port, _ := net.SplitHostPort(tt.listenAddress)
+			wantSubsysListener := &rdmaSubsystemListener{
+				listenAddr: net.ParseIP(host),
+				listenPort: port,
+				protocol:   tt.protocol,
+			}
+
+			if !reflect.DeepEqual(gotSubsysListener, wantSubsysListener) {
+				t.Errorf("Expect %v subsystem listener, received %v", wantSubsysListener, gotSubsysListener)
+			}
+		})
+	}
+}
+
+func TestFrontEnd_NewSecureTcpSubsystemListener(t *testing.T) {
+	listener := NewSecureTCPSubsystemListener("10.10.10.10:12345")
+	s := NewServerWithSubsystemListener(server.CreateTestSpdkStub(map[string]string{}), listener)
+	controller := &pb.NVMeController{Spec: &pb.NVMeControllerSpec{Id: &pc.ObjectKey{Value: "controller-test"}}}
+
+	params, err := s.listenerParams(controller, s.controllerTransport("controller-test"), "nqn.2022-09.io.spdk:opi3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !params.SecureChannel {
+		t.Error("expected listener to require a secure channel")
+	}
+	if params.ListenAddress.Trtype != "tcp" || params.ListenAddress.Traddr != "10.10.10.10" || params.ListenAddress.Trsvcid != "12345" {
+		t.Error("expected tcp listen address 10.10.10.10:12345, received", params.ListenAddress)
+	}
+
+	s = NewServerWithSubsystemListener(server.CreateTestSpdkStub(map[string]string{}),
+		NewTCPSubsystemListener("10.10.10.10:12345"))
+	if params, _ := s.listenerParams(controller, s.controllerTransport("controller-test"), "nqn.2022-09.io.spdk:opi3"); params.SecureChannel {
+		t.Error("expected plain listener to accept connections without TLS")
+	}
+}
+
+func TestFrontEnd_RdmaSubsystemListenerParams(t *testing.T) {
+	tests := map[string]struct {
+		listenAddress string
+		adrfam        string
+		traddr        string
+	}{
+		"ipv4": {"10.10.10.10:4420", ipv4NvmeTCPProtocol, "10.10.10.10"},
+		"ipv6": {"[2002:db0:8833::8a8a:330:7337]:4420", ipv6NvmeTCPProtocol, "2002:db0:8833::8a8a:330:7337"},
+	}
+	for testName, tt := range tests {
+		t.Run(testName, func(t *testing.T) {
+			params := NewRDMASubsystemListener(tt.listenAddress).Params(nil, "nqn.2022-09.io.spdk:opi3")
+			if params.Nqn != "nqn.2022-09.io.spdk:opi3" {
+				t.Error("expected nqn nqn.2022-09.io.spdk:opi3, received", params.Nqn)
+			}
+			if params.ListenAddress.Trtype != "rdma" {
+				t.Error("expected trtype rdma, received", params.ListenAddress.Trtype)
+			}
+			if params.ListenAddress.Adrfam != tt.adrfam || params.ListenAddress.Traddr != tt.traddr {
+				t.Error("expected", tt.adrfam, tt.traddr, "received", params.ListenAddress.Adrfam, params.ListenAddress.Traddr)
+			}
+			if params.ListenAddress.Trsvcid != "4420" {
+				t.Error("expected trsvcid 4420, received", params.ListenAddress.Trsvcid)
+			}
+		})
+	}
+}
@@ -0,0 +1,392 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/opiproject/gospdk/spdk"
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+)
+
+const discoveryNqn = "nqn.2014-08.org.nvmexpress.discovery"
+
+// Reconcile compares NVMe subsystems, namespaces, controllers and virtio-blk
+// controllers known to the server with the objects present in SPDK and
+// handles the differences according to the policy.
+// Only SPDK objects are re-created, devices plugged into a VM are not.
+func (s *Server) Reconcile(policy server.ReconcilePolicy) ([]server.Drift, error) {
+	// no object can be changed by other operations during reconciliation
+	unlock := s.locks.LockAll()
+	defer unlock()
+
+	var subsystems []nvmfSubsystem
+	// reconciliation is not a part of any request, so SPDK calls start new traces
+	err := tracing.Call(context.Background(), s.rpc, "nvmf_get_subsystems", nil, &subsystems)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return nil, err
+	}
+	log.Printf("Received from SPDK: %v", subsystems)
+	var ctrlrs []vhostController
+	err = tracing.Call(context.Background(), s.rpc, "vhost_get_controllers", nil, &ctrlrs)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return nil, err
+	}
+	log.Printf("Received from SPDK: %v", ctrlrs)
+
+	drifts := s.reconcileSubsystems(policy, subsystems)
+	drifts = append(drifts, s.reconcileDiscoveryListeners(policy, subsystems)...)
+	referralDrifts, err := s.reconcileDiscoveryReferrals(policy)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return nil, err
+	}
+	drifts = append(drifts, referralDrifts...)
+	drifts = append(drifts, s.reconcileVirtioBlks(policy, ctrlrs)...)
+	return drifts, nil
+}
+
+// reconcileDiscoveryListeners re-adds discovery listeners missing in SPDK.
+// Other listeners of the discovery subsystem are not adopted since SPDK
+// may be configured with them.
+func (s *Server) reconcileDiscoveryListeners(policy server.ReconcilePolicy, subsystems []nvmfSubsystem) []server.Drift {
+	discovery := &nvmfSubsystem{Nqn: discoveryNqn}
+	for i := range subsystems {
+		if subsystems[i].Nqn == discoveryNqn {
+			discovery = &subsystems[i]
+		}
+	}
+	var drifts []server.Drift
+	for _, name := range server.SortedKeys(s.Nvme.DiscoveryListeners) {
+		params, err := s.discoveryListenerParams(s.Nvme.DiscoveryListeners[name])
+		if err == nil && hasListener(discovery, &params.NvmfSubsystemAddListenerParams) {
+			continue
+		}
+		drifts = append(drifts, server.ResolveDrift(
+			server.Drift{Object: "NVMeDiscoveryListener", ID: name, Kind: server.DriftMissingInSpdk},
+			policy, func() error {
+				if err != nil {
+					return err
+				}
+				return s.recreateListener(&params)
+			}))
+	}
+	return drifts
+}
+
+// reconcileDiscoveryReferrals re-adds discovery referrals missing in SPDK.
+// SPDK is asked for referrals only if the bridge added any.
+func (s *Server) reconcileDiscoveryReferrals(policy server.ReconcilePolicy) ([]server.Drift, error) {
+	if len(s.Nvme.DiscoveryReferrals) == 0 {
+		return nil, nil
+	}
+	var referrals []nvmfDiscoveryReferral
+	err := tracing.Call(context.Background(), s.rpc, "nvmf_discovery_get_referrals", nil, &referrals)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Received from SPDK: %v", referrals)
+	var drifts []server.Drift
+	for _, name := range server.SortedKeys(s.Nvme.DiscoveryReferrals) {
+		referral := s.Nvme.DiscoveryReferrals[name]
+		if hasReferral(referrals, referral) {
+			continue
+		}
+		drifts = append(drifts, server.ResolveDrift(
+			server.Drift{Object: "NVMeDiscoveryReferral", ID: name, Kind: server.DriftMissingInSpdk},
+			policy, func() error { return s.addReferral(context.Background(), referral) }))
+	}
+	return drifts, nil
+}
+
+func (s *Server) reconcileSubsystems(policy server.ReconcilePolicy, subsystems []nvmfSubsystem) []server.Drift {
+	present := make(map[string]*nvmfSubsystem, len(subsystems))
+	for i := range subsystems {
+		if subsystems[i].Nqn != discoveryNqn {
+			present[subsystems[i].Nqn] = &subsystems[i]
+		}
+	}
+
+	var drifts []server.Drift
+	known := make(map[string]bool, len(s.Nvme.Subsystems))
+	for _, id := range server.SortedKeys(s.Nvme.Subsystems) {
+		subsys := s.Nvme.Subsystems[id]
+		known[subsys.Spec.Nqn] = true
+		spdkSubsys, ok := present[subsys.Spec.Nqn]
+		if !ok {
+			drift := server.ResolveDrift(
+				server.Drift{Object: "NVMeSubsystem", ID: id, Kind: server.DriftMissingInSpdk},
+				policy, func() error { return s.recreateSubsystem(subsys) })
+			drifts = append(drifts, drift)
+			if !drift.Resolved() {
+				continue
+			}
+			// re-created subsystem has neither namespaces nor listeners yet
+			spdkSubsys = &nvmfSubsystem{Nqn: subsys.Spec.Nqn}
+		}
+		drifts = append(drifts, s.reconcileNamespaces(policy, id, spdkSubsys)...)
+		drifts = append(drifts, s.reconcileControllers(policy, id, spdkSubsys)...)
+	}
+
+	for _, nqn := range server.SortedKeys(present) {
+		if known[nqn] {
+			continue
+		}
+		spdkSubsys := present[nqn]
+		drift := server.ResolveDrift(
+			server.Drift{Object: "NVMeSubsystem", ID: nqn, Kind: server.DriftUnknownToBridge},
+			policy, func() error { return s.adoptSubsystem(spdkSubsys) })
+		drifts = append(drifts, drift)
+		if drift.Resolved() {
+			drifts = append(drifts, s.reconcileNamespaces(policy, nqn, spdkSubsys)...)
+		}
+	}
+	return drifts
+}
+
+func (s *Server) reconcileNamespaces(policy server.ReconcilePolicy, subsysID string, spdkSubsys *nvmfSubsystem) []server.Drift {
+	var drifts []server.Drift
+	known := make(map[int]bool, len(spdkSubsys.Namespaces))
+	for _, id := range server.SortedKeys(s.Nvme.Namespaces) {
+		namespace := s.Nvme.Namespaces[id]
+		if namespace.Spec.SubsystemId.Value != subsysID {
+			continue
+		}
+		if spdkNs := findNamespace(spdkSubsys, namespace); spdkNs != nil {
+			known[spdkNs.Nsid] = true
+			continue
+		}
+		drifts = append(drifts, server.ResolveDrift(
+			server.Drift{Object: "NVMeNamespace", ID: id, Kind: server.DriftMissingInSpdk},
+			policy, func() error { return s.recreateNamespace(spdkSubsys.Nqn, namespace) }))
+	}
+
+	for i := range spdkSubsys.Namespaces {
+		spdkNs := &spdkSubsys.Namespaces[i]
+		if known[spdkNs.Nsid] {
+			continue
+		}
+		id := fmt.Sprintf("%v-ns%d", subsysID, spdkNs.Nsid)
+		drifts = append(drifts, server.ResolveDrift(
+			server.Drift{Object: "NVMeNamespace", ID: id, Kind: server.DriftUnknownToBridge},
+			policy, func() error { return s.adoptNamespace(id, subsysID, spdkNs) }))
+	}
+	return drifts
+}
+
+func (s *Server) reconcileControllers(policy server.ReconcilePolicy, subsysID string, spdkSubsys *nvmfSubsystem) []server.Drift {
+	var drifts []server.Drift
+	for _, id := range server.SortedKeys(s.Nvme.Controllers) {
+		controller := s.Nvme.Controllers[id]
+		if controller.Spec.SubsystemId.Value != subsysID {
+			continue
+		}
+		params, err := s.listenerParams(controller, s.controllerTransport(id), spdkSubsys.Nqn)
+		if err == nil && hasListener(spdkSubsys, &params.NvmfSubsystemAddListenerParams) {
+			continue
+		}
+		drifts = append(drifts, server.ResolveDrift(
+			server.Drift{Object: "NVMeController", ID: id, Kind: server.DriftMissingInSpdk},
+			policy, func() error {
+				if err != nil {
+					return err
+				}
+				if err := s.recreateListener(&params); err != nil {
+					return err
+				}
+				return s.restoreAnaState(context.Background(), id, &params)
+			}))
+	}
+	return drifts
+}
+
+func (s *Server) reconcileVirtioBlks(policy server.ReconcilePolicy, ctrlrs []vhostController) []server.Drift {
+	present := make(map[string]*vhostController, len(ctrlrs))
+	for i := range ctrlrs {
+		if ctrlrs[i].BackendSpecific.Block != nil {
+			present[ctrlrs[i].Ctrlr] = &ctrlrs[i]
+		}
+	}
+
+	return server.ReconcileObjects("VirtioBlk", policy, s.Virt.BlkCtrls, present,
+		s.recreateVirtioBlk, s.adoptVirtioBlk)
+}
+
+func findNamespace(spdkSubsys *nvmfSubsystem, namespace *pb.NVMeNamespace) *nvmfNamespace {
+	for i := range spdkSubsys.Namespaces {
+		spdkNs := &spdkSubsys.Namespaces[i]
+		if namespace.Spec.HostNsid != 0 && spdkNs.Nsid == int(namespace.Spec.HostNsid) {
+			return spdkNs
+		}
+		if namespace.Spec.HostNsid == 0 && spdkNs.BdevName == namespace.Spec.VolumeId.Value {
+			return spdkNs
+		}
+	}
+	return nil
+}
+
+func hasListener(spdkSubsys *nvmfSubsystem, params *spdk.NvmfSubsystemAddListenerParams) bool {
+	for _, addr := range spdkSubsys.ListenAddresses {
+		if strings.EqualFold(addr.Trtype, params.ListenAddress.Trtype) &&
+			addr.Traddr == params.ListenAddress.Traddr &&
+			addr.Trsvcid == params.ListenAddress.Trsvcid {
+			return true
+		}
+	}
+	return false
+}
+
+func hasReferral(referrals []nvmfDiscoveryReferral, referral *NVMeDiscoveryReferral) bool {
+	for _, r := range referrals {
+		if strings.EqualFold(r.Address.Trtype, referral.Transport) &&
+			sameTraddr(r.Address.Traddr, referral.Traddr) &&
+			(referral.Trsvcid == "" || r.Address.Trsvcid == referral.Trsvcid) &&
+			(referral.Subnqn == "" || r.Subnqn == referral.Subnqn) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) recreateSubsystem(subsys *pb.NVMeSubsystem) error {
+	hosts := s.subsystemHosts(subsys.Spec.Id.Value)
+	params := nvmfCreateSubsystemParams{
+		NvmfCreateSubsystemParams: spdk.NvmfCreateSubsystemParams{
+			Nqn:           subsys.Spec.Nqn,
+			SerialNumber:  subsys.Spec.SerialNumber,
+			ModelNumber:   subsys.Spec.ModelNumber,
+			AllowAnyHost:  hosts.AllowAnyHost,
+			MaxNamespaces: int(subsys.Spec.MaxNamespaces),
+		},
+		AnaReporting: s.subsystemAna(subsys.Spec.Id.Value).Reporting,
+	}
+	var result spdk.NvmfCreateSubsystemResult
+	err := tracing.Call(context.Background(), s.rpc, "nvmf_create_subsystem", &params, &result)
+	if err != nil {
+		return err
+	}
+	log.Printf("Received from SPDK: %v", result)
+	if !result {
+		return fmt.Errorf("could not create NQN: %s", subsys.Spec.Nqn)
+	}
+	// the access control list is restored before namespaces are added
+	for _, host := range hosts.Hosts {
+		if err := s.addHost(context.Background(), subsys.Spec.Nqn, host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) recreateNamespace(nqn string, namespace *pb.NVMeNamespace) error {
+	params := nvmfSubsystemAddNsParams{
+		Nqn: nqn,
+	}
+	params.Namespace.Nsid = int(namespace.Spec.HostNsid)
+	params.Namespace.BdevName = namespace.Spec.VolumeId.Value
+	params.Namespace.PtplFile = s.activeReservation(namespace.Spec.Id.Value).PtplFile
+	params.Namespace.NoAutoVisible = s.visibility(namespace.Spec.Id.Value).Hidden
+	if err := setIdentity(&params.Namespace, namespace.Spec); err != nil {
+		return err
+	}
+	var result spdk.NvmfSubsystemAddNsResult
+	err := tracing.Call(context.Background(), s.rpc, "nvmf_subsystem_add_ns", &params, &result)
+	if err != nil {
+		return err
+	}
+	log.Printf("Received from SPDK: %v", result)
+	if result < 0 {
+		return fmt.Errorf("could not create NS: %s", namespace.Spec.Id.Value)
+	}
+	return s.addVisibleHosts(context.Background(), namespace.Spec.Id.Value, nqn, int(result))
+}
+
+func (s *Server) recreateListener(params *nvmfSubsystemAddListenerParams) error {
+	var result spdk.NvmfSubsystemAddListenerResult
+	err := tracing.Call(context.Background(), s.rpc, "nvmf_subsystem_add_listener", params, &result)
+	if err != nil {
+		return err
+	}
+	log.Printf("Received from SPDK: %v", result)
+	if !result {
+		return fmt.Errorf("could not add listener for NQN: %s", params.Nqn)
+	}
+	return nil
+}
+
+func (s *Server) recreateVirtioBlk(blk *pb.VirtioBlk) error {
+	options := s.activeBlkOptions(blk.Id.Value)
+	return s.createVhostBlk(context.Background(), blk, &options)
+}
+
+func (s *Server) adoptSubsystem(spdkSubsys *nvmfSubsystem) error {
+	subsys := &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{
+			Id:            &pc.ObjectKey{Value: spdkSubsys.Nqn},
+			Nqn:           spdkSubsys.Nqn,
+			SerialNumber:  spdkSubsys.SerialNumber,
+			ModelNumber:   spdkSubsys.ModelNumber,
+			MaxNamespaces: int64(spdkSubsys.MaxNamespaces),
+		},
+		Status: &pb.NVMeSubsystemStatus{},
+	}
+	hosts := &NVMeSubsystemHosts{AllowAnyHost: spdkSubsys.AllowAnyHost, Hosts: []NVMeHost{}}
+	for _, host := range spdkSubsys.Hosts {
+		hosts.Hosts = append(hosts.Hosts, hostFromSpdk(host))
+	}
+	if err := store.SetJSON(s.store, subsystemHostsTable, subsys.Spec.Id.Value, hosts); err != nil {
+		return err
+	}
+	if err := s.store.Set(subsystemsTable, subsys.Spec.Id.Value, subsys); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.Nvme.Subsystems[subsys.Spec.Id.Value] = subsys
+	s.Nvme.SubsystemHosts[subsys.Spec.Id.Value] = hosts
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Server) adoptNamespace(id string, subsysID string, spdkNs *nvmfNamespace) error {
+	namespace := &pb.NVMeNamespace{
+		Spec: &pb.NVMeNamespaceSpec{
+			Id:          &pc.ObjectKey{Value: id},
+			SubsystemId: &pc.ObjectKey{Value: subsysID},
+			HostNsid:    int32(spdkNs.Nsid),
+			VolumeId:    &pc.ObjectKey{Value: spdkNs.BdevName},
+		},
+		Status: &pb.NVMeNamespaceStatus{PciState: 2, PciOperState: 1},
+	}
+	if err := s.store.Set(namespacesTable, id, namespace); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.Nvme.Namespaces[id] = namespace
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *Server) adoptVirtioBlk(id string, ctrlr *vhostController) error {
+	blk := &pb.VirtioBlk{
+		Id:       &pc.ObjectKey{Value: id},
+		VolumeId: &pc.ObjectKey{Value: ctrlr.BackendSpecific.Block.Bdev},
+	}
+	if err := s.store.Set(blkCtrlsTable, id, blk); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.Virt.BlkCtrls[id] = blk
+	s.mu.Unlock()
+	return nil
+}
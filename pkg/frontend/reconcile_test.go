@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"reflect"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+func TestFrontEnd_Reconcile(t *testing.T) {
+	namespace := &pb.NVMeNamespace{
+		Spec: &pb.NVMeNamespaceSpec{
+			Id:          testNamespace.Spec.Id,
+			HostNsid:    testNamespace.Spec.HostNsid,
+			SubsystemId: testSubsystem.Spec.Id,
+			VolumeId:    &pc.ObjectKey{Value: "Malloc1"},
+		},
+	}
+	inSync := `{"id":%d,"error":{"code":0,"message":""},"result":[` +
+		`{"nqn":"nqn.2014-08.org.nvmexpress.discovery","subtype":"Discovery","listen_addresses":[],"namespaces":[]},` +
+		`{"nqn":"nqn.2022-09.io.spdk:opi3","subtype":"NVMe","listen_addresses":[{"trtype":"TCP","adrfam":"IPv4","traddr":"127.0.0.1","trsvcid":"4420"}],` +
+		`"namespaces":[{"nsid":22,"bdev_name":"Malloc1"}]}]}`
+	noSubsystems := `{"id":%d,"error":{"code":0,"message":""},"result":[]}`
+	blkInSync := `{"id":%d,"error":{"code":0,"message":""},"result":[{"ctrlr":"virtio-blk-42","cpumask":"0x1","backend_specific":{"block":{"readonly":false,"bdev":"Malloc42"}}}]}`
+	noBlks := `{"id":%d,"error":{"code":0,"message":""},"result":[]}`
+	ok := `{"id":%d,"error":{"code":0,"message":""},"result":true}`
+
+	tests := map[string]struct {
+		policy  server.ReconcilePolicy
+		spdk    []string
+		known   bool
+		drifts  []server.Drift
+		errMsg  string
+		adopted bool
+	}{
+		"in sync": {
+			server.ReconcilePolicy{Recreate: true, Adopt: true},
+			[]string{inSync, blkInSync},
+			true,
+			nil,
+			"",
+			false,
+		},
+		"missing objects reported": {
+			server.ReconcilePolicy{},
+			[]string{noSubsystems, noBlks},
+			true,
+			[]server.Drift{
+				{Object: "NVMeSubsystem", ID: "subsystem-test", Kind: server.DriftMissingInSpdk, Resolution: "reported"},
+				{Object: "VirtioBlk", ID: "virtio-blk-42", Kind: server.DriftMissingInSpdk, Resolution: "reported"},
+			},
+			"",
+			false,
+		},
+		"missing objects recreated": {
+			server.ReconcilePolicy{Recreate: true},
+			[]string{noSubsystems, noBlks, ok, `{"id":%d,"error":{"code":0,"message":""},"result":22}`, ok, ok},
+			true,
+			[]server.Drift{
+				{Object: "NVMeSubsystem", ID: "subsystem-test", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+				{Object: "NVMeNamespace", ID: "namespace-test", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+				{Object: "NVMeController", ID: "controller-test", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+				{Object: "VirtioBlk", ID: "virtio-blk-42", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+			},
+			"",
+			false,
+		},
+		"missing subsystem failed to recreate": {
+			server.ReconcilePolicy{Recreate: true},
+			[]string{noSubsystems, blkInSync, `{"id":%d,"error":{"code":0,"message":""},"result":false}`},
+			true,
+			[]server.Drift{
+				{Object: "NVMeSubsystem", ID: "subsystem-test", Kind: server.DriftMissingInSpdk,
+					Resolution: "failed to recreate: could not create NQN: nqn.2022-09.io.spdk:opi3"},
+			},
+			"",
+			false,
+		},
+		"unknown objects reported": {
+			server.ReconcilePolicy{Recreate: true},
+			[]string{inSync, blkInSync},
+			false,
+			[]server.Drift{
+				{Object: "NVMeSubsystem", ID: "nqn.2022-09.io.spdk:opi3", Kind: server.DriftUnknownToBridge, Resolution: "reported"},
+				{Object: "VirtioBlk", ID: "virtio-blk-42", Kind: server.DriftUnknownToBridge, Resolution: "reported"},
+			},
+			"",
+			false,
+		},
+		"unknown objects adopted": {
+			server.ReconcilePolicy{Adopt: true},
+			[]string{inSync, blkInSync},
+			false,
+			[]server.Drift{
+				{Object: "NVMeSubsystem", ID: "nqn.2022-09.io.spdk:opi3", Kind: server.DriftUnknownToBridge, Resolution: "adopted"},
+				{Object: "NVMeNamespace", ID: "nqn.2022-09.io.spdk:opi3-ns22", Kind: server.DriftUnknownToBridge, Resolution: "adopted"},
+				{Object: "VirtioBlk", ID: "virtio-blk-42", Kind: server.DriftUnknownToBridge, Resolution: "adopted"},
+			},
+			"",
+			true,
+		},
+		"error from SPDK": {
+			server.ReconcilePolicy{},
+			[]string{`{"id":%d,"error":{"code":1,"message":"myopierr"},"result":[]}`},
+			true,
+			nil,
+			"nvmf_get_subsystems: json response error: myopierr",
+			false,
+		},
+	}
+
+	// run tests
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+
+			if tt.known {
+				testEnv.opiSpdkServer.Nvme.Subsystems[testSubsystem.Spec.Id.Value] = &testSubsystem
+				testEnv.opiSpdkServer.Nvme.Controllers[testController.Spec.Id.Value] = &testController
+				testEnv.opiSpdkServer.Nvme.Namespaces[namespace.Spec.Id.Value] = namespace
+				testEnv.opiSpdkServer.Virt.BlkCtrls[testVirtioCtrl.Id.Value] = &testVirtioCtrl
+			}
+
+			drifts, err := testEnv.opiSpdkServer.Reconcile(tt.policy)
+			if !reflect.DeepEqual(drifts, tt.drifts) {
+				t.Error("drifts: expected", tt.drifts, "received", drifts)
+			}
+			if err != nil && err.Error() != tt.errMsg {
+				t.Error("error: expected", tt.errMsg, "received", err)
+			}
+			if err == nil && tt.errMsg != "" {
+				t.Error("expected error", tt.errMsg)
+			}
+
+			if tt.adopted {
+				subsys := testEnv.opiSpdkServer.Nvme.Subsystems["nqn.2022-09.io.spdk:opi3"]
+				if subsys == nil || subsys.Spec.Nqn != "nqn.2022-09.io.spdk:opi3" {
+					t.Error("expected adopted subsystem, received", testEnv.opiSpdkServer.Nvme.Subsystems)
+				}
+				ns := testEnv.opiSpdkServer.Nvme.Namespaces["nqn.2022-09.io.spdk:opi3-ns22"]
+				if ns == nil || ns.Spec.VolumeId.Value != "Malloc1" || ns.Spec.HostNsid != 22 {
+					t.Error("expected adopted namespace, received", testEnv.opiSpdkServer.Nvme.Namespaces)
+				}
+				blk := testEnv.opiSpdkServer.Virt.BlkCtrls["virtio-blk-42"]
+				if blk == nil || blk.VolumeId.Value != "Malloc42" {
+					t.Error("expected adopted virtio-blk, received", testEnv.opiSpdkServer.Virt.BlkCtrls)
+				}
+				entries, _ := testEnv.opiSpdkServer.store.Entries(subsystemsTable)
+				if len(entries) != 1 {
+					t.Error("expected adopted subsystem to be persisted, received", entries)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/opiproject/gospdk/spdk"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NVMeNamespaceReservation configures persistent reservations of an NVMe
+// namespace. SPDK namespaces always support reservations, they are kept
+// only in memory unless PtplFile is set. It is used by CreateNVMeNamespace
+// or, for created namespaces, UpdateNVMeNamespace.
+type NVMeNamespaceReservation struct {
+	// PtplFile is the absolute path of the file SPDK persists reservations
+	// of the namespace in, so they survive power loss and restarts of SPDK
+	PtplFile string `json:"ptpl_file,omitempty"`
+}
+
+// SetNVMeNamespaceReservationRequest sets the reservation configuration of
+// an NVMe namespace
+type SetNVMeNamespaceReservationRequest struct {
+	// Name is the ID of the NVMe namespace
+	Name        string
+	Reservation *NVMeNamespaceReservation
+}
+
+// GetNVMeNamespaceReservationStateRequest reads the reservation of an NVMe namespace
+type GetNVMeNamespaceReservationStateRequest struct {
+	// Name is the ID of the NVMe namespace
+	Name string
+}
+
+// NVMeReservationState is the reservation of an NVMe namespace
+type NVMeReservationState struct {
+	// Type of the reservation, empty if the namespace is not reserved
+	Type string `json:"type,omitempty"`
+	// HolderHostID is the host identifier of the reservation holder
+	HolderHostID string `json:"holder_host_id,omitempty"`
+	// Registrants are the hosts registered with a reservation key
+	Registrants []NVMeReservationRegistrant `json:"registrants"`
+}
+
+// NVMeReservationRegistrant is a host registered for reservations of a namespace
+type NVMeReservationRegistrant struct {
+	// HostID is the host identifier of the registrant
+	HostID string `json:"host_id"`
+	// Rkey is the reservation key the host registered with
+	Rkey uint64 `json:"rkey"`
+}
+
+// reservationTypes returns names of reservation types by their value in
+// the NVMe specification
+func reservationTypes() map[int]string {
+	return map[int]string{
+		1: "write_exclusive",
+		2: "exclusive_access",
+		3: "write_exclusive_registrants_only",
+		4: "exclusive_access_registrants_only",
+		5: "write_exclusive_all_registrants",
+		6: "exclusive_access_all_registrants",
+	}
+}
+
+// SetNVMeNamespaceReservation sets the reservation configuration of an NVMe
+// namespace. It takes effect when the namespace is created or, if it
+// already exists, when it is updated with UpdateNVMeNamespace.
+func (s *Server) SetNVMeNamespaceReservation(ctx context.Context, in *SetNVMeNamespaceReservationRequest) (*NVMeNamespaceReservation, error) {
+	logging.FromContext(ctx).Infof("SetNVMeNamespaceReservation: Received from client: %v %+v", in.Name, in.Reservation)
+	if err := validateReservation(in.Reservation); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	unlock := s.locks.Lock(server.LockKey(namespacesTable, in.Name))
+	defer unlock()
+	reservation := *in.Reservation
+	if err := store.SetJSON(s.store, namespaceReservationsTable, in.Name, &reservation); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.NamespaceReservations[in.Name] = &reservation
+	s.mu.Unlock()
+	response := reservation
+	return &response, nil
+}
+
+// GetNVMeNamespaceReservationState reports the reservation holder and
+// registrants of an NVMe namespace. They are read from the file SPDK
+// persists reservations in, so PtplFile must be set for the namespace.
+func (s *Server) GetNVMeNamespaceReservationState(ctx context.Context, in *GetNVMeNamespaceReservationStateRequest) (*NVMeReservationState, error) {
+	logging.FromContext(ctx).Infof("GetNVMeNamespaceReservationState: Received from client: %v", in.Name)
+	s.mu.RLock()
+	_, ok := s.Nvme.Namespaces[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	ptplFile := s.activeReservation(in.Name).PtplFile
+	if ptplFile == "" {
+		err := status.Errorf(codes.FailedPrecondition, "reservations of NVMeNamespace %s are not persisted", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	state, err := readReservationState(ptplFile)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return state, nil
+}
+
+// applyReservation re-adds the namespace to SPDK if its reservation
+// configuration changed since it was added
+func (s *Server) applyReservation(ctx context.Context, namespace *pb.NVMeNamespace) error {
+	id := namespace.Spec.Id.Value
+	s.mu.RLock()
+	wanted, ok := s.Nvme.NamespaceReservations[id]
+	s.mu.RUnlock()
+	if !ok || *wanted == s.activeReservation(id) {
+		return nil
+	}
+	s.mu.RLock()
+	subsys, ok := s.Nvme.Subsystems[namespace.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("unable to find subsystem %s", namespace.Spec.SubsystemId.Value)
+	}
+	spdkSubsys, err := s.spdkSubsystem(ctx, subsys.Spec.Nqn)
+	if err != nil {
+		return err
+	}
+	spdkNs := findNamespace(spdkSubsys, namespace)
+	if spdkNs == nil {
+		return status.Errorf(codes.FailedPrecondition, "NVMeNamespace %s is missing in SPDK", id)
+	}
+	// SPDK reads the persistence file only when a namespace is added
+	params := spdk.NvmfSubsystemRemoveNsParams{Nqn: subsys.Spec.Nqn, Nsid: spdkNs.Nsid}
+	if err := s.callSpdk(ctx, "nvmf_subsystem_remove_ns", &params, fmt.Sprintf("Could not delete NS: %s", id)); err != nil {
+		return err
+	}
+	if err := s.addNamespace(ctx, subsys.Spec.Nqn, id, nvmfNamespaceSpec{
+		Nsid: spdkNs.Nsid, BdevName: spdkNs.BdevName, Nguid: spdkNs.Nguid, Eui64: spdkNs.Eui64, UUID: spdkNs.UUID,
+		PtplFile: wanted.PtplFile,
+	}); err != nil {
+		return err
+	}
+	return s.setActiveReservation(id, wanted)
+}
+
+// setActiveReservation records the reservation configuration a namespace
+// was added to SPDK with
+func (s *Server) setActiveReservation(id string, reservation *NVMeNamespaceReservation) error {
+	active := *reservation
+	if err := store.SetJSON(s.store, activeReservationsTable, id, &active); err != nil {
+		return server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.ActiveReservations[id] = &active
+	s.mu.Unlock()
+	return nil
+}
+
+// activeReservation returns the reservation configuration namespace id
+// was added to SPDK with
+func (s *Server) activeReservation(id string) NVMeNamespaceReservation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if reservation, ok := s.Nvme.ActiveReservations[id]; ok {
+		return *reservation
+	}
+	return NVMeNamespaceReservation{}
+}
+
+// wantedReservation returns the reservation configuration namespace id is
+// going to be added to SPDK with
+func (s *Server) wantedReservation(id string) NVMeNamespaceReservation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if reservation, ok := s.Nvme.NamespaceReservations[id]; ok {
+		return *reservation
+	}
+	return NVMeNamespaceReservation{}
+}
+
+// spdkReservationInfo is the content of a file SPDK persists reservations in
+type spdkReservationInfo struct {
+	Rtype       int    `json:"rtype"`
+	HolderUUID  string `json:"holder_uuid"`
+	Registrants []struct {
+		Rkey     uint64 `json:"rkey"`
+		HostUUID string `json:"host_uuid"`
+	} `json:"registrants"`
+}
+
+func readReservationState(ptplFile string) (*NVMeReservationState, error) {
+	state := &NVMeReservationState{Registrants: []NVMeReservationRegistrant{}}
+	data, err := os.ReadFile(filepath.Clean(ptplFile))
+	// SPDK writes the file on the first registration
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var info spdkReservationInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("cannot parse reservations in %s: %w", ptplFile, err)
+	}
+	state.Type = reservationTypes()[info.Rtype]
+	if state.Type != "" {
+		state.HolderHostID = info.HolderUUID
+	}
+	for _, registrant := range info.Registrants {
+		state.Registrants = append(state.Registrants, NVMeReservationRegistrant{HostID: registrant.HostUUID, Rkey: registrant.Rkey})
+	}
+	return state, nil
+}
+
+func validateReservation(reservation *NVMeNamespaceReservation) error {
+	if reservation == nil {
+		return fmt.Errorf("reservation cannot be empty")
+	}
+	if reservation.PtplFile != "" && (!filepath.IsAbs(reservation.PtplFile) || filepath.Clean(reservation.PtplFile) != reservation.PtplFile) {
+		return fmt.Errorf("ptpl_file %q must be a clean absolute path", reservation.PtplFile)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFrontEnd_SetNVMeNamespaceReservation(t *testing.T) {
+	tests := map[string]struct {
+		in      *NVMeNamespaceReservation
+		errCode codes.Code
+		errMsg  string
+	}{
+		"persisted reservations": {
+			&NVMeNamespaceReservation{PtplFile: "/var/lib/spdk/ptpl/namespace-test.json"},
+			codes.OK,
+			"",
+		},
+		"reservations in memory": {
+			&NVMeNamespaceReservation{},
+			codes.OK,
+			"",
+		},
+		"relative path": {
+			&NVMeNamespaceReservation{PtplFile: "ptpl/namespace-test.json"},
+			codes.InvalidArgument,
+			`ptpl_file "ptpl/namespace-test.json" must be a clean absolute path`,
+		},
+		"unclean path": {
+			&NVMeNamespaceReservation{PtplFile: "/var/lib/spdk/../namespace-test.json"},
+			codes.InvalidArgument,
+			`ptpl_file "/var/lib/spdk/../namespace-test.json" must be a clean absolute path`,
+		},
+		"empty reservation": {
+			nil,
+			codes.InvalidArgument,
+			"reservation cannot be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+
+			request := &SetNVMeNamespaceReservationRequest{Name: "namespace-test", Reservation: tt.in}
+			response, err := s.SetNVMeNamespaceReservation(context.Background(), request)
+			if tt.errCode == codes.OK {
+				if !reflect.DeepEqual(response, tt.in) {
+					t.Error("response: expected", tt.in, "received", response)
+				}
+				if !reflect.DeepEqual(s.Nvme.NamespaceReservations["namespace-test"], tt.in) {
+					t.Error("expected reservation to be kept", tt.in)
+				}
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestFrontEnd_GetNVMeNamespaceReservationState(t *testing.T) {
+	dir := t.TempDir()
+	tests := map[string]struct {
+		ptpl    string
+		content string
+		out     *NVMeReservationState
+		errCode codes.Code
+	}{
+		"reserved namespace": {
+			filepath.Join(dir, "reserved.json"),
+			`{"ptpl":true,"rtype":5,"crkey":1,"bdev_uuid":"b9d2b8f8-1d3a-4c0e-9bb4-8c9a2c2c5a11",` +
+				`"holder_uuid":"6d2a2a2e-1111-4c0e-9bb4-000000000001","registrants":[` +
+				`{"rkey":1,"host_uuid":"6d2a2a2e-1111-4c0e-9bb4-000000000001"},{"rkey":2,"host_uuid":"6d2a2a2e-1111-4c0e-9bb4-000000000002"}]}`,
+			&NVMeReservationState{
+				Type:         "write_exclusive_all_registrants",
+				HolderHostID: "6d2a2a2e-1111-4c0e-9bb4-000000000001",
+				Registrants: []NVMeReservationRegistrant{
+					{HostID: "6d2a2a2e-1111-4c0e-9bb4-000000000001", Rkey: 1},
+					{HostID: "6d2a2a2e-1111-4c0e-9bb4-000000000002", Rkey: 2},
+				},
+			},
+			codes.OK,
+		},
+		"no registrations yet": {
+			filepath.Join(dir, "missing.json"),
+			"",
+			&NVMeReservationState{Registrants: []NVMeReservationRegistrant{}},
+			codes.OK,
+		},
+		"corrupted file": {
+			filepath.Join(dir, "corrupted.json"),
+			"{",
+			nil,
+			codes.Internal,
+		},
+		"reservations in memory": {
+			"",
+			"",
+			nil,
+			codes.FailedPrecondition,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tt.content != "" {
+				if err := os.WriteFile(tt.ptpl, []byte(tt.content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+			s.Nvme.Namespaces["namespace-test"] = &pb.NVMeNamespace{Spec: &pb.NVMeNamespaceSpec{Id: &pc.ObjectKey{Value: "namespace-test"}}}
+			if tt.ptpl != "" {
+				s.Nvme.ActiveReservations["namespace-test"] = &NVMeNamespaceReservation{PtplFile: tt.ptpl}
+			}
+
+			request := &GetNVMeNamespaceReservationStateRequest{Name: "namespace-test"}
+			response, err := s.GetNVMeNamespaceReservationState(context.Background(), request)
+			if !reflect.DeepEqual(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if status.Code(err) != tt.errCode {
+				t.Error("error code: expected", tt.errCode, "received", err)
+			}
+		})
+	}
+
+	s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+	_, err := s.GetNVMeNamespaceReservationState(context.Background(), &GetNVMeNamespaceReservationStateRequest{Name: "unknown-id"})
+	if status.Code(err) != codes.NotFound {
+		t.Error("expected NotFound for unknown namespace, received", err)
+	}
+}
+
+func TestFrontEnd_ApplyReservation(t *testing.T) {
+	namespace := &pb.NVMeNamespace{Spec: &pb.NVMeNamespaceSpec{
+		Id:          &pc.ObjectKey{Value: "namespace-test"},
+		SubsystemId: &pc.ObjectKey{Value: "subsystem-test"},
+		VolumeId:    &pc.ObjectKey{Value: "Malloc1"},
+	}}
+	reservation := &NVMeNamespaceReservation{PtplFile: "/var/lib/spdk/ptpl/namespace-test.json"}
+	s := NewServer(server.CreateTestSpdkStub(map[string]string{
+		"nvmf_get_subsystems": `[{"nqn":"nqn.2022-09.io.spdk:opi3","subtype":"NVMe",` +
+			`"namespaces":[{"nsid":3,"bdev_name":"Malloc1","uuid":"b9d2b8f8-1d3a-4c0e-9bb4-8c9a2c2c5a11"}]}]`,
+		"nvmf_subsystem_remove_ns": `true`,
+		"nvmf_subsystem_add_ns":    `3`,
+	}))
+	s.Nvme.Subsystems["subsystem-test"] = &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{Id: &pc.ObjectKey{Value: "subsystem-test"}, Nqn: "nqn.2022-09.io.spdk:opi3"},
+	}
+	s.Nvme.Namespaces["namespace-test"] = namespace
+	ctx := context.Background()
+
+	if err := s.applyReservation(ctx, namespace); err != nil {
+		t.Error("expected namespace without changed reservation to be kept, received", err)
+	}
+	if _, err := s.SetNVMeNamespaceReservation(ctx, &SetNVMeNamespaceReservationRequest{
+		Name: "namespace-test", Reservation: reservation,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.applyReservation(ctx, namespace); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s.Nvme.ActiveReservations["namespace-test"], reservation) {
+		t.Error("expected namespace to be re-added with", reservation, "received", s.Nvme.ActiveReservations["namespace-test"])
+	}
+}
@@ -8,12 +8,13 @@ package frontend
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/opiproject/gospdk/spdk"
 	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 
 	"github.com/google/uuid"
 	"github.com/ulule/deepcopier"
@@ -23,76 +24,80 @@ import (
 )
 
 // CreateVirtioScsiController creates a Virtio SCSI controller
-func (s *Server) CreateVirtioScsiController(_ context.Context, in *pb.CreateVirtioScsiControllerRequest) (*pb.VirtioScsiController, error) {
-	log.Printf("CreateVirtioScsiController: Received from client: %v", in)
+func (s *Server) CreateVirtioScsiController(ctx context.Context, in *pb.CreateVirtioScsiControllerRequest) (*pb.VirtioScsiController, error) {
+	logging.FromContext(ctx).Infof("CreateVirtioScsiController: Received from client: %v", logging.Redact(in))
 	params := spdk.VhostCreateScsiControllerParams{
 		Ctrlr: in.VirtioScsiController.Id.Value,
 	}
 	var result spdk.VhostCreateScsiControllerResult
-	err := s.rpc.Call("vhost_create_scsi_controller", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_create_scsi_controller", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
-		log.Printf("Could not create: %v", in)
+		logging.FromContext(ctx).Errorf("Could not create: %v", in)
 	}
 	response := &pb.VirtioScsiController{}
 	err = deepcopier.Copy(in.VirtioScsiController).To(response)
 	if err != nil {
-		log.Printf("Error at response creation: %v", err)
+		logging.FromContext(ctx).Errorf("Error at response creation: %v", err)
 		return nil, status.Error(codes.Internal, "Failed to construct device create response")
 	}
 	return response, nil
 }
 
 // DeleteVirtioScsiController deletes a Virtio SCSI controller
-func (s *Server) DeleteVirtioScsiController(_ context.Context, in *pb.DeleteVirtioScsiControllerRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteVirtioScsiController: Received from client: %v", in)
+func (s *Server) DeleteVirtioScsiController(ctx context.Context, in *pb.DeleteVirtioScsiControllerRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteVirtioScsiController: Received from client: %v", logging.Redact(in))
 	params := spdk.VhostDeleteControllerParams{
 		Ctrlr: in.Name,
 	}
 	var result spdk.VhostDeleteControllerResult
-	err := s.rpc.Call("vhost_delete_controller", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_delete_controller", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
-		log.Printf("Could not delete: %v", in)
+		logging.FromContext(ctx).Errorf("Could not delete: %v", in)
 	}
 	return &emptypb.Empty{}, nil
 }
 
 // UpdateVirtioScsiController updates a Virtio SCSI controller
-func (s *Server) UpdateVirtioScsiController(_ context.Context, in *pb.UpdateVirtioScsiControllerRequest) (*pb.VirtioScsiController, error) {
-	log.Printf("Received from client: %v", in)
+func (s *Server) UpdateVirtioScsiController(ctx context.Context, in *pb.UpdateVirtioScsiControllerRequest) (*pb.VirtioScsiController, error) {
+	logging.FromContext(ctx).Infof("Received from client: %v", logging.Redact(in))
 	return &pb.VirtioScsiController{}, nil
 }
 
 // ListVirtioScsiControllers lists Virtio SCSI controllers
-func (s *Server) ListVirtioScsiControllers(_ context.Context, in *pb.ListVirtioScsiControllersRequest) (*pb.ListVirtioScsiControllersResponse, error) {
-	log.Printf("ListVirtioScsiControllers: Received from client: %v", in)
-	size, offset, perr := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+func (s *Server) ListVirtioScsiControllers(ctx context.Context, in *pb.ListVirtioScsiControllersRequest) (*pb.ListVirtioScsiControllersResponse, error) {
+	logging.FromContext(ctx).Infof("ListVirtioScsiControllers: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	size, offset, perr := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if perr != nil {
-		log.Printf("error: %v", perr)
+		logging.FromContext(ctx).Error(perr)
 		return nil, perr
 	}
 	var result []spdk.VhostGetControllersResult
-	err := s.rpc.Call("vhost_get_controllers", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_get_controllers", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	token := ""
-	log.Printf("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
+	logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
 	result, hasMoreElements := server.LimitPagination(result, offset, size)
 	if hasMoreElements {
 		token = uuid.New().String()
+		s.mu.Lock()
 		s.Pagination[token] = offset + size
+		s.mu.Unlock()
 	}
 	Blobarray := make([]*pb.VirtioScsiController, len(result))
 	for i := range result {
@@ -103,35 +108,35 @@ func (s *Server) ListVirtioScsiControllers(_ context.Context, in *pb.ListVirtioS
 }
 
 // GetVirtioScsiController gets a Virtio SCSI controller
-func (s *Server) GetVirtioScsiController(_ context.Context, in *pb.GetVirtioScsiControllerRequest) (*pb.VirtioScsiController, error) {
-	log.Printf("GetVirtioScsiController: Received from client: %v", in)
+func (s *Server) GetVirtioScsiController(ctx context.Context, in *pb.GetVirtioScsiControllerRequest) (*pb.VirtioScsiController, error) {
+	logging.FromContext(ctx).Infof("GetVirtioScsiController: Received from client: %v", logging.Redact(in))
 	params := spdk.VhostGetControllersParams{
 		Name: in.Name,
 	}
 	var result []spdk.VhostGetControllersResult
-	err := s.rpc.Call("vhost_get_controllers", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_get_controllers", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	return &pb.VirtioScsiController{Id: &pc.ObjectKey{Value: result[0].Ctrlr}}, nil
 }
 
 // VirtioScsiControllerStats gets a Virtio SCSI controller stats
-func (s *Server) VirtioScsiControllerStats(_ context.Context, in *pb.VirtioScsiControllerStatsRequest) (*pb.VirtioScsiControllerStatsResponse, error) {
-	log.Printf("Received from client: %v", in)
+func (s *Server) VirtioScsiControllerStats(ctx context.Context, in *pb.VirtioScsiControllerStatsRequest) (*pb.VirtioScsiControllerStatsResponse, error) {
+	logging.FromContext(ctx).Infof("Received from client: %v", logging.Redact(in))
 	return &pb.VirtioScsiControllerStatsResponse{}, nil
 }
 
 // CreateVirtioScsiLun creates a Virtio SCSI LUN
-func (s *Server) CreateVirtioScsiLun(_ context.Context, in *pb.CreateVirtioScsiLunRequest) (*pb.VirtioScsiLun, error) {
-	log.Printf("CreateVirtioScsiLun: Received from client: %v", in)
+func (s *Server) CreateVirtioScsiLun(ctx context.Context, in *pb.CreateVirtioScsiLunRequest) (*pb.VirtioScsiLun, error) {
+	logging.FromContext(ctx).Infof("CreateVirtioScsiLun: Received from client: %v", logging.Redact(in))
 	params := struct {
 		Name string `json:"ctrlr"`
 		Num  int    `json:"scsi_target_num"`
@@ -142,18 +147,18 @@ func (s *Server) CreateVirtioScsiLun(_ context.Context, in *pb.CreateVirtioScsiL
 		Bdev: in.VirtioScsiLun.VolumeId.Value,
 	}
 	var result int
-	err := s.rpc.Call("vhost_scsi_controller_add_target", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_scsi_controller_add_target", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	return &pb.VirtioScsiLun{}, nil
 }
 
 // DeleteVirtioScsiLun deletes a Virtio SCSI LUN
-func (s *Server) DeleteVirtioScsiLun(_ context.Context, in *pb.DeleteVirtioScsiLunRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteVirtioScsiLun: Received from client: %v", in)
+func (s *Server) DeleteVirtioScsiLun(ctx context.Context, in *pb.DeleteVirtioScsiLunRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteVirtioScsiLun: Received from client: %v", logging.Redact(in))
 	params := struct {
 		Name string `json:"ctrlr"`
 		Num  int    `json:"scsi_target_num"`
@@ -162,45 +167,49 @@ func (s *Server) DeleteVirtioScsiLun(_ context.Context, in *pb.DeleteVirtioScsiL
 		Num:  5,
 	}
 	var result bool
-	err := s.rpc.Call("vhost_scsi_controller_remove_target", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_scsi_controller_remove_target", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
-		log.Printf("Could not delete: %v", in)
+		logging.FromContext(ctx).Errorf("Could not delete: %v", in)
 	}
 	return &emptypb.Empty{}, nil
 }
 
 // UpdateVirtioScsiLun updates a Virtio SCSI LUN
-func (s *Server) UpdateVirtioScsiLun(_ context.Context, in *pb.UpdateVirtioScsiLunRequest) (*pb.VirtioScsiLun, error) {
-	log.Printf("Received from client: %v", in)
+func (s *Server) UpdateVirtioScsiLun(ctx context.Context, in *pb.UpdateVirtioScsiLunRequest) (*pb.VirtioScsiLun, error) {
+	logging.FromContext(ctx).Infof("Received from client: %v", logging.Redact(in))
 	return &pb.VirtioScsiLun{}, nil
 }
 
 // ListVirtioScsiLuns lists Virtio SCSI LUNs
-func (s *Server) ListVirtioScsiLuns(_ context.Context, in *pb.ListVirtioScsiLunsRequest) (*pb.ListVirtioScsiLunsResponse, error) {
-	log.Printf("ListVirtioScsiLuns: Received from client: %v", in)
-	size, offset, perr := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+func (s *Server) ListVirtioScsiLuns(ctx context.Context, in *pb.ListVirtioScsiLunsRequest) (*pb.ListVirtioScsiLunsResponse, error) {
+	logging.FromContext(ctx).Infof("ListVirtioScsiLuns: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	size, offset, perr := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if perr != nil {
-		log.Printf("error: %v", perr)
+		logging.FromContext(ctx).Error(perr)
 		return nil, perr
 	}
 	var result []spdk.VhostGetControllersResult
-	err := s.rpc.Call("vhost_get_controllers", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_get_controllers", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	token := ""
-	log.Printf("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
+	logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
 	result, hasMoreElements := server.LimitPagination(result, offset, size)
 	if hasMoreElements {
 		token = uuid.New().String()
+		s.mu.Lock()
 		s.Pagination[token] = offset + size
+		s.mu.Unlock()
 	}
 	Blobarray := make([]*pb.VirtioScsiLun, len(result))
 	for i := range result {
@@ -211,28 +220,28 @@ func (s *Server) ListVirtioScsiLuns(_ context.Context, in *pb.ListVirtioScsiLuns
 }
 
 // GetVirtioScsiLun gets a Virtio SCSI LUN
-func (s *Server) GetVirtioScsiLun(_ context.Context, in *pb.GetVirtioScsiLunRequest) (*pb.VirtioScsiLun, error) {
-	log.Printf("GetVirtioScsiLun: Received from client: %v", in)
+func (s *Server) GetVirtioScsiLun(ctx context.Context, in *pb.GetVirtioScsiLunRequest) (*pb.VirtioScsiLun, error) {
+	logging.FromContext(ctx).Infof("GetVirtioScsiLun: Received from client: %v", logging.Redact(in))
 	params := spdk.VhostGetControllersParams{
 		Name: in.Name,
 	}
 	var result []spdk.VhostGetControllersResult
-	err := s.rpc.Call("vhost_get_controllers", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "vhost_get_controllers", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	return &pb.VirtioScsiLun{VolumeId: &pc.ObjectKey{Value: result[0].Ctrlr}}, nil
 }
 
 // VirtioScsiLunStats gets a Virtio SCSI LUN stats
-func (s *Server) VirtioScsiLunStats(_ context.Context, in *pb.VirtioScsiLunStatsRequest) (*pb.VirtioScsiLunStatsResponse, error) {
-	log.Printf("Received from client: %v", in)
+func (s *Server) VirtioScsiLunStats(ctx context.Context, in *pb.VirtioScsiLunStatsRequest) (*pb.VirtioScsiLunStatsResponse, error) {
+	logging.FromContext(ctx).Infof("Received from client: %v", logging.Redact(in))
 	return &pb.VirtioScsiLunStatsResponse{}, nil
 }
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import "github.com/opiproject/gospdk/spdk"
+
+// SPDK JSON-RPC structures which are used by the frontend and are not
+// provided by gospdk
+
+// nvmfSubsystem is an entry of nvmf_get_subsystems result
+type nvmfSubsystem struct {
+	Nqn             string              `json:"nqn"`
+	Subtype         string              `json:"subtype"`
+	ListenAddresses []nvmfListenAddress `json:"listen_addresses"`
+	AllowAnyHost    bool                `json:"allow_any_host"`
+	Hosts           []nvmfHost          `json:"hosts"`
+	SerialNumber    string              `json:"serial_number"`
+	ModelNumber     string              `json:"model_number"`
+	MaxNamespaces   int                 `json:"max_namespaces"`
+	Namespaces      []nvmfNamespace     `json:"namespaces"`
+}
+
+type nvmfListenAddress struct {
+	Trtype  string `json:"trtype"`
+	Adrfam  string `json:"adrfam"`
+	Traddr  string `json:"traddr"`
+	Trsvcid string `json:"trsvcid"`
+}
+
+type nvmfHost struct {
+	Nqn            string `json:"nqn"`
+	Psk            string `json:"psk"`
+	DhchapKey      string `json:"dhchap_key"`
+	DhchapCtrlrKey string `json:"dhchap_ctrlr_key"`
+}
+
+type nvmfNamespace struct {
+	Nsid     int    `json:"nsid"`
+	BdevName string `json:"bdev_name"`
+	Nguid    string `json:"nguid"`
+	Eui64    string `json:"eui64"`
+	UUID     string `json:"uuid"`
+}
+
+// nvmfSubsystemAddNsParams are nvmf_subsystem_add_ns params including
+// the namespace identifiers missing in gospdk
+type nvmfSubsystemAddNsParams struct {
+	Nqn       string            `json:"nqn"`
+	Namespace nvmfNamespaceSpec `json:"namespace"`
+}
+
+type nvmfNamespaceSpec struct {
+	Nsid     int    `json:"nsid,omitempty"`
+	BdevName string `json:"bdev_name"`
+	Nguid    string `json:"nguid,omitempty"`
+	Eui64    string `json:"eui64,omitempty"`
+	UUID     string `json:"uuid,omitempty"`
+	PtplFile string `json:"ptpl_file,omitempty"`
+	// NoAutoVisible hides the namespace from hosts until it is attached
+	// to them with nvmf_ns_add_host
+	NoAutoVisible bool `json:"no_auto_visible,omitempty"`
+}
+
+// nvmfNsHostParams are nvmf_ns_add_host and nvmf_ns_remove_host params
+type nvmfNsHostParams struct {
+	Nqn  string `json:"nqn"`
+	Nsid int    `json:"nsid"`
+	Host string `json:"host"`
+}
+
+// nvmfCreateSubsystemParams are params of nvmf_create_subsystem including
+// the fields missing in gospdk
+type nvmfCreateSubsystemParams struct {
+	spdk.NvmfCreateSubsystemParams
+	AnaReporting bool `json:"ana_reporting,omitempty"`
+}
+
+// nvmfSubsystemAddListenerParams are params of nvmf_subsystem_add_listener
+// including the fields missing in gospdk
+type nvmfSubsystemAddListenerParams struct {
+	spdk.NvmfSubsystemAddListenerParams
+	SecureChannel bool `json:"secure_channel,omitempty"`
+}
+
+// nvmfSubsystemListenerSetAnaStateParams are params of
+// nvmf_subsystem_listener_set_ana_state
+type nvmfSubsystemListenerSetAnaStateParams struct {
+	spdk.NvmfSubsystemAddListenerParams
+	AnaState string `json:"ana_state"`
+}
+
+// nvmfSubsystemGetListenersParams are params of nvmf_subsystem_get_listeners
+type nvmfSubsystemGetListenersParams struct {
+	Nqn string `json:"nqn"`
+}
+
+// nvmfSubsystemListener is an entry of nvmf_subsystem_get_listeners result
+type nvmfSubsystemListener struct {
+	Address   nvmfListenAddress `json:"address"`
+	AnaStates []nvmfAnaState    `json:"ana_states"`
+}
+
+type nvmfAnaState struct {
+	AnaGroup int    `json:"ana_group"`
+	AnaState string `json:"ana_state"`
+}
+
+// nvmfDiscoveryReferralParams are params of nvmf_discovery_remove_referral
+type nvmfDiscoveryReferralParams struct {
+	Trtype  string `json:"trtype"`
+	Adrfam  string `json:"adrfam,omitempty"`
+	Traddr  string `json:"traddr"`
+	Trsvcid string `json:"trsvcid,omitempty"`
+	Subnqn  string `json:"subnqn,omitempty"`
+}
+
+// nvmfDiscoveryAddReferralParams are params of nvmf_discovery_add_referral
+type nvmfDiscoveryAddReferralParams struct {
+	nvmfDiscoveryReferralParams
+	SecureChannel bool `json:"secure_channel,omitempty"`
+}
+
+// nvmfDiscoveryReferral is an entry of nvmf_discovery_get_referrals result
+type nvmfDiscoveryReferral struct {
+	Address       nvmfListenAddress `json:"address"`
+	SecureChannel bool              `json:"secure_channel"`
+	Subnqn        string            `json:"subnqn"`
+}
+
+// nvmfSubsystemAllowAnyHostParams are params of nvmf_subsystem_allow_any_host
+type nvmfSubsystemAllowAnyHostParams struct {
+	Nqn          string `json:"nqn"`
+	AllowAnyHost bool   `json:"allow_any_host"`
+}
+
+// nvmfSubsystemHostParams are params of nvmf_subsystem_add_host and
+// nvmf_subsystem_remove_host
+type nvmfSubsystemHostParams struct {
+	Nqn            string `json:"nqn"`
+	Host           string `json:"host"`
+	Psk            string `json:"psk,omitempty"`
+	DhchapKey      string `json:"dhchap_key,omitempty"`
+	DhchapCtrlrKey string `json:"dhchap_ctrlr_key,omitempty"`
+}
+
+// vhostController is an entry of vhost_get_controllers result
+type vhostController struct {
+	Ctrlr           string `json:"ctrlr"`
+	Cpumask         string `json:"cpumask"`
+	Socket          string `json:"socket"`
+	BackendSpecific struct {
+		Block *struct {
+			Readonly bool   `json:"readonly"`
+			Bdev     string `json:"bdev"`
+		} `json:"block"`
+	} `json:"backend_specific"`
+}
+
+// vhostCreateBlkControllerParams are params of vhost_create_blk_controller
+// including the fields missing in gospdk
+type vhostCreateBlkControllerParams struct {
+	Ctrlr      string `json:"ctrlr"`
+	DevName    string `json:"dev_name"`
+	Cpumask    string `json:"cpumask,omitempty"`
+	Readonly   bool   `json:"readonly,omitempty"`
+	PackedRing bool   `json:"packed_ring,omitempty"`
+}
+
+// frameworkGetReactorsResult is the framework_get_reactors result
+type frameworkGetReactorsResult struct {
+	TickRate int `json:"tick_rate"`
+	Reactors []struct {
+		Lcore int `json:"lcore"`
+	} `json:"reactors"`
+}
+
+// nvmfGetStatsResult is the nvmf_get_stats result including the counters
+// missing in gospdk
+type nvmfGetStatsResult struct {
+	TickRate   int                  `json:"tick_rate"`
+	PollGroups []nvmfPollGroupStats `json:"poll_groups"`
+}
+
+type nvmfPollGroupStats struct {
+	Name               string               `json:"name"`
+	AdminQpairs        int                  `json:"admin_qpairs"`
+	IoQpairs           int                  `json:"io_qpairs"`
+	CurrentAdminQpairs int                  `json:"current_admin_qpairs"`
+	CurrentIoQpairs    int                  `json:"current_io_qpairs"`
+	PendingBdevIo      int                  `json:"pending_bdev_io"`
+	CompletedNvmeIo    int                  `json:"completed_nvme_io"`
+	Transports         []nvmfTransportStats `json:"transports"`
+}
+
+// nvmfTransportStats are statistics of a transport in a poll group, only
+// RDMA reports them per device
+type nvmfTransportStats struct {
+	Trtype  string `json:"trtype"`
+	Devices []struct {
+		Name           string `json:"name"`
+		Completions    int    `json:"completions"`
+		Requests       int    `json:"requests"`
+		RequestLatency int    `json:"request_latency"`
+	} `json:"devices"`
+}
+
+// nvmfSubsystemGetQpairsParams are nvmf_subsystem_get_qpairs params
+type nvmfSubsystemGetQpairsParams struct {
+	Nqn string `json:"nqn"`
+}
+
+// nvmfQpair is an entry of nvmf_subsystem_get_qpairs result
+type nvmfQpair struct {
+	Cntlid        int               `json:"cntlid"`
+	Qid           int               `json:"qid"`
+	State         string            `json:"state"`
+	Thread        string            `json:"thread"`
+	ListenAddress nvmfListenAddress `json:"listen_address"`
+}
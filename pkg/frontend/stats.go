@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opiproject/gospdk/spdk"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// bdevStats returns the I/O statistics of the bdev name
+func (s *Server) bdevStats(ctx context.Context, name string) (*pb.VolumeStats, error) {
+	params := spdk.BdevGetIostatParams{
+		Name: name,
+	}
+	var result spdk.BdevGetIostatResult
+	err := tracing.Call(ctx, s.rpc, "bdev_get_iostat", &params, &result)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if len(result.Bdevs) != 1 {
+		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result.Bdevs))
+		return nil, status.Errorf(codes.InvalidArgument, msg)
+	}
+	return &pb.VolumeStats{
+		ReadBytesCount:    int32(result.Bdevs[0].BytesRead),
+		ReadOpsCount:      int32(result.Bdevs[0].NumReadOps),
+		WriteBytesCount:   int32(result.Bdevs[0].BytesWritten),
+		WriteOpsCount:     int32(result.Bdevs[0].NumWriteOps),
+		UnmapBytesCount:   int32(result.Bdevs[0].BytesUnmapped),
+		UnmapOpsCount:     int32(result.Bdevs[0].NumUnmapOps),
+		ReadLatencyTicks:  int32(result.Bdevs[0].ReadLatencyTicks),
+		WriteLatencyTicks: int32(result.Bdevs[0].WriteLatencyTicks),
+		UnmapLatencyTicks: int32(result.Bdevs[0].UnmapLatencyTicks),
+	}, nil
+}
+
+// pollGroupStats aggregates the statistics of the SPDK poll groups serving
+// the queue pairs of the subsystem nqn which are accepted by match. Poll
+// groups count completed NVMe I/O commands without telling reads from
+// writes, so all of them are reported in ReadOpsCount, and the request
+// latency of RDMA devices in ReadLatencyTicks.
+func (s *Server) pollGroupStats(ctx context.Context, nqn string, match func(*nvmfQpair) bool) (*pb.VolumeStats, error) {
+	var stats nvmfGetStatsResult
+	err := tracing.Call(ctx, s.rpc, "nvmf_get_stats", nil, &stats)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", stats)
+	params := nvmfSubsystemGetQpairsParams{Nqn: nqn}
+	var qpairs []nvmfQpair
+	err = tracing.Call(ctx, s.rpc, "nvmf_subsystem_get_qpairs", &params, &qpairs)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", qpairs)
+	threads := make(map[string]bool)
+	for i := range qpairs {
+		if match(&qpairs[i]) {
+			threads[qpairs[i].Thread] = true
+		}
+	}
+	var completed, latency int
+	for _, group := range stats.PollGroups {
+		if !threads[group.Name] {
+			continue
+		}
+		completed += group.CompletedNvmeIo
+		for _, transport := range group.Transports {
+			for _, device := range transport.Devices {
+				latency += device.RequestLatency
+			}
+		}
+	}
+	return &pb.VolumeStats{ReadOpsCount: int32(completed), ReadLatencyTicks: int32(latency)}, nil
+}
+
+// onListener returns a match of queue pairs connected through the listener
+// with params
+func onListener(params *nvmfSubsystemAddListenerParams) func(*nvmfQpair) bool {
+	return func(qpair *nvmfQpair) bool {
+		return strings.EqualFold(qpair.ListenAddress.Trtype, params.ListenAddress.Trtype) &&
+			qpair.ListenAddress.Traddr == params.ListenAddress.Traddr &&
+			qpair.ListenAddress.Trsvcid == params.ListenAddress.Trsvcid
+	}
+}
+
+func anyQpair(*nvmfQpair) bool {
+	return true
+}
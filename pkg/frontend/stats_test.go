@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testPollGroupStats = `{"tick_rate":2490000000,"poll_groups":[` +
+		`{"name":"nvmf_tgt_poll_group_000","current_io_qpairs":1,"completed_nvme_io":1000,"transports":[{"trtype":"TCP"}]},` +
+		`{"name":"nvmf_tgt_poll_group_001","current_io_qpairs":1,"completed_nvme_io":200,` +
+		`"transports":[{"trtype":"RDMA","devices":[{"name":"mlx5_0","requests":200,"request_latency":5000}]}]},` +
+		`{"name":"nvmf_tgt_poll_group_002","current_io_qpairs":0,"completed_nvme_io":30,"transports":[{"trtype":"TCP"}]}]}`
+	testQpairs = `[` +
+		`{"cntlid":1,"qid":0,"state":"active","thread":"nvmf_tgt_poll_group_000",` +
+		`"listen_address":{"trtype":"TCP","adrfam":"IPv4","traddr":"10.10.10.10","trsvcid":"4420"}},` +
+		`{"cntlid":1,"qid":1,"state":"active","thread":"nvmf_tgt_poll_group_000",` +
+		`"listen_address":{"trtype":"TCP","adrfam":"IPv4","traddr":"10.10.10.10","trsvcid":"4420"}},` +
+		`{"cntlid":2,"qid":1,"state":"active","thread":"nvmf_tgt_poll_group_001",` +
+		`"listen_address":{"trtype":"RDMA","adrfam":"IPv4","traddr":"10.10.20.10","trsvcid":"4420"}}]`
+)
+
+func TestFrontEnd_PollGroupStats(t *testing.T) {
+	tests := map[string]struct {
+		controller string
+		out        *pb.VolumeStats
+		errCode    codes.Code
+	}{
+		"tcp controller": {
+			"controller-tcp",
+			&pb.VolumeStats{ReadOpsCount: 1000},
+			codes.OK,
+		},
+		"rdma controller": {
+			"controller-rdma",
+			&pb.VolumeStats{ReadOpsCount: 200, ReadLatencyTicks: 5000},
+			codes.OK,
+		},
+		"controller without connections": {
+			"controller-idle",
+			&pb.VolumeStats{},
+			codes.OK,
+		},
+		"unknown controller": {
+			"unknown-id",
+			nil,
+			codes.NotFound,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServerWithSubsystemListener(server.CreateTestSpdkStub(map[string]string{
+				"nvmf_get_stats":            testPollGroupStats,
+				"nvmf_subsystem_get_qpairs": testQpairs,
+			}), NewTCPSubsystemListener("10.10.10.10:4420"))
+			s.listeners[TransportRDMA] = NewRDMASubsystemListener("10.10.20.10:4420")
+			s.Nvme.Subsystems["subsystem-test"] = &pb.NVMeSubsystem{
+				Spec: &pb.NVMeSubsystemSpec{Id: &pc.ObjectKey{Value: "subsystem-test"}, Nqn: "nqn.2022-09.io.spdk:opi3"},
+			}
+			for _, id := range []string{"controller-tcp", "controller-rdma", "controller-idle"} {
+				s.Nvme.Controllers[id] = &pb.NVMeController{Spec: &pb.NVMeControllerSpec{
+					Id:          &pc.ObjectKey{Value: id},
+					SubsystemId: &pc.ObjectKey{Value: "subsystem-test"},
+				}}
+			}
+			s.Nvme.ControllerTransports["controller-rdma"] = &NVMeControllerTransport{Transport: TransportRDMA}
+			s.Nvme.ControllerTransports["controller-idle"] = &NVMeControllerTransport{Transport: TransportTCP, Trsvcid: "4421"}
+
+			request := &pb.NVMeControllerStatsRequest{Id: &pc.ObjectKey{Value: tt.controller}}
+			response, err := s.NVMeControllerStats(context.Background(), request)
+			if tt.errCode == codes.OK && !reflect.DeepEqual(response.Stats, tt.out) {
+				t.Error("response: expected", tt.out, "received", response.Stats)
+			}
+			if status.Code(err) != tt.errCode {
+				t.Error("error code: expected", tt.errCode, "received", err)
+			}
+		})
+	}
+
+	s := NewServer(server.CreateTestSpdkStub(map[string]string{
+		"nvmf_get_stats":            testPollGroupStats,
+		"nvmf_subsystem_get_qpairs": testQpairs,
+	}))
+	s.Nvme.Subsystems["subsystem-test"] = &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{Id: &pc.ObjectKey{Value: "subsystem-test"}, Nqn: "nqn.2022-09.io.spdk:opi3"},
+	}
+	response, err := s.NVMeSubsystemStats(context.Background(), &pb.NVMeSubsystemStatsRequest{SubsystemId: &pc.ObjectKey{Value: "subsystem-test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.VolumeStats{ReadOpsCount: 1200, ReadLatencyTicks: 5000}
+	if !reflect.DeepEqual(response.Stats, want) {
+		t.Error("expected poll groups of all controllers to be aggregated", want, "received", response.Stats)
+	}
+}
+
+func TestFrontEnd_BdevStats(t *testing.T) {
+	tests := map[string]struct {
+		spdk    string
+		out     *pb.VolumeStats
+		errCode codes.Code
+	}{
+		"backing bdev": {
+			`{"tick_rate":2490000000,"ticks":12345,"bdevs":[{"name":"Malloc1","bytes_read":36864,"num_read_ops":9,` +
+				`"bytes_written":4096,"num_write_ops":1,"bytes_unmapped":8192,"num_unmap_ops":2,` +
+				`"read_latency_ticks":900,"write_latency_ticks":100,"unmap_latency_ticks":20}]}`,
+			&pb.VolumeStats{
+				ReadBytesCount:    36864,
+				ReadOpsCount:      9,
+				WriteBytesCount:   4096,
+				WriteOpsCount:     1,
+				UnmapBytesCount:   8192,
+				UnmapOpsCount:     2,
+				ReadLatencyTicks:  900,
+				WriteLatencyTicks: 100,
+				UnmapLatencyTicks: 20,
+			},
+			codes.OK,
+		},
+		"missing bdev": {
+			`{"tick_rate":2490000000,"ticks":12345,"bdevs":[]}`,
+			nil,
+			codes.InvalidArgument,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{"bdev_get_iostat": tt.spdk}))
+			s.Nvme.Namespaces["namespace-test"] = &pb.NVMeNamespace{Spec: &pb.NVMeNamespaceSpec{
+				Id:          &pc.ObjectKey{Value: "namespace-test"},
+				SubsystemId: &pc.ObjectKey{Value: "subsystem-test"},
+				VolumeId:    &pc.ObjectKey{Value: "Malloc1"},
+			}}
+
+			request := &pb.NVMeNamespaceStatsRequest{NamespaceId: &pc.ObjectKey{Value: "namespace-test"}}
+			response, err := s.NVMeNamespaceStats(context.Background(), request)
+			if tt.errCode == codes.OK && !reflect.DeepEqual(response.Stats, tt.out) {
+				t.Error("response: expected", tt.out, "received", response.Stats)
+			}
+			if status.Code(err) != tt.errCode {
+				t.Error("error code: expected", tt.errCode, "received", err)
+			}
+		})
+	}
+}
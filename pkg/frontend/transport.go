@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/opiproject/gospdk/spdk"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// transports NVMe controllers can be exposed over
+const (
+	TransportTCP      = "tcp"
+	TransportRDMA     = "rdma"
+	TransportVfioUser = "vfiouser"
+)
+
+// NVMeControllerTransport selects the transport an NVMe controller is exposed
+// over, set before the controller is created. Controllers created without it
+// use the default transport of the server.
+type NVMeControllerTransport struct {
+	// Transport is the type of a registered SubsystemListener
+	Transport string `json:"transport"`
+	// Traddr is the IP address the controller listens on instead of the
+	// address of the listener. Valid only with tcp and rdma transports.
+	Traddr string `json:"traddr,omitempty"`
+	// Trsvcid is the port the controller listens on instead of the port
+	// of the listener. Valid only with tcp and rdma transports.
+	Trsvcid string `json:"trsvcid,omitempty"`
+}
+
+// SetNVMeControllerTransportRequest sets the transport of an NVMe controller
+type SetNVMeControllerTransportRequest struct {
+	// Name is the ID of the NVMe controller
+	Name      string
+	Transport *NVMeControllerTransport
+}
+
+// GetNVMeControllerTransportRequest reads the transport of an NVMe controller
+type GetNVMeControllerTransportRequest struct {
+	// Name is the ID of the NVMe controller
+	Name string
+}
+
+// RegisterSubsystemListener makes controllers of the listener transport
+// use listener. A listener registered before for the transport is replaced.
+func (s *Server) RegisterSubsystemListener(listener SubsystemListener) {
+	if listener == nil {
+		log.Panic("nil for SubsystemListener is not allowed")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners[listener.Transport()] = listener
+}
+
+// SetDefaultTransport selects the transport of controllers created without
+// SetNVMeControllerTransport
+func (s *Server) SetDefaultTransport(transport string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.listeners[transport]; !ok {
+		return fmt.Errorf("no SubsystemListener registered for transport %q", transport)
+	}
+	s.defaultTransport = transport
+	return nil
+}
+
+// SetNVMeControllerTransport sets the transport an NVMe controller is going
+// to be created with. The transport of a created controller cannot be changed.
+func (s *Server) SetNVMeControllerTransport(ctx context.Context, in *SetNVMeControllerTransportRequest) (*NVMeControllerTransport, error) {
+	logging.FromContext(ctx).Infof("SetNVMeControllerTransport: Received from client: %v %+v", in.Name, in.Transport)
+	if err := s.validateTransport(in.Transport); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	unlock := s.locks.Lock(server.LockKey(controllersTable, in.Name))
+	defer unlock()
+	s.mu.RLock()
+	_, ok := s.Nvme.Controllers[in.Name]
+	s.mu.RUnlock()
+	if ok {
+		err := status.Errorf(codes.FailedPrecondition, "transport of created NVMeController %s cannot be changed", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	transport := *in.Transport
+	if err := store.SetJSON(s.store, controllerTransportsTable, in.Name, &transport); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.ControllerTransports[in.Name] = &transport
+	s.mu.Unlock()
+	response := transport
+	return &response, nil
+}
+
+// GetNVMeControllerTransport reports the transport of a created NVMe controller
+func (s *Server) GetNVMeControllerTransport(ctx context.Context, in *GetNVMeControllerTransportRequest) (*NVMeControllerTransport, error) {
+	logging.FromContext(ctx).Infof("GetNVMeControllerTransport: Received from client: %v", in.Name)
+	s.mu.RLock()
+	_, ok := s.Nvme.Controllers[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	response := s.controllerTransport(in.Name)
+	return &response, nil
+}
+
+// ControllerTransport returns the transport an NVMe controller is, or is
+// going to be, exposed over
+func (s *Server) ControllerTransport(id string) string {
+	return s.controllerTransport(id).Transport
+}
+
+// controllerTransport returns the transport set for controller id or the
+// default transport
+func (s *Server) controllerTransport(id string) NVMeControllerTransport {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if transport, ok := s.Nvme.ControllerTransports[id]; ok {
+		return *transport
+	}
+	return NVMeControllerTransport{Transport: s.defaultTransport}
+}
+
+// listenerParams returns params of nvmf_subsystem_add_listener and
+// nvmf_subsystem_remove_listener for ctrlr exposed over transport
+func (s *Server) listenerParams(ctrlr *pb.NVMeController, transport NVMeControllerTransport, nqn string) (nvmfSubsystemAddListenerParams, error) {
+	s.mu.RLock()
+	listener, ok := s.listeners[transport.Transport]
+	s.mu.RUnlock()
+	if !ok {
+		return nvmfSubsystemAddListenerParams{}, status.Errorf(codes.FailedPrecondition,
+			"no SubsystemListener registered for transport %s of NVMeController %s", transport.Transport, ctrlr.Spec.Id.Value)
+	}
+	params := nvmfSubsystemAddListenerParams{
+		NvmfSubsystemAddListenerParams: listener.Params(ctrlr, nqn),
+		SecureChannel:                  secureChannel(listener),
+	}
+	overrideListenAddress(&params.NvmfSubsystemAddListenerParams, transport.Traddr, transport.Trsvcid)
+	return params, nil
+}
+
+// overrideListenAddress replaces the address and port of the listener in
+// params with traddr and trsvcid unless they are empty
+func overrideListenAddress(params *spdk.NvmfSubsystemAddListenerParams, traddr string, trsvcid string) {
+	if traddr != "" {
+		params.ListenAddress.Traddr = traddr
+		params.ListenAddress.Adrfam = addressFamily(net.ParseIP(traddr))
+	}
+	if trsvcid != "" {
+		params.ListenAddress.Trsvcid = trsvcid
+	}
+}
+
+// conflictingController returns the ID of another controller of the same
+// subsystem listening on the same address as params or empty string.
+// Controllers without their own address share the listener of the
+// transport, so only an explicit traddr or trsvcid can conflict.
+func (s *Server) conflictingController(ctrlr *pb.NVMeController, transport NVMeControllerTransport, params *nvmfSubsystemAddListenerParams) string {
+	if transport.Traddr == "" && transport.Trsvcid == "" {
+		return ""
+	}
+	s.mu.RLock()
+	var others []*pb.NVMeController
+	for _, other := range s.Nvme.Controllers {
+		if other.Spec.SubsystemId.Value == ctrlr.Spec.SubsystemId.Value && other.Spec.Id.Value != ctrlr.Spec.Id.Value {
+			others = append(others, other)
+		}
+	}
+	s.mu.RUnlock()
+	for _, other := range others {
+		otherParams, err := s.listenerParams(other, s.controllerTransport(other.Spec.Id.Value), params.Nqn)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(otherParams.ListenAddress.Trtype, params.ListenAddress.Trtype) &&
+			sameTraddr(otherParams.ListenAddress.Traddr, params.ListenAddress.Traddr) &&
+			otherParams.ListenAddress.Trsvcid == params.ListenAddress.Trsvcid {
+			return other.Spec.Id.Value
+		}
+	}
+	return ""
+}
+
+// sameTraddr compares IP addresses regardless of their notation and other
+// addresses, e.g. vfio-user socket paths, as strings
+func sameTraddr(a string, b string) bool {
+	ipA, ipB := net.ParseIP(a), net.ParseIP(b)
+	if ipA == nil || ipB == nil {
+		return a == b
+	}
+	return ipA.Equal(ipB)
+}
+
+// secureChannel returns true if the listener of transport requires TLS
+func (s *Server) secureChannel(transport string) bool {
+	s.mu.RLock()
+	listener, ok := s.listeners[transport]
+	s.mu.RUnlock()
+	return ok && secureChannel(listener)
+}
+
+func secureChannel(listener SubsystemListener) bool {
+	secure, ok := listener.(secureChannelListener)
+	return ok && secure.SecureChannel()
+}
+
+func (s *Server) validateTransport(transport *NVMeControllerTransport) error {
+	if transport == nil {
+		return fmt.Errorf("transport cannot be empty")
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.listeners[transport.Transport]; !ok {
+		return fmt.Errorf("no SubsystemListener registered for transport %q, expected one of %v",
+			transport.Transport, server.SortedKeys(s.listeners))
+	}
+	if transport.Traddr == "" && transport.Trsvcid == "" {
+		return nil
+	}
+	if transport.Transport != TransportTCP && transport.Transport != TransportRDMA {
+		return fmt.Errorf("traddr and trsvcid cannot be set for transport %s", transport.Transport)
+	}
+	return validateAddress(transport.Traddr, transport.Trsvcid)
+}
+
+// validateAddress checks traddr and trsvcid of IP based transports, which
+// are not checked if empty
+func validateAddress(traddr string, trsvcid string) error {
+	if traddr != "" && net.ParseIP(traddr) == nil {
+		return fmt.Errorf("traddr %q is not an IP address", traddr)
+	}
+	if trsvcid != "" {
+		port, err := strconv.Atoi(trsvcid)
+		if err != nil || port <= 0 || port > 65535 {
+			return fmt.Errorf("trsvcid %q is not a port number", trsvcid)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/opiproject/gospdk/spdk"
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// listenerSpdkStub records the listen addresses SPDK is called with
+type listenerSpdkStub struct {
+	spdk.JSONRPC
+	added   []nvmfListenAddress
+	removed []nvmfListenAddress
+}
+
+func newListenerSpdkStub() *listenerSpdkStub {
+	return &listenerSpdkStub{JSONRPC: server.CreateTestSpdkStub(map[string]string{
+		"nvmf_subsystem_add_listener":    `true`,
+		"nvmf_subsystem_remove_listener": `true`,
+	})}
+}
+
+func (s *listenerSpdkStub) Call(method string, params, result interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	var listener struct {
+		ListenAddress nvmfListenAddress `json:"listen_address"`
+	}
+	if err := json.Unmarshal(data, &listener); err != nil {
+		return err
+	}
+	switch method {
+	case "nvmf_subsystem_add_listener":
+		s.added = append(s.added, listener.ListenAddress)
+	case "nvmf_subsystem_remove_listener":
+		s.removed = append(s.removed, listener.ListenAddress)
+	}
+	return s.JSONRPC.Call(method, params, result)
+}
+
+func TestFrontEnd_SetNVMeControllerTransport(t *testing.T) {
+	tests := map[string]struct {
+		in      *NVMeControllerTransport
+		out     *NVMeControllerTransport
+		errCode codes.Code
+		errMsg  string
+		exist   bool
+	}{
+		"registered transport": {
+			&NVMeControllerTransport{Transport: TransportRDMA},
+			&NVMeControllerTransport{Transport: TransportRDMA},
+			codes.OK,
+			"",
+			false,
+		},
+		"own listen address": {
+			&NVMeControllerTransport{Transport: TransportTCP, Traddr: "fd00::10", Trsvcid: "4421"},
+			&NVMeControllerTransport{Transport: TransportTCP, Traddr: "fd00::10", Trsvcid: "4421"},
+			codes.OK,
+			"",
+			false,
+		},
+		"traddr is not an ip": {
+			&NVMeControllerTransport{Transport: TransportTCP, Traddr: "localhost"},
+			nil,
+			codes.InvalidArgument,
+			`traddr "localhost" is not an IP address`,
+			false,
+		},
+		"trsvcid out of range": {
+			&NVMeControllerTransport{Transport: TransportRDMA, Trsvcid: "65536"},
+			nil,
+			codes.InvalidArgument,
+			`trsvcid "65536" is not a port number`,
+			false,
+		},
+		"unregistered transport": {
+			&NVMeControllerTransport{Transport: TransportVfioUser},
+			nil,
+			codes.InvalidArgument,
+			`no SubsystemListener registered for transport "vfiouser", expected one of [rdma tcp]`,
+			false,
+		},
+		"empty transport": {
+			nil,
+			nil,
+			codes.InvalidArgument,
+			"transport cannot be empty",
+			false,
+		},
+		"already created controller": {
+			&NVMeControllerTransport{Transport: TransportRDMA},
+			nil,
+			codes.FailedPrecondition,
+			"transport of created NVMeController controller-test cannot be changed",
+			true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+			s.RegisterSubsystemListener(NewRDMASubsystemListener("10.10.10.10:4420"))
+			if tt.exist {
+				s.Nvme.Controllers["controller-test"] = &pb.NVMeController{Spec: &pb.NVMeControllerSpec{Id: &pc.ObjectKey{Value: "controller-test"}}}
+			}
+
+			request := &SetNVMeControllerTransportRequest{Name: "controller-test", Transport: tt.in}
+			response, err := s.SetNVMeControllerTransport(context.Background(), request)
+			if !reflect.DeepEqual(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if tt.out != nil && !reflect.DeepEqual(s.Nvme.ControllerTransports["controller-test"], tt.out) {
+				t.Error("expected transport to be kept", tt.out)
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestFrontEnd_ControllersOfMultipleTransports(t *testing.T) {
+	rpc := newListenerSpdkStub()
+	s := NewServerWithSubsystemListener(rpc, NewTCPSubsystemListener("10.10.10.10:4420"))
+	s.RegisterSubsystemListener(NewRDMASubsystemListener("10.10.10.20:4420"))
+	s.Nvme.Subsystems["subsystem-test"] = &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{Id: &pc.ObjectKey{Value: "subsystem-test"}, Nqn: "nqn.2022-09.io.spdk:opi3"},
+	}
+	ctx := context.Background()
+	if _, err := s.SetNVMeControllerTransport(ctx, &SetNVMeControllerTransportRequest{
+		Name: "controller-rdma", Transport: &NVMeControllerTransport{Transport: TransportRDMA},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"controller-tcp", "controller-rdma"} {
+		controller := &pb.NVMeController{Spec: &pb.NVMeControllerSpec{
+			Id: &pc.ObjectKey{Value: id}, SubsystemId: &pc.ObjectKey{Value: "subsystem-test"},
+		}}
+		if _, err := s.CreateNVMeController(ctx, &pb.CreateNVMeControllerRequest{NvMeController: controller}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wantAdded := []nvmfListenAddress{
+		{Trtype: TransportTCP, Adrfam: ipv4NvmeTCPProtocol, Traddr: "10.10.10.10", Trsvcid: "4420"},
+		{Trtype: TransportRDMA, Adrfam: ipv4NvmeTCPProtocol, Traddr: "10.10.10.20", Trsvcid: "4420"},
+	}
+	if !reflect.DeepEqual(rpc.added, wantAdded) {
+		t.Error("expected listeners", wantAdded, "received", rpc.added)
+	}
+
+	// the default transport does not change transports of created controllers
+	if err := s.SetDefaultTransport(TransportRDMA); err != nil {
+		t.Fatal(err)
+	}
+	transport, err := s.GetNVMeControllerTransport(ctx, &GetNVMeControllerTransportRequest{Name: "controller-tcp"})
+	if err != nil || transport.Transport != TransportTCP {
+		t.Error("expected tcp transport, received", transport, err)
+	}
+	if _, err := s.DeleteNVMeController(ctx, &pb.DeleteNVMeControllerRequest{Name: "controller-tcp"}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rpc.removed, wantAdded[:1]) {
+		t.Error("expected listener", wantAdded[:1], "to be removed, received", rpc.removed)
+	}
+	if _, ok := s.Nvme.ControllerTransports["controller-tcp"]; ok {
+		t.Error("expected transport to be deleted with the controller")
+	}
+}
+
+func TestFrontEnd_SetDefaultTransport(t *testing.T) {
+	s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+	if err := s.SetDefaultTransport(TransportRDMA); err == nil {
+		t.Error("expected unregistered transport to be rejected")
+	}
+	s.RegisterSubsystemListener(NewRDMASubsystemListener("10.10.10.10:4420"))
+	if err := s.SetDefaultTransport(TransportRDMA); err != nil {
+		t.Error(err)
+	}
+	if transport := s.ControllerTransport("controller-test"); transport != TransportRDMA {
+		t.Error("expected rdma transport, received", transport)
+	}
+}
+
+func TestFrontEnd_ControllerListenAddress(t *testing.T) {
+	subsystem := &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{Id: &pc.ObjectKey{Value: "subsystem-test"}, Nqn: "nqn.2022-09.io.spdk:opi3"},
+	}
+	existing := &pb.NVMeController{Spec: &pb.NVMeControllerSpec{
+		Id: &pc.ObjectKey{Value: "controller-1"}, SubsystemId: subsystem.Spec.Id,
+	}}
+	tests := map[string]struct {
+		transport *NVMeControllerTransport
+		want      nvmfListenAddress
+		errCode   codes.Code
+		errMsg    string
+	}{
+		"shared listener of the transport": {
+			&NVMeControllerTransport{Transport: TransportTCP},
+			nvmfListenAddress{Trtype: TransportTCP, Adrfam: ipv4NvmeTCPProtocol, Traddr: "10.10.10.10", Trsvcid: "4420"},
+			codes.OK,
+			"",
+		},
+		"other port": {
+			&NVMeControllerTransport{Transport: TransportTCP, Trsvcid: "4421"},
+			nvmfListenAddress{Trtype: TransportTCP, Adrfam: ipv4NvmeTCPProtocol, Traddr: "10.10.10.10", Trsvcid: "4421"},
+			codes.OK,
+			"",
+		},
+		"other ipv6 address": {
+			&NVMeControllerTransport{Transport: TransportTCP, Traddr: "fd00::10"},
+			nvmfListenAddress{Trtype: TransportTCP, Adrfam: ipv6NvmeTCPProtocol, Traddr: "fd00::10", Trsvcid: "4420"},
+			codes.OK,
+			"",
+		},
+		"same address and port": {
+			&NVMeControllerTransport{Transport: TransportTCP, Traddr: "10.10.10.10"},
+			nvmfListenAddress{Trtype: TransportTCP, Adrfam: ipv4NvmeTCPProtocol, Traddr: "10.10.10.10", Trsvcid: "4420"},
+			codes.AlreadyExists,
+			"NVMeController controller-1 already listens on tcp 10.10.10.10:4420 for nqn.2022-09.io.spdk:opi3",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServerWithSubsystemListener(server.CreateTestSpdkStub(map[string]string{}),
+				NewTCPSubsystemListener("10.10.10.10:4420"))
+			s.Nvme.Subsystems[subsystem.Spec.Id.Value] = subsystem
+			s.Nvme.Controllers[existing.Spec.Id.Value] = existing
+			controller := &pb.NVMeController{Spec: &pb.NVMeControllerSpec{
+				Id: &pc.ObjectKey{Value: "controller-2"}, SubsystemId: subsystem.Spec.Id,
+			}}
+
+			params, err := s.listenerParams(controller, *tt.transport, subsystem.Spec.Nqn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := nvmfListenAddress{
+				Trtype:  params.ListenAddress.Trtype,
+				Adrfam:  params.ListenAddress.Adrfam,
+				Traddr:  params.ListenAddress.Traddr,
+				Trsvcid: params.ListenAddress.Trsvcid,
+			}
+			if got != tt.want {
+				t.Error("expected listen address", tt.want, "received", params.ListenAddress)
+			}
+			other := s.conflictingController(controller, *tt.transport, &params)
+			if (other != "") != (tt.errCode == codes.AlreadyExists) {
+				t.Error("unexpected conflict with", other)
+			}
+			if tt.errCode == codes.OK {
+				return
+			}
+
+			ctx := context.Background()
+			if _, err := s.SetNVMeControllerTransport(ctx, &SetNVMeControllerTransportRequest{Name: "controller-2", Transport: tt.transport}); err != nil {
+				t.Fatal(err)
+			}
+			_, err = s.CreateNVMeController(ctx, &pb.CreateNVMeControllerRequest{NvMeController: controller})
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NVMeNamespaceVisibility selects the hosts an NVMe namespace is attached
+// to, so tenants sharing a subsystem see only their namespaces
+type NVMeNamespaceVisibility struct {
+	// Hidden namespaces are visible only to Hosts, otherwise to every host
+	// connected to the subsystem. It can be chosen only before the
+	// namespace is created.
+	Hidden bool `json:"hidden"`
+	// Hosts are the NQNs of the hosts a hidden namespace is visible to
+	Hosts []string `json:"hosts"`
+}
+
+// SetNVMeNamespaceVisibilityRequest sets the visibility of an NVMe namespace
+type SetNVMeNamespaceVisibilityRequest struct {
+	// Name is the ID of the NVMe namespace
+	Name       string
+	Visibility *NVMeNamespaceVisibility
+}
+
+// GetNVMeNamespaceVisibilityRequest reads the visibility of an NVMe namespace
+type GetNVMeNamespaceVisibilityRequest struct {
+	// Name is the ID of the NVMe namespace
+	Name string
+}
+
+// SetNVMeNamespaceVisibility sets the visibility an NVMe namespace is going
+// to be created with. Hosts of a created hidden namespace are changed in
+// SPDK right away, connected hosts are notified about the change.
+func (s *Server) SetNVMeNamespaceVisibility(ctx context.Context, in *SetNVMeNamespaceVisibilityRequest) (*NVMeNamespaceVisibility, error) {
+	logging.FromContext(ctx).Infof("SetNVMeNamespaceVisibility: Received from client: %v %+v", in.Name, in.Visibility)
+	if err := validateVisibility(in.Visibility); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	unlock := s.lockWithSubsystem(namespacesTable, in.Name)
+	defer unlock()
+	s.mu.RLock()
+	namespace, ok := s.Nvme.Namespaces[in.Name]
+	s.mu.RUnlock()
+	if ok {
+		if err := s.changeVisibleHosts(ctx, in.Name, in.Visibility); err != nil {
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		logging.FromContext(ctx).Infof("Changed hosts of NVMeNamespace %v in subsystem %v", in.Name, namespace.Spec.SubsystemId.Value)
+	}
+	visibility := copyVisibility(in.Visibility)
+	if err := store.SetJSON(s.store, namespaceVisibilityTable, in.Name, visibility); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Nvme.NamespaceVisibility[in.Name] = visibility
+	s.mu.Unlock()
+	return copyVisibility(visibility), nil
+}
+
+// GetNVMeNamespaceVisibility reports the visibility of a created NVMe namespace
+func (s *Server) GetNVMeNamespaceVisibility(ctx context.Context, in *GetNVMeNamespaceVisibilityRequest) (*NVMeNamespaceVisibility, error) {
+	logging.FromContext(ctx).Infof("GetNVMeNamespaceVisibility: Received from client: %v", in.Name)
+	s.mu.RLock()
+	_, ok := s.Nvme.Namespaces[in.Name]
+	s.mu.RUnlock()
+	if !ok {
+		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	visibility := s.visibility(in.Name)
+	return copyVisibility(&visibility), nil
+}
+
+// changeVisibleHosts attaches the created namespace id to hosts of
+// visibility which it is not attached to yet and detaches it from others
+func (s *Server) changeVisibleHosts(ctx context.Context, id string, visibility *NVMeNamespaceVisibility) error {
+	old := s.visibility(id)
+	if old.Hidden != visibility.Hidden {
+		return status.Errorf(codes.FailedPrecondition, "hidden of created NVMeNamespace %s cannot be changed", id)
+	}
+	if !visibility.Hidden {
+		return nil
+	}
+	nqn, nsid, err := s.spdkNamespace(ctx, id)
+	if err != nil {
+		return err
+	}
+	wanted := make(map[string]bool, len(visibility.Hosts))
+	for _, host := range visibility.Hosts {
+		wanted[host] = true
+	}
+	current := make(map[string]bool, len(old.Hosts))
+	for _, host := range old.Hosts {
+		current[host] = true
+		if !wanted[host] {
+			if err := s.setNamespaceHost(ctx, "nvmf_ns_remove_host", nqn, nsid, host); err != nil {
+				return err
+			}
+		}
+	}
+	for _, host := range visibility.Hosts {
+		if !current[host] {
+			if err := s.setNamespaceHost(ctx, "nvmf_ns_add_host", nqn, nsid, host); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addVisibleHosts attaches the hidden namespace id added to the subsystem
+// nqn with nsid to its hosts
+func (s *Server) addVisibleHosts(ctx context.Context, id string, nqn string, nsid int) error {
+	visibility := s.visibility(id)
+	if !visibility.Hidden {
+		return nil
+	}
+	for _, host := range visibility.Hosts {
+		if err := s.setNamespaceHost(ctx, "nvmf_ns_add_host", nqn, nsid, host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) setNamespaceHost(ctx context.Context, method string, nqn string, nsid int, host string) error {
+	params := nvmfNsHostParams{Nqn: nqn, Nsid: nsid, Host: host}
+	return s.callSpdk(ctx, method, &params, fmt.Sprintf("Could not change visibility of NS %d for host %s", nsid, host))
+}
+
+// spdkNamespace returns the NQN of the subsystem and NSID the created
+// namespace id has in SPDK
+func (s *Server) spdkNamespace(ctx context.Context, id string) (string, int, error) {
+	s.mu.RLock()
+	namespace := s.Nvme.Namespaces[id]
+	subsys, ok := s.Nvme.Subsystems[namespace.Spec.SubsystemId.Value]
+	s.mu.RUnlock()
+	if !ok {
+		return "", 0, fmt.Errorf("unable to find subsystem %s", namespace.Spec.SubsystemId.Value)
+	}
+	spdkSubsys, err := s.spdkSubsystem(ctx, subsys.Spec.Nqn)
+	if err != nil {
+		return "", 0, err
+	}
+	spdkNs := findNamespace(spdkSubsys, namespace)
+	if spdkNs == nil {
+		return "", 0, status.Errorf(codes.FailedPrecondition, "NVMeNamespace %s is missing in SPDK", id)
+	}
+	return subsys.Spec.Nqn, spdkNs.Nsid, nil
+}
+
+// visibility returns the visibility set for namespace id, namespaces are
+// visible to all hosts by default
+func (s *Server) visibility(id string) NVMeNamespaceVisibility {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if visibility, ok := s.Nvme.NamespaceVisibility[id]; ok {
+		return *visibility
+	}
+	return NVMeNamespaceVisibility{Hosts: []string{}}
+}
+
+func copyVisibility(visibility *NVMeNamespaceVisibility) *NVMeNamespaceVisibility {
+	return &NVMeNamespaceVisibility{Hidden: visibility.Hidden, Hosts: append([]string{}, visibility.Hosts...)}
+}
+
+func validateVisibility(visibility *NVMeNamespaceVisibility) error {
+	if visibility == nil {
+		return fmt.Errorf("visibility cannot be empty")
+	}
+	if !visibility.Hidden && len(visibility.Hosts) != 0 {
+		return fmt.Errorf("hosts can be set only for hidden namespaces")
+	}
+	seen := make(map[string]bool, len(visibility.Hosts))
+	for _, host := range visibility.Hosts {
+		if err := validateNqn(host); err != nil {
+			return fmt.Errorf("invalid host: %w", err)
+		}
+		if seen[host] {
+			return fmt.Errorf("duplicate host %s", host)
+		}
+		seen[host] = true
+	}
+	return nil
+}
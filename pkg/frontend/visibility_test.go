@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package frontend implements the FrontEnd APIs (host facing) of the storage Server
+package frontend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFrontEnd_SetNVMeNamespaceVisibility(t *testing.T) {
+	tests := map[string]struct {
+		in      *NVMeNamespaceVisibility
+		errCode codes.Code
+		errMsg  string
+	}{
+		"hidden namespace": {
+			&NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c"}},
+			codes.OK,
+			"",
+		},
+		"hidden from all hosts": {
+			&NVMeNamespaceVisibility{Hidden: true, Hosts: []string{}},
+			codes.OK,
+			"",
+		},
+		"visible namespace": {
+			&NVMeNamespaceVisibility{Hosts: []string{}},
+			codes.OK,
+			"",
+		},
+		"hosts of visible namespace": {
+			&NVMeNamespaceVisibility{Hosts: []string{"nqn.2014-08.org.nvmexpress:uuid:feb98abe-d51f-40c8-b348-2753f3571d3c"}},
+			codes.InvalidArgument,
+			"hosts can be set only for hidden namespaces",
+		},
+		"duplicate host": {
+			&NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"nqn.2022-09.io.spdk:host1", "nqn.2022-09.io.spdk:host1"}},
+			codes.InvalidArgument,
+			"duplicate host nqn.2022-09.io.spdk:host1",
+		},
+		"invalid host": {
+			&NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"host1"}},
+			codes.InvalidArgument,
+			`invalid host: NQN "host1" must start with "nqn."`,
+		},
+		"empty visibility": {
+			nil,
+			codes.InvalidArgument,
+			"visibility cannot be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+
+			request := &SetNVMeNamespaceVisibilityRequest{Name: "namespace-test", Visibility: tt.in}
+			response, err := s.SetNVMeNamespaceVisibility(context.Background(), request)
+			if tt.errCode == codes.OK {
+				if !reflect.DeepEqual(response, tt.in) {
+					t.Error("response: expected", tt.in, "received", response)
+				}
+				if !reflect.DeepEqual(s.Nvme.NamespaceVisibility["namespace-test"], tt.in) {
+					t.Error("expected visibility to be kept", tt.in)
+				}
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestFrontEnd_ChangeNVMeNamespaceVisibility(t *testing.T) {
+	tests := map[string]struct {
+		in      *NVMeNamespaceVisibility
+		spdk    string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"attach to another host": {
+			&NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"nqn.2022-09.io.spdk:host1", "nqn.2022-09.io.spdk:host2"}},
+			`true`,
+			codes.OK,
+			"",
+		},
+		"detach from all hosts": {
+			&NVMeNamespaceVisibility{Hidden: true, Hosts: []string{}},
+			`true`,
+			codes.OK,
+			"",
+		},
+		"spdk failure": {
+			&NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"nqn.2022-09.io.spdk:host2"}},
+			`false`,
+			codes.InvalidArgument,
+			"Could not change visibility of NS 3 for host nqn.2022-09.io.spdk:host1",
+		},
+		"make visible to all hosts": {
+			&NVMeNamespaceVisibility{Hosts: []string{}},
+			`true`,
+			codes.FailedPrecondition,
+			"hidden of created NVMeNamespace namespace-test cannot be changed",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(map[string]string{
+				"nvmf_get_subsystems": `[{"nqn":"nqn.2022-09.io.spdk:opi3","subtype":"NVMe",` +
+					`"namespaces":[{"nsid":3,"bdev_name":"Malloc1","uuid":"b9d2b8f8-1d3a-4c0e-9bb4-8c9a2c2c5a11"}]}]`,
+				"nvmf_ns_add_host":    tt.spdk,
+				"nvmf_ns_remove_host": tt.spdk,
+			}))
+			s.Nvme.Subsystems["subsystem-test"] = &pb.NVMeSubsystem{
+				Spec: &pb.NVMeSubsystemSpec{Id: &pc.ObjectKey{Value: "subsystem-test"}, Nqn: "nqn.2022-09.io.spdk:opi3"},
+			}
+			s.Nvme.Namespaces["namespace-test"] = &pb.NVMeNamespace{Spec: &pb.NVMeNamespaceSpec{
+				Id:          &pc.ObjectKey{Value: "namespace-test"},
+				SubsystemId: &pc.ObjectKey{Value: "subsystem-test"},
+				VolumeId:    &pc.ObjectKey{Value: "Malloc1"},
+			}}
+			old := &NVMeNamespaceVisibility{Hidden: true, Hosts: []string{"nqn.2022-09.io.spdk:host1"}}
+			s.Nvme.NamespaceVisibility["namespace-test"] = old
+
+			request := &SetNVMeNamespaceVisibilityRequest{Name: "namespace-test", Visibility: tt.in}
+			_, err := s.SetNVMeNamespaceVisibility(context.Background(), request)
+			want := tt.in
+			if tt.errCode != codes.OK {
+				want = old
+			}
+			response, _ := s.GetNVMeNamespaceVisibility(context.Background(), &GetNVMeNamespaceVisibilityRequest{Name: "namespace-test"})
+			if !reflect.DeepEqual(response, want) {
+				t.Error("expected visibility", want, "received", response)
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestFrontEnd_GetNVMeNamespaceVisibility(t *testing.T) {
+	s := NewServer(server.CreateTestSpdkStub(map[string]string{}))
+	s.Nvme.Namespaces["namespace-test"] = &pb.NVMeNamespace{Spec: &pb.NVMeNamespaceSpec{Id: &pc.ObjectKey{Value: "namespace-test"}}}
+
+	response, err := s.GetNVMeNamespaceVisibility(context.Background(), &GetNVMeNamespaceVisibilityRequest{Name: "namespace-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &NVMeNamespaceVisibility{Hosts: []string{}}
+	if !reflect.DeepEqual(response, want) {
+		t.Error("expected namespaces to be visible by default", want, "received", response)
+	}
+	_, err = s.GetNVMeNamespaceVisibility(context.Background(), &GetNVMeNamespaceVisibilityRequest{Name: "unknown-id"})
+	if status.Code(err) != codes.NotFound {
+		t.Error("expected NotFound for unknown namespace, received", err)
+	}
+}
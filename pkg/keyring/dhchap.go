@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package keyring manages the keys in the SPDK keyring which are referenced by
+// name from NVMe-oF subsystems and remote controllers. Keys are read by SPDK
+// from files, so the key material never passes through the bridge.
+package keyring
+
+import "fmt"
+
+// dhchapDigests returns the DH-HMAC-CHAP digests supported by SPDK
+func dhchapDigests() []string {
+	return []string{"sha256", "sha384", "sha512"}
+}
+
+// dhchapDhgroups returns the Diffie-Hellman groups supported by SPDK
+func dhchapDhgroups() []string {
+	return []string{"null", "ffdhe2048", "ffdhe3072", "ffdhe4096", "ffdhe6144", "ffdhe8192"}
+}
+
+// Dhchap selects the digests and Diffie-Hellman groups offered for NVMe-oF
+// in-band authentication. SPDK defaults are used for empty lists.
+type Dhchap struct {
+	Digests  []string `yaml:"digests,omitempty"`
+	Dhgroups []string `yaml:"dhgroups,omitempty"`
+}
+
+// Enabled returns true if the SPDK defaults are overridden
+func (c Dhchap) Enabled() bool {
+	return len(c.Digests) != 0 || len(c.Dhgroups) != 0
+}
+
+// Validate checks that SPDK supports the digests and groups
+func (c Dhchap) Validate() error {
+	for _, digest := range c.Digests {
+		if !contains(dhchapDigests(), digest) {
+			return fmt.Errorf("unknown digest %q, expected one of %v", digest, dhchapDigests())
+		}
+	}
+	for _, group := range c.Dhgroups {
+		if !contains(dhchapDhgroups(), group) {
+			return fmt.Errorf("unknown dhgroup %q, expected one of %v", group, dhchapDhgroups())
+		}
+	}
+	return nil
+}
+
+func contains(list []string, item string) bool {
+	for _, i := range list {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package keyring manages the keys in the SPDK keyring which are referenced by
+// name from NVMe-oF subsystems and remote controllers. Keys are read by SPDK
+// from files, so the key material never passes through the bridge.
+package keyring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/opiproject/gospdk/spdk"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// keysTable is used to persist keys
+const keysTable = "keyring_keys"
+
+// keyName is the set of names accepted for keys
+var keyName = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+// Key is a key in the SPDK keyring read from a file
+type Key struct {
+	// Name the key is referenced with
+	Name string `json:"name"`
+	// Path is the absolute path of the file with the key, which must only be
+	// accessible by the owner of the SPDK process
+	Path string `json:"path"`
+}
+
+// Server manages the keys in the SPDK keyring
+type Server struct {
+	rpc   spdk.JSONRPC
+	store store.Store
+	Keys  map[string]*Key
+
+	// mu guards Keys, locks serializes operations on the same keys
+	// while SPDK is called
+	mu    sync.RWMutex
+	locks *server.KeyLocker
+}
+
+// NewServer creates initialized instance of keyring server communicating
+// with provided jsonRPC
+func NewServer(jsonRPC spdk.JSONRPC) *Server {
+	return &Server{
+		rpc:   jsonRPC,
+		store: store.NewMemoryStore(),
+		Keys:  make(map[string]*Key),
+		locks: server.NewKeyLocker(),
+	}
+}
+
+// UseStore makes the server persist its keys in the provided store and
+// restores all keys previously saved there
+func (s *Server) UseStore(st store.Store) error {
+	if st == nil {
+		return errors.New("nil store is not allowed")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := store.LoadJSON(st, keysTable, s.Keys); err != nil {
+		return err
+	}
+	log.Printf("Restored %d keyring keys", len(s.Keys))
+	s.store = st
+	return nil
+}
+
+// AddKey adds a key read from a file to the SPDK keyring
+func (s *Server) AddKey(ctx context.Context, in *Key) (*Key, error) {
+	logging.FromContext(ctx).Infof("AddKey: Received from client: %+v", in)
+	if err := validateKey(in); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	unlock := s.locks.Lock(server.LockKey(keysTable, in.Name))
+	defer unlock()
+	// idempotent API when called with same key, should return same object
+	s.mu.RLock()
+	key, ok := s.Keys[in.Name]
+	s.mu.RUnlock()
+	if ok {
+		if key.Path != in.Path {
+			err := status.Errorf(codes.AlreadyExists, "key %s already exists with path %s", in.Name, key.Path)
+			logging.FromContext(ctx).Error(err)
+			return nil, err
+		}
+		logging.FromContext(ctx).Infof("Already existing key with name %v", in.Name)
+		return &Key{Name: key.Name, Path: key.Path}, nil
+	}
+	if err := addKey(ctx, s.rpc, in); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, err
+	}
+	key = &Key{Name: in.Name, Path: in.Path}
+	if err := store.SetJSON(s.store, keysTable, key.Name, key); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
+	s.Keys[key.Name] = key
+	s.mu.Unlock()
+	return &Key{Name: key.Name, Path: key.Path}, nil
+}
+
+// DeleteKey removes a key from the SPDK keyring. Subsystem hosts and
+// remote controllers keep using the key until they are deleted.
+func (s *Server) DeleteKey(ctx context.Context, name string, allowMissing bool) error {
+	logging.FromContext(ctx).Infof("DeleteKey: Received from client: %v", name)
+	unlock := s.locks.Lock(server.LockKey(keysTable, name))
+	defer unlock()
+	s.mu.RLock()
+	_, ok := s.Keys[name]
+	s.mu.RUnlock()
+	if !ok {
+		if allowMissing {
+			return nil
+		}
+		err := status.Errorf(codes.NotFound, "unable to find key %s", name)
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	params := keyringFileRemoveKeyParams{Name: name}
+	var result bool
+	err := tracing.Call(ctx, s.rpc, "keyring_file_remove_key", &params, &result)
+	if err != nil {
+		logging.FromContext(ctx).Error(err)
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if !result {
+		msg := fmt.Sprintf("Could not remove key: %s", name)
+		logging.FromContext(ctx).Error(msg)
+		return status.Errorf(codes.InvalidArgument, msg)
+	}
+	if err := s.store.Delete(keysTable, name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return server.StoreError(err)
+	}
+	s.mu.Lock()
+	delete(s.Keys, name)
+	s.mu.Unlock()
+	return nil
+}
+
+// ListKeys returns the keys added to the SPDK keyring sorted by name
+func (s *Server) ListKeys(ctx context.Context) ([]*Key, error) {
+	logging.FromContext(ctx).Infof("ListKeys: Received from client")
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]*Key, 0, len(s.Keys))
+	for _, name := range server.SortedKeys(s.Keys) {
+		keys = append(keys, &Key{Name: s.Keys[name].Name, Path: s.Keys[name].Path})
+	}
+	return keys, nil
+}
+
+// ObjectCounts returns the number of objects managed by the server per type
+func (s *Server) ObjectCounts() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return map[string]int{"keyring_key": len(s.Keys)}
+}
+
+// Reconcile compares the keys known to the server with the keys present in
+// the SPDK keyring and handles the differences according to the policy
+func (s *Server) Reconcile(policy server.ReconcilePolicy) ([]server.Drift, error) {
+	// no key can be changed by other operations during reconciliation
+	unlock := s.locks.LockAll()
+	defer unlock()
+
+	present, err := spdkKeys(context.Background(), s.rpc)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return nil, err
+	}
+	recreate := func(key *Key) error {
+		return addKey(context.Background(), s.rpc, key)
+	}
+	adopt := func(name string, k *keyringKey) error {
+		key := &Key{Name: name, Path: k.Path}
+		if err := store.SetJSON(s.store, keysTable, name, key); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		s.Keys[name] = key
+		s.mu.Unlock()
+		return nil
+	}
+	return server.ReconcileObjects("KeyringKey", policy, s.Keys, present, recreate, adopt), nil
+}
+
+// Check returns InvalidArgument if any of the named keys is not in the SPDK
+// keyring. Empty names are ignored.
+func Check(ctx context.Context, rpc spdk.JSONRPC, names ...string) error {
+	var wanted []string
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if err := ValidateName(name); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		wanted = append(wanted, name)
+	}
+	if len(wanted) == 0 {
+		return nil
+	}
+	present, err := spdkKeys(ctx, rpc)
+	if err != nil {
+		return err
+	}
+	for _, name := range wanted {
+		if _, ok := present[name]; !ok {
+			return status.Errorf(codes.InvalidArgument, "key %s not found in keyring", name)
+		}
+	}
+	return nil
+}
+
+// ValidateName checks that name can be used for a key
+func ValidateName(name string) error {
+	if !keyName.MatchString(name) {
+		return fmt.Errorf("key name %q must be 1 to 64 letters, digits, '_', '.' or '-'", name)
+	}
+	return nil
+}
+
+func validateKey(key *Key) error {
+	if key == nil {
+		return errors.New("key cannot be empty")
+	}
+	if err := ValidateName(key.Name); err != nil {
+		return err
+	}
+	if !filepath.IsAbs(key.Path) {
+		return fmt.Errorf("path %q of key %s must be absolute", key.Path, key.Name)
+	}
+	return nil
+}
+
+func addKey(ctx context.Context, rpc spdk.JSONRPC, key *Key) error {
+	params := keyringFileAddKeyParams{Name: key.Name, Path: key.Path}
+	var result bool
+	err := tracing.Call(ctx, rpc, "keyring_file_add_key", &params, &result)
+	if err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
+	if !result {
+		return status.Errorf(codes.InvalidArgument, "Could not add key: %s", key.Name)
+	}
+	return nil
+}
+
+func spdkKeys(ctx context.Context, rpc spdk.JSONRPC) (map[string]*keyringKey, error) {
+	var result []keyringKey
+	err := tracing.Call(ctx, rpc, "keyring_get_keys", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*keyringKey, len(result))
+	for i := range result {
+		keys[result[i].Name] = &result[i]
+	}
+	return keys, nil
+}
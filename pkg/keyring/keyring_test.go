@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package keyring manages the keys in the SPDK keyring which are referenced by
+// name from NVMe-oF subsystems and remote controllers. Keys are read by SPDK
+// from files, so the key material never passes through the bridge.
+package keyring
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestKeyring_AddKey(t *testing.T) {
+	existing := &Key{Name: "key0", Path: "/etc/opi/key0"}
+	tests := map[string]struct {
+		in      *Key
+		out     *Key
+		spdk    map[string]string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"valid request with valid SPDK response": {
+			&Key{Name: "key1", Path: "/etc/opi/key1"},
+			&Key{Name: "key1", Path: "/etc/opi/key1"},
+			map[string]string{"keyring_file_add_key": `true`},
+			codes.OK,
+			"",
+		},
+		"valid request with invalid SPDK response": {
+			&Key{Name: "key1", Path: "/etc/opi/key1"},
+			nil,
+			map[string]string{"keyring_file_add_key": `false`},
+			codes.InvalidArgument,
+			"Could not add key: key1",
+		},
+		"already existing key": {
+			existing,
+			existing,
+			map[string]string{},
+			codes.OK,
+			"",
+		},
+		"already existing key with other path": {
+			&Key{Name: "key0", Path: "/etc/opi/other"},
+			nil,
+			map[string]string{},
+			codes.AlreadyExists,
+			"key key0 already exists with path /etc/opi/key0",
+		},
+		"invalid name": {
+			&Key{Name: "key/1", Path: "/etc/opi/key1"},
+			nil,
+			map[string]string{},
+			codes.InvalidArgument,
+			`key name "key/1" must be 1 to 64 letters, digits, '_', '.' or '-'`,
+		},
+		"relative path": {
+			&Key{Name: "key1", Path: "key1"},
+			nil,
+			map[string]string{},
+			codes.InvalidArgument,
+			`path "key1" of key key1 must be absolute`,
+		},
+		"empty key": {
+			nil,
+			nil,
+			map[string]string{},
+			codes.InvalidArgument,
+			"key cannot be empty",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(tt.spdk))
+			s.Keys[existing.Name] = existing
+
+			response, err := s.AddKey(context.Background(), tt.in)
+			if !reflect.DeepEqual(response, tt.out) {
+				t.Error("response: expected", tt.out, "received", response)
+			}
+			if tt.out != nil && !reflect.DeepEqual(s.Keys[tt.out.Name], tt.out) {
+				t.Error("expected key to be kept", tt.out)
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestKeyring_DeleteKey(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		missing bool
+		spdk    map[string]string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"valid request with valid SPDK response": {
+			"key0", false, map[string]string{"keyring_file_remove_key": `true`}, codes.OK, "",
+		},
+		"valid request with invalid SPDK response": {
+			"key0", false, map[string]string{"keyring_file_remove_key": `false`}, codes.InvalidArgument, "Could not remove key: key0",
+		},
+		"unknown key": {
+			"key1", false, map[string]string{}, codes.NotFound, "unable to find key key1",
+		},
+		"unknown key with allow missing": {
+			"key1", true, map[string]string{}, codes.OK, "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewServer(server.CreateTestSpdkStub(tt.spdk))
+			s.Keys["key0"] = &Key{Name: "key0", Path: "/etc/opi/key0"}
+
+			err := s.DeleteKey(context.Background(), tt.name, tt.missing)
+			if _, ok := s.Keys[tt.name]; ok && err == nil {
+				t.Error("expected key to be deleted")
+			}
+
+			if er, ok := status.FromError(err); ok {
+				if er.Code() != tt.errCode {
+					t.Error("error code: expected", tt.errCode, "received", er.Code())
+				}
+				if er.Message() != tt.errMsg {
+					t.Error("error message: expected", tt.errMsg, "received", er.Message())
+				}
+			} else {
+				t.Error("expected grpc error status")
+			}
+		})
+	}
+}
+
+func TestKeyring_Check(t *testing.T) {
+	rpc := server.CreateTestSpdkStub(map[string]string{
+		"keyring_get_keys": `[{"name":"key0","path":"/etc/opi/key0","removed":false}]`,
+	})
+	tests := map[string]struct {
+		names   []string
+		errCode codes.Code
+		errMsg  string
+	}{
+		"present key":  {[]string{"key0", ""}, codes.OK, ""},
+		"no keys":      {[]string{"", ""}, codes.OK, ""},
+		"missing key":  {[]string{"key0", "key1"}, codes.InvalidArgument, "key key1 not found in keyring"},
+		"invalid name": {[]string{"key 0"}, codes.InvalidArgument, `key name "key 0" must be 1 to 64 letters, digits, '_', '.' or '-'`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Check(context.Background(), rpc, tt.names...)
+			er, ok := status.FromError(err)
+			if !ok {
+				t.Fatal("expected grpc error status")
+			}
+			if er.Code() != tt.errCode {
+				t.Error("error code: expected", tt.errCode, "received", er.Code())
+			}
+			if er.Message() != tt.errMsg {
+				t.Error("error message: expected", tt.errMsg, "received", er.Message())
+			}
+		})
+	}
+}
+
+func TestKeyring_Reconcile(t *testing.T) {
+	rpc := server.CreateTestSpdkStub(map[string]string{
+		"keyring_get_keys":     `[{"name":"key1","path":"/etc/opi/key1","removed":false}]`,
+		"keyring_file_add_key": `true`,
+	})
+	s := NewServer(rpc)
+	s.Keys["key0"] = &Key{Name: "key0", Path: "/etc/opi/key0"}
+
+	drifts, err := s.Reconcile(server.ReconcilePolicy{Recreate: true, Adopt: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []server.Drift{
+		{Object: "KeyringKey", ID: "key0", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+		{Object: "KeyringKey", ID: "key1", Kind: server.DriftUnknownToBridge, Resolution: "adopted"},
+	}
+	if !reflect.DeepEqual(drifts, want) {
+		t.Error("expected drifts", want, "received", drifts)
+	}
+	if key := s.Keys["key1"]; key == nil || key.Path != "/etc/opi/key1" {
+		t.Error("expected key1 to be adopted, received", key)
+	}
+}
+
+func TestDhchap_Validate(t *testing.T) {
+	tests := map[string]struct {
+		cfg     Dhchap
+		wantErr bool
+	}{
+		"defaults":        {Dhchap{}, false},
+		"selected":        {Dhchap{Digests: []string{"sha384"}, Dhgroups: []string{"null", "ffdhe8192"}}, false},
+		"unknown digest":  {Dhchap{Digests: []string{"md5"}}, true},
+		"unknown dhgroup": {Dhchap{Dhgroups: []string{"ffdhe1024"}}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Error("expected error", tt.wantErr, "received", err)
+			}
+		})
+	}
+}
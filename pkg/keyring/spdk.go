@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package keyring manages the keys in the SPDK keyring which are referenced by
+// name from NVMe-oF subsystems and remote controllers. Keys are read by SPDK
+// from files, so the key material never passes through the bridge.
+package keyring
+
+// SPDK JSON-RPC structures which are used by the keyring and are not
+// provided by gospdk
+
+// keyringFileAddKeyParams are params of keyring_file_add_key
+type keyringFileAddKeyParams struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// keyringFileRemoveKeyParams are params of keyring_file_remove_key
+type keyringFileRemoveKeyParams struct {
+	Name string `json:"name"`
+}
+
+// keyringKey is an entry of keyring_get_keys result. Keys of the file
+// keyring only report their path, never the key material.
+type keyringKey struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Removed bool   `json:"removed"`
+}
@@ -6,25 +6,26 @@ package kvm
 
 import (
 	"context"
-	"log"
 	"path/filepath"
 
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
 )
 
 // CreateVirtioBlk creates a virtio-blk device and attaches it to QEMU instance
 func (s *Server) CreateVirtioBlk(ctx context.Context, in *pb.CreateVirtioBlkRequest) (*pb.VirtioBlk, error) {
 	out, err := s.Server.CreateVirtioBlk(ctx, in)
 	if err != nil {
-		log.Println("Error running cmd on opi-spdk bridge:", err)
+		logging.FromContext(ctx).Errorln("Error running cmd on opi-spdk bridge:", err)
 		return out, err
 	}
 
 	id := out.Id.Value
-	mon, err := newMonitor(s.qmpAddress, s.protocol, s.timeout, s.pollDevicePresenceStep)
+	mon, err := newMonitor(ctx, s.qmpAddress, s.protocol, s.timeout, s.pollDevicePresenceStep)
 	if err != nil {
-		log.Println("Couldn't create QEMU monitor")
+		logging.FromContext(ctx).Errorln("Couldn't create QEMU monitor")
 		_, _ = s.Server.DeleteVirtioBlk(context.Background(), &pb.DeleteVirtioBlkRequest{Name: id})
 		return nil, errMonitorCreation
 	}
@@ -32,15 +33,15 @@ func (s *Server) CreateVirtioBlk(ctx context.Context, in *pb.CreateVirtioBlkRequ
 
 	ctrlr := filepath.Join(s.ctrlrDir, id)
 	chardevID := out.Id.Value
-	if err := mon.AddChardev(chardevID, ctrlr); err != nil {
-		log.Println("Couldn't add chardev:", err)
+	if err := mon.AddChardev(ctx, chardevID, ctrlr); err != nil {
+		logging.FromContext(ctx).Errorln("Couldn't add chardev:", err)
 		_, _ = s.Server.DeleteVirtioBlk(context.Background(), &pb.DeleteVirtioBlkRequest{Name: id})
 		return nil, errAddChardevFailed
 	}
 
-	if err = mon.AddVirtioBlkDevice(id, id); err != nil {
-		log.Println("Couldn't add device:", err)
-		_ = mon.DeleteChardev(id)
+	if err = mon.AddVirtioBlkDevice(ctx, id, id, s.Server.VirtioBlkQueues(id)); err != nil {
+		logging.FromContext(ctx).Errorln("Couldn't add device:", err)
+		_ = mon.DeleteChardev(ctx, id)
 		_, _ = s.Server.DeleteVirtioBlk(context.Background(), &pb.DeleteVirtioBlkRequest{Name: id})
 		return nil, errAddDeviceFailed
 	}
@@ -48,29 +49,77 @@ func (s *Server) CreateVirtioBlk(ctx context.Context, in *pb.CreateVirtioBlkRequ
 	return out, nil
 }
 
+// UpdateVirtioBlk detaches a virtio-blk device from QEMU instance while its
+// vhost controller is re-created and attaches it back. The device is
+// attached back also if the update fails, SPDK keeps the old controller then.
+func (s *Server) UpdateVirtioBlk(ctx context.Context, in *pb.UpdateVirtioBlkRequest) (*pb.VirtioBlk, error) {
+	if in.GetVirtioBlk().GetId().GetValue() == "" {
+		// rejected by the bridge, nothing to detach
+		return s.Server.UpdateVirtioBlk(ctx, in)
+	}
+	mon, err := newMonitor(ctx, s.qmpAddress, s.protocol, s.timeout, s.pollDevicePresenceStep)
+	if err != nil {
+		logging.FromContext(ctx).Errorln("Couldn't create QEMU monitor")
+		return nil, errMonitorCreation
+	}
+	defer mon.Disconnect()
+
+	id := in.VirtioBlk.Id.Value
+	if err := mon.DeleteVirtioBlkDevice(ctx, id); err != nil {
+		logging.FromContext(ctx).Errorf("Couldn't delete virtio-blk: %v", err)
+		return nil, errDeviceNotDeleted
+	}
+	if err := mon.DeleteChardev(ctx, id); err != nil {
+		logging.FromContext(ctx).Errorf("Couldn't delete chardev for virtio-blk: %v", err)
+		if err := mon.AddVirtioBlkDevice(ctx, id, id, s.Server.VirtioBlkQueues(id)); err != nil {
+			logging.FromContext(ctx).Errorln("Couldn't add device:", err)
+			return nil, errAddDeviceFailed
+		}
+		return nil, errDeviceNotDeleted
+	}
+
+	out, updateErr := s.Server.UpdateVirtioBlk(ctx, in)
+	if updateErr != nil {
+		logging.FromContext(ctx).Errorln("Error running cmd on opi-spdk bridge:", updateErr)
+	}
+
+	ctrlr := filepath.Join(s.ctrlrDir, id)
+	if err := mon.AddChardev(ctx, id, ctrlr); err != nil {
+		logging.FromContext(ctx).Errorln("Couldn't add chardev:", err)
+		return nil, errAddChardevFailed
+	}
+	if err := mon.AddVirtioBlkDevice(ctx, id, id, s.Server.VirtioBlkQueues(id)); err != nil {
+		logging.FromContext(ctx).Errorln("Couldn't add device:", err)
+		_ = mon.DeleteChardev(ctx, id)
+		return nil, errAddDeviceFailed
+	}
+
+	return out, updateErr
+}
+
 // DeleteVirtioBlk deletes a virtio-blk device and detaches it from QEMU instance
 func (s *Server) DeleteVirtioBlk(ctx context.Context, in *pb.DeleteVirtioBlkRequest) (*emptypb.Empty, error) {
-	mon, monErr := newMonitor(s.qmpAddress, s.protocol, s.timeout, s.pollDevicePresenceStep)
+	mon, monErr := newMonitor(ctx, s.qmpAddress, s.protocol, s.timeout, s.pollDevicePresenceStep)
 	if monErr != nil {
-		log.Println("Couldn't create QEMU monitor")
+		logging.FromContext(ctx).Errorln("Couldn't create QEMU monitor")
 		return nil, errMonitorCreation
 	}
 	defer mon.Disconnect()
 
 	id := in.Name
-	delDevErr := mon.DeleteVirtioBlkDevice(id)
+	delDevErr := mon.DeleteVirtioBlkDevice(ctx, id)
 	if delDevErr != nil {
-		log.Printf("Couldn't delete virtio-blk: %v", delDevErr)
+		logging.FromContext(ctx).Errorf("Couldn't delete virtio-blk: %v", delDevErr)
 	}
 
-	delChardevErr := mon.DeleteChardev(id)
+	delChardevErr := mon.DeleteChardev(ctx, id)
 	if delChardevErr != nil {
-		log.Printf("Couldn't delete chardev for virtio-blk: %v. Device is partially deleted", delChardevErr)
+		logging.FromContext(ctx).Errorf("Couldn't delete chardev for virtio-blk: %v. Device is partially deleted", delChardevErr)
 	}
 
 	response, spdkErr := s.Server.DeleteVirtioBlk(ctx, in)
 	if spdkErr != nil {
-		log.Println("Error running underlying cmd on opi-spdk bridge:", spdkErr)
+		logging.FromContext(ctx).Errorln("Error running underlying cmd on opi-spdk bridge:", spdkErr)
 	}
 
 	var err error
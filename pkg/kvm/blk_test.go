@@ -16,6 +16,8 @@ import (
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
 	"github.com/ulule/deepcopier"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -28,6 +30,10 @@ var (
 		MaxIoQps: 1,
 	}}
 	testDeleteVirtioBlkRequest = &pb.DeleteVirtioBlkRequest{Name: testVirtioBlkID}
+	testUpdateVirtioBlkRequest = &pb.UpdateVirtioBlkRequest{VirtioBlk: &pb.VirtioBlk{
+		Id:       &pc.ObjectKey{Value: testVirtioBlkID},
+		VolumeId: &pc.ObjectKey{Value: "Malloc43"},
+	}}
 )
 
 func TestCreateVirtioBlk(t *testing.T) {
@@ -106,6 +112,103 @@ func TestCreateVirtioBlk(t *testing.T) {
 	}
 }
 
+func TestUpdateVirtioBlk(t *testing.T) {
+	tests := map[string]struct {
+		jsonRPC              spdk.JSONRPC
+		expectError          error
+		nonDefaultQmpAddress string
+		in                   *pb.UpdateVirtioBlkRequest
+
+		mockQmpCalls *mockQmpCalls
+	}{
+		"valid virtio-blk update": {
+			jsonRPC: alwaysSuccessfulJSONRPC,
+			mockQmpCalls: newMockQmpCalls().
+				ExpectDeleteVirtioBlkWithEvent(testVirtioBlkID).
+				ExpectDeleteChardev(testVirtioBlkID).
+				ExpectAddChardev(testVirtioBlkID).
+				ExpectAddVirtioBlk(testVirtioBlkID, testVirtioBlkID).
+				ExpectQueryPci(testVirtioBlkID),
+		},
+		"spdk failed to update virtio-blk": {
+			jsonRPC:     alwaysFailingJSONRPC,
+			expectError: errStub,
+			mockQmpCalls: newMockQmpCalls().
+				ExpectDeleteVirtioBlkWithEvent(testVirtioBlkID).
+				ExpectDeleteChardev(testVirtioBlkID).
+				ExpectAddChardev(testVirtioBlkID).
+				ExpectAddVirtioBlk(testVirtioBlkID, testVirtioBlkID).
+				ExpectQueryPci(testVirtioBlkID),
+		},
+		"qemu device delete failed": {
+			jsonRPC:     alwaysSuccessfulJSONRPC,
+			expectError: errDeviceNotDeleted,
+			mockQmpCalls: newMockQmpCalls().
+				ExpectDeleteVirtioBlk(testVirtioBlkID).WithErrorResponse(),
+		},
+		"qemu chardev delete failed": {
+			jsonRPC:     alwaysSuccessfulJSONRPC,
+			expectError: errDeviceNotDeleted,
+			mockQmpCalls: newMockQmpCalls().
+				ExpectDeleteVirtioBlkWithEvent(testVirtioBlkID).
+				ExpectDeleteChardev(testVirtioBlkID).WithErrorResponse().
+				ExpectAddVirtioBlk(testVirtioBlkID, testVirtioBlkID).
+				ExpectQueryPci(testVirtioBlkID),
+		},
+		"qemu device add failed": {
+			jsonRPC:     alwaysSuccessfulJSONRPC,
+			expectError: errAddDeviceFailed,
+			mockQmpCalls: newMockQmpCalls().
+				ExpectDeleteVirtioBlkWithEvent(testVirtioBlkID).
+				ExpectDeleteChardev(testVirtioBlkID).
+				ExpectAddChardev(testVirtioBlkID).
+				ExpectAddVirtioBlk(testVirtioBlkID, testVirtioBlkID).WithErrorResponse().
+				ExpectDeleteChardev(testVirtioBlkID),
+		},
+		"failed to create monitor": {
+			nonDefaultQmpAddress: "/dev/null",
+			jsonRPC:              alwaysSuccessfulJSONRPC,
+			expectError:          errMonitorCreation,
+		},
+		"missing virtio-blk": {
+			jsonRPC:      alwaysSuccessfulJSONRPC,
+			expectError:  status.Error(codes.InvalidArgument, "virtio_blk.id cannot be empty"),
+			in:           &pb.UpdateVirtioBlkRequest{},
+			mockQmpCalls: newMockQmpCalls(),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			opiSpdkServer := frontend.NewServer(test.jsonRPC)
+			opiSpdkServer.Virt.BlkCtrls[testVirtioBlkID] = testCreateVirtioBlkRequest.VirtioBlk
+			qmpServer := startMockQmpServer(t, test.mockQmpCalls)
+			defer qmpServer.Stop()
+			qmpAddress := qmpServer.socketPath
+			if test.nonDefaultQmpAddress != "" {
+				qmpAddress = test.nonDefaultQmpAddress
+			}
+			kvmServer := NewServer(opiSpdkServer, qmpAddress, qmpServer.testDir)
+			kvmServer.timeout = qmplibTimeout
+
+			in := testUpdateVirtioBlkRequest
+			if test.in != nil {
+				in = test.in
+			}
+			out, err := kvmServer.UpdateVirtioBlk(context.Background(), in)
+			if !errors.Is(err, test.expectError) {
+				t.Errorf("Expected error %v, got %v", test.expectError, err)
+			}
+			if test.expectError == nil && out.GetVolumeId().GetValue() != "Malloc43" {
+				t.Errorf("Expected volume Malloc43, got %v", out)
+			}
+			if !qmpServer.WereExpectedCallsPerformed() {
+				t.Errorf("Not all expected calls were performed")
+			}
+		})
+	}
+}
+
 func TestDeleteVirtioBlk(t *testing.T) {
 	tests := map[string]struct {
 		jsonRPC              spdk.JSONRPC
@@ -21,6 +21,12 @@ const (
 	unixSocketProtocol = "unix"
 )
 
+// Default timeouts used to wait for QEMU to add or remove a device
+const (
+	DefaultTimeout                = 2 * time.Second
+	DefaultPollDevicePresenceStep = 5 * time.Millisecond
+)
+
 var (
 	errAddChardevFailed       = status.Error(codes.FailedPrecondition, "couldn't add chardev")
 	errMonitorCreation        = status.Error(codes.Internal, "failed to create QEMU monitor")
@@ -62,9 +68,24 @@ func NewServer(s *frontend.Server, qmpAddress string, ctrlrDir string) *Server {
 		log.Fatalf(err.Error())
 	}
 
-	timeout := 2 * time.Second
-	pollDevicePresenceStep := 5 * time.Millisecond
-	return &Server{s, qmpAddress, ctrlrDir, qmpProtocol, timeout, pollDevicePresenceStep}
+	// only vfio-user controllers are plugged into the QEMU instance
+	s.RegisterSubsystemListener(NewVfiouserSubsystemListener(ctrlrDir))
+
+	return &Server{s, qmpAddress, ctrlrDir, qmpProtocol, DefaultTimeout, DefaultPollDevicePresenceStep}
+}
+
+// SetTimeouts changes how long the server waits for QEMU to add or remove
+// a device and how often the device presence is checked meanwhile
+func (s *Server) SetTimeouts(timeout time.Duration, pollDevicePresenceStep time.Duration) error {
+	if timeout <= 0 || pollDevicePresenceStep <= 0 {
+		return fmt.Errorf("timeouts must be positive, got %v and %v", timeout, pollDevicePresenceStep)
+	}
+	if pollDevicePresenceStep >= timeout {
+		return fmt.Errorf("poll step %v must be less than timeout %v", pollDevicePresenceStep, timeout)
+	}
+	s.timeout = timeout
+	s.pollDevicePresenceStep = pollDevicePresenceStep
+	return nil
 }
 
 func getProtocol(qmpAddress string) (string, error) {
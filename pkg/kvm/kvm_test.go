@@ -342,3 +342,51 @@ func (s *mockQmpServer) read(conn net.Conn) string {
 	log.Println("QMP server got :", data)
 	return data
 }
+
+func TestSetTimeouts(t *testing.T) {
+	tests := map[string]struct {
+		timeout  time.Duration
+		pollStep time.Duration
+		wantErr  bool
+	}{
+		"valid timeouts": {
+			timeout:  time.Second,
+			pollStep: 10 * time.Millisecond,
+			wantErr:  false,
+		},
+		"zero timeout": {
+			timeout:  0,
+			pollStep: 10 * time.Millisecond,
+			wantErr:  true,
+		},
+		"negative poll step": {
+			timeout:  time.Second,
+			pollStep: -time.Millisecond,
+			wantErr:  true,
+		},
+		"poll step not less than timeout": {
+			timeout:  time.Second,
+			pollStep: time.Second,
+			wantErr:  true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			kvmServer := &Server{timeout: DefaultTimeout, pollDevicePresenceStep: DefaultPollDevicePresenceStep}
+
+			err := kvmServer.SetTimeouts(test.timeout, test.pollStep)
+			if (err != nil) != test.wantErr {
+				t.Errorf("Expected error %v, received: %v", test.wantErr, err)
+			}
+			wantTimeout, wantPollStep := test.timeout, test.pollStep
+			if test.wantErr {
+				wantTimeout, wantPollStep = DefaultTimeout, DefaultPollDevicePresenceStep
+			}
+			if kvmServer.timeout != wantTimeout || kvmServer.pollDevicePresenceStep != wantPollStep {
+				t.Errorf("Expected timeouts %v/%v, received: %v/%v", wantTimeout, wantPollStep,
+					kvmServer.timeout, kvmServer.pollDevicePresenceStep)
+			}
+		})
+	}
+}
@@ -15,6 +15,11 @@ import (
 
 	"github.com/digitalocean/go-qemu/qmp"
 	qmpraw "github.com/digitalocean/go-qemu/qmp/raw"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 )
 
 // TODO: check for device existence to provide idempotence in all methods
@@ -28,16 +33,16 @@ type monitor struct {
 	pollDevicePresenceStep    time.Duration
 }
 
-func newMonitor(qmpAddress string, protocol string,
+func newMonitor(ctx context.Context, qmpAddress string, protocol string,
 	timeout time.Duration, pollDevicePresenceStep time.Duration) (*monitor, error) {
 	mon, err := qmp.NewSocketMonitor(protocol, qmpAddress, timeout)
 	if err != nil {
-		log.Printf("couldn't create QEMU monitor: %v", err)
+		logging.FromContext(ctx).Errorf("couldn't create QEMU monitor: %v", err)
 		return nil, err
 	}
 
 	if err := mon.Connect(); err != nil {
-		log.Printf("Failed to connect to QEMU: %v", err)
+		logging.FromContext(ctx).Errorf("Failed to connect to QEMU: %v", err)
 		return nil, err
 	}
 
@@ -57,37 +62,44 @@ func (m *monitor) Disconnect() {
 	}
 }
 
-func (m *monitor) AddChardev(id string, sockPath string) error {
+func (m *monitor) AddChardev(ctx context.Context, id string, sockPath string) (err error) {
+	_, span := startQmpSpan(ctx, "chardev-add", id)
+	defer func() { tracing.End(span, err) }()
+
 	server := false
 	socketBackend := qmpraw.ChardevBackendSocket{
 		Addr: qmpraw.SocketAddressLegacyUnix{
 			Path: sockPath},
 		Server: &server}
-	_, err := m.rmon.ChardevAdd(id, socketBackend)
+	_, err = m.rmon.ChardevAdd(id, socketBackend)
 	return err
 }
 
-func (m *monitor) DeleteChardev(id string) error {
+func (m *monitor) DeleteChardev(ctx context.Context, id string) (err error) {
+	_, span := startQmpSpan(ctx, "chardev-remove", id)
+	defer func() { tracing.End(span, err) }()
 	return m.rmon.ChardevRemove(id)
 }
 
-func (m *monitor) AddVirtioBlkDevice(id string, chardevID string) error {
+func (m *monitor) AddVirtioBlkDevice(ctx context.Context, id string, chardevID string, numQueues int) error {
 	qmpCmd := struct {
-		Driver  string  `json:"driver"`
-		ID      *string `json:"id,omitempty"`
-		Chardev *string `json:"chardev,omitempty"`
+		Driver    string  `json:"driver"`
+		ID        *string `json:"id,omitempty"`
+		Chardev   *string `json:"chardev,omitempty"`
+		NumQueues int     `json:"num-queues,omitempty"`
 	}{
-		Driver:  "vhost-user-blk-pci",
-		ID:      &id,
-		Chardev: &chardevID,
+		Driver:    "vhost-user-blk-pci",
+		ID:        &id,
+		Chardev:   &chardevID,
+		NumQueues: numQueues,
 	}
-	if err := m.addDevice(qmpCmd); err != nil {
+	if err := m.addDevice(ctx, id, qmpCmd); err != nil {
 		return err
 	}
-	return m.waitForDeviceExist(id)
+	return m.waitForDeviceExist(ctx, id)
 }
 
-func (m *monitor) AddNvmeControllerDevice(id string, ctrlrDir string) error {
+func (m *monitor) AddNvmeControllerDevice(ctx context.Context, id string, ctrlrDir string) error {
 	socket := filepath.Join(ctrlrDir, "cntrl")
 	qmpCmd := struct {
 		Driver string  `json:"driver"`
@@ -98,46 +110,55 @@ func (m *monitor) AddNvmeControllerDevice(id string, ctrlrDir string) error {
 		ID:     &id,
 		Socket: &socket,
 	}
-	if err := m.addDevice(qmpCmd); err != nil {
+	if err := m.addDevice(ctx, id, qmpCmd); err != nil {
 		return err
 	}
-	return m.waitForDeviceExist(id)
+	return m.waitForDeviceExist(ctx, id)
 }
 
-func (m *monitor) DeleteVirtioBlkDevice(id string) error {
-	err := m.rmon.DeviceDel(id)
+func (m *monitor) DeleteVirtioBlkDevice(ctx context.Context, id string) error {
+	err := m.deleteDevice(ctx, id)
 	if err != nil {
 		return fmt.Errorf("couldn't delete device: %w", err)
 	}
-	return m.waitForEvent("DEVICE_DELETED", "device", id)
+	return m.waitForEvent(ctx, "DEVICE_DELETED", "device", id)
 }
 
-func (m *monitor) DeleteNvmeControllerDevice(id string) error {
-	if err := m.rmon.DeviceDel(id); err != nil {
+func (m *monitor) DeleteNvmeControllerDevice(ctx context.Context, id string) error {
+	if err := m.deleteDevice(ctx, id); err != nil {
 		return err
 	}
-	return m.waitForDeviceNotExist(id)
+	return m.waitForDeviceNotExist(ctx, id)
 }
 
-func (m *monitor) addDevice(qmpCmd interface{}) error {
+func (m *monitor) deleteDevice(ctx context.Context, id string) (err error) {
+	_, span := startQmpSpan(ctx, "device_del", id)
+	defer func() { tracing.End(span, err) }()
+	return m.rmon.DeviceDel(id)
+}
+
+func (m *monitor) addDevice(ctx context.Context, id string, qmpCmd interface{}) (err error) {
+	_, span := startQmpSpan(ctx, "device_add", id)
+	defer func() { tracing.End(span, err) }()
+
 	bs, err := json.Marshal(map[string]interface{}{
 		"execute":   "device_add",
 		"arguments": qmpCmd,
 	})
 	if err != nil {
-		log.Println("json marshalling error:", err)
+		logging.FromContext(ctx).Errorln("json marshalling error:", err)
 		return fmt.Errorf("couldn't create QMP command: %w", err)
 	}
 
-	log.Println("QMP command to send: ", string(bs))
+	logging.FromContext(ctx).Infoln("QMP command to send: ", string(bs))
 	raw, err := m.mon.Run(bs)
 	if err != nil {
-		log.Println("QMP error:", err)
+		logging.FromContext(ctx).Errorln("QMP error:", err)
 		return fmt.Errorf("couldn't run QMP command: %w", err)
 	}
 
 	response := string(raw)
-	log.Println("QMP response:", response)
+	logging.FromContext(ctx).Infoln("QMP response:", response)
 	if strings.Contains(response, "error") {
 		return fmt.Errorf("qemu cmd run error: %v", string(bs))
 	}
@@ -145,7 +166,11 @@ func (m *monitor) addDevice(qmpCmd interface{}) error {
 	return nil
 }
 
-func (m *monitor) waitForEvent(event string, key string, value string) error {
+func (m *monitor) waitForEvent(ctx context.Context, event string, key string, value string) (err error) {
+	_, span := tracing.Start(ctx, "qmp/wait_for_event",
+		attribute.String("qmp.event", event), attribute.String("qemu.device_id", value))
+	defer func() { tracing.End(span, err) }()
+
 	stream, err := m.mon.Events(context.Background())
 	if err != nil {
 		return fmt.Errorf("couldn't get event channel: %v", err)
@@ -155,7 +180,7 @@ func (m *monitor) waitForEvent(event string, key string, value string) error {
 	for {
 		select {
 		case e := <-stream:
-			log.Println("qemu event:", e)
+			logging.FromContext(ctx).Infoln("qemu event:", e)
 			if e.Event != event {
 				continue
 			}
@@ -170,24 +195,28 @@ func (m *monitor) waitForEvent(event string, key string, value string) error {
 			if val != value {
 				continue
 			}
-			log.Println("Event:", event, "found")
+			logging.FromContext(ctx).Infoln("Event:", event, "found")
 			return nil
 		case <-timeoutTimer.C:
-			log.Println("Event timeout:", event, ", key:", key, "value:", value)
+			logging.FromContext(ctx).Errorln("Event timeout:", event, ", key:", key, "value:", value)
 			return fmt.Errorf("qemu event not found: %v", event)
 		}
 	}
 }
 
-func (m *monitor) waitForDeviceExist(id string) error {
-	return m.waitForDevicePresence(id, true)
+func (m *monitor) waitForDeviceExist(ctx context.Context, id string) error {
+	return m.waitForDevicePresence(ctx, id, true)
 }
 
-func (m *monitor) waitForDeviceNotExist(id string) error {
-	return m.waitForDevicePresence(id, false)
+func (m *monitor) waitForDeviceNotExist(ctx context.Context, id string) error {
+	return m.waitForDevicePresence(ctx, id, false)
 }
 
-func (m *monitor) waitForDevicePresence(id string, shouldExist bool) error {
+func (m *monitor) waitForDevicePresence(ctx context.Context, id string, shouldExist bool) (err error) {
+	ctx, span := tracing.Start(ctx, "qmp/wait_for_device_presence",
+		attribute.String("qemu.device_id", id), attribute.Bool("qemu.device_should_exist", shouldExist))
+	defer func() { tracing.End(span, err) }()
+
 	timeoutTimer := time.NewTimer(m.pollDevicePresenceTimeout)
 	devicePresenceTicker := time.NewTicker(m.pollDevicePresenceStep)
 	defer devicePresenceTicker.Stop()
@@ -196,9 +225,9 @@ func (m *monitor) waitForDevicePresence(id string, shouldExist bool) error {
 		case <-timeoutTimer.C:
 			return fmt.Errorf("timeout waiting for PCI device %v presence %v", id, shouldExist)
 		case <-devicePresenceTicker.C:
-			exist, err := m.pciDeviceExist(id)
+			exist, err := m.pciDeviceExist(ctx, id)
 			if err != nil {
-				log.Println("failed to check pci device existence:", err)
+				logging.FromContext(ctx).Errorln("failed to check pci device existence:", err)
 				continue
 			}
 			if exist != shouldExist {
@@ -209,8 +238,10 @@ func (m *monitor) waitForDevicePresence(id string, shouldExist bool) error {
 	}
 }
 
-func (m *monitor) pciDeviceExist(id string) (bool, error) {
+func (m *monitor) pciDeviceExist(ctx context.Context, id string) (bool, error) {
+	_, span := startQmpSpan(ctx, "query-pci", id)
 	pciDevs, err := m.rmon.QueryPCI()
+	tracing.End(span, err)
 	if err != nil {
 		return false, err
 	}
@@ -224,3 +255,9 @@ func (m *monitor) pciDeviceExist(id string) (bool, error) {
 	}
 	return false, nil
 }
+
+// startQmpSpan starts a span of a QMP command run for the device with id
+func startQmpSpan(ctx context.Context, command string, id string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "qmp/"+command,
+		attribute.String("qmp.command", command), attribute.String("qemu.device_id", id))
+}
@@ -11,11 +11,14 @@ import (
 	"os"
 	"path/filepath"
 
+	"go.opentelemetry.io/otel/attribute"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/opiproject/gospdk/spdk"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 )
 
 type vfiouserSubsystemListener struct {
@@ -41,45 +44,55 @@ func NewVfiouserSubsystemListener(ctrlrDir string) frontend.SubsystemListener {
 	}
 }
 
+func (c *vfiouserSubsystemListener) Transport() string {
+	return frontend.TransportVfioUser
+}
+
 func (c *vfiouserSubsystemListener) Params(ctrlr *pb.NVMeController, nqn string) spdk.NvmfSubsystemAddListenerParams {
 	result := spdk.NvmfSubsystemAddListenerParams{}
 	ctrlrDirPath := controllerDirPath(c.ctrlrDir, ctrlr.Spec.Id.Value)
 	result.Nqn = nqn
-	result.ListenAddress.Trtype = "vfiouser"
+	result.ListenAddress.Trtype = frontend.TransportVfioUser
 	result.ListenAddress.Traddr = ctrlrDirPath
 
 	return result
 }
 
-// CreateNVMeController creates an NVMe controller device and attaches it to QEMU instance
+// CreateNVMeController creates an NVMe controller device and attaches it to QEMU instance.
+// Controllers of other transports than vfio-user are only created in SPDK.
 func (s *Server) CreateNVMeController(ctx context.Context, in *pb.CreateNVMeControllerRequest) (*pb.NVMeController, error) {
 	id := in.NvMeController.Spec.Id.Value
-	err := createControllerDir(s.ctrlrDir, id)
+	if s.Server.ControllerTransport(id) != frontend.TransportVfioUser {
+		return s.Server.CreateNVMeController(ctx, in)
+	}
+	_, span := tracing.Start(ctx, "kvm/create_controller_dir", attribute.String("qemu.device_id", id))
+	err := createControllerDir(ctx, s.ctrlrDir, id)
+	tracing.End(span, err)
 	if err != nil {
-		log.Print(err)
+		logging.FromContext(ctx).Error(err)
 		return nil, errFailedToCreateNvmeDir
 	}
 
 	out, err := s.Server.CreateNVMeController(ctx, in)
 	if err != nil {
-		log.Println("Error running cmd on opi-spdk bridge:", err)
-		_ = deleteControllerDir(s.ctrlrDir, id)
+		logging.FromContext(ctx).Errorln("Error running cmd on opi-spdk bridge:", err)
+		_ = deleteControllerDir(ctx, s.ctrlrDir, id)
 		return out, err
 	}
 
-	mon, monErr := newMonitor(s.qmpAddress, s.protocol, s.timeout, s.pollDevicePresenceStep)
+	mon, monErr := newMonitor(ctx, s.qmpAddress, s.protocol, s.timeout, s.pollDevicePresenceStep)
 	if monErr != nil {
-		log.Println("Couldn't create QEMU monitor")
+		logging.FromContext(ctx).Errorln("Couldn't create QEMU monitor")
 		_, _ = s.Server.DeleteNVMeController(context.Background(), &pb.DeleteNVMeControllerRequest{Name: id})
-		_ = deleteControllerDir(s.ctrlrDir, id)
+		_ = deleteControllerDir(ctx, s.ctrlrDir, id)
 		return nil, errMonitorCreation
 	}
 	defer mon.Disconnect()
 
-	if err := mon.AddNvmeControllerDevice(id, controllerDirPath(s.ctrlrDir, id)); err != nil {
-		log.Println("Couldn't add NVMe controller:", err)
+	if err := mon.AddNvmeControllerDevice(ctx, id, controllerDirPath(s.ctrlrDir, id)); err != nil {
+		logging.FromContext(ctx).Errorln("Couldn't add NVMe controller:", err)
 		_, _ = s.Server.DeleteNVMeController(context.Background(), &pb.DeleteNVMeControllerRequest{Name: id})
-		_ = deleteControllerDir(s.ctrlrDir, id)
+		_ = deleteControllerDir(ctx, s.ctrlrDir, id)
 		return nil, errAddDeviceFailed
 	}
 	return out, nil
@@ -87,26 +100,29 @@ func (s *Server) CreateNVMeController(ctx context.Context, in *pb.CreateNVMeCont
 
 // DeleteNVMeController deletes an NVMe controller device and detaches it from QEMU instance
 func (s *Server) DeleteNVMeController(ctx context.Context, in *pb.DeleteNVMeControllerRequest) (*emptypb.Empty, error) {
-	mon, monErr := newMonitor(s.qmpAddress, s.protocol, s.timeout, s.pollDevicePresenceStep)
+	if s.Server.ControllerTransport(in.Name) != frontend.TransportVfioUser {
+		return s.Server.DeleteNVMeController(ctx, in)
+	}
+	mon, monErr := newMonitor(ctx, s.qmpAddress, s.protocol, s.timeout, s.pollDevicePresenceStep)
 	if monErr != nil {
-		log.Println("Couldn't create QEMU monitor")
+		logging.FromContext(ctx).Errorln("Couldn't create QEMU monitor")
 		return nil, errMonitorCreation
 	}
 	defer mon.Disconnect()
 
-	delNvmeErr := mon.DeleteNvmeControllerDevice(in.Name)
+	delNvmeErr := mon.DeleteNvmeControllerDevice(ctx, in.Name)
 	if delNvmeErr != nil {
-		log.Printf("Couldn't delete NVMe controller: %v", delNvmeErr)
+		logging.FromContext(ctx).Errorf("Couldn't delete NVMe controller: %v", delNvmeErr)
 	}
 
 	response, spdkErr := s.Server.DeleteNVMeController(ctx, in)
 	if spdkErr != nil {
-		log.Println("Error running underlying cmd on opi-spdk bridge:", spdkErr)
+		logging.FromContext(ctx).Errorln("Error running underlying cmd on opi-spdk bridge:", spdkErr)
 	}
 
-	delDirErr := deleteControllerDir(s.ctrlrDir, in.Name)
+	delDirErr := deleteControllerDir(ctx, s.ctrlrDir, in.Name)
 	if delDirErr != nil {
-		log.Println("Failed to delete NVMe controller directory:", delDirErr)
+		logging.FromContext(ctx).Errorln("Failed to delete NVMe controller directory:", delDirErr)
 	}
 
 	var err error
@@ -118,20 +134,20 @@ func (s *Server) DeleteNVMeController(ctx context.Context, in *pb.DeleteNVMeCont
 	return response, err
 }
 
-func createControllerDir(ctrlrDir string, ctrlrID string) error {
+func createControllerDir(ctx context.Context, ctrlrDir string, ctrlrID string) error {
 	ctrlrDirPath := controllerDirPath(ctrlrDir, ctrlrID)
-	log.Printf("Creating dir for %v NVMe controller: %v", ctrlrID, ctrlrDirPath)
+	logging.FromContext(ctx).Infof("Creating dir for %v NVMe controller: %v", ctrlrID, ctrlrDirPath)
 	if os.Mkdir(ctrlrDirPath, 0600) != nil {
 		return fmt.Errorf("cannot create controller directory %v", ctrlrDirPath)
 	}
 	return nil
 }
 
-func deleteControllerDir(ctrlrDir string, ctrlrID string) error {
+func deleteControllerDir(ctx context.Context, ctrlrDir string, ctrlrID string) error {
 	ctrlrDirPath := controllerDirPath(ctrlrDir, ctrlrID)
-	log.Printf("Deleting dir for %v NVMe controller: %v", ctrlrID, ctrlrDirPath)
+	logging.FromContext(ctx).Infof("Deleting dir for %v NVMe controller: %v", ctrlrID, ctrlrDirPath)
 	if _, err := os.Stat(ctrlrDirPath); os.IsNotExist(err) {
-		log.Printf("%v directory does not exist.", ctrlrDirPath)
+		logging.FromContext(ctx).Infof("%v directory does not exist.", ctrlrDirPath)
 		return nil
 	}
 
@@ -19,6 +19,11 @@ import (
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/opiproject/opi-spdk-bridge/pkg/frontend"
 	"github.com/ulule/deepcopier"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -182,6 +187,9 @@ func TestCreateNvmeController(t *testing.T) {
 			}
 			kvmServer := NewServer(opiSpdkServer, qmpAddress, qmpServer.testDir)
 			kvmServer.timeout = qmplibTimeout
+			if err := opiSpdkServer.SetDefaultTransport(frontend.TransportVfioUser); err != nil {
+				t.Fatal(err)
+			}
 			testCtrlrDir := filepath.Join(qmpServer.testDir, testCreateNvmeControllerRequest.NvMeController.Spec.Id.Value)
 			if test.ctrlrDirExistsBeforeOperation &&
 				os.Mkdir(testCtrlrDir, os.ModePerm) != nil {
@@ -208,6 +216,95 @@ func TestCreateNvmeController(t *testing.T) {
 	}
 }
 
+func TestCreateNvmeControllerSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	defer otel.SetTracerProvider(prevProvider)
+
+	opiSpdkServer := frontend.NewServer(alwaysSuccessfulJSONRPC)
+	opiSpdkServer.Nvme.Subsystems[testSubsystem.Spec.Id.Value] = &testSubsystem
+	qmpServer := startMockQmpServer(t, newMockQmpCalls().
+		ExpectAddNvmeController(testNvmeControllerID).
+		ExpectQueryPci(testNvmeControllerID))
+	defer qmpServer.Stop()
+	kvmServer := NewServer(opiSpdkServer, qmpServer.socketPath, qmpServer.testDir)
+	kvmServer.timeout = qmplibTimeout
+	if err := opiSpdkServer.SetDefaultTransport(frontend.TransportVfioUser); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, requestSpan := otel.Tracer("test").Start(context.Background(), "request")
+	if _, err := kvmServer.CreateNVMeController(ctx, testCreateNvmeControllerRequest); err != nil {
+		t.Fatal(err)
+	}
+	requestSpan.End()
+
+	wantParents := map[string]string{
+		"kvm/create_controller_dir":        "request",
+		"spdk/nvmf_subsystem_add_listener": "request",
+		"qmp/device_add":                   "request",
+		"qmp/wait_for_device_presence":     "request",
+		"qmp/query-pci":                    "qmp/wait_for_device_presence",
+	}
+	spans := exporter.GetSpans()
+	names := make(map[trace.SpanID]string, len(spans))
+	for _, span := range spans {
+		names[span.SpanContext.SpanID()] = span.Name
+	}
+	for _, span := range spans {
+		if span.Name == "request" {
+			continue
+		}
+		wantParent, ok := wantParents[span.Name]
+		if !ok {
+			t.Errorf("Unexpected span %v", span.Name)
+			continue
+		}
+		delete(wantParents, span.Name)
+		if parent := names[span.Parent.SpanID()]; parent != wantParent {
+			t.Errorf("Expected span %v to be a child of %v, got %v", span.Name, wantParent, parent)
+		}
+		if span.Status.Code != codes.Unset {
+			t.Errorf("Expected span %v to succeed, got %v", span.Name, span.Status)
+		}
+	}
+	for name := range wantParents {
+		t.Errorf("Expected span %v was not recorded", name)
+	}
+}
+
+func TestNvmeControllerOverTcpIsNotPlugged(t *testing.T) {
+	opiSpdkServer := frontend.NewServer(alwaysSuccessfulJSONRPC)
+	opiSpdkServer.Nvme.Subsystems[testSubsystem.Spec.Id.Value] = &testSubsystem
+	qmpServer := startMockQmpServer(t, nil)
+	defer qmpServer.Stop()
+	kvmServer := NewServer(opiSpdkServer, qmpServer.socketPath, qmpServer.testDir)
+	kvmServer.timeout = qmplibTimeout
+	request := &pb.CreateNVMeControllerRequest{NvMeController: &pb.NVMeController{
+		Spec: &pb.NVMeControllerSpec{
+			Id:          &pc.ObjectKey{Value: "nvme-tcp"},
+			SubsystemId: testSubsystem.Spec.Id,
+		},
+	}}
+
+	if _, err := kvmServer.CreateNVMeController(context.Background(), request); err != nil {
+		t.Fatal(err)
+	}
+	if dirExists(filepath.Join(qmpServer.testDir, "nvme-tcp")) {
+		t.Error("expected no controller dir for tcp controller")
+	}
+	if transport := opiSpdkServer.ControllerTransport("nvme-tcp"); transport != frontend.TransportTCP {
+		t.Error("expected tcp transport, received", transport)
+	}
+	if _, err := kvmServer.DeleteNVMeController(context.Background(), &pb.DeleteNVMeControllerRequest{Name: "nvme-tcp"}); err != nil {
+		t.Error(err)
+	}
+	if !qmpServer.WereExpectedCallsPerformed() {
+		t.Errorf("Not all expected calls were performed")
+	}
+}
+
 func TestDeleteNvmeController(t *testing.T) {
 	tests := map[string]struct {
 		jsonRPC              spdk.JSONRPC
@@ -301,6 +398,9 @@ func TestDeleteNvmeController(t *testing.T) {
 			}
 			kvmServer := NewServer(opiSpdkServer, qmpAddress, qmpServer.testDir)
 			kvmServer.timeout = qmplibTimeout
+			if err := opiSpdkServer.SetDefaultTransport(frontend.TransportVfioUser); err != nil {
+				t.Fatal(err)
+			}
 			testCtrlrDir := filepath.Join(qmpServer.testDir, testNvmeControllerID)
 			if test.ctrlrDirExistsBeforeOperation {
 				if err := os.Mkdir(testCtrlrDir, os.ModePerm); err != nil {
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package logging implements structured, leveled logging with request
+// correlation IDs and redaction of secrets
+package logging
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// CorrelationIDKey is the gRPC metadata key of the correlation ID of a request.
+// A new ID is generated if the client does not send one, the ID is sent back
+// in the response header.
+const CorrelationIDKey = "x-correlation-id"
+
+// maxCorrelationIDLength limits IDs sent by clients to keep log records short
+const maxCorrelationIDLength = 128
+
+// UnaryServerInterceptor passes a logger with the correlation ID of the
+// request to the handler in its context and logs the result of the request
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, logger := newRequestContext(ctx, info.FullMethod)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logResult(logger, start, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor passes a logger with the correlation ID of the
+// request to the handler in the stream context and logs the result of the stream
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, logger := newRequestContext(ss.Context(), info.FullMethod)
+		start := time.Now()
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logResult(logger, start, err)
+		return err
+	}
+}
+
+// CorrelationID returns the correlation ID of the request ctx belongs to
+func CorrelationID(ctx context.Context) string {
+	id, _ := FromContext(ctx).Data["correlation_id"].(string)
+	return id
+}
+
+func newRequestContext(ctx context.Context, method string) (context.Context, *logrus.Entry) {
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(CorrelationIDKey); len(values) > 0 && len(values[0]) <= maxCorrelationIDLength {
+			id = values[0]
+		}
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+	// fails only outside of a gRPC server, e.g. in tests
+	_ = grpc.SetHeader(ctx, metadata.Pairs(CorrelationIDKey, id))
+
+	logger := FromContext(ctx).WithFields(logrus.Fields{
+		"correlation_id": id,
+		"grpc_method":    method,
+	})
+	return NewContext(ctx, logger), logger
+}
+
+func logResult(logger *logrus.Entry, start time.Time, err error) {
+	logger = logger.WithFields(logrus.Fields{
+		"grpc_code":   status.Code(err).String(),
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("Request failed")
+		return
+	}
+	logger.Info("Request finished")
+}
+
+// serverStream replaces the context of the wrapped stream
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package logging implements structured, leveled logging with request
+// correlation IDs and redaction of secrets
+package logging
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	method := "/opi_api.storage.v1.FrontendNvmeService/CreateNVMeSubsystem"
+	tests := map[string]struct {
+		md        metadata.MD
+		handleErr error
+		wantID    string
+	}{
+		"id from client": {
+			metadata.Pairs(CorrelationIDKey, "client-id-1"),
+			nil,
+			"client-id-1",
+		},
+		"id from client for failed request": {
+			metadata.Pairs(CorrelationIDKey, "client-id-2"),
+			errors.New("SPDK is not running"),
+			"client-id-2",
+		},
+		"generated id": {
+			nil,
+			nil,
+			"",
+		},
+		"too long id is replaced": {
+			metadata.Pairs(CorrelationIDKey, strings.Repeat("a", maxCorrelationIDLength+1)),
+			nil,
+			"",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			var handlerID string
+			var handlerMethod interface{}
+			handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+				handlerID = CorrelationID(ctx)
+				handlerMethod = FromContext(ctx).Data["grpc_method"]
+				return nil, tt.handleErr
+			}
+
+			_, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+			if !errors.Is(err, tt.handleErr) {
+				t.Error("expected error", tt.handleErr, "received", err)
+			}
+
+			if tt.wantID != "" && handlerID != tt.wantID {
+				t.Error("expected correlation ID", tt.wantID, "received", handlerID)
+			}
+			if tt.wantID == "" {
+				if _, err := uuid.Parse(handlerID); err != nil {
+					t.Error("expected generated correlation ID, received", handlerID)
+				}
+			}
+			if handlerMethod != method {
+				t.Error("expected grpc_method", method, "received", handlerMethod)
+			}
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CorrelationIDKey, "stream-id"))
+	var handlerID string
+	handler := func(_ interface{}, ss grpc.ServerStream) error {
+		handlerID = CorrelationID(ss.Context())
+		return nil
+	}
+
+	err := StreamServerInterceptor()(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/test/Stream"}, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handlerID != "stream-id" {
+		t.Error("expected correlation ID stream-id, received", handlerID)
+	}
+}
+
+// fakeServerStream provides only the context of a stream
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package logging implements structured, leveled logging with request
+// correlation IDs and redaction of secrets
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Supported formats of log records
+const (
+	// FormatJSON writes every record as a JSON object on its own line
+	FormatJSON = "json"
+	// FormatText writes records as key=value pairs
+	FormatText = "text"
+)
+
+// Config defines which records are written and in which format
+type Config struct {
+	// Level is the lowest level of written records: debug, info, warning or error
+	Level string `yaml:"level"`
+	// Format is either json or text
+	Format string `yaml:"format"`
+}
+
+// Validate checks that level and format are known
+func (c Config) Validate() error {
+	if _, err := logrus.ParseLevel(c.Level); err != nil {
+		return err
+	}
+	if c.Format != FormatJSON && c.Format != FormatText {
+		return fmt.Errorf("unknown format %q, expected %q or %q", c.Format, FormatJSON, FormatText)
+	}
+	return nil
+}
+
+// Setup configures the standard logger according to cfg and redirects
+// the log package to it, so that all records share the same format
+func Setup(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	logger := logrus.StandardLogger()
+	level, _ := logrus.ParseLevel(cfg.Level)
+	logger.SetLevel(level)
+	if cfg.Format == FormatJSON {
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	}
+	log.SetFlags(0)
+	log.SetOutput(&stdlogWriter{logger: logger})
+	return nil
+}
+
+// secretParams matches crypto keys in the JSON params of SPDK calls,
+// which gospdk logs before sending them
+var secretParams = regexp.MustCompile(`"(key|key2)":\s*"[^"]*"`)
+
+// stdlogWriter turns lines written by the log package into records of logger.
+// Lines starting with "error" or "warning" get the matching level.
+type stdlogWriter struct {
+	logger *logrus.Logger
+}
+
+func (w *stdlogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	msg = secretParams.ReplaceAllString(msg, `"$1":"REDACTED"`)
+	level := logrus.InfoLevel
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.HasPrefix(lower, "error"):
+		level = logrus.ErrorLevel
+	case strings.HasPrefix(lower, "warn"):
+		level = logrus.WarnLevel
+	}
+	w.logger.Log(level, msg)
+	return len(p), nil
+}
+
+type loggerKey struct{}
+
+// NewContext returns ctx carrying logger, e.g. with the correlation ID of a request
+func NewContext(ctx context.Context, logger *logrus.Entry) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx or the standard logger
+// if there is none
+func FromContext(ctx context.Context) *logrus.Entry {
+	if logger, ok := ctx.Value(loggerKey{}).(*logrus.Entry); ok {
+		return logger
+	}
+	return logrus.NewEntry(logrus.StandardLogger())
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package logging implements structured, leveled logging with request
+// correlation IDs and redaction of secrets
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"json":           {Config{Level: "info", Format: FormatJSON}, false},
+		"text":           {Config{Level: "debug", Format: FormatText}, false},
+		"unknown level":  {Config{Level: "verbose", Format: FormatJSON}, true},
+		"unknown format": {Config{Level: "info", Format: "xml"}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Error("expected error", tt.wantErr, "received", err)
+			}
+		})
+	}
+}
+
+func TestStdlogWriter(t *testing.T) {
+	tests := map[string]struct {
+		line      string
+		wantLevel logrus.Level
+		wantMsg   string
+	}{
+		"info": {
+			"Server listening at [::]:50051\n",
+			logrus.InfoLevel,
+			"Server listening at [::]:50051",
+		},
+		"error": {
+			"error: failed to connect to SPDK\n",
+			logrus.ErrorLevel,
+			"error: failed to connect to SPDK",
+		},
+		"warning": {
+			"warning: sending plaintext\n",
+			logrus.WarnLevel,
+			"warning: sending plaintext",
+		},
+		"crypto keys sent to SPDK": {
+			`Sending to SPDK: {"jsonrpc":"2.0","method":"accel_crypto_key_create","params":{"cipher":"AES_XTS","key":"0123","key2": "4567","name":"crypto-test"}}` + "\n",
+			logrus.InfoLevel,
+			`Sending to SPDK: {"jsonrpc":"2.0","method":"accel_crypto_key_create","params":{"cipher":"AES_XTS","key":"REDACTED","key2":"REDACTED","name":"crypto-test"}}`,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			logger := logrus.New()
+			logger.SetFormatter(&logrus.JSONFormatter{})
+			var out bytes.Buffer
+			logger.SetOutput(&out)
+			var entry *logrus.Entry
+			logger.AddHook(&captureHook{entry: &entry})
+
+			w := &stdlogWriter{logger: logger}
+			if _, err := w.Write([]byte(tt.line)); err != nil {
+				t.Fatal(err)
+			}
+
+			if entry == nil {
+				t.Fatal("expected a record for", tt.line)
+			}
+			if entry.Level != tt.wantLevel {
+				t.Error("expected level", tt.wantLevel, "received", entry.Level)
+			}
+			if entry.Message != tt.wantMsg {
+				t.Errorf("expected message %q, received %q", tt.wantMsg, entry.Message)
+			}
+			if strings.Contains(out.String(), "0123") {
+				t.Error("expected no secret in", out.String())
+			}
+		})
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if logger := FromContext(context.Background()); logger.Logger != logrus.StandardLogger() {
+		t.Error("expected standard logger without a logger in context")
+	}
+
+	want := logrus.WithField("correlation_id", "abc")
+	if logger := FromContext(NewContext(context.Background(), want)); logger != want {
+		t.Error("expected logger of context, received", logger)
+	}
+}
+
+// captureHook stores the last record fired by a logger
+type captureHook struct {
+	entry **logrus.Entry
+}
+
+func (h *captureHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *captureHook) Fire(entry *logrus.Entry) error {
+	*h.entry = entry
+	return nil
+}
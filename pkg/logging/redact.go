@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package logging implements structured, leveled logging with request
+// correlation IDs and redaction of secrets
+package logging
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// isSecretField reports whether the value of the field must never be logged
+func isSecretField(fd protoreflect.FieldDescriptor) bool {
+	if opts, ok := fd.Options().(*descriptorpb.FieldOptions); ok && opts.GetDebugRedact() {
+		return true
+	}
+	switch fd.Name() {
+	case "key", "key2", "password", "secret", "psk", "token":
+		return true
+	default:
+		return false
+	}
+}
+
+// Redact returns a copy of msg with all secret fields cleared, e.g. keys
+// of encrypted volumes, also in nested messages
+func Redact(msg proto.Message) proto.Message {
+	if msg == nil {
+		return nil
+	}
+	msg = proto.Clone(msg)
+	redactMessage(msg.ProtoReflect())
+	return msg
+}
+
+func redactMessage(m protoreflect.Message) {
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case isSecretField(fd):
+			m.Clear(fd)
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				redactMessage(list.Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+				redactMessage(value.Message())
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			redactMessage(v.Message())
+		}
+		return true
+	})
+}
@@ -0,0 +1,64 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package logging implements structured, leveled logging with request
+// correlation IDs and redaction of secrets
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRedact(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	volume := &pb.EncryptedVolume{
+		EncryptedVolumeId: &pc.ObjectKey{Value: "crypto-test"},
+		VolumeId:          &pc.ObjectKey{Value: "volume-test"},
+		Key:               key,
+		Cipher:            pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128,
+	}
+	tests := map[string]struct {
+		in      proto.Message
+		wantKey func(proto.Message) []byte
+	}{
+		"encrypted volume": {
+			volume,
+			func(m proto.Message) []byte { return m.(*pb.EncryptedVolume).Key },
+		},
+		"nested in request": {
+			&pb.CreateEncryptedVolumeRequest{EncryptedVolume: volume},
+			func(m proto.Message) []byte { return m.(*pb.CreateEncryptedVolumeRequest).EncryptedVolume.Key },
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			redacted := Redact(tt.in)
+
+			if k := tt.wantKey(redacted); len(k) != 0 {
+				t.Error("expected key to be cleared, received", k)
+			}
+			if k := tt.wantKey(tt.in); string(k) != string(key) {
+				t.Error("expected original message to keep the key, received", k)
+			}
+			s := fmt.Sprintf("%v", redacted)
+			if strings.Contains(s, string(key)) {
+				t.Error("expected no key in", s)
+			}
+			if !strings.Contains(s, "crypto-test") {
+				t.Error("expected other fields to be kept in", s)
+			}
+		})
+	}
+}
+
+func TestRedact_Nil(t *testing.T) {
+	if msg := Redact(nil); msg != nil {
+		t.Error("expected nil, received", msg)
+	}
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package metrics exports Prometheus metrics of the bridge and SPDK
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "opi_spdk_bridge"
+
+// ObjectCounter is implemented by servers which manage objects
+type ObjectCounter interface {
+	// ObjectCounts returns the number of managed objects per type
+	ObjectCounts() map[string]int
+}
+
+// Metrics holds all metrics of the bridge in its own registry
+type Metrics struct {
+	registry *prometheus.Registry
+
+	grpcRequests *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+	spdkDuration *prometheus.HistogramVec
+	spdkFailures *prometheus.CounterVec
+
+	objects *objectsCollector
+	iostat  *iostatCollector
+}
+
+// New creates Metrics registered in a new registry together with
+// the Go runtime and process metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Number of handled gRPC requests by method and status code. Errors have code other than OK.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of handled gRPC requests by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		spdkDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "spdk",
+			Name:      "call_duration_seconds",
+			Help:      "Duration of SPDK JSON-RPC calls by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		spdkFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "spdk",
+			Name:      "call_failures_total",
+			Help:      "Number of failed SPDK JSON-RPC calls by method.",
+		}, []string{"method"}),
+		objects: newObjectsCollector(),
+		iostat:  newIostatCollector(),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.grpcRequests, m.grpcDuration, m.spdkDuration, m.spdkFailures,
+		m.objects, m.iostat,
+	)
+	return m
+}
+
+// Handler serves the metrics in Prometheus format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// CountObjects exports the number of objects managed by the servers
+func (m *Metrics) CountObjects(servers ...ObjectCounter) {
+	m.objects.mu.Lock()
+	defer m.objects.mu.Unlock()
+	m.objects.servers = append(m.objects.servers, servers...)
+}
+
+// objectsCollector asks the servers for their object counts on every scrape
+type objectsCollector struct {
+	desc *prometheus.Desc
+
+	mu      sync.Mutex
+	servers []ObjectCounter
+}
+
+func newObjectsCollector() *objectsCollector {
+	return &objectsCollector{
+		desc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "objects"),
+			"Number of objects managed by the bridge by type.", []string{"type"}, nil),
+	}
+}
+
+func (c *objectsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *objectsCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, s := range c.servers {
+		for objectType, count := range s.ObjectCounts() {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), objectType)
+		}
+	}
+}
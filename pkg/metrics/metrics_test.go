@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package metrics exports Prometheus metrics of the bridge and SPDK
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opiproject/gospdk/spdk"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+type fakeObjectCounter map[string]int
+
+func (c fakeObjectCounter) ObjectCounts() map[string]int {
+	return c
+}
+
+func TestMetrics_UnaryServerInterceptor(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		wantCode string
+	}{
+		"successful request": {nil, "OK"},
+		"failed request":     {status.Error(codes.NotFound, "not found"), "NotFound"},
+		"plain error":        {errors.New("some error"), "Unknown"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := New()
+			method := "/opi_api.storage.v1.FrontendNvmeService/GetNVMeSubsystem"
+			handler := func(context.Context, interface{}) (interface{}, error) {
+				return nil, tt.err
+			}
+
+			_, err := m.UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+			if !errors.Is(err, tt.err) {
+				t.Error("expected error", tt.err, "received", err)
+			}
+			if count := testutil.ToFloat64(m.grpcRequests.WithLabelValues(method, tt.wantCode)); count != 1 {
+				t.Error("expected 1 request with code", tt.wantCode, "received", count)
+			}
+			if count := testutil.CollectAndCount(m.grpcDuration); count != 1 {
+				t.Error("expected 1 duration series, received", count)
+			}
+		})
+	}
+}
+
+func TestMetrics_InstrumentJSONRPC(t *testing.T) {
+	m := New()
+	rpc := m.InstrumentJSONRPC(server.CreateTestSpdkStub(map[string]string{
+		"bdev_get_bdevs": `[]`,
+	}))
+
+	var result []interface{}
+	if err := rpc.Call("bdev_get_bdevs", nil, &result); err != nil {
+		t.Fatal(err)
+	}
+	if err := rpc.Call("bdev_aio_create", nil, &result); err == nil {
+		t.Fatal("expected error for unexpected call")
+	}
+
+	if count := testutil.ToFloat64(m.spdkFailures.WithLabelValues("bdev_get_bdevs")); count != 0 {
+		t.Error("expected no failures of bdev_get_bdevs, received", count)
+	}
+	if count := testutil.ToFloat64(m.spdkFailures.WithLabelValues("bdev_aio_create")); count != 1 {
+		t.Error("expected 1 failure of bdev_aio_create, received", count)
+	}
+	if count := testutil.CollectAndCount(m.spdkDuration); count != 2 {
+		t.Error("expected 2 duration series, received", count)
+	}
+}
+
+func TestMetrics_CountObjects(t *testing.T) {
+	m := New()
+	m.CountObjects(fakeObjectCounter{"aio_controller": 2}, fakeObjectCounter{"qos_volume": 1})
+
+	expected := `
+# HELP opi_spdk_bridge_objects Number of objects managed by the bridge by type.
+# TYPE opi_spdk_bridge_objects gauge
+opi_spdk_bridge_objects{type="aio_controller"} 2
+opi_spdk_bridge_objects{type="qos_volume"} 1
+`
+	if err := testutil.CollectAndCompare(m.objects, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMetrics_UpdateIostat(t *testing.T) {
+	tests := map[string]struct {
+		results  map[string]string
+		expected string
+	}{
+		"iostat of volumes": {
+			results: map[string]string{
+				"bdev_get_iostat": `{"tick_rate": 1000, "ticks": 5000, "bdevs": [
+					{"name": "Malloc0", "bytes_read": 4096, "num_read_ops": 1, "bytes_written": 8192, "num_write_ops": 2,
+					 "bytes_unmapped": 0, "num_unmap_ops": 0, "read_latency_ticks": 500, "write_latency_ticks": 2000, "unmap_latency_ticks": 0}]}`,
+			},
+			expected: `
+# HELP opi_spdk_bridge_bdev_read_bytes_total Number of bytes read from the bdev.
+# TYPE opi_spdk_bridge_bdev_read_bytes_total counter
+opi_spdk_bridge_bdev_read_bytes_total{volume_id="Malloc0"} 4096
+# HELP opi_spdk_bridge_bdev_write_latency_seconds_total Total time spent in write operations on the bdev.
+# TYPE opi_spdk_bridge_bdev_write_latency_seconds_total counter
+opi_spdk_bridge_bdev_write_latency_seconds_total{volume_id="Malloc0"} 2
+`,
+		},
+		"spdk error": {
+			results:  map[string]string{},
+			expected: ``,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := New()
+			m.UpdateIostat(server.CreateTestSpdkStub(tt.results))
+
+			err := testutil.CollectAndCompare(m.iostat, strings.NewReader(tt.expected),
+				"opi_spdk_bridge_bdev_read_bytes_total", "opi_spdk_bridge_bdev_write_latency_seconds_total")
+			if err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	m.CountObjects(fakeObjectCounter{"nvme_subsystem": 1})
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	body, err := io.ReadAll(recorder.Result().Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{`opi_spdk_bridge_objects{type="nvme_subsystem"} 1`, "go_goroutines"} {
+		if !strings.Contains(string(body), name) {
+			t.Error("expected", name, "in metrics output")
+		}
+	}
+}
+
+// unreachableJSONRPC fails the test on every call, like gospdk terminates
+// the process when SPDK is not running
+type unreachableJSONRPC struct {
+	spdk.JSONRPC
+	t *testing.T
+}
+
+func (r unreachableJSONRPC) Call(method string, _, _ interface{}) error {
+	r.t.Error("unexpected call of", method, "to unreachable SPDK")
+	return errors.New("unreachable")
+}
+
+func TestMetrics_ScrapeIostat(t *testing.T) {
+	m := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	m.ScrapeIostat(ctx, unreachableJSONRPC{t: t}, filepath.Join(t.TempDir(), "spdk.sock"), time.Second)
+
+	if count := testutil.CollectAndCount(m.iostat); count != 0 {
+		t.Error("expected no iostat series, received", count)
+	}
+}
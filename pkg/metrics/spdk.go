@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package metrics exports Prometheus metrics of the bridge and SPDK
+package metrics
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/opiproject/gospdk/spdk"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+// instrumentedJSONRPC measures every call made through the wrapped JSONRPC
+type instrumentedJSONRPC struct {
+	spdk.JSONRPC
+	metrics *Metrics
+}
+
+// InstrumentJSONRPC returns JSONRPC which measures duration and counts
+// failures of calls made through rpc
+func (m *Metrics) InstrumentJSONRPC(rpc spdk.JSONRPC) spdk.JSONRPC {
+	return &instrumentedJSONRPC{JSONRPC: rpc, metrics: m}
+}
+
+func (r *instrumentedJSONRPC) Call(method string, args, result interface{}) error {
+	start := time.Now()
+	err := r.JSONRPC.Call(method, args, result)
+	r.metrics.spdkDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		r.metrics.spdkFailures.WithLabelValues(method).Inc()
+	}
+	return err
+}
+
+// ScrapeIostat periodically reads I/O statistics of all bdevs from SPDK
+// and exports them labelled by volume ID until ctx is done. A scrape is
+// skipped while SPDK is not reachable at spdkAddress, because a call to it
+// would terminate the bridge.
+func (m *Metrics) ScrapeIostat(ctx context.Context, rpc spdk.JSONRPC, spdkAddress string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := server.ProbeSpdk(spdkAddress); err != nil {
+			log.Printf("error: skipping bdev iostat scrape, SPDK is not reachable: %v", err)
+		} else {
+			m.UpdateIostat(rpc)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// UpdateIostat reads I/O statistics of all bdevs from SPDK once
+func (m *Metrics) UpdateIostat(rpc spdk.JSONRPC) {
+	var result spdk.BdevGetIostatResult
+	err := rpc.Call("bdev_get_iostat", nil, &result)
+	if err != nil {
+		log.Printf("error: cannot scrape bdev iostat: %v", err)
+		return
+	}
+	m.iostat.update(&result)
+}
+
+// iostatCollector exports the I/O statistics from the last scrape
+type iostatCollector struct {
+	readBytes    *prometheus.Desc
+	readOps      *prometheus.Desc
+	writeBytes   *prometheus.Desc
+	writeOps     *prometheus.Desc
+	unmapBytes   *prometheus.Desc
+	unmapOps     *prometheus.Desc
+	readLatency  *prometheus.Desc
+	writeLatency *prometheus.Desc
+	unmapLatency *prometheus.Desc
+
+	mu     sync.Mutex
+	result *spdk.BdevGetIostatResult
+}
+
+func newIostatCollector() *iostatCollector {
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "bdev", name), help, []string{"volume_id"}, nil)
+	}
+	return &iostatCollector{
+		readBytes:    desc("read_bytes_total", "Number of bytes read from the bdev."),
+		readOps:      desc("read_ops_total", "Number of read operations on the bdev."),
+		writeBytes:   desc("written_bytes_total", "Number of bytes written to the bdev."),
+		writeOps:     desc("write_ops_total", "Number of write operations on the bdev."),
+		unmapBytes:   desc("unmapped_bytes_total", "Number of bytes unmapped on the bdev."),
+		unmapOps:     desc("unmap_ops_total", "Number of unmap operations on the bdev."),
+		readLatency:  desc("read_latency_seconds_total", "Total time spent in read operations on the bdev."),
+		writeLatency: desc("write_latency_seconds_total", "Total time spent in write operations on the bdev."),
+		unmapLatency: desc("unmap_latency_seconds_total", "Total time spent in unmap operations on the bdev."),
+	}
+}
+
+func (c *iostatCollector) update(result *spdk.BdevGetIostatResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.result = result
+}
+
+func (c *iostatCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		c.readBytes, c.readOps, c.writeBytes, c.writeOps, c.unmapBytes,
+		c.unmapOps, c.readLatency, c.writeLatency, c.unmapLatency,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *iostatCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.result == nil {
+		return
+	}
+	ticksToSeconds := func(ticks int) float64 {
+		if c.result.TickRate == 0 {
+			return 0
+		}
+		return float64(ticks) / float64(c.result.TickRate)
+	}
+	counter := func(desc *prometheus.Desc, value float64, volumeID string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, value, volumeID)
+	}
+	for _, b := range c.result.Bdevs {
+		counter(c.readBytes, float64(b.BytesRead), b.Name)
+		counter(c.readOps, float64(b.NumReadOps), b.Name)
+		counter(c.writeBytes, float64(b.BytesWritten), b.Name)
+		counter(c.writeOps, float64(b.NumWriteOps), b.Name)
+		counter(c.unmapBytes, float64(b.BytesUnmapped), b.Name)
+		counter(c.unmapOps, float64(b.NumUnmapOps), b.Name)
+		counter(c.readLatency, ticksToSeconds(b.ReadLatencyTicks), b.Name)
+		counter(c.writeLatency, ticksToSeconds(b.WriteLatencyTicks), b.Name)
+		counter(c.unmapLatency, ticksToSeconds(b.UnmapLatencyTicks), b.Name)
+	}
+}
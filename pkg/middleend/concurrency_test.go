@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package middleend implements the MiddleEnd APIs (service) of the storage Server
+package middleend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	_go "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+func TestMiddleEnd_Concurrency(t *testing.T) {
+	jsonRPC := server.CreateTestSpdkStub(map[string]string{
+		"bdev_set_qos_limit": `true`,
+	})
+	opiSpdkServer := NewServer(jsonRPC)
+	ctx := context.Background()
+	conn, err := grpc.DialContext(ctx,
+		"",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(dialer(opiSpdkServer)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.CloseGrpcConnection(conn)
+	client := pb.NewMiddleendQosVolumeServiceClient(conn)
+
+	const workers = 20
+	run := func(work func(id string, volumeID string)) {
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				// every QoS volume is shared by two workers to race on the same key
+				work(fmt.Sprintf("qos-volume-%d", i/2), fmt.Sprintf("volume-%d", i/2))
+			}(i)
+		}
+		wg.Wait()
+	}
+
+	run(func(id string, volumeID string) {
+		volume := &pb.QosVolume{
+			QosVolumeId: &_go.ObjectKey{Value: id},
+			VolumeId:    &_go.ObjectKey{Value: volumeID},
+			LimitMax:    &pb.QosLimit{RwBandwidthMbs: 1},
+		}
+		if _, err := client.CreateQosVolume(ctx, &pb.CreateQosVolumeRequest{QosVolume: volume}); err != nil {
+			t.Error(err)
+		}
+		volume.LimitMax.RwBandwidthMbs = 2
+		if _, err := client.UpdateQosVolume(ctx, &pb.UpdateQosVolumeRequest{QosVolume: volume}); err != nil {
+			t.Error(err)
+		}
+		if _, err := client.ListQosVolumes(ctx, &pb.ListQosVolumesRequest{PageSize: 1}); err != nil {
+			t.Error(err)
+		}
+	})
+	if len(opiSpdkServer.volumes.qosVolumes) != workers/2 {
+		t.Error("expected", workers/2, "QoS volumes, received", len(opiSpdkServer.volumes.qosVolumes))
+	}
+
+	run(func(id string, _ string) {
+		request := &pb.DeleteQosVolumeRequest{Name: id, AllowMissing: true}
+		if _, err := client.DeleteQosVolume(ctx, request); err != nil {
+			t.Error(err)
+		}
+	})
+	if len(opiSpdkServer.volumes.qosVolumes) != 0 {
+		t.Error("expected all QoS volumes to be deleted, received", opiSpdkServer.volumes.qosVolumes)
+	}
+}
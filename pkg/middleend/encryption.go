@@ -9,13 +9,14 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
-	"log"
 
 	"github.com/google/uuid"
 	"github.com/opiproject/gospdk/spdk"
 	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 	"github.com/ulule/deepcopier"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -23,25 +24,25 @@ import (
 )
 
 // CreateEncryptedVolume creates an encrypted volume
-func (s *Server) CreateEncryptedVolume(_ context.Context, in *pb.CreateEncryptedVolumeRequest) (*pb.EncryptedVolume, error) {
-	log.Printf("CreateEncryptedVolume: Received from client: %v", in)
+func (s *Server) CreateEncryptedVolume(ctx context.Context, in *pb.CreateEncryptedVolumeRequest) (*pb.EncryptedVolume, error) {
+	logging.FromContext(ctx).Infof("CreateEncryptedVolume: Received from client: %v", logging.Redact(in))
 	if err := s.verifyEncryptedVolume(in.EncryptedVolume); err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	// first create a key
 	params1 := s.getAccelCryptoKeyCreateParams(in.EncryptedVolume)
 	var result1 spdk.AccelCryptoKeyCreateResult
-	err1 := s.rpc.Call("accel_crypto_key_create", &params1, &result1)
+	err1 := tracing.Call(ctx, s.rpc, "accel_crypto_key_create", &params1, &result1)
 	if err1 != nil {
-		log.Printf("error: %v", err1)
+		logging.FromContext(ctx).Error(err1)
 		return nil, err1
 	}
-	log.Printf("Received from SPDK: %v", result1)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result1)
 	if !result1 {
-		msg := fmt.Sprintf("Could not create Crypto Key: %s", string(in.EncryptedVolume.Key))
-		log.Print(msg)
+		msg := fmt.Sprintf("Could not create Crypto Key: %v", params1.Name)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	// create bdev now
@@ -51,42 +52,42 @@ func (s *Server) CreateEncryptedVolume(_ context.Context, in *pb.CreateEncrypted
 		KeyName:      in.EncryptedVolume.EncryptedVolumeId.Value,
 	}
 	var result spdk.BdevCryptoCreateResult
-	err := s.rpc.Call("bdev_crypto_create", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_crypto_create", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if result == "" {
 		msg := fmt.Sprintf("Could not create Crypto Dev: %s", in.EncryptedVolume.EncryptedVolumeId.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	response := &pb.EncryptedVolume{}
 	err = deepcopier.Copy(in.EncryptedVolume).To(response)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	return response, nil
 }
 
 // DeleteEncryptedVolume deletes an encrypted volume
-func (s *Server) DeleteEncryptedVolume(_ context.Context, in *pb.DeleteEncryptedVolumeRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteEncryptedVolume: Received from client: %v", in)
+func (s *Server) DeleteEncryptedVolume(ctx context.Context, in *pb.DeleteEncryptedVolumeRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteEncryptedVolume: Received from client: %v", logging.Redact(in))
 	bdevCryptoDeleteParams := spdk.BdevCryptoDeleteParams{
 		Name: in.Name,
 	}
 	var bdevCryptoDeleteResult spdk.BdevCryptoDeleteResult
-	err := s.rpc.Call("bdev_crypto_delete", &bdevCryptoDeleteParams, &bdevCryptoDeleteResult)
+	err := tracing.Call(ctx, s.rpc, "bdev_crypto_delete", &bdevCryptoDeleteParams, &bdevCryptoDeleteResult)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", bdevCryptoDeleteResult)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", bdevCryptoDeleteResult)
 	if !bdevCryptoDeleteResult {
 		msg := fmt.Sprintf("Could not delete Crypto: %s", in.Name)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 
@@ -94,15 +95,15 @@ func (s *Server) DeleteEncryptedVolume(_ context.Context, in *pb.DeleteEncrypted
 		KeyName: in.Name,
 	}
 	var keyDestroyResult spdk.AccelCryptoKeyDestroyResult
-	err = s.rpc.Call("accel_crypto_key_destroy", &keyDestroyParams, &keyDestroyResult)
+	err = tracing.Call(ctx, s.rpc, "accel_crypto_key_destroy", &keyDestroyParams, &keyDestroyResult)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", keyDestroyResult)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", keyDestroyResult)
 	if !keyDestroyResult {
 		msg := fmt.Sprintf("Could not destroy Crypto Key: %v", keyDestroyParams.KeyName)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 
@@ -110,10 +111,10 @@ func (s *Server) DeleteEncryptedVolume(_ context.Context, in *pb.DeleteEncrypted
 }
 
 // UpdateEncryptedVolume updates an encrypted volume
-func (s *Server) UpdateEncryptedVolume(_ context.Context, in *pb.UpdateEncryptedVolumeRequest) (*pb.EncryptedVolume, error) {
-	log.Printf("UpdateEncryptedVolume: Received from client: %v", in)
+func (s *Server) UpdateEncryptedVolume(ctx context.Context, in *pb.UpdateEncryptedVolumeRequest) (*pb.EncryptedVolume, error) {
+	logging.FromContext(ctx).Infof("UpdateEncryptedVolume: Received from client: %v", logging.Redact(in))
 	if err := s.verifyEncryptedVolume(in.EncryptedVolume); err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	// first delete old bdev
@@ -121,15 +122,15 @@ func (s *Server) UpdateEncryptedVolume(_ context.Context, in *pb.UpdateEncrypted
 		Name: in.EncryptedVolume.EncryptedVolumeId.Value,
 	}
 	var result1 spdk.BdevCryptoDeleteResult
-	err1 := s.rpc.Call("bdev_crypto_delete", &params1, &result1)
+	err1 := tracing.Call(ctx, s.rpc, "bdev_crypto_delete", &params1, &result1)
 	if err1 != nil {
-		log.Printf("error: %v", err1)
+		logging.FromContext(ctx).Error(err1)
 		return nil, err1
 	}
-	log.Printf("Received from SPDK: %v", result1)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result1)
 	if !result1 {
 		msg := fmt.Sprintf("Could not delete Crypto: %s", in.EncryptedVolume.EncryptedVolumeId.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	// now delete a key
@@ -137,28 +138,28 @@ func (s *Server) UpdateEncryptedVolume(_ context.Context, in *pb.UpdateEncrypted
 		KeyName: in.EncryptedVolume.EncryptedVolumeId.Value,
 	}
 	var result0 spdk.AccelCryptoKeyDestroyResult
-	err0 := s.rpc.Call("accel_crypto_key_destroy", &params0, &result0)
+	err0 := tracing.Call(ctx, s.rpc, "accel_crypto_key_destroy", &params0, &result0)
 	if err0 != nil {
-		log.Printf("error: %v", err0)
+		logging.FromContext(ctx).Error(err0)
 		return nil, err0
 	}
-	log.Printf("Received from SPDK: %v", result0)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result0)
 	if !result0 {
 		msg := fmt.Sprintf("Could not destroy Crypto Key: %v", params0.KeyName)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	params2 := s.getAccelCryptoKeyCreateParams(in.EncryptedVolume)
 	var result2 spdk.AccelCryptoKeyCreateResult
-	err2 := s.rpc.Call("accel_crypto_key_create", &params2, &result2)
+	err2 := tracing.Call(ctx, s.rpc, "accel_crypto_key_create", &params2, &result2)
 	if err2 != nil {
-		log.Printf("error: %v", err2)
+		logging.FromContext(ctx).Error(err2)
 		return nil, err2
 	}
-	log.Printf("Received from SPDK: %v", result2)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result2)
 	if !result2 {
-		msg := fmt.Sprintf("Could not create Crypto Key: %s", string(in.EncryptedVolume.Key))
-		log.Print(msg)
+		msg := fmt.Sprintf("Could not create Crypto Key: %v", params2.Name)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	// create bdev now
@@ -168,48 +169,52 @@ func (s *Server) UpdateEncryptedVolume(_ context.Context, in *pb.UpdateEncrypted
 		KeyName:      in.EncryptedVolume.EncryptedVolumeId.Value,
 	}
 	var result3 spdk.BdevCryptoCreateResult
-	err3 := s.rpc.Call("bdev_crypto_create", &params3, &result3)
+	err3 := tracing.Call(ctx, s.rpc, "bdev_crypto_create", &params3, &result3)
 	if err3 != nil {
-		log.Printf("error: %v", err3)
+		logging.FromContext(ctx).Error(err3)
 		return nil, err3
 	}
-	log.Printf("Received from SPDK: %v", result3)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result3)
 	if result3 == "" {
 		msg := fmt.Sprintf("Could not create Crypto Dev: %s", in.EncryptedVolume.EncryptedVolumeId.Value)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	// return result
 	response := &pb.EncryptedVolume{}
 	err4 := deepcopier.Copy(in.EncryptedVolume).To(response)
 	if err4 != nil {
-		log.Printf("error: %v", err4)
+		logging.FromContext(ctx).Error(err4)
 		return nil, err4
 	}
 	return response, nil
 }
 
 // ListEncryptedVolumes lists encrypted volumes
-func (s *Server) ListEncryptedVolumes(_ context.Context, in *pb.ListEncryptedVolumesRequest) (*pb.ListEncryptedVolumesResponse, error) {
-	log.Printf("ListEncryptedVolumes: Received from client: %v", in)
-	size, offset, perr := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+func (s *Server) ListEncryptedVolumes(ctx context.Context, in *pb.ListEncryptedVolumesRequest) (*pb.ListEncryptedVolumesResponse, error) {
+	logging.FromContext(ctx).Infof("ListEncryptedVolumes: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
+	size, offset, perr := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if perr != nil {
-		log.Printf("error: %v", perr)
+		logging.FromContext(ctx).Error(perr)
 		return nil, perr
 	}
 	var result []spdk.BdevGetBdevsResult
-	err := s.rpc.Call("bdev_get_bdevs", nil, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_bdevs", nil, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	token := ""
-	log.Printf("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
+	logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(result), offset, size)
 	result, hasMoreElements := server.LimitPagination(result, offset, size)
 	if hasMoreElements {
 		token = uuid.New().String()
+		s.mu.Lock()
 		s.Pagination[token] = offset + size
+		s.mu.Unlock()
 	}
 	Blobarray := make([]*pb.EncryptedVolume, len(result))
 	for i := range result {
@@ -220,43 +225,43 @@ func (s *Server) ListEncryptedVolumes(_ context.Context, in *pb.ListEncryptedVol
 }
 
 // GetEncryptedVolume gets an encrypted volume
-func (s *Server) GetEncryptedVolume(_ context.Context, in *pb.GetEncryptedVolumeRequest) (*pb.EncryptedVolume, error) {
-	log.Printf("GetEncryptedVolume: Received from client: %v", in)
+func (s *Server) GetEncryptedVolume(ctx context.Context, in *pb.GetEncryptedVolumeRequest) (*pb.EncryptedVolume, error) {
+	logging.FromContext(ctx).Infof("GetEncryptedVolume: Received from client: %v", logging.Redact(in))
 	params := spdk.BdevGetBdevsParams{
 		Name: in.Name,
 	}
 	var result []spdk.BdevGetBdevsResult
-	err := s.rpc.Call("bdev_get_bdevs", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_bdevs", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	return &pb.EncryptedVolume{EncryptedVolumeId: &pc.ObjectKey{Value: result[0].Name}}, nil
 }
 
 // EncryptedVolumeStats gets an encrypted volume stats
-func (s *Server) EncryptedVolumeStats(_ context.Context, in *pb.EncryptedVolumeStatsRequest) (*pb.EncryptedVolumeStatsResponse, error) {
-	log.Printf("EncryptedVolumeStats: Received from client: %v", in)
+func (s *Server) EncryptedVolumeStats(ctx context.Context, in *pb.EncryptedVolumeStatsRequest) (*pb.EncryptedVolumeStatsResponse, error) {
+	logging.FromContext(ctx).Infof("EncryptedVolumeStats: Received from client: %v", logging.Redact(in))
 	params := spdk.BdevGetIostatParams{
 		Name: in.EncryptedVolumeId.Value,
 	}
 	// See https://mholt.github.io/json-to-go/
 	var result spdk.BdevGetIostatResult
-	err := s.rpc.Call("bdev_get_iostat", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_iostat", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result.Bdevs) != 1 {
 		msg := fmt.Sprintf("expecting exactly 1 result, got %d", len(result.Bdevs))
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
 	return &pb.EncryptedVolumeStatsResponse{Stats: &pb.VolumeStats{
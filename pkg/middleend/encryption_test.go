@@ -33,7 +33,7 @@ func TestMiddleEnd_CreateEncryptedVolume(t *testing.T) {
 			nil,
 			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":false}`},
 			codes.InvalidArgument,
-			fmt.Sprintf("Could not create Crypto Key: %v", "0123456789abcdef0123456789abcdef"),
+			fmt.Sprintf("Could not create Crypto Key: %v", "crypto-test"),
 			true,
 		},
 		"valid request with invalid marshal SPDK response": {
@@ -328,7 +328,7 @@ func TestMiddleEnd_UpdateEncryptedVolume(t *testing.T) {
 			nil,
 			[]string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`, `{"id":%d,"error":{"code":0,"message":""},"result":true}`, `{"id":%d,"error":{"code":0,"message":""},"result":false}`},
 			codes.InvalidArgument,
-			fmt.Sprintf("Could not create Crypto Key: %v", "0123456789abcdef0123456789abcdef"),
+			fmt.Sprintf("Could not create Crypto Key: %v", "crypto-test"),
 			true,
 		},
 		"bdev delete ok ; key delete ok ; key create empty": {
@@ -6,10 +6,19 @@
 package middleend
 
 import (
+	"errors"
+	"log"
+	"sync"
+
 	"github.com/opiproject/gospdk/spdk"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
 )
 
+// qosVolumesTable is used to persist QoS volumes
+const qosVolumesTable = "qos_volumes"
+
 // VolumeParameters contains MiddleEnd volume related structures
 type VolumeParameters struct {
 	qosVolumes map[string]*pb.QosVolume
@@ -21,18 +30,65 @@ type Server struct {
 	pb.UnimplementedMiddleendQosVolumeServiceServer
 
 	rpc        spdk.JSONRPC
+	store      store.Store
 	volumes    VolumeParameters
 	Pagination map[string]int
+	pageLimits server.PaginationLimits
+
+	// mu guards the volume maps and Pagination, locks serializes operations
+	// on the same volumes while SPDK is called
+	mu    sync.RWMutex
+	locks *server.KeyLocker
 }
 
 // NewServer creates initialized instance of MiddleEnd server communicating
 // with provided jsonRPC
 func NewServer(jsonRPC spdk.JSONRPC) *Server {
 	return &Server{
-		rpc: jsonRPC,
+		rpc:   jsonRPC,
+		store: store.NewMemoryStore(),
 		volumes: VolumeParameters{
 			qosVolumes: make(map[string]*pb.QosVolume),
 		},
 		Pagination: make(map[string]int),
+		pageLimits: server.DefaultPaginationLimits(),
+		locks:      server.NewKeyLocker(),
+	}
+}
+
+// UseStore makes the server persist its objects in the provided store and
+// restores all objects previously saved there
+func (s *Server) UseStore(st store.Store) error {
+	if st == nil {
+		return errors.New("nil store is not allowed")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	newQos := func() *pb.QosVolume { return &pb.QosVolume{} }
+	if err := store.Load(st, qosVolumesTable, s.volumes.qosVolumes, newQos); err != nil {
+		return err
+	}
+	log.Printf("Restored %d QoS volumes", len(s.volumes.qosVolumes))
+	s.store = st
+	return nil
+}
+
+// SetPaginationLimits changes the page sizes used by List calls
+func (s *Server) SetPaginationLimits(limits server.PaginationLimits) error {
+	if err := limits.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pageLimits = limits
+	return nil
+}
+
+// ObjectCounts returns the number of objects managed by the server per type
+func (s *Server) ObjectCounts() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return map[string]int{
+		"qos_volume": len(s.volumes.qosVolumes),
 	}
 }
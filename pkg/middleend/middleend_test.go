@@ -10,15 +10,18 @@ import (
 	"log"
 	"net"
 	"os"
+	"testing"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/opiproject/gospdk/spdk"
 	pc "github.com/opiproject/opi-api/common/v1/gen/go"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/store"
 )
 
 // TODO: move test infrastructure code to a separate (test/server) package to avoid duplication
@@ -96,3 +99,35 @@ var (
 		Cipher:            pb.EncryptionType_ENCRYPTION_TYPE_AES_XTS_128,
 	}
 )
+
+func TestMiddleEnd_UseStore(t *testing.T) {
+	st := store.NewMemoryStore()
+	if err := st.Set(qosVolumesTable, testQosVolume.QosVolumeId.Value, testQosVolume); err != nil {
+		t.Fatal(err)
+	}
+
+	testEnv := createTestEnvironment(true, []string{`{"id":%d,"error":{"code":0,"message":""},"result":true}`})
+	defer testEnv.Close()
+
+	if err := testEnv.opiSpdkServer.UseStore(nil); err == nil {
+		t.Error("expected error for nil store")
+	}
+	if err := testEnv.opiSpdkServer.UseStore(st); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(testEnv.opiSpdkServer.volumes.qosVolumes[testQosVolume.QosVolumeId.Value], testQosVolume) {
+		t.Error("expected restored QoS volume", testQosVolume, "received", testEnv.opiSpdkServer.volumes.qosVolumes)
+	}
+
+	request := &pb.DeleteQosVolumeRequest{Name: testQosVolume.QosVolumeId.Value}
+	if _, err := testEnv.client.DeleteQosVolume(testEnv.ctx, request); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := st.Entries(qosVolumesTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Error("expected deleted QoS volume to be removed from store, received", entries)
+	}
+}
@@ -7,12 +7,13 @@ package middleend
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"github.com/google/uuid"
 	"github.com/opiproject/gospdk/spdk"
 	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
 	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
@@ -20,137 +21,178 @@ import (
 )
 
 // CreateQosVolume creates a QoS volume
-func (s *Server) CreateQosVolume(_ context.Context, in *pb.CreateQosVolumeRequest) (*pb.QosVolume, error) {
-	log.Printf("CreateQosVolume: Received from client: %v", in)
+func (s *Server) CreateQosVolume(ctx context.Context, in *pb.CreateQosVolumeRequest) (*pb.QosVolume, error) {
+	logging.FromContext(ctx).Infof("CreateQosVolume: Received from client: %v", logging.Redact(in))
 	if err := s.verifyQosVolume(in.QosVolume); err != nil {
-		log.Println("error:", err)
+		logging.FromContext(ctx).Errorln("error:", err)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
-	if volume, ok := s.volumes.qosVolumes[in.QosVolume.QosVolumeId.Value]; ok {
-		log.Printf("Already existing QoS volume with id %v", in.QosVolume.QosVolumeId.Value)
+	unlock := s.locks.Lock(server.LockKey(qosVolumesTable, in.QosVolume.QosVolumeId.Value))
+	defer unlock()
+	s.mu.RLock()
+	volume, ok := s.volumes.qosVolumes[in.QosVolume.QosVolumeId.Value]
+	s.mu.RUnlock()
+	if ok {
+		logging.FromContext(ctx).Infof("Already existing QoS volume with id %v", in.QosVolume.QosVolumeId.Value)
 		return volume, nil
 	}
 
-	if err := s.setMaxLimit(in.QosVolume.VolumeId.Value, in.QosVolume.LimitMax); err != nil {
+	if err := s.setMaxLimit(ctx, in.QosVolume.VolumeId.Value, in.QosVolume.LimitMax); err != nil {
 		return nil, err
 	}
 
+	if err := s.store.Set(qosVolumesTable, in.QosVolume.QosVolumeId.Value, in.QosVolume); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.volumes.qosVolumes[in.QosVolume.QosVolumeId.Value] = proto.Clone(in.QosVolume).(*pb.QosVolume)
+	s.mu.Unlock()
 	return in.QosVolume, nil
 }
 
 // DeleteQosVolume deletes a QoS volume
-func (s *Server) DeleteQosVolume(_ context.Context, in *pb.DeleteQosVolumeRequest) (*emptypb.Empty, error) {
-	log.Printf("DeleteQosVolume: Received from client: %v", in)
+func (s *Server) DeleteQosVolume(ctx context.Context, in *pb.DeleteQosVolumeRequest) (*emptypb.Empty, error) {
+	logging.FromContext(ctx).Infof("DeleteQosVolume: Received from client: %v", logging.Redact(in))
+	unlock := s.locks.Lock(server.LockKey(qosVolumesTable, in.Name))
+	defer unlock()
+	s.mu.RLock()
 	qosVolume, ok := s.volumes.qosVolumes[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		if in.AllowMissing {
 			return &emptypb.Empty{}, nil
 		}
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
-	if err := s.cleanMaxLimit(qosVolume.VolumeId.Value); err != nil {
+	if err := s.cleanMaxLimit(ctx, qosVolume.VolumeId.Value); err != nil {
 		return nil, err
 	}
 
+	if err := s.store.Delete(qosVolumesTable, in.Name); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	delete(s.volumes.qosVolumes, in.Name)
+	s.mu.Unlock()
 	return &emptypb.Empty{}, nil
 }
 
 // UpdateQosVolume updates a QoS volume
-func (s *Server) UpdateQosVolume(_ context.Context, in *pb.UpdateQosVolumeRequest) (*pb.QosVolume, error) {
-	log.Printf("UpdateQosVolume: Received from client: %v", in)
+func (s *Server) UpdateQosVolume(ctx context.Context, in *pb.UpdateQosVolumeRequest) (*pb.QosVolume, error) {
+	logging.FromContext(ctx).Infof("UpdateQosVolume: Received from client: %v", logging.Redact(in))
 	if err := s.verifyQosVolume(in.QosVolume); err != nil {
-		log.Println("error:", err)
+		logging.FromContext(ctx).Errorln("error:", err)
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 	qosVolumeID := in.QosVolume.QosVolumeId.Value
+	unlock := s.locks.Lock(server.LockKey(qosVolumesTable, qosVolumeID))
+	defer unlock()
+	s.mu.RLock()
 	volume, ok := s.volumes.qosVolumes[qosVolumeID]
+	s.mu.RUnlock()
 	if !ok {
-		log.Printf("Non-existing QoS volume with id %v", qosVolumeID)
+		logging.FromContext(ctx).Infof("Non-existing QoS volume with id %v", qosVolumeID)
 		return nil, status.Errorf(codes.NotFound, "volume_id %v does not exist", qosVolumeID)
 	}
 
 	if volume.VolumeId.Value != in.QosVolume.VolumeId.Value {
 		msg := fmt.Sprintf("Change of underlying volume %v to a new one %v is forbidden",
 			volume.VolumeId.Value, in.QosVolume.VolumeId.Value)
-		log.Println("error:", msg)
+		logging.FromContext(ctx).Errorln("error:", msg)
 		return nil, status.Errorf(codes.InvalidArgument, msg)
 	}
-	log.Println("Set new max limit values")
-	if err := s.setMaxLimit(in.QosVolume.VolumeId.Value, in.QosVolume.LimitMax); err != nil {
+	logging.FromContext(ctx).Infoln("Set new max limit values")
+	if err := s.setMaxLimit(ctx, in.QosVolume.VolumeId.Value, in.QosVolume.LimitMax); err != nil {
 		return nil, err
 	}
 
+	if err := s.store.Set(qosVolumesTable, qosVolumeID, in.QosVolume); err != nil {
+		logging.FromContext(ctx).Error(err)
+		return nil, server.StoreError(err)
+	}
+	s.mu.Lock()
 	s.volumes.qosVolumes[qosVolumeID] = in.QosVolume
+	s.mu.Unlock()
 	return in.QosVolume, nil
 }
 
 // ListQosVolumes lists QoS volumes
-func (s *Server) ListQosVolumes(_ context.Context, in *pb.ListQosVolumesRequest) (*pb.ListQosVolumesResponse, error) {
-	log.Printf("ListQosVolume: Received from client: %v", in)
+func (s *Server) ListQosVolumes(ctx context.Context, in *pb.ListQosVolumesRequest) (*pb.ListQosVolumesResponse, error) {
+	logging.FromContext(ctx).Infof("ListQosVolume: Received from client: %v", logging.Redact(in))
 
-	size, offset, err := server.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RLock()
+	size, offset, err := s.pageLimits.ExtractPagination(in.PageSize, in.PageToken, s.Pagination)
+	s.mu.RUnlock()
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 
 	volumes := []*pb.QosVolume{}
+	s.mu.RLock()
 	for _, qosVolume := range s.volumes.qosVolumes {
 		volumes = append(volumes, proto.Clone(qosVolume).(*pb.QosVolume))
 	}
+	s.mu.RUnlock()
 
 	token := ""
-	log.Printf("Limiting result len(%d) to [%d:%d]", len(volumes), offset, size)
+	logging.FromContext(ctx).Infof("Limiting result len(%d) to [%d:%d]", len(volumes), offset, size)
 	volumes, hasMoreElements := server.LimitPagination(volumes, offset, size)
 	if hasMoreElements {
 		token = uuid.New().String()
+		s.mu.Lock()
 		s.Pagination[token] = offset + size
+		s.mu.Unlock()
 	}
 
 	return &pb.ListQosVolumesResponse{QosVolumes: volumes, NextPageToken: token}, nil
 }
 
 // GetQosVolume gets a QoS volume
-func (s *Server) GetQosVolume(_ context.Context, in *pb.GetQosVolumeRequest) (*pb.QosVolume, error) {
-	log.Printf("GetQosVolume: Received from client: %v", in)
+func (s *Server) GetQosVolume(ctx context.Context, in *pb.GetQosVolumeRequest) (*pb.QosVolume, error) {
+	logging.FromContext(ctx).Infof("GetQosVolume: Received from client: %v", logging.Redact(in))
+	s.mu.RLock()
 	volume, ok := s.volumes.qosVolumes[in.Name]
+	s.mu.RUnlock()
 	if !ok {
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.Name)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	return volume, nil
 }
 
 // QosVolumeStats gets a QoS volume stats
-func (s *Server) QosVolumeStats(_ context.Context, in *pb.QosVolumeStatsRequest) (*pb.QosVolumeStatsResponse, error) {
-	log.Printf("QosVolumeStats: Received from client: %v", in)
+func (s *Server) QosVolumeStats(ctx context.Context, in *pb.QosVolumeStatsRequest) (*pb.QosVolumeStatsResponse, error) {
+	logging.FromContext(ctx).Infof("QosVolumeStats: Received from client: %v", logging.Redact(in))
 	if in.VolumeId == nil || in.VolumeId.Value == "" {
 		return nil, status.Error(codes.InvalidArgument, "volume_id cannot be empty")
 	}
+	s.mu.RLock()
 	volume, ok := s.volumes.qosVolumes[in.VolumeId.Value]
+	s.mu.RUnlock()
 	if !ok {
 		err := status.Errorf(codes.NotFound, "unable to find key %s", in.VolumeId.Value)
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, err
 	}
 	params := spdk.BdevGetIostatParams{
 		Name: volume.VolumeId.Value,
 	}
 	var result spdk.BdevGetIostatResult
-	err := s.rpc.Call("bdev_get_iostat", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_get_iostat", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return nil, spdk.ErrFailedSpdkCall
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if len(result.Bdevs) != 1 {
-		log.Printf("error: expect to find one bdev in response")
+		logging.FromContext(ctx).Errorf("expect to find one bdev in response")
 		return nil, spdk.ErrUnexpectedSpdkCallResult
 	}
 
@@ -212,30 +254,34 @@ func (s *Server) verifyQosVolume(volume *pb.QosVolume) error {
 	return nil
 }
 
-func (s *Server) setMaxLimit(qosVolumeID string, limit *pb.QosLimit) error {
-	params := spdk.BdevQoSParams{
-		Name:           qosVolumeID,
-		RwIosPerSec:    int(limit.RwIopsKiops * 1000),
-		RwMbytesPerSec: int(limit.RwBandwidthMbs),
-		RMbytesPerSec:  int(limit.RdBandwidthMbs),
-		WMbytesPerSec:  int(limit.RdBandwidthMbs),
-	}
+func (s *Server) setMaxLimit(ctx context.Context, qosVolumeID string, limit *pb.QosLimit) error {
+	params := maxLimitParams(qosVolumeID, limit)
 	var result spdk.BdevQoSResult
-	err := s.rpc.Call("bdev_set_qos_limit", &params, &result)
+	err := tracing.Call(ctx, s.rpc, "bdev_set_qos_limit", &params, &result)
 	if err != nil {
-		log.Printf("error: %v", err)
+		logging.FromContext(ctx).Error(err)
 		return spdk.ErrFailedSpdkCall
 	}
-	log.Printf("Received from SPDK: %v", result)
+	logging.FromContext(ctx).Infof("Received from SPDK: %v", result)
 	if !result {
 		msg := fmt.Sprintf("Could not set max QoS limit: %s on %v", limit, qosVolumeID)
-		log.Print(msg)
+		logging.FromContext(ctx).Error(msg)
 		return spdk.ErrUnexpectedSpdkCallResult
 	}
 
 	return nil
 }
 
-func (s *Server) cleanMaxLimit(qosVolumeID string) error {
-	return s.setMaxLimit(qosVolumeID, &pb.QosLimit{})
+func (s *Server) cleanMaxLimit(ctx context.Context, qosVolumeID string) error {
+	return s.setMaxLimit(ctx, qosVolumeID, &pb.QosLimit{})
+}
+
+func maxLimitParams(qosVolumeID string, limit *pb.QosLimit) spdk.BdevQoSParams {
+	return spdk.BdevQoSParams{
+		Name:           qosVolumeID,
+		RwIosPerSec:    int(limit.RwIopsKiops * 1000),
+		RwMbytesPerSec: int(limit.RwBandwidthMbs),
+		RMbytesPerSec:  int(limit.RdBandwidthMbs),
+		WMbytesPerSec:  int(limit.RdBandwidthMbs),
+	}
 }
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package middleend implements the MiddleEnd APIs (service) of the storage Server
+package middleend
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/opiproject/gospdk/spdk"
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+	"github.com/opiproject/opi-spdk-bridge/pkg/tracing"
+)
+
+// bdevRateLimits is an entry of bdev_get_bdevs result with assigned QoS limits
+type bdevRateLimits struct {
+	Name               string             `json:"name"`
+	AssignedRateLimits spdk.BdevQoSParams `json:"assigned_rate_limits"`
+}
+
+// Reconcile compares QoS volumes known to the server with the rate limits
+// assigned to bdevs in SPDK. Volumes with missing bdevs or different limits
+// are reported and limits are re-applied when re-creation is enabled.
+// QoS volumes cannot be adopted, since SPDK does not keep their identifiers.
+func (s *Server) Reconcile(policy server.ReconcilePolicy) ([]server.Drift, error) {
+	// no object can be changed by other operations during reconciliation
+	unlock := s.locks.LockAll()
+	defer unlock()
+
+	var bdevs []bdevRateLimits
+	// reconciliation is not a part of any request, so SPDK calls start new traces
+	err := tracing.Call(context.Background(), s.rpc, "bdev_get_bdevs", nil, &bdevs)
+	if err != nil {
+		log.Printf("error: %v", err)
+		return nil, err
+	}
+	log.Printf("Received from SPDK: %v", bdevs)
+	limits := make(map[string]spdk.BdevQoSParams, len(bdevs))
+	for _, b := range bdevs {
+		b.AssignedRateLimits.Name = b.Name
+		limits[b.Name] = b.AssignedRateLimits
+	}
+
+	var drifts []server.Drift
+	for _, id := range server.SortedKeys(s.volumes.qosVolumes) {
+		volume := s.volumes.qosVolumes[id]
+		assigned, ok := limits[volume.VolumeId.Value]
+		if ok && assigned == maxLimitParams(volume.VolumeId.Value, volume.LimitMax) {
+			continue
+		}
+		drifts = append(drifts, server.ResolveDrift(
+			server.Drift{Object: "QosVolume", ID: id, Kind: server.DriftMissingInSpdk},
+			policy, func() error {
+				if !ok {
+					return fmt.Errorf("volume %v does not exist", volume.VolumeId.Value)
+				}
+				return s.setMaxLimit(context.Background(), volume.VolumeId.Value, volume.LimitMax)
+			}))
+	}
+	return drifts, nil
+}
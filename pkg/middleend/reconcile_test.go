@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package middleend implements the MiddleEnd APIs (service) of the storage Server
+package middleend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+func TestMiddleEnd_Reconcile(t *testing.T) {
+	withLimits := `{"id":%d,"error":{"code":0,"message":""},"result":[{"name":"volume-42",` +
+		`"assigned_rate_limits":{"rw_ios_per_sec":0,"rw_mbytes_per_sec":1,"r_mbytes_per_sec":0,"w_mbytes_per_sec":0}}]}`
+	withoutLimits := `{"id":%d,"error":{"code":0,"message":""},"result":[{"name":"volume-42",` +
+		`"assigned_rate_limits":{"rw_ios_per_sec":0,"rw_mbytes_per_sec":0,"r_mbytes_per_sec":0,"w_mbytes_per_sec":0}}]}`
+	noBdevs := `{"id":%d,"error":{"code":0,"message":""},"result":[]}`
+
+	tests := map[string]struct {
+		policy server.ReconcilePolicy
+		spdk   []string
+		drifts []server.Drift
+		errMsg string
+	}{
+		"in sync": {
+			server.ReconcilePolicy{Recreate: true},
+			[]string{withLimits},
+			nil,
+			"",
+		},
+		"missing limits reported": {
+			server.ReconcilePolicy{},
+			[]string{withoutLimits},
+			[]server.Drift{
+				{Object: "QosVolume", ID: "qos-volume-42", Kind: server.DriftMissingInSpdk, Resolution: "reported"},
+			},
+			"",
+		},
+		"missing limits re-applied": {
+			server.ReconcilePolicy{Recreate: true},
+			[]string{withoutLimits, `{"id":%d,"error":{"code":0,"message":""},"result":true}`},
+			[]server.Drift{
+				{Object: "QosVolume", ID: "qos-volume-42", Kind: server.DriftMissingInSpdk, Resolution: "recreated"},
+			},
+			"",
+		},
+		"missing volume cannot be recreated": {
+			server.ReconcilePolicy{Recreate: true},
+			[]string{noBdevs},
+			[]server.Drift{
+				{Object: "QosVolume", ID: "qos-volume-42", Kind: server.DriftMissingInSpdk,
+					Resolution: "failed to recreate: volume volume-42 does not exist"},
+			},
+			"",
+		},
+		"error from SPDK": {
+			server.ReconcilePolicy{},
+			[]string{`{"id":%d,"error":{"code":1,"message":"myopierr"},"result":[]}`},
+			nil,
+			"bdev_get_bdevs: json response error: myopierr",
+		},
+	}
+
+	// run tests
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			testEnv := createTestEnvironment(true, tt.spdk)
+			defer testEnv.Close()
+
+			testEnv.opiSpdkServer.volumes.qosVolumes[testQosVolume.QosVolumeId.Value] = testQosVolume
+
+			drifts, err := testEnv.opiSpdkServer.Reconcile(tt.policy)
+			if !reflect.DeepEqual(drifts, tt.drifts) {
+				t.Error("drifts: expected", tt.drifts, "received", drifts)
+			}
+			if err != nil && err.Error() != tt.errMsg {
+				t.Error("error: expected", tt.errMsg, "received", err)
+			}
+			if err == nil && tt.errMsg != "" {
+				t.Error("expected error", tt.errMsg)
+			}
+		})
+	}
+}
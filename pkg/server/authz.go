@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package server implements the server
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+)
+
+const servicePrefix = "/opi_api.storage.v1."
+
+// extensionPrefix starts the names of the ExtensionService methods, which
+// complement the OPI services
+const extensionPrefix = "/opi_spdk_bridge.v1alpha1.ExtensionService/"
+
+// AuthorizationPolicy maps caller identities to roles and roles to the
+// gRPC methods they are allowed to call
+type AuthorizationPolicy struct {
+	// Roles maps a role name to full gRPC method names allowed for the role,
+	// e.g. /opi_api.storage.v1.FrontendNvmeService/ListNVMeSubsystems.
+	// Names may contain path.Match patterns. Roles defined here replace
+	// the built-in roles with the same name.
+	Roles map[string][]string `yaml:"roles"`
+	// Subjects maps a caller identity to its roles. The identity is the
+	// common name or a SAN of a verified client certificate, or the name
+	// assigned to a bearer token in Tokens.
+	Subjects map[string][]string `yaml:"subjects"`
+	// Tokens maps hex encoded SHA-256 hashes of bearer tokens to identities
+	Tokens map[string]string `yaml:"tokens"`
+}
+
+// DefaultRoles returns the built-in roles available to every policy
+func DefaultRoles() map[string][]string {
+	readOnly := []string{"/grpc.reflection.*/*"}
+	for _, service := range []string{
+		"Frontend*", "NVMfRemoteControllerService", "NullDebugService",
+		"AioControllerService", "MiddleendQosVolumeService",
+	} {
+		readOnly = append(readOnly,
+			servicePrefix+service+"/Get*",
+			servicePrefix+service+"/List*",
+			servicePrefix+service+"/*Stats")
+	}
+	readOnly = append(readOnly, extensionPrefix+"Get*", extensionPrefix+"List*")
+	return map[string][]string{
+		"read-only":         readOnly,
+		"frontend-operator": {servicePrefix + "Frontend*/*", extensionPrefix + "*NVMe*", extensionPrefix + "*Virtio*"},
+		"backend-operator": {
+			servicePrefix + "NVMfRemoteControllerService/*",
+			servicePrefix + "NullDebugService/*",
+			servicePrefix + "AioControllerService/*",
+			servicePrefix + "MiddleendQosVolumeService/*",
+			extensionPrefix + "*NVMfRemoteController*",
+		},
+		"crypto-admin": {servicePrefix + "MiddleendEncryptionService/*", extensionPrefix + "*KeyringKey*"},
+	}
+}
+
+// Authorizer checks whether callers are allowed to call gRPC methods
+type Authorizer struct {
+	roles    map[string][]string
+	subjects map[string][]string
+	tokens   map[string]string
+}
+
+// LoadAuthorizer creates an Authorizer from a policy in a YAML or JSON file
+func LoadAuthorizer(file string) (*Authorizer, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var policy AuthorizationPolicy
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("cannot parse authorization policy %v: %w", file, err)
+	}
+	return NewAuthorizer(policy)
+}
+
+// NewAuthorizer creates an Authorizer enforcing the policy
+func NewAuthorizer(policy AuthorizationPolicy) (*Authorizer, error) {
+	roles := DefaultRoles()
+	for role, methods := range policy.Roles {
+		roles[role] = methods
+	}
+	for role, methods := range roles {
+		for _, method := range methods {
+			if _, err := path.Match(method, ""); err != nil {
+				return nil, fmt.Errorf("invalid method %q in role %v: %w", method, role, err)
+			}
+		}
+	}
+	for subject, subjectRoles := range policy.Subjects {
+		for _, role := range subjectRoles {
+			if _, ok := roles[role]; !ok {
+				return nil, fmt.Errorf("unknown role %v for subject %v", role, subject)
+			}
+		}
+	}
+	tokens := make(map[string]string, len(policy.Tokens))
+	for hash, subject := range policy.Tokens {
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("token of %v is not a hex encoded SHA-256 hash", subject)
+		}
+		tokens[strings.ToLower(hash)] = subject
+	}
+	return &Authorizer{roles: roles, subjects: policy.Subjects, tokens: tokens}, nil
+}
+
+// Authorize returns PermissionDenied error if none of the caller identities
+// has a role allowing to call fullMethod
+func (a *Authorizer) Authorize(ctx context.Context, fullMethod string) error {
+	identities := callerIdentities(ctx)
+	for _, hash := range bearerTokenHashes(ctx) {
+		if identity, ok := a.tokens[hash]; ok {
+			identities = append(identities, identity)
+		}
+	}
+	for _, identity := range identities {
+		for _, role := range a.subjects[identity] {
+			if a.roleAllows(role, fullMethod) {
+				return nil
+			}
+		}
+	}
+	msg := fmt.Sprintf("caller %v is not allowed to call %v", identities, fullMethod)
+	log.Print(msg)
+	return status.Errorf(codes.PermissionDenied, msg)
+}
+
+func (a *Authorizer) roleAllows(role string, fullMethod string) bool {
+	for _, pattern := range a.roles[role] {
+		if ok, _ := path.Match(pattern, fullMethod); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// UnaryInterceptor rejects unary calls not allowed by the policy
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.Authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor rejects streaming calls not allowed by the policy
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.Authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// callerIdentities returns identities of the verified client certificate
+func callerIdentities(ctx context.Context) []string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return CertificateIdentities(tlsInfo.State.VerifiedChains[0][0])
+}
+
+// bearerTokenHashes returns hex encoded SHA-256 hashes of all bearer
+// tokens found in the authorization metadata
+func bearerTokenHashes(ctx context.Context) []string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	var hashes []string
+	for _, value := range md.Get("authorization") {
+		scheme, token, found := strings.Cut(value, " ")
+		if !found || !strings.EqualFold(scheme, "bearer") || token == "" {
+			continue
+		}
+		hash := sha256.Sum256([]byte(token))
+		hashes = append(hashes, hex.EncodeToString(hash[:]))
+	}
+	return hashes
+}
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package server implements the server
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const testPolicy = `
+roles:
+  subsystem-reader:
+    - /opi_api.storage.v1.FrontendNvmeService/GetNVMeSubsystem
+subjects:
+  frontend-client: [frontend-operator]
+  reader-client: [read-only, subsystem-reader]
+  crypto-client: [crypto-admin]
+  ops-team: [backend-operator]
+tokens:
+  %v: ops-team
+`
+
+func tokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func certContext(commonName string) context.Context {
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+		},
+	})
+}
+
+func tokenContext(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("authorization", "Bearer "+token))
+}
+
+func TestAuthorizer_Authorize(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "policy.yaml")
+	policy := []byte(fmt.Sprintf(testPolicy, tokenHash("secret-token")))
+	if err := os.WriteFile(file, policy, 0600); err != nil {
+		t.Fatal(err)
+	}
+	authorizer, err := LoadAuthorizer(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		ctx     context.Context
+		method  string
+		allowed bool
+	}{
+		"frontend operator creates subsystem": {
+			ctx:     certContext("frontend-client"),
+			method:  "/opi_api.storage.v1.FrontendNvmeService/CreateNVMeSubsystem",
+			allowed: true,
+		},
+		"frontend operator updates subsystem hosts": {
+			ctx:     certContext("frontend-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/UpdateNVMeSubsystemHosts",
+			allowed: true,
+		},
+		"reader gets subsystem hosts": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/GetNVMeSubsystemHosts",
+			allowed: true,
+		},
+		"reader updates subsystem hosts": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/UpdateNVMeSubsystemHosts",
+			allowed: false,
+		},
+		"reader gets controller ANA state": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/GetNVMeControllerAnaState",
+			allowed: true,
+		},
+		"reader sets controller ANA state": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/SetNVMeControllerAnaState",
+			allowed: false,
+		},
+		"frontend operator adds discovery listener": {
+			ctx:     certContext("frontend-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/AddNVMeDiscoveryListener",
+			allowed: true,
+		},
+		"reader lists discovery referrals": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/ListNVMeDiscoveryReferrals",
+			allowed: true,
+		},
+		"frontend operator gets virtio-blk capacity": {
+			ctx:     certContext("frontend-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/GetVirtioBlkCapacity",
+			allowed: true,
+		},
+		"reader sets virtio-blk options": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/SetVirtioBlkOptions",
+			allowed: false,
+		},
+		"backend operator gets namespace capacity": {
+			ctx:     tokenContext("secret-token"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/GetNVMeNamespaceCapacity",
+			allowed: false,
+		},
+		"crypto admin adds keyring key": {
+			ctx:     certContext("crypto-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/AddKeyringKey",
+			allowed: true,
+		},
+		"frontend operator adds keyring key": {
+			ctx:     certContext("frontend-client"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/AddKeyringKey",
+			allowed: false,
+		},
+		"backend operator sets remote controller auth": {
+			ctx:     tokenContext("secret-token"),
+			method:  "/opi_spdk_bridge.v1alpha1.ExtensionService/SetNVMfRemoteControllerAuth",
+			allowed: true,
+		},
+		"frontend operator creates aio controller": {
+			ctx:     certContext("frontend-client"),
+			method:  "/opi_api.storage.v1.AioControllerService/CreateAioController",
+			allowed: false,
+		},
+		"read only lists controllers": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_api.storage.v1.FrontendNvmeService/ListNVMeControllers",
+			allowed: true,
+		},
+		"read only gets stats": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_api.storage.v1.FrontendNvmeService/NVMeNamespaceStats",
+			allowed: true,
+		},
+		"read only deletes subsystem": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_api.storage.v1.FrontendNvmeService/DeleteNVMeSubsystem",
+			allowed: false,
+		},
+		"read only gets encrypted volume": {
+			ctx:     certContext("reader-client"),
+			method:  "/opi_api.storage.v1.MiddleendEncryptionService/GetEncryptedVolume",
+			allowed: false,
+		},
+		"read only uses reflection": {
+			ctx:     certContext("reader-client"),
+			method:  "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+			allowed: true,
+		},
+		"crypto admin creates encrypted volume": {
+			ctx:     certContext("crypto-client"),
+			method:  "/opi_api.storage.v1.MiddleendEncryptionService/CreateEncryptedVolume",
+			allowed: true,
+		},
+		"bearer token of backend operator": {
+			ctx:     tokenContext("secret-token"),
+			method:  "/opi_api.storage.v1.NullDebugService/DeleteNullDebug",
+			allowed: true,
+		},
+		"unknown bearer token": {
+			ctx:     tokenContext("wrong-token"),
+			method:  "/opi_api.storage.v1.NullDebugService/DeleteNullDebug",
+			allowed: false,
+		},
+		"unknown certificate": {
+			ctx:     certContext("stranger"),
+			method:  "/opi_api.storage.v1.FrontendNvmeService/ListNVMeSubsystems",
+			allowed: false,
+		},
+		"anonymous caller": {
+			ctx:     context.Background(),
+			method:  "/opi_api.storage.v1.FrontendNvmeService/ListNVMeSubsystems",
+			allowed: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := authorizer.Authorize(tt.ctx, tt.method)
+			if tt.allowed && err != nil {
+				t.Error("expected call to be allowed, received", err)
+			}
+			if !tt.allowed && status.Code(err) != codes.PermissionDenied {
+				t.Error("expected PermissionDenied, received", err)
+			}
+		})
+	}
+}
+
+func TestNewAuthorizer_InvalidPolicy(t *testing.T) {
+	tests := map[string]AuthorizationPolicy{
+		"unknown role": {
+			Subjects: map[string][]string{"client": {"super-admin"}},
+		},
+		"bad method pattern": {
+			Roles: map[string][]string{"broken": {"/opi_api.storage.v1.[/*"}},
+		},
+		"token is not a hash": {
+			Tokens: map[string]string{"secret-token": "client"},
+		},
+	}
+	for name, policy := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewAuthorizer(policy); err == nil {
+				t.Error("expected error for invalid policy")
+			}
+		})
+	}
+}
+
+func TestAuthorizer_Interceptors(t *testing.T) {
+	authorizer, err := NewAuthorizer(AuthorizationPolicy{
+		Subjects: map[string][]string{"frontend-client": {"frontend-operator"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	method := "/opi_api.storage.v1.FrontendNvmeService/DeleteNVMeSubsystem"
+
+	tests := map[string]struct {
+		ctx        context.Context
+		wantCalled bool
+	}{
+		"allowed caller":  {certContext("frontend-client"), true},
+		"rejected caller": {certContext("other-client"), false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			called := false
+			unary := func(context.Context, interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			}
+			_, err := authorizer.UnaryInterceptor()(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, unary)
+			if called != tt.wantCalled || (err == nil) != tt.wantCalled {
+				t.Error("unary handler: expected call", tt.wantCalled, "received", called, err)
+			}
+
+			called = false
+			stream := func(interface{}, grpc.ServerStream) error {
+				called = true
+				return nil
+			}
+			err = authorizer.StreamInterceptor()(nil, &testServerStream{ctx: tt.ctx}, &grpc.StreamServerInfo{FullMethod: method}, stream)
+			if called != tt.wantCalled || (err == nil) != tt.wantCalled {
+				t.Error("stream handler: expected call", tt.wantCalled, "received", called, err)
+			}
+		})
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package server implements the server
+package server
+
+import (
+	"sort"
+	"sync"
+)
+
+// KeyLocker serializes operations on the same object keys, while operations
+// on different keys proceed in parallel
+type KeyLocker struct {
+	all   sync.RWMutex
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewKeyLocker creates an instance of KeyLocker
+func NewKeyLocker() *KeyLocker {
+	return &KeyLocker{locks: make(map[string]*keyLock)}
+}
+
+// LockKey builds a lock key for the object with id of the given kind, so
+// objects of different kinds with the same id do not block each other
+func LockKey(kind string, id string) string {
+	return kind + "/" + id
+}
+
+// Lock locks all keys and returns a function unlocking them. Keys are always
+// locked in sorted order, so callers locking overlapping sets of keys, e.g.
+// a parent and a child object, cannot deadlock each other.
+func (l *KeyLocker) Lock(keys ...string) (unlock func()) {
+	keys = uniqueSorted(keys)
+	l.all.RLock()
+	locks := make([]*keyLock, len(keys))
+	for i, key := range keys {
+		locks[i] = l.acquire(key)
+		locks[i].Lock()
+	}
+	return func() {
+		for i := len(keys) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+			l.release(keys[i])
+		}
+		l.all.RUnlock()
+	}
+}
+
+// LockAll waits for all operations holding keys to finish and blocks new
+// ones until the returned function is called
+func (l *KeyLocker) LockAll() (unlock func()) {
+	l.all.Lock()
+	return l.all.Unlock
+}
+
+func (l *KeyLocker) acquire(key string) *keyLock {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &keyLock{}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	return lock
+}
+
+func (l *KeyLocker) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock := l.locks[key]
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, key)
+	}
+}
+
+func uniqueSorted(keys []string) []string {
+	sorted := make([]string, 0, len(keys))
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if !seen[key] {
+			seen[key] = true
+			sorted = append(sorted, key)
+		}
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package server implements the server
+package server
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyLocker_SameKeySerialized(t *testing.T) {
+	locker := NewKeyLocker()
+	counter := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock := locker.Lock("key")
+			defer unlock()
+			counter++
+		}()
+	}
+	wg.Wait()
+	if counter != 100 {
+		t.Error("expected 100 increments, received", counter)
+	}
+	if len(locker.locks) != 0 {
+		t.Error("expected all locks to be released, received", locker.locks)
+	}
+}
+
+func TestKeyLocker_DifferentKeysInParallel(t *testing.T) {
+	locker := NewKeyLocker()
+	unlock := locker.Lock("key1")
+	defer unlock()
+
+	done := make(chan struct{})
+	go func() {
+		unlock := locker.Lock("key2")
+		unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("lock of a different key is blocked")
+	}
+}
+
+func TestKeyLocker_OverlappingKeys(t *testing.T) {
+	locker := NewKeyLocker()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			unlock := locker.Lock("parent", "child", "parent")
+			unlock()
+		}()
+		go func() {
+			defer wg.Done()
+			unlock := locker.Lock("child", "parent")
+			unlock()
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock locking overlapping keys")
+	}
+}
+
+func TestKeyLocker_LockAll(t *testing.T) {
+	locker := NewKeyLocker()
+	unlockAll := locker.LockAll()
+
+	locked := make(chan struct{})
+	go func() {
+		unlock := locker.Lock("key")
+		close(locked)
+		unlock()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("key locked while all keys are locked")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unlockAll()
+	select {
+	case <-locked:
+	case <-time.After(time.Second):
+		t.Error("key is not locked after all keys are unlocked")
+	}
+}
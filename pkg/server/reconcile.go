@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package server implements the server
+package server
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// ReconcilePolicy selects what is done with differences found between the
+// objects known to the bridge and the objects actually present in SPDK.
+// Differences are always reported regardless of the policy.
+type ReconcilePolicy struct {
+	// Recreate re-creates objects known to the bridge but missing in SPDK
+	Recreate bool
+	// Adopt adds objects present in SPDK but unknown to the bridge into the bridge state
+	Adopt bool
+}
+
+// ParseReconcilePolicy parses a comma separated list of reconcile actions.
+// Supported actions are "report", "recreate" and "adopt".
+func ParseReconcilePolicy(actions string) (ReconcilePolicy, error) {
+	policy := ReconcilePolicy{}
+	for _, action := range strings.Split(actions, ",") {
+		switch strings.TrimSpace(action) {
+		case "report", "":
+		case "recreate":
+			policy.Recreate = true
+		case "adopt":
+			policy.Adopt = true
+		default:
+			return policy, fmt.Errorf("unknown reconcile action %q", action)
+		}
+	}
+	return policy, nil
+}
+
+// DriftKind describes in which direction bridge and SPDK states diverged
+type DriftKind string
+
+const (
+	// DriftMissingInSpdk means an object is known to the bridge but not present in SPDK
+	DriftMissingInSpdk DriftKind = "missing in SPDK"
+	// DriftUnknownToBridge means an object is present in SPDK but not known to the bridge
+	DriftUnknownToBridge DriftKind = "unknown to bridge"
+)
+
+// Drift describes a single difference between bridge and SPDK states and
+// how it was resolved
+type Drift struct {
+	Object     string
+	ID         string
+	Kind       DriftKind
+	Resolution string
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%v %v is %v: %v", d.Object, d.ID, d.Kind, d.Resolution)
+}
+
+// ResolveDrift runs fix when the policy enables the action suitable for the
+// drift kind and records the outcome in the drift resolution
+func ResolveDrift(d Drift, policy ReconcilePolicy, fix func() error) Drift {
+	action, done, enabled := "recreate", "recreated", policy.Recreate
+	if d.Kind == DriftUnknownToBridge {
+		action, done, enabled = "adopt", "adopted", policy.Adopt
+	}
+	if !enabled {
+		d.Resolution = "reported"
+		return d
+	}
+	if err := fix(); err != nil {
+		log.Printf("error: %v", err)
+		d.Resolution = fmt.Sprintf("failed to %v: %v", action, err)
+		return d
+	}
+	d.Resolution = done
+	return d
+}
+
+// Resolved reports if the drift was fixed
+func (d Drift) Resolved() bool {
+	return d.Resolution == "recreated" || d.Resolution == "adopted"
+}
+
+// Reconciler is implemented by servers which are able to compare their state
+// with the objects present in SPDK
+type Reconciler interface {
+	Reconcile(policy ReconcilePolicy) ([]Drift, error)
+}
+
+// Reconcile runs all reconcilers with the policy and logs every found drift.
+// All reconcilers are run even if some of them fail, the first error is returned.
+func Reconcile(policy ReconcilePolicy, reconcilers ...Reconciler) ([]Drift, error) {
+	var drifts []Drift
+	var firstErr error
+	for _, r := range reconcilers {
+		found, err := r.Reconcile(policy)
+		if err != nil {
+			log.Printf("error: reconciliation failed: %v", err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		drifts = append(drifts, found...)
+	}
+	for _, d := range drifts {
+		log.Printf("Drift: %v", d)
+	}
+	log.Printf("Reconciliation finished, %d drift(s) found", len(drifts))
+	return drifts, firstErr
+}
+
+// SortedKeys returns keys of the map in sorted order, so the objects are
+// processed in the same order on every reconciliation
+func SortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ReconcileObjects compares objects known to the bridge with objects present
+// in SPDK by their keys. Missing objects are passed to recreate and unknown
+// objects are passed to adopt according to the policy.
+func ReconcileObjects[K any, P any](object string, policy ReconcilePolicy,
+	known map[string]K, present map[string]P, recreate func(K) error, adopt func(string, P) error) []Drift {
+	var drifts []Drift
+	for _, id := range SortedKeys(known) {
+		if _, ok := present[id]; ok {
+			continue
+		}
+		obj := known[id]
+		drifts = append(drifts, ResolveDrift(
+			Drift{Object: object, ID: id, Kind: DriftMissingInSpdk},
+			policy, func() error { return recreate(obj) }))
+	}
+	for _, id := range SortedKeys(present) {
+		if _, ok := known[id]; ok {
+			continue
+		}
+		obj := present[id]
+		drifts = append(drifts, ResolveDrift(
+			Drift{Object: object, ID: id, Kind: DriftUnknownToBridge},
+			policy, func() error { return adopt(id, obj) }))
+	}
+	return drifts
+}
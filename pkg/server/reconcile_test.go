@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package server implements the server
+package server
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseReconcilePolicy(t *testing.T) {
+	tests := map[string]struct {
+		in     string
+		out    ReconcilePolicy
+		hasErr bool
+	}{
+		"empty":               {"", ReconcilePolicy{}, false},
+		"report only":         {"report", ReconcilePolicy{}, false},
+		"recreate":            {"report,recreate", ReconcilePolicy{Recreate: true}, false},
+		"recreate and adopt":  {"recreate, adopt", ReconcilePolicy{Recreate: true, Adopt: true}, false},
+		"unknown action":      {"report,delete", ReconcilePolicy{}, true},
+		"misspelled recreate": {"recreat", ReconcilePolicy{}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy, err := ParseReconcilePolicy(tt.in)
+			if (err != nil) != tt.hasErr {
+				t.Error("expected error", tt.hasErr, "received", err)
+			}
+			if err == nil && policy != tt.out {
+				t.Error("expected", tt.out, "received", policy)
+			}
+		})
+	}
+}
+
+func TestReconcileObjects(t *testing.T) {
+	known := map[string]int{"a": 1, "b": 2}
+	present := map[string]int{"b": 2, "c": 3}
+	tests := map[string]struct {
+		policy   ReconcilePolicy
+		fixErr   error
+		drifts   []Drift
+		recreate []int
+		adopt    []string
+	}{
+		"report": {
+			ReconcilePolicy{},
+			nil,
+			[]Drift{
+				{Object: "obj", ID: "a", Kind: DriftMissingInSpdk, Resolution: "reported"},
+				{Object: "obj", ID: "c", Kind: DriftUnknownToBridge, Resolution: "reported"},
+			},
+			nil,
+			nil,
+		},
+		"recreate and adopt": {
+			ReconcilePolicy{Recreate: true, Adopt: true},
+			nil,
+			[]Drift{
+				{Object: "obj", ID: "a", Kind: DriftMissingInSpdk, Resolution: "recreated"},
+				{Object: "obj", ID: "c", Kind: DriftUnknownToBridge, Resolution: "adopted"},
+			},
+			[]int{1},
+			[]string{"c"},
+		},
+		"failed fix": {
+			ReconcilePolicy{Recreate: true, Adopt: true},
+			errors.New("boom"),
+			[]Drift{
+				{Object: "obj", ID: "a", Kind: DriftMissingInSpdk, Resolution: "failed to recreate: boom"},
+				{Object: "obj", ID: "c", Kind: DriftUnknownToBridge, Resolution: "failed to adopt: boom"},
+			},
+			[]int{1},
+			[]string{"c"},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var recreated []int
+			var adopted []string
+			drifts := ReconcileObjects("obj", tt.policy, known, present,
+				func(v int) error { recreated = append(recreated, v); return tt.fixErr },
+				func(id string, _ int) error { adopted = append(adopted, id); return tt.fixErr })
+			if !reflect.DeepEqual(drifts, tt.drifts) {
+				t.Error("expected", tt.drifts, "received", drifts)
+			}
+			if !reflect.DeepEqual(recreated, tt.recreate) {
+				t.Error("expected recreated", tt.recreate, "received", recreated)
+			}
+			if !reflect.DeepEqual(adopted, tt.adopt) {
+				t.Error("expected adopted", tt.adopt, "received", adopted)
+			}
+		})
+	}
+}
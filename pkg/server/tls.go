@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package server implements the server
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// TLSConfig defines the certificates used to secure the gRPC endpoint
+type TLSConfig struct {
+	// CertFile and KeyFile contain the server certificate and its key in PEM format.
+	// TLS is disabled if they are empty.
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	// ClientCAFile contains PEM encoded CA certificates used to verify client
+	// certificates. Client certificates are required if it is set (mutual TLS).
+	ClientCAFile string `yaml:"client_ca_file"`
+	// AllowedClients lists identities of clients allowed to connect. An identity
+	// is the common name or any DNS, URI or email SAN of the client certificate.
+	// Any client with a valid certificate is allowed if it is empty.
+	AllowedClients []string `yaml:"allowed_clients,omitempty"`
+}
+
+// Enabled reports whether TLS is configured
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// Validate checks that the TLS settings are consistent
+func (c TLSConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("both cert_file and key_file must be set")
+	}
+	if c.ClientCAFile != "" && !c.Enabled() {
+		return errors.New("client_ca_file requires cert_file and key_file")
+	}
+	if len(c.AllowedClients) != 0 && c.ClientCAFile == "" {
+		return errors.New("allowed_clients requires client_ca_file")
+	}
+	return nil
+}
+
+// NewServerTLSConfig creates TLS configuration for the gRPC server.
+// The certificate files are checked on every handshake and reloaded
+// if they were modified, so certificates can be rotated without a restart.
+func NewServerTLSConfig(c TLSConfig) (*tls.Config, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	r := &certReloader{config: c}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: r.getConfigForClient,
+	}, nil
+}
+
+// http2Protocol is the ALPN protocol ID of HTTP/2 which gRPC runs on
+const http2Protocol = "h2"
+
+// certReloader keeps the last successfully loaded certificates
+// and the modification times of the files they were loaded from
+type certReloader struct {
+	config TLSConfig
+
+	mu       sync.Mutex
+	modTimes map[string]time.Time
+	current  *tls.Config
+}
+
+func (r *certReloader) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.modified() {
+		if err := r.load(); err != nil {
+			// keep serving the previous certificates until the files are fixed
+			log.Printf("error: cannot reload TLS certificates: %v", err)
+		}
+	}
+	return r.current, nil
+}
+
+func (r *certReloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.load()
+}
+
+func (r *certReloader) files() []string {
+	files := []string{r.config.CertFile, r.config.KeyFile}
+	if r.config.ClientCAFile != "" {
+		files = append(files, r.config.ClientCAFile)
+	}
+	return files
+}
+
+func (r *certReloader) modified() bool {
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil || !info.ModTime().Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *certReloader) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return err
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		// the config returned for a client replaces the base config from
+		// credentials.NewTLS, so HTTP/2 has to be offered for ALPN here again
+		NextProtos: []string{http2Protocol},
+	}
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(filepath.Clean(r.config.ClientCAFile))
+		if err != nil {
+			return err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %v", r.config.ClientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.VerifyConnection = allowClients(r.config.AllowedClients)
+	}
+
+	r.current = config
+	r.modTimes = modTimes
+	log.Printf("Loaded TLS certificate from %v", r.config.CertFile)
+	return nil
+}
+
+// allowClients rejects connections from verified clients whose certificate
+// does not carry any of the allowed identities
+func allowClients(allowed []string) func(tls.ConnectionState) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, id := range allowed {
+		allowedSet[id] = true
+	}
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("client certificate is required")
+		}
+		for _, id := range CertificateIdentities(cs.PeerCertificates[0]) {
+			if allowedSet[id] {
+				return nil
+			}
+		}
+		return fmt.Errorf("client %v is not allowed", cs.PeerCertificates[0].Subject)
+	}
+}
+
+// CertificateIdentities returns all identities carried by a certificate:
+// its common name and DNS, URI and email SANs
+func CertificateIdentities(cert *x509.Certificate) []string {
+	var ids []string
+	if cert.Subject.CommonName != "" {
+		ids = append(ids, cert.Subject.CommonName)
+	}
+	ids = append(ids, cert.DNSNames...)
+	for _, uri := range cert.URIs {
+		ids = append(ids, uri.String())
+	}
+	ids = append(ids, cert.EmailAddresses...)
+	return ids
+}
@@ -0,0 +1,361 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package server implements the server
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	tls  tls.Certificate
+}
+
+// newTestCert creates a certificate signed by ca or a self-signed CA if ca is nil
+func newTestCert(t *testing.T, ca *testCert, template *x509.Certificate) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.SerialNumber, err = rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	parent, signer := template, key
+	if ca == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign
+	} else {
+		parent, signer = ca.cert, ca.key
+		template.KeyUsage = x509.KeyUsageDigitalSignature
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key, tls: tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}}
+}
+
+func newServerCert(t *testing.T, ca *testCert) *testCert {
+	return newTestCert(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "opi-spdk-bridge"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+}
+
+func newClientCert(t *testing.T, ca *testCert, name string) *testCert {
+	return newTestCert(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: name},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+}
+
+// writeFiles saves certificate and key in PEM format and moves their
+// modification time forward, so the change is noticed by the reloader
+func (c *testCert) writeFiles(t *testing.T, certFile string, keyFile string) {
+	t.Helper()
+	certPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw})
+	der, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPem := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	writeFileWithNewModTime(t, certFile, certPem)
+	writeFileWithNewModTime(t, keyFile, keyPem)
+}
+
+func writeFileWithNewModTime(t *testing.T, file string, data []byte) {
+	t.Helper()
+	modTime := time.Now()
+	if info, err := os.Stat(file); err == nil {
+		modTime = info.ModTime().Add(time.Second)
+	}
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// startTLSServer accepts connections and writes a single byte to every
+// client which completes the handshake
+func startTLSServer(t *testing.T, config *tls.Config) string {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = lis.Close() })
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				if err := conn.(*tls.Conn).Handshake(); err == nil {
+					_, _ = conn.Write([]byte{1})
+				}
+			}()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+// connect returns the certificate presented by the server or an error
+// if the server rejected the client
+func connect(addr string, ca *testCert, client *testCert) (*x509.Certificate, error) {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    pool,
+		ServerName: "localhost",
+	}
+	if client != nil {
+		config.Certificates = []tls.Certificate{client.tls}
+	}
+	conn, err := tls.Dial("tcp", addr, config)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	// with TLS 1.3 the server verifies the client after the client handshake ends
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return nil, err
+	}
+	return conn.ConnectionState().PeerCertificates[0], nil
+}
+
+func TestTLSConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		config  TLSConfig
+		wantErr bool
+	}{
+		"disabled":             {TLSConfig{}, false},
+		"server tls":           {TLSConfig{CertFile: "c", KeyFile: "k"}, false},
+		"mutual tls":           {TLSConfig{CertFile: "c", KeyFile: "k", ClientCAFile: "ca", AllowedClients: []string{"a"}}, false},
+		"missing key":          {TLSConfig{CertFile: "c"}, true},
+		"missing cert":         {TLSConfig{KeyFile: "k"}, true},
+		"client ca only":       {TLSConfig{ClientCAFile: "ca"}, true},
+		"allowlist without ca": {TLSConfig{CertFile: "c", KeyFile: "k", AllowedClients: []string{"a"}}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Error("expected error", tt.wantErr, "received", err)
+			}
+		})
+	}
+}
+
+func TestNewServerTLSConfig(t *testing.T) {
+	ca := newTestCert(t, nil, &x509.Certificate{Subject: pkix.Name{CommonName: "test ca"}})
+	otherCa := newTestCert(t, nil, &x509.Certificate{Subject: pkix.Name{CommonName: "other ca"}})
+	allowedClient := newClientCert(t, ca, "allowed-client")
+	otherClient := newClientCert(t, ca, "other-client")
+	untrustedClient := newClientCert(t, otherCa, "allowed-client")
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.pem")
+	keyFile := filepath.Join(dir, "server.key")
+	caFile := filepath.Join(dir, "ca.pem")
+	newServerCert(t, ca).writeFiles(t, certFile, keyFile)
+	writeFileWithNewModTime(t, caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+
+	tests := map[string]struct {
+		clientCA  string
+		allowed   []string
+		client    *testCert
+		wantError bool
+	}{
+		"server tls without client certificate": {
+			clientCA:  "",
+			allowed:   nil,
+			client:    nil,
+			wantError: false,
+		},
+		"mutual tls without client certificate": {
+			clientCA:  caFile,
+			allowed:   nil,
+			client:    nil,
+			wantError: true,
+		},
+		"mutual tls with trusted client": {
+			clientCA:  caFile,
+			allowed:   nil,
+			client:    otherClient,
+			wantError: false,
+		},
+		"mutual tls with untrusted client": {
+			clientCA:  caFile,
+			allowed:   nil,
+			client:    untrustedClient,
+			wantError: true,
+		},
+		"allowed client": {
+			clientCA:  caFile,
+			allowed:   []string{"allowed-client"},
+			client:    allowedClient,
+			wantError: false,
+		},
+		"client not in allowlist": {
+			clientCA:  caFile,
+			allowed:   []string{"allowed-client"},
+			client:    otherClient,
+			wantError: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config, err := NewServerTLSConfig(TLSConfig{
+				CertFile:       certFile,
+				KeyFile:        keyFile,
+				ClientCAFile:   tt.clientCA,
+				AllowedClients: tt.allowed,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			addr := startTLSServer(t, config)
+
+			_, err = connect(addr, ca, tt.client)
+			if (err != nil) != tt.wantError {
+				t.Error("expected error", tt.wantError, "received", err)
+			}
+		})
+	}
+}
+
+func TestNewServerTLSConfig_Reload(t *testing.T) {
+	ca := newTestCert(t, nil, &x509.Certificate{Subject: pkix.Name{CommonName: "test ca"}})
+	firstCert := newServerCert(t, ca)
+	secondCert := newServerCert(t, ca)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.pem")
+	keyFile := filepath.Join(dir, "server.key")
+	firstCert.writeFiles(t, certFile, keyFile)
+
+	config, err := NewServerTLSConfig(TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := startTLSServer(t, config)
+
+	expectServerCert := func(want *testCert) {
+		t.Helper()
+		cert, err := connect(addr, ca, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cert.SerialNumber.Cmp(want.cert.SerialNumber) != 0 {
+			t.Error("expected certificate", want.cert.SerialNumber, "received", cert.SerialNumber)
+		}
+	}
+
+	expectServerCert(firstCert)
+	secondCert.writeFiles(t, certFile, keyFile)
+	expectServerCert(secondCert)
+
+	// broken files are not loaded, the last valid certificate is still used
+	writeFileWithNewModTime(t, certFile, []byte("not a certificate"))
+	expectServerCert(secondCert)
+}
+
+func TestNewServerTLSConfig_ALPN(t *testing.T) {
+	ca := newTestCert(t, nil, &x509.Certificate{Subject: pkix.Name{CommonName: "test ca"}})
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.pem")
+	keyFile := filepath.Join(dir, "server.key")
+	newServerCert(t, ca).writeFiles(t, certFile, keyFile)
+	config, err := NewServerTLSConfig(TLSConfig{CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(config)))
+	go func() { _ = s.Serve(lis) }()
+	defer s.Stop()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	conn, err := tls.Dial("tcp", lis.Addr().String(), &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    pool,
+		ServerName: "localhost",
+		NextProtos: []string{"h2"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if protocol := conn.ConnectionState().NegotiatedProtocol; protocol != "h2" {
+		t.Errorf("expected negotiated protocol h2, received %q", protocol)
+	}
+}
+
+func TestNewServerTLSConfig_MissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, err := NewServerTLSConfig(TLSConfig{
+		CertFile: filepath.Join(dir, "server.pem"),
+		KeyFile:  filepath.Join(dir, "server.key"),
+	})
+	if err == nil {
+		t.Error("expected error for missing certificate files")
+	}
+}
+
+func TestCertificateIdentities(t *testing.T) {
+	uri, err := url.Parse("spiffe://opi/client")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "client"},
+		DNSNames:       []string{"client.opi"},
+		URIs:           []*url.URL{uri},
+		EmailAddresses: []string{"client@opi"},
+	}
+	want := []string{"client", "client.opi", "spiffe://opi/client", "client@opi"}
+	if ids := CertificateIdentities(cert); !reflect.DeepEqual(ids, want) {
+		t.Error("expected", want, "received", ids)
+	}
+}
@@ -7,6 +7,7 @@ package server
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"log"
 	"math/big"
@@ -14,10 +15,13 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	"github.com/opiproject/gospdk/spdk"
 )
@@ -33,19 +37,42 @@ func (r *Rectangle) containsBad(x, y float64) bool {
 		y <= r.y+r.height
 }
 
+// PaginationLimits holds the page sizes used by List calls
+type PaginationLimits struct {
+	// DefaultPageSize is used when no PageSize is requested
+	DefaultPageSize int `yaml:"default_page_size"`
+	// MaxPageSize caps the requested PageSize
+	MaxPageSize int `yaml:"max_page_size"`
+}
+
+// DefaultPaginationLimits returns the page sizes used when nothing is configured
+func DefaultPaginationLimits() PaginationLimits {
+	return PaginationLimits{
+		DefaultPageSize: 50,
+		MaxPageSize:     250,
+	}
+}
+
+// Validate checks that the limits can be used for pagination
+func (l PaginationLimits) Validate() error {
+	if l.DefaultPageSize <= 0 {
+		return fmt.Errorf("default page size must be positive, got %d", l.DefaultPageSize)
+	}
+	if l.MaxPageSize < l.DefaultPageSize {
+		return fmt.Errorf("max page size %d is less than default page size %d", l.MaxPageSize, l.DefaultPageSize)
+	}
+	return nil
+}
+
 // ExtractPagination is a helper function for List pagination to fetch PageSize and PageToken
-func ExtractPagination(pageSize int32, pageToken string, pagination map[string]int) (size int, offset int, err error) {
-	const (
-		maxPageSize     = 250
-		defaultPageSize = 50
-	)
+func (l PaginationLimits) ExtractPagination(pageSize int32, pageToken string, pagination map[string]int) (size int, offset int, err error) {
 	switch {
 	case pageSize < 0:
 		return -1, -1, status.Error(codes.InvalidArgument, "negative PageSize is not allowed")
 	case pageSize == 0:
-		size = defaultPageSize
-	case pageSize > maxPageSize:
-		size = maxPageSize
+		size = l.DefaultPageSize
+	case int(pageSize) > l.MaxPageSize:
+		size = l.MaxPageSize
 	default:
 		size = int(pageSize)
 	}
@@ -62,6 +89,12 @@ func ExtractPagination(pageSize int32, pageToken string, pagination map[string]i
 	return size, offset, nil
 }
 
+// ExtractPagination is a helper function for List pagination to fetch PageSize and PageToken
+// using the default page sizes
+func ExtractPagination(pageSize int32, pageToken string, pagination map[string]int) (size int, offset int, err error) {
+	return DefaultPaginationLimits().ExtractPagination(pageSize, pageToken, pagination)
+}
+
 // LimitPagination is a helper function for slice the result by offset and size
 func LimitPagination[T any](result []T, offset int, size int) ([]T, bool) {
 	end := offset + size
@@ -74,14 +107,74 @@ func LimitPagination[T any](result []T, offset int, size int) ([]T, bool) {
 	return result[offset:end], hasMoreElements
 }
 
+// spdkProbeTimeout limits how long ProbeSpdk waits for a connection
+const spdkProbeTimeout = time.Second
+
+// ProbeSpdk checks that SPDK accepts connections on address, detecting the
+// protocol the same way as spdk.NewSpdkJSONRPC. Calls to SPDK which cannot be
+// reached terminate the bridge, so background calls should be skipped if it fails.
+func ProbeSpdk(address string) error {
+	protocol := "tcp"
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		protocol = "unix"
+	}
+	conn, err := net.DialTimeout(protocol, address, spdkProbeTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
 // CreateTestSpdkServer creates a mock spdk server for testing
 func CreateTestSpdkServer(socket string, startSpdkServer bool, spdkResponses []string) (net.Listener, spdk.JSONRPC) {
+	ln, jsonRPC, _ := CreateTestSpdkRecordingServer(socket, startSpdkServer, spdkResponses)
+	return ln, jsonRPC
+}
+
+// CreateTestSpdkRecordingServer creates a mock spdk server for testing which
+// also passes the requests it receives to the returned channel
+func CreateTestSpdkRecordingServer(socket string, startSpdkServer bool, spdkResponses []string) (net.Listener, spdk.JSONRPC, <-chan string) {
 	jsonRPC := spdk.NewSpdkJSONRPC(socket)
 	ln := jsonRPC.StartUnixListener()
+	requests := make(chan string, len(spdkResponses))
 	if startSpdkServer {
-		go spdkMockServerCommunicate(jsonRPC, ln, spdkResponses)
+		go spdkMockServerCommunicate(jsonRPC, ln, spdkResponses, requests)
 	}
-	return ln, jsonRPC.(*spdk.SpdkJSONRPC)
+	return ln, jsonRPC.(*spdk.SpdkJSONRPC), requests
+}
+
+// testSpdkStub answers SPDK calls with canned results by method name and can
+// be used concurrently, unlike the mock server answering in a fixed order
+type testSpdkStub struct {
+	id      uint64
+	results map[string]string
+}
+
+// CreateTestSpdkStub creates a thread-safe SPDK stub for testing. Every call
+// of a method gets the result from results decoded into the result argument.
+func CreateTestSpdkStub(results map[string]string) spdk.JSONRPC {
+	return &testSpdkStub{results: results}
+}
+
+func (s *testSpdkStub) GetID() uint64 {
+	return atomic.AddUint64(&s.id, 1)
+}
+
+func (s *testSpdkStub) StartUnixListener() net.Listener {
+	return nil
+}
+
+func (s *testSpdkStub) GetVersion() string {
+	return ""
+}
+
+func (s *testSpdkStub) Call(method string, _, result interface{}) error {
+	s.GetID()
+	data, ok := s.results[method]
+	if !ok {
+		return fmt.Errorf("%s: unexpected call", method)
+	}
+	return json.Unmarshal([]byte(data), result)
 }
 
 // CloseGrpcConnection is utility function used to defer grpc connection close is tests
@@ -110,7 +203,7 @@ func GenerateSocketName(testType string) string {
 	return filepath.Join(os.TempDir(), "opi-spdk-"+testType+"-test-"+fmt.Sprint(n)+".sock")
 }
 
-func spdkMockServerCommunicate(rpc spdk.JSONRPC, l net.Listener, toSend []string) {
+func spdkMockServerCommunicate(rpc spdk.JSONRPC, l net.Listener, toSend []string, requests chan<- string) {
 	for _, spdk := range toSend {
 		// wait for client to connect (accept stage)
 		fd, err := l.Accept()
@@ -133,6 +226,7 @@ func spdkMockServerCommunicate(rpc spdk.JSONRPC, l net.Listener, toSend []string
 			spdk = fmt.Sprintf(spdk, id)
 		}
 		log.Printf("SPDK mockup Server: got : %s", string(data))
+		requests <- string(data)
 		log.Printf("SPDK mockup Server: snd : %s", spdk)
 		// send data back to client
 		_, err = fd.Write([]byte(spdk))
@@ -151,3 +245,40 @@ func spdkMockServerCommunicate(rpc spdk.JSONRPC, l net.Listener, toSend []string
 		}
 	}
 }
+
+// UpdatedFields returns the set of field paths of mask which an Update call
+// should change. An empty mask or "*" selects all updatable paths, other
+// paths are rejected as InvalidArgument, e.g. immutable identifiers.
+func UpdatedFields(mask *fieldmaskpb.FieldMask, updatable ...string) (map[string]bool, error) {
+	fields := make(map[string]bool, len(updatable))
+	if len(mask.GetPaths()) == 0 {
+		for _, path := range updatable {
+			fields[path] = true
+		}
+		return fields, nil
+	}
+	for _, path := range mask.GetPaths() {
+		if path == "*" {
+			return UpdatedFields(nil, updatable...)
+		}
+		found := false
+		for _, u := range updatable {
+			if path == u {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, status.Errorf(codes.InvalidArgument, "field %q cannot be updated, updatable fields: %v",
+				path, strings.Join(updatable, ", "))
+		}
+		fields[path] = true
+	}
+	return fields, nil
+}
+
+// StoreError converts a failure to persist an object into a gRPC status,
+// so that clients see the change was not saved and can retry it
+func StoreError(err error) error {
+	return status.Errorf(codes.Internal, "cannot persist object: %v", err)
+}
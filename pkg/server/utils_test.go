@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package server implements the server
+package server
+
+import (
+	"net"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestPaginationLimits_ExtractPagination(t *testing.T) {
+	limits := PaginationLimits{DefaultPageSize: 5, MaxPageSize: 10}
+	pagination := map[string]int{"token": 7}
+	tests := map[string]struct {
+		pageSize   int32
+		pageToken  string
+		wantSize   int
+		wantOffset int
+		wantErr    bool
+	}{
+		"default page size": {0, "", 5, 0, false},
+		"requested size":    {8, "", 8, 0, false},
+		"capped size":       {20, "token", 10, 7, false},
+		"negative size":     {-1, "", -1, -1, true},
+		"unknown token":     {1, "unknown", -1, -1, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			size, offset, err := limits.ExtractPagination(tt.pageSize, tt.pageToken, pagination)
+			if (err != nil) != tt.wantErr {
+				t.Error("expected error", tt.wantErr, "received", err)
+			}
+			if size != tt.wantSize || offset != tt.wantOffset {
+				t.Error("expected", tt.wantSize, tt.wantOffset, "received", size, offset)
+			}
+		})
+	}
+}
+
+func TestPaginationLimits_Validate(t *testing.T) {
+	tests := map[string]struct {
+		limits  PaginationLimits
+		wantErr bool
+	}{
+		"defaults":              {DefaultPaginationLimits(), false},
+		"equal sizes":           {PaginationLimits{DefaultPageSize: 10, MaxPageSize: 10}, false},
+		"zero default":          {PaginationLimits{DefaultPageSize: 0, MaxPageSize: 10}, true},
+		"max less than default": {PaginationLimits{DefaultPageSize: 10, MaxPageSize: 5}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.limits.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Error("expected error", tt.wantErr, "received", err)
+			}
+		})
+	}
+}
+
+func TestProbeSpdk(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "spdk.sock")
+	if err := ProbeSpdk(socket); err == nil {
+		t.Error("expected error for SPDK which is not started")
+	}
+
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer CloseListener(ln)
+	if err := ProbeSpdk(socket); err != nil {
+		t.Error("expected started SPDK to be reachable, received", err)
+	}
+}
+
+func TestUpdatedFields(t *testing.T) {
+	updatable := []string{"spec.serial_number", "spec.model_number"}
+	tests := map[string]struct {
+		paths   []string
+		want    map[string]bool
+		wantErr bool
+	}{
+		"empty mask":    {nil, map[string]bool{"spec.serial_number": true, "spec.model_number": true}, false},
+		"wildcard":      {[]string{"*"}, map[string]bool{"spec.serial_number": true, "spec.model_number": true}, false},
+		"selected path": {[]string{"spec.model_number"}, map[string]bool{"spec.model_number": true}, false},
+		"immutable":     {[]string{"spec.nqn"}, nil, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var mask *fieldmaskpb.FieldMask
+			if tt.paths != nil {
+				mask = &fieldmaskpb.FieldMask{Paths: tt.paths}
+			}
+			fields, err := UpdatedFields(mask, updatable...)
+			if (err != nil) != tt.wantErr {
+				t.Fatal("expected error", tt.wantErr, "received", err)
+			}
+			if status.Code(err) != codes.OK && status.Code(err) != codes.InvalidArgument {
+				t.Error("expected InvalidArgument, received", err)
+			}
+			if !reflect.DeepEqual(fields, tt.want) && !tt.wantErr {
+				t.Error("expected", tt.want, "received", fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package store implements persistent storage for the objects managed by the bridge
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// fileStore keeps all tables in memory and writes the whole content into a
+// single JSON file on every change. The file is replaced atomically, so a
+// crash in the middle of a write never leaves a partially written file behind.
+type fileStore struct {
+	memoryStore
+	path string
+}
+
+// NewFileStore creates a store backed by the file at path. Objects already
+// saved in the file are loaded, a missing file is created on the first change.
+func NewFileStore(path string) (Store, error) {
+	if path == "" {
+		return nil, errors.New("store file path cannot be empty")
+	}
+	s := &fileStore{
+		memoryStore: memoryStore{tables: make(map[string]map[string][]byte)},
+		path:        path,
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	switch {
+	case os.IsNotExist(err):
+		log.Printf("Store file %v does not exist, starting with empty store", path)
+		return s, nil
+	case err != nil:
+		return nil, fmt.Errorf("cannot read store file %v: %w", path, err)
+	}
+	var content map[string]map[string]json.RawMessage
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("cannot parse store file %v: %w", path, err)
+	}
+	for table, entries := range content {
+		for key, value := range entries {
+			s.set(table, key, value)
+		}
+	}
+	log.Printf("Loaded store file %v", path)
+	return s, nil
+}
+
+func (s *fileStore) Set(table string, key string, value proto.Message) error {
+	data, err := encode(value)
+	if err != nil {
+		return fmt.Errorf("cannot encode %v/%v: %w", table, key, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, existed := s.tables[table][key]
+	s.set(table, key, data)
+	if err := s.flush(); err != nil {
+		if existed {
+			s.set(table, key, old)
+		} else {
+			s.delete(table, key)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) Delete(table string, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, existed := s.tables[table][key]
+	if !existed {
+		return nil
+	}
+	s.delete(table, key)
+	if err := s.flush(); err != nil {
+		s.set(table, key, old)
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) flush() error {
+	content := make(map[string]map[string]json.RawMessage, len(s.tables))
+	for table, entries := range s.tables {
+		if len(entries) == 0 {
+			continue
+		}
+		content[table] = make(map[string]json.RawMessage, len(entries))
+		for key, data := range entries {
+			content[table][key] = data
+		}
+	}
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot encode store content: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot create temporary store file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("cannot write store file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("cannot sync store file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cannot close store file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("cannot replace store file %v: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package store implements persistent storage for the objects managed by the bridge
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStore_NewFileStore(t *testing.T) {
+	tests := map[string]struct {
+		content   *string
+		emptyPath bool
+		wantErr   bool
+	}{
+		"missing file": {
+			content:   nil,
+			emptyPath: false,
+			wantErr:   false,
+		},
+		"empty path": {
+			content:   nil,
+			emptyPath: true,
+			wantErr:   true,
+		},
+		"empty object": {
+			content:   newString("{}"),
+			emptyPath: false,
+			wantErr:   false,
+		},
+		"corrupted file": {
+			content:   newString("{not a json"),
+			emptyPath: false,
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "store.json")
+			if tt.content != nil {
+				if err := os.WriteFile(path, []byte(*tt.content), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.emptyPath {
+				path = ""
+			}
+
+			s, err := NewFileStore(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatal("error: expected", tt.wantErr, "received", err)
+			}
+			if s != nil {
+				_ = s.Close()
+			}
+		})
+	}
+}
+
+func TestStore_FileStoreSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("subsystems", "subsystem-test", testSubsystem); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set("subsystems", "subsystem-other", testOtherSubsystem); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("subsystems", "subsystem-other"); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Close()
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = reopened.Close() }()
+
+	loaded := loadSubsystems(t, reopened, "subsystems")
+	if len(loaded) != 1 {
+		t.Fatal("expected 1 object, received", len(loaded))
+	}
+	if !proto.Equal(loaded["subsystem-test"], testSubsystem) {
+		t.Error("expected", testSubsystem, "received", loaded["subsystem-test"])
+	}
+}
+
+func TestStore_FileStoreWriteFailure(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewFileStore(filepath.Join(dir, "missing-dir", "store.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = s.Close() }()
+
+	if err := s.Set("subsystems", "subsystem-test", testSubsystem); err == nil {
+		t.Fatal("expected error on write into missing directory")
+	}
+	if loaded := loadSubsystems(t, s, "subsystems"); len(loaded) != 0 {
+		t.Error("expected failed write to be rolled back, received", loaded)
+	}
+}
+
+func newString(s string) *string {
+	return &s
+}
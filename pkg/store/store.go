@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package store implements persistent storage for the objects managed by the bridge
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Store is a key/value storage where bridge servers keep their objects,
+// grouped into tables, so the objects survive a restart of the bridge
+type Store interface {
+	// Set saves value under key in table, replacing any previous value
+	Set(table string, key string, value proto.Message) error
+	// Delete removes key from table. Deleting a missing key is not an error
+	Delete(table string, key string) error
+	// Entries returns all encoded values saved in table indexed by key
+	Entries(table string) (map[string][]byte, error)
+	// Close releases resources held by the store
+	Close() error
+}
+
+// Load decodes all objects saved in table and puts them into dst.
+// newValue is used to allocate an empty object for every entry.
+func Load[T proto.Message](s Store, table string, dst map[string]T, newValue func() T) error {
+	entries, err := s.Entries(table)
+	if err != nil {
+		return err
+	}
+	for key, data := range entries {
+		value := newValue()
+		if err := protojson.Unmarshal(data, value); err != nil {
+			return fmt.Errorf("cannot decode %v/%v: %w", table, key, err)
+		}
+		dst[key] = value
+	}
+	return nil
+}
+
+// SetJSON saves value encoded as JSON under key in table. It is used for
+// objects of the bridge which have no protobuf message in the OPI API.
+func SetJSON(s Store, table string, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cannot encode %v/%v: %w", table, key, err)
+	}
+	object := &structpb.Struct{}
+	if err := protojson.Unmarshal(data, object); err != nil {
+		return fmt.Errorf("cannot encode %v/%v: %w", table, key, err)
+	}
+	return s.Set(table, key, object)
+}
+
+// LoadJSON decodes all objects saved with SetJSON in table and puts them into dst
+func LoadJSON[T any](s Store, table string, dst map[string]*T) error {
+	entries, err := s.Entries(table)
+	if err != nil {
+		return err
+	}
+	for key, data := range entries {
+		value := new(T)
+		if err := json.Unmarshal(data, value); err != nil {
+			return fmt.Errorf("cannot decode %v/%v: %w", table, key, err)
+		}
+		dst[key] = value
+	}
+	return nil
+}
+
+func encode(value proto.Message) ([]byte, error) {
+	return protojson.Marshal(value)
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	tables map[string]map[string][]byte
+}
+
+// NewMemoryStore creates a store which keeps objects only in memory.
+// It is used when no persistent storage is configured.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		tables: make(map[string]map[string][]byte),
+	}
+}
+
+func (s *memoryStore) Set(table string, key string, value proto.Message) error {
+	data, err := encode(value)
+	if err != nil {
+		return fmt.Errorf("cannot encode %v/%v: %w", table, key, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(table, key, data)
+	return nil
+}
+
+func (s *memoryStore) Delete(table string, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delete(table, key)
+	return nil
+}
+
+func (s *memoryStore) Entries(table string) (map[string][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries(table), nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
+
+func (s *memoryStore) set(table string, key string, data []byte) {
+	t, ok := s.tables[table]
+	if !ok {
+		t = make(map[string][]byte)
+		s.tables[table] = t
+	}
+	t[key] = data
+}
+
+func (s *memoryStore) delete(table string, key string) {
+	delete(s.tables[table], key)
+}
+
+func (s *memoryStore) entries(table string) map[string][]byte {
+	result := make(map[string][]byte, len(s.tables[table]))
+	for key, data := range s.tables[table] {
+		result[key] = append([]byte(nil), data...)
+	}
+	return result
+}
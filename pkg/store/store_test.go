@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package store implements persistent storage for the objects managed by the bridge
+package store
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	pc "github.com/opiproject/opi-api/common/v1/gen/go"
+	pb "github.com/opiproject/opi-api/storage/v1alpha1/gen/go"
+)
+
+var (
+	testSubsystem = &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{
+			Id:           &pc.ObjectKey{Value: "subsystem-test"},
+			Nqn:          "nqn.2022-09.io.spdk:opi3",
+			SerialNumber: "OpiSerialNumber",
+		},
+		Status: &pb.NVMeSubsystemStatus{FirmwareRevision: "SPDK v20.10"},
+	}
+	testOtherSubsystem = &pb.NVMeSubsystem{
+		Spec: &pb.NVMeSubsystemSpec{
+			Id:  &pc.ObjectKey{Value: "subsystem-other"},
+			Nqn: "nqn.2022-09.io.spdk:opi4",
+		},
+	}
+)
+
+func newSubsystem() *pb.NVMeSubsystem { return &pb.NVMeSubsystem{} }
+
+func loadSubsystems(t *testing.T, s Store, table string) map[string]*pb.NVMeSubsystem {
+	subsystems := make(map[string]*pb.NVMeSubsystem)
+	if err := Load(s, table, subsystems, newSubsystem); err != nil {
+		t.Fatal("unexpected load error:", err)
+	}
+	return subsystems
+}
+
+func TestStore_Memory(t *testing.T) {
+	tests := map[string]struct {
+		set    map[string]*pb.NVMeSubsystem
+		delete []string
+		out    map[string]*pb.NVMeSubsystem
+	}{
+		"empty store": {
+			set:    nil,
+			delete: nil,
+			out:    map[string]*pb.NVMeSubsystem{},
+		},
+		"set objects": {
+			set: map[string]*pb.NVMeSubsystem{
+				"subsystem-test":  testSubsystem,
+				"subsystem-other": testOtherSubsystem,
+			},
+			delete: nil,
+			out: map[string]*pb.NVMeSubsystem{
+				"subsystem-test":  testSubsystem,
+				"subsystem-other": testOtherSubsystem,
+			},
+		},
+		"delete object": {
+			set: map[string]*pb.NVMeSubsystem{
+				"subsystem-test":  testSubsystem,
+				"subsystem-other": testOtherSubsystem,
+			},
+			delete: []string{"subsystem-other"},
+			out: map[string]*pb.NVMeSubsystem{
+				"subsystem-test": testSubsystem,
+			},
+		},
+		"delete missing object": {
+			set:    nil,
+			delete: []string{"unknown-id"},
+			out:    map[string]*pb.NVMeSubsystem{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := NewMemoryStore()
+			defer func() { _ = s.Close() }()
+
+			for key, value := range tt.set {
+				if err := s.Set("subsystems", key, value); err != nil {
+					t.Fatal("unexpected set error:", err)
+				}
+			}
+			for _, key := range tt.delete {
+				if err := s.Delete("subsystems", key); err != nil {
+					t.Fatal("unexpected delete error:", err)
+				}
+			}
+
+			loaded := loadSubsystems(t, s, "subsystems")
+			if len(loaded) != len(tt.out) {
+				t.Fatal("expected", len(tt.out), "objects, received", len(loaded))
+			}
+			for key, expected := range tt.out {
+				if !proto.Equal(loaded[key], expected) {
+					t.Error("object", key, ": expected", expected, "received", loaded[key])
+				}
+			}
+			if other := loadSubsystems(t, s, "other-table"); len(other) != 0 {
+				t.Error("expected tables to be independent, received", other)
+			}
+		})
+	}
+}
+
+func TestStore_LoadInvalidData(t *testing.T) {
+	s := &memoryStore{tables: map[string]map[string][]byte{
+		"subsystems": {"subsystem-test": []byte("{invalid json")},
+	}}
+
+	subsystems := make(map[string]*pb.NVMeSubsystem)
+	if err := Load(s, "subsystems", subsystems, newSubsystem); err == nil {
+		t.Error("expected error for invalid stored data")
+	}
+}
+
+func TestStore_JSON(t *testing.T) {
+	type hosts struct {
+		AllowAnyHost bool     `json:"allow_any_host"`
+		Hosts        []string `json:"hosts"`
+		MaxHosts     int      `json:"max_hosts"`
+	}
+	want := map[string]*hosts{
+		"subsystem-test":  {AllowAnyHost: false, Hosts: []string{"nqn.2014-08.org.nvmexpress:uuid:host1"}, MaxHosts: 32},
+		"subsystem-other": {AllowAnyHost: true, Hosts: []string{}},
+	}
+	s := NewMemoryStore()
+	for key, value := range want {
+		if err := SetJSON(s, "hosts", key, value); err != nil {
+			t.Fatal("unexpected set error:", err)
+		}
+	}
+
+	loaded := make(map[string]*hosts)
+	if err := LoadJSON(s, "hosts", loaded); err != nil {
+		t.Fatal("unexpected load error:", err)
+	}
+	if !reflect.DeepEqual(loaded, want) {
+		t.Error("expected", want, "received", loaded)
+	}
+}
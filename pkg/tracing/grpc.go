@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package tracing records OpenTelemetry spans of gRPC requests, SPDK calls
+// and QMP commands and exports them
+package tracing
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerInterceptor starts a span for every unary gRPC request. The span
+// continues the trace of the client if its context is propagated in metadata.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return otelgrpc.UnaryServerInterceptor()
+}
+
+// StreamServerInterceptor starts a span for every streaming gRPC request
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return otelgrpc.StreamServerInterceptor()
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package tracing records OpenTelemetry spans of gRPC requests, SPDK calls
+// and QMP commands and exports them
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/opiproject/gospdk/spdk"
+	"github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/logging"
+)
+
+// Call calls SPDK method through rpc within a span which is a child
+// of the span in ctx, e.g. of the gRPC request the call is made for.
+// The call is logged with the logger of ctx to keep the correlation ID.
+func Call(ctx context.Context, rpc spdk.JSONRPC, method string, args, result interface{}) error {
+	_, span := Tracer().Start(ctx, "spdk/"+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.RPCSystemKey.String("jsonrpc"), semconv.RPCMethodKey.String(method)))
+	start := time.Now()
+	err := rpc.Call(method, args, result)
+	End(span, err)
+	logger := logging.FromContext(ctx).WithFields(logrus.Fields{
+		"spdk_method": method,
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+	if err != nil {
+		logger.WithError(err).Error("SPDK call failed")
+	} else {
+		logger.Debug("SPDK call finished")
+	}
+	return err
+}
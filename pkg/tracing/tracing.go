@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package tracing records OpenTelemetry spans of gRPC requests, SPDK calls
+// and QMP commands and exports them
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/opiproject/opi-spdk-bridge"
+
+const serviceName = "opi-spdk-bridge"
+
+// Supported span exporters
+const (
+	// ExporterNone disables tracing
+	ExporterNone = ""
+	// ExporterOtlp sends spans to an OTLP collector over gRPC
+	ExporterOtlp = "otlp"
+	// ExporterFile writes spans to a local file as JSON
+	ExporterFile = "file"
+)
+
+// Config defines where spans are exported to
+type Config struct {
+	// Exporter is one of "otlp" or "file", tracing is disabled if empty
+	Exporter string `yaml:"exporter"`
+	// OtlpEndpoint is host:port of the OTLP collector
+	OtlpEndpoint string `yaml:"otlp_endpoint"`
+	// OtlpInsecure disables TLS for the connection to the OTLP collector
+	OtlpInsecure bool `yaml:"otlp_insecure"`
+	// File to append spans to with the file exporter
+	File string `yaml:"file"`
+}
+
+// Enabled returns true if spans are exported
+func (c Config) Enabled() bool {
+	return c.Exporter != ExporterNone
+}
+
+// Validate checks that the configured exporter can be created
+func (c Config) Validate() error {
+	switch c.Exporter {
+	case ExporterNone:
+	case ExporterOtlp:
+		if c.OtlpEndpoint == "" {
+			return errors.New("otlp_endpoint cannot be empty with otlp exporter")
+		}
+	case ExporterFile:
+		if c.File == "" {
+			return errors.New("file cannot be empty with file exporter")
+		}
+	default:
+		return fmt.Errorf("unknown exporter %q, expected %q or %q", c.Exporter, ExporterOtlp, ExporterFile)
+	}
+	return nil
+}
+
+// Setup installs a global tracer provider exporting spans according to cfg.
+// The returned function flushes buffered spans and stops the exporter.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if !cfg.Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	if cfg.Exporter == ExporterFile {
+		f, err := os.OpenFile(filepath.Clean(cfg.File), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return &fileExporter{SpanExporter: exporter, file: f}, nil
+	}
+
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OtlpEndpoint)}
+	if cfg.OtlpInsecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+	return otlptracegrpc.New(ctx, options...)
+}
+
+// fileExporter closes the file spans are written to on shutdown
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Tracer returns the tracer of the bridge from the global tracer provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End marks span as failed if err is not nil and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
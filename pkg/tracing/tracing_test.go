@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (C) 2023 Intel Corporation
+
+// Package tracing records OpenTelemetry spans of gRPC requests, SPDK calls
+// and QMP commands and exports them
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+
+	"github.com/opiproject/opi-spdk-bridge/pkg/server"
+)
+
+func useInMemoryExporter(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	prevProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(prevProvider) })
+	return exporter
+}
+
+func TestCall(t *testing.T) {
+	tests := map[string]struct {
+		method     string
+		wantStatus codes.Code
+	}{
+		"successful call": {"bdev_get_bdevs", codes.Unset},
+		"failed call":     {"bdev_aio_create", codes.Error},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			exporter := useInMemoryExporter(t)
+			rpc := server.CreateTestSpdkStub(map[string]string{"bdev_get_bdevs": `[]`})
+
+			ctx, parent := Tracer().Start(context.Background(), "request")
+			var result []interface{}
+			_ = Call(ctx, rpc, tt.method, nil, &result)
+			parent.End()
+
+			spans := exporter.GetSpans()
+			if len(spans) != 2 {
+				t.Fatal("expected 2 spans, received", len(spans))
+			}
+			span := spans[0]
+			if span.Name != "spdk/"+tt.method {
+				t.Error("expected span of", tt.method, "received", span.Name)
+			}
+			if span.Parent.SpanID() != parent.SpanContext().SpanID() {
+				t.Error("expected span to be a child of the request span")
+			}
+			if span.Status.Code != tt.wantStatus {
+				t.Error("expected status", tt.wantStatus, "received", span.Status.Code)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	exporter := useInMemoryExporter(t)
+	method := "/opi_api.storage.v1.FrontendNvmeService/CreateNVMeSubsystem"
+	rpc := server.CreateTestSpdkStub(map[string]string{"nvmf_create_subsystem": `true`})
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		var result bool
+		return nil, Call(ctx, rpc, "nvmf_create_subsystem", nil, &result)
+	}
+
+	_, err := UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatal("expected 2 spans, received", len(spans))
+	}
+	spdkSpan, grpcSpan := spans[0], spans[1]
+	if grpcSpan.Name != strings.TrimPrefix(method, "/") {
+		t.Error("expected span of gRPC method, received", grpcSpan.Name)
+	}
+	if spdkSpan.Parent.SpanID() != grpcSpan.SpanContext.SpanID() {
+		t.Error("expected SPDK call span to be a child of the gRPC request span")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"disabled":             {Config{}, false},
+		"otlp":                 {Config{Exporter: ExporterOtlp, OtlpEndpoint: "localhost:4317"}, false},
+		"otlp without address": {Config{Exporter: ExporterOtlp}, true},
+		"file":                 {Config{Exporter: ExporterFile, File: "spans.json"}, false},
+		"file without path":    {Config{Exporter: ExporterFile}, true},
+		"unknown exporter":     {Config{Exporter: "jaeger"}, true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Error("expected error", tt.wantErr, "received", err)
+			}
+		})
+	}
+}
+
+func TestSetup_FileExporter(t *testing.T) {
+	prevProvider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prevProvider)
+	file := filepath.Join(t.TempDir(), "spans.json")
+
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterFile, File: file})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := Start(context.Background(), "qmp/device_add")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"Name":"qmp/device_add"`, `"Value":"opi-spdk-bridge"`} {
+		if !strings.Contains(string(out), want) {
+			t.Error("expected", want, "in", string(out))
+		}
+	}
+}